@@ -9,21 +9,30 @@ import (
 	"strconv"
 	"syscall"
 	"time"
+	_ "time/tzdata" // Embed zone data so APP_TIMEZONE works in minimal images
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
+	"github.com/oscar/mileagetracker/internal/api/attachment"
 	"github.com/oscar/mileagetracker/internal/api/client"
+	"github.com/oscar/mileagetracker/internal/api/exchangerate"
 	"github.com/oscar/mileagetracker/internal/api/health"
+	"github.com/oscar/mileagetracker/internal/api/location"
 	"github.com/oscar/mileagetracker/internal/api/middleware"
 	"github.com/oscar/mileagetracker/internal/api/settings"
+	"github.com/oscar/mileagetracker/internal/api/tag"
+	"github.com/oscar/mileagetracker/internal/api/trackimport"
 	"github.com/oscar/mileagetracker/internal/api/trip"
+	"github.com/oscar/mileagetracker/internal/api/view"
 	"github.com/oscar/mileagetracker/internal/config"
 	"github.com/oscar/mileagetracker/internal/database"
-	"github.com/oscar/mileagetracker/internal/domain"
 	"github.com/oscar/mileagetracker/internal/logger"
 	"github.com/oscar/mileagetracker/internal/repository"
+	"github.com/oscar/mileagetracker/internal/routing"
 	"github.com/oscar/mileagetracker/internal/service"
+	"github.com/oscar/mileagetracker/internal/storage"
+	"github.com/oscar/mileagetracker/migrations"
 )
 
 func main() {
@@ -36,38 +45,116 @@ func main() {
 
 	logger.Info("Starting Mileage Tracker API", zap.String("version", cfg.App.Version))
 
+	businessLocation, err := cfg.App.Location()
+	if err != nil {
+		logger.Error("Invalid APP_TIMEZONE", zap.String("timezone", cfg.App.Timezone), zap.Error(err))
+		panic(fmt.Sprintf("Invalid APP_TIMEZONE %q: %v", cfg.App.Timezone, err))
+	}
+
 	// Initialize database
-	if err := database.Init(&cfg.Database); err != nil {
+	store, err := database.Open(&cfg.Database)
+	if err != nil {
 		logger.Error("Failed to initialize database", zap.Error(err))
 		panic(fmt.Sprintf("Failed to initialize database: %v", err))
 	}
-	defer database.Close()
-
-	// Auto-migrate database schema
-	if err := database.DB.AutoMigrate(
-		&domain.Client{},
-		&domain.Trip{},
-		&domain.Settings{},
-	); err != nil {
-		logger.Error("Failed to migrate database", zap.Error(err))
-		panic(fmt.Sprintf("Failed to migrate database: %v", err))
+	defer store.Close()
+
+	migrationFiles, err := migrations.For(store.Dialect())
+	if err != nil {
+		logger.Error("Failed to load migrations", zap.Error(err))
+		panic(fmt.Sprintf("Failed to load migrations: %v", err))
+	}
+	migrator, err := database.NewMigrator(store.DB(), migrationFiles)
+	if err != nil {
+		logger.Error("Failed to load migrations", zap.Error(err))
+		panic(fmt.Sprintf("Failed to load migrations: %v", err))
+	}
+
+	// "server migrate ..." manages the schema instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(context.Background(), migrator, os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "migrate: %v\n", err)
+			store.Close()
+			os.Exit(1)
+		}
+		return
+	}
+
+	if cfg.Database.MigrateOnStart {
+		applied, err := migrator.Up(context.Background())
+		for _, migration := range applied {
+			logger.Info("Applied migration", zap.Uint("version", migration.Version), zap.String("name", migration.Name))
+		}
+		if err != nil {
+			logger.Error("Failed to migrate database", zap.Error(err))
+			panic(fmt.Sprintf("Failed to migrate database: %v", err))
+		}
+	} else {
+		version, err := migrator.Version(context.Background())
+		if err != nil {
+			logger.Error("Failed to read schema version", zap.Error(err))
+			panic(fmt.Sprintf("Failed to read schema version: %v", err))
+		}
+		if version < migrator.Latest() {
+			logger.Error("Database schema is out of date", zap.Uint("version", version), zap.Uint("latest", migrator.Latest()))
+			fmt.Fprintf(os.Stderr, "database schema is at version %d but this server needs %d; run `server migrate up` first\n", version, migrator.Latest())
+			store.Close()
+			os.Exit(1)
+		}
 	}
 
 	// Initialize repositories
-	clientRepo := repository.NewClientRepository(database.DB)
-	tripRepo := repository.NewTripRepository(database.DB)
-	settingsRepo := repository.NewSettingsRepository(database.DB)
+	clientRepo := repository.NewClientRepository(store)
+	tripRepo := repository.NewTripRepository(store)
+	settingsRepo := repository.NewSettingsRepository(store)
+	locationRepo := repository.NewLocationRepository(store)
+	routeCacheRepo := repository.NewRouteCacheRepository(store)
+	viewRepo := repository.NewSavedViewRepository(store)
+	tagRepo := repository.NewTagRepository(store)
+	attachmentRepo := repository.NewAttachmentRepository(store)
+	exchangeRateRepo := repository.NewExchangeRateRepository(store)
+
+	// Routing is optional; without it unrecorded distances are estimated
+	var distanceProvider service.DistanceProvider
+	if cfg.Routing.OSRMURL != "" {
+		osrm := routing.NewOSRMProvider(cfg.Routing.OSRMURL, cfg.Routing.Profile, time.Duration(cfg.Routing.TimeoutSeconds)*time.Second)
+		distanceProvider = service.NewCachedDistanceProvider(osrm, routeCacheRepo)
+		logger.Info("Routing enabled", zap.String("provider", osrm.Name()), zap.String("url", cfg.Routing.OSRMURL))
+	}
+
+	blobStore, err := newBlobStore(cfg.Storage)
+	if err != nil {
+		logger.Error("Failed to initialize attachment storage", zap.String("driver", cfg.Storage.Driver), zap.Error(err))
+		panic(fmt.Sprintf("Failed to initialize attachment storage: %v", err))
+	}
+	maxAttachmentBytes := int64(cfg.Storage.MaxAttachmentMB) << 20
 
 	// Initialize services
 	clientService := service.NewClientService(clientRepo)
-	tripService := service.NewTripService(tripRepo, clientService, settingsRepo)
+	locationService := service.NewLocationService(locationRepo, distanceProvider)
+	tagService := service.NewTagService(tagRepo)
+	attachmentService := service.NewAttachmentService(attachmentRepo, tripRepo, blobStore, maxAttachmentBytes)
+	exchangeRateService := service.NewExchangeRateService(exchangeRateRepo)
+	tripService := service.NewTripService(tripRepo, clientService, settingsRepo, locationService, tagService, attachmentService, exchangeRateService, businessLocation)
 	settingsService := service.NewSettingsService(settingsRepo)
+	if err := settingsService.SeedDefaults(context.Background()); err != nil {
+		logger.Error("Failed to seed default settings", zap.Error(err))
+		panic(fmt.Sprintf("Failed to seed default settings: %v", err))
+	}
+	trackImportService := service.NewTrackImportService(tripService, businessLocation)
+	viewService := service.NewViewService(viewRepo, businessLocation)
 
 	// Initialize handlers
 	clientHandler := client.NewHandler(clientService)
-	tripHandler := trip.NewHandler(tripService)
+	tripHandler := trip.NewHandler(tripService, viewService)
 	settingsHandler := settings.NewHandler(settingsService)
-	healthHandler := health.NewHandler(cfg.App.Version)
+	locationHandler := location.NewHandler(locationService)
+	trackImportHandler := trackimport.NewHandler(trackImportService)
+	viewHandler := view.NewHandler(viewService)
+	tagHandler := tag.NewHandler(tagService)
+	attachmentHandler := attachment.NewHandler(attachmentService, maxAttachmentBytes)
+	exchangeRateHandler := exchangerate.NewHandler(exchangeRateService)
+	healthHandler := health.NewHandler(cfg.App.Version, store)
 
 	gin.SetMode(cfg.Server.Mode)
 	router := gin.New()
@@ -76,7 +163,7 @@ func main() {
 	router.Use(middleware.Logger())
 	router.Use(middleware.CORS())
 
-	setupRoutes(router, clientHandler, tripHandler, settingsHandler, healthHandler)
+	setupRoutes(router, clientHandler, tripHandler, settingsHandler, locationHandler, trackImportHandler, viewHandler, tagHandler, attachmentHandler, exchangeRateHandler, healthHandler)
 
 	server := &http.Server{
 		Addr:         ":" + strconv.Itoa(cfg.Server.Port),
@@ -111,7 +198,19 @@ func main() {
 	logger.Info("Server exited")
 }
 
-func setupRoutes(router *gin.Engine, clientHandler *client.Handler, tripHandler *trip.Handler, settingsHandler *settings.Handler, healthHandler *health.Handler) {
+func setupRoutes(
+	router *gin.Engine,
+	clientHandler *client.Handler,
+	tripHandler *trip.Handler,
+	settingsHandler *settings.Handler,
+	locationHandler *location.Handler,
+	trackImportHandler *trackimport.Handler,
+	viewHandler *view.Handler,
+	tagHandler *tag.Handler,
+	attachmentHandler *attachment.Handler,
+	exchangeRateHandler *exchangerate.Handler,
+	healthHandler *health.Handler,
+) {
 	router.GET("/health", healthHandler.HealthHandler)
 	router.GET("/ready", healthHandler.ReadinessHandler)
 
@@ -124,12 +223,75 @@ func setupRoutes(router *gin.Engine, clientHandler *client.Handler, tripHandler
 		v1.PUT("/trips/:id", tripHandler.UpdateTrip)
 		v1.DELETE("/trips/:id", tripHandler.DeleteTrip)
 		v1.GET("/trips/summary", tripHandler.GetSummary)
+		v1.POST("/trips/import", trackImportHandler.ImportTrack)
+		v1.GET("/dashboard", tripHandler.GetDashboard)
+		v1.GET("/tax-summary", tripHandler.GetTaxSummary)
+
+		// Attachment routes
+		v1.POST("/trips/:id/attachments", attachmentHandler.UploadAttachment)
+		v1.GET("/trips/:id/attachments", attachmentHandler.GetAttachments)
+		v1.GET("/trips/:id/attachments/:attachmentId", attachmentHandler.DownloadAttachment)
+		v1.DELETE("/trips/:id/attachments/:attachmentId", attachmentHandler.DeleteAttachment)
 
 		// Client routes
 		v1.GET("/clients", clientHandler.GetSuggestions)
+		v1.PUT("/clients/:id", clientHandler.UpdateClient)
 
 		// Settings routes
 		v1.GET("/settings", settingsHandler.GetSettings)
 		v1.PUT("/settings", settingsHandler.UpdateSettings)
+		v1.GET("/settings/definitions", settingsHandler.GetDefinitions)
+
+		// Location routes
+		v1.GET("/locations", locationHandler.GetLocations)
+		v1.POST("/locations", locationHandler.CreateLocation)
+		v1.GET("/locations/distances", locationHandler.GetDistances)
+		v1.PUT("/locations/distances", locationHandler.SetDistance)
+		v1.GET("/locations/:id", locationHandler.GetLocationByID)
+		v1.PUT("/locations/:id", locationHandler.UpdateLocation)
+		v1.DELETE("/locations/:id", locationHandler.DeleteLocation)
+		v1.GET("/locations/:id/distance/:toId", locationHandler.ResolveDistance)
+
+		// Saved view routes
+		v1.GET("/views", viewHandler.GetViews)
+		v1.POST("/views", viewHandler.CreateView)
+		v1.GET("/views/:id", viewHandler.GetViewByID)
+		v1.PUT("/views/:id", viewHandler.UpdateView)
+		v1.DELETE("/views/:id", viewHandler.DeleteView)
+
+		// Tag routes
+		v1.GET("/tags", tagHandler.GetTags)
+		v1.POST("/tags", tagHandler.CreateTag)
+		v1.GET("/tags/:id", tagHandler.GetTagByID)
+		v1.PUT("/tags/:id", tagHandler.UpdateTag)
+		v1.DELETE("/tags/:id", tagHandler.DeleteTag)
+
+		// Exchange rate routes
+		v1.GET("/exchange-rates", exchangeRateHandler.GetRates)
+		v1.PUT("/exchange-rates", exchangeRateHandler.SetRate)
+		v1.POST("/exchange-rates/import", exchangeRateHandler.ImportRates)
+		v1.DELETE("/exchange-rates/:id", exchangeRateHandler.DeleteRate)
+	}
+}
+
+// newBlobStore creates the store attachments are kept in
+func newBlobStore(cfg config.StorageConfig) (service.BlobStore, error) {
+	switch cfg.Driver {
+	case "local":
+		return storage.NewLocalStore(cfg.LocalDir)
+	case "s3":
+		if cfg.S3Endpoint == "" || cfg.S3Bucket == "" {
+			return nil, fmt.Errorf("S3_ENDPOINT and S3_BUCKET are required for the s3 driver")
+		}
+		return storage.NewS3Store(storage.S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+			PathStyle:       cfg.S3ForcePathStyle,
+		}, time.Duration(cfg.S3TimeoutSeconds)*time.Second), nil
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_DRIVER %q, expected local or s3", cfg.Driver)
 	}
 }
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/oscar/mileagetracker/internal/database"
+)
+
+const migrateUsage = `usage: server migrate <command>
+
+commands:
+  up              apply all pending migrations
+  down [n]        revert the n most recent migrations (default 1)
+  status          list migrations and when they were applied
+  goto <version>  migrate up or down to version; 0 reverts everything`
+
+// runMigrate runs a "migrate" subcommand against the database and reports
+// what it did on out
+func runMigrate(ctx context.Context, migrator *database.Migrator, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	switch command, rest := args[0], args[1:]; command {
+	case "up":
+		if len(rest) != 0 {
+			return errors.New(migrateUsage)
+		}
+		applied, err := migrator.Up(ctx)
+		printMigrations(out, "applied", applied)
+		return err
+
+	case "down":
+		n := 1
+		if len(rest) > 1 {
+			return errors.New(migrateUsage)
+		}
+		if len(rest) == 1 {
+			var err error
+			if n, err = strconv.Atoi(rest[0]); err != nil || n < 1 {
+				return fmt.Errorf("invalid migration count %q", rest[0])
+			}
+		}
+		reverted, err := migrator.Down(ctx, n)
+		printMigrations(out, "reverted", reverted)
+		return err
+
+	case "goto":
+		if len(rest) != 1 {
+			return errors.New(migrateUsage)
+		}
+		version, err := strconv.ParseUint(rest[0], 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid migration version %q", rest[0])
+		}
+		current, err := migrator.Version(ctx)
+		if err != nil {
+			return err
+		}
+		verb := "applied"
+		if uint(version) < current {
+			verb = "reverted"
+		}
+		migrated, err := migrator.Goto(ctx, uint(version))
+		printMigrations(out, verb, migrated)
+		return err
+
+	case "status":
+		if len(rest) != 0 {
+			return errors.New(migrateUsage)
+		}
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if status.Missing {
+				appliedAt += " (no migration files)"
+			}
+			fmt.Fprintf(w, "%03d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+		return w.Flush()
+
+	default:
+		return fmt.Errorf("unknown migrate command %q\n%s", command, migrateUsage)
+	}
+}
+
+func printMigrations(out io.Writer, verb string, migrations []database.Migration) {
+	if len(migrations) == 0 {
+		fmt.Fprintln(out, "no migrations", verb)
+		return
+	}
+	for _, migration := range migrations {
+		fmt.Fprintf(out, "%s %03d_%s\n", verb, migration.Version, migration.Name)
+	}
+}
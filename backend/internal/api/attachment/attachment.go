@@ -0,0 +1,161 @@
+package attachment
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oscar/mileagetracker/internal/api/common"
+	"github.com/oscar/mileagetracker/internal/service"
+)
+
+type Handler struct {
+	attachmentService service.AttachmentService
+	maxBytes          int64
+}
+
+// NewHandler creates an attachment handler accepting uploads of up to
+// maxBytes
+func NewHandler(attachmentService service.AttachmentService, maxBytes int64) *Handler {
+	return &Handler{
+		attachmentService: attachmentService,
+		maxBytes:          maxBytes,
+	}
+}
+
+// respondWithServiceError maps service errors onto HTTP responses; resource
+// names what a not found error refers to
+func respondWithServiceError(c *gin.Context, err error, resource string) {
+	switch {
+	case errors.Is(err, service.ErrValidation):
+		common.RespondWithBadRequestError(c, err.Error())
+	case errors.Is(err, service.ErrNotFound):
+		common.RespondWithNotFoundError(c, resource)
+	default:
+		common.RespondWithInternalError(c, err)
+	}
+}
+
+func parseTripID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		common.RespondWithBadRequestError(c, "Invalid trip ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func parseIDs(c *gin.Context) (uint, uint, bool) {
+	tripID, ok := parseTripID(c)
+	if !ok {
+		return 0, 0, false
+	}
+	id, err := strconv.ParseUint(c.Param("attachmentId"), 10, 32)
+	if err != nil {
+		common.RespondWithBadRequestError(c, "Invalid attachment ID")
+		return 0, 0, false
+	}
+	return tripID, uint(id), true
+}
+
+// UploadAttachment stores a file sent as multipart form data in the 'file'
+// field
+func (h *Handler) UploadAttachment(c *gin.Context) {
+	tripID, ok := parseTripID(c)
+	if !ok {
+		return
+	}
+
+	// Leave room for the multipart envelope around the file
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxBytes+1<<20)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			common.RespondWithError(c, http.StatusRequestEntityTooLarge, h.sizeLimitMessage())
+			return
+		}
+		common.RespondWithBadRequestError(c, "An attachment file is required in the 'file' field")
+		return
+	}
+	if fileHeader.Size > h.maxBytes {
+		common.RespondWithError(c, http.StatusRequestEntityTooLarge, h.sizeLimitMessage())
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		common.RespondWithInternalError(c, err)
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.attachmentService.UploadAttachment(c.Request.Context(), tripID, fileHeader.Filename, file, fileHeader.Size)
+	if err != nil {
+		respondWithServiceError(c, err, "Trip")
+		return
+	}
+
+	c.JSON(http.StatusCreated, attachment)
+}
+
+// GetAttachments lists a trip's attachments
+func (h *Handler) GetAttachments(c *gin.Context) {
+	tripID, ok := parseTripID(c)
+	if !ok {
+		return
+	}
+
+	attachments, err := h.attachmentService.GetAttachments(c.Request.Context(), tripID)
+	if err != nil {
+		respondWithServiceError(c, err, "Trip")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"attachments": attachments})
+}
+
+// DownloadAttachment streams an attachment's content as a file download
+func (h *Handler) DownloadAttachment(c *gin.Context) {
+	tripID, id, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+
+	attachment, content, err := h.attachmentService.OpenAttachment(c.Request.Context(), tripID, id)
+	if err != nil {
+		respondWithServiceError(c, err, "Attachment")
+		return
+	}
+	defer content.Close()
+
+	// Always download rather than render, and stop browsers second-guessing
+	// the stored type
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}),
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+// DeleteAttachment deletes an attachment and its content
+func (h *Handler) DeleteAttachment(c *gin.Context) {
+	tripID, id, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.attachmentService.DeleteAttachment(c.Request.Context(), tripID, id); err != nil {
+		respondWithServiceError(c, err, "Attachment")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) sizeLimitMessage() string {
+	return fmt.Sprintf("Attachment must be %d MB or smaller", h.maxBytes>>20)
+}
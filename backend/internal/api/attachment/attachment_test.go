@@ -0,0 +1,251 @@
+package attachment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAttachmentService implements the AttachmentService interface for testing
+type MockAttachmentService struct {
+	mock.Mock
+}
+
+func (m *MockAttachmentService) UploadAttachment(ctx context.Context, tripID uint, fileName string, content io.Reader, size int64) (*domain.Attachment, error) {
+	args := m.Called(ctx, tripID, fileName, content, size)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentService) GetAttachments(ctx context.Context, tripID uint) ([]domain.Attachment, error) {
+	args := m.Called(ctx, tripID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentService) OpenAttachment(ctx context.Context, tripID, id uint) (*domain.Attachment, io.ReadCloser, error) {
+	args := m.Called(ctx, tripID, id)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*domain.Attachment), args.Get(1).(io.ReadCloser), args.Error(2)
+}
+
+func (m *MockAttachmentService) DeleteAttachment(ctx context.Context, tripID, id uint) error {
+	args := m.Called(ctx, tripID, id)
+	return args.Error(0)
+}
+
+func (m *MockAttachmentService) FindTripAttachments(ctx context.Context, tripID uint) ([]domain.Attachment, error) {
+	args := m.Called(ctx, tripID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentService) RemoveContent(ctx context.Context, attachments []domain.Attachment) {
+	m.Called(ctx, attachments)
+}
+
+func setupTestRouter(attachmentService *MockAttachmentService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	handler := NewHandler(attachmentService, 1<<20)
+
+	api := router.Group("/api/v1")
+	{
+		api.POST("/trips/:id/attachments", handler.UploadAttachment)
+		api.GET("/trips/:id/attachments", handler.GetAttachments)
+		api.GET("/trips/:id/attachments/:attachmentId", handler.DownloadAttachment)
+		api.DELETE("/trips/:id/attachments/:attachmentId", handler.DeleteAttachment)
+	}
+
+	return router
+}
+
+func multipartBody(t *testing.T, field, fileName string, content []byte) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(field, fileName)
+	assert.NoError(t, err)
+	_, _ = part.Write(content)
+	assert.NoError(t, writer.Close())
+	return body, writer.FormDataContentType()
+}
+
+func TestAttachmentHandler_UploadAttachment(t *testing.T) {
+	t.Run("should upload attachment successfully", func(t *testing.T) {
+		mockService := new(MockAttachmentService)
+		router := setupTestRouter(mockService)
+
+		stored := &domain.Attachment{ID: 1, TripID: 5, FileName: "receipt.pdf", ContentType: "application/pdf", Size: 8}
+		mockService.On("UploadAttachment", mock.Anything, uint(5), "receipt.pdf", mock.Anything, int64(8)).Return(stored, nil)
+
+		body, contentType := multipartBody(t, "file", "receipt.pdf", []byte("%PDF-1.7"))
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/trips/5/attachments", body)
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var response map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "receipt.pdf", response["file_name"])
+		assert.NotContains(t, response, "storage_key")
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should require a file", func(t *testing.T) {
+		mockService := new(MockAttachmentService)
+		router := setupTestRouter(mockService)
+
+		body, contentType := multipartBody(t, "other", "receipt.pdf", []byte("%PDF-1.7"))
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/trips/5/attachments", body)
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "UploadAttachment")
+	})
+
+	t.Run("should reject files over the size limit", func(t *testing.T) {
+		mockService := new(MockAttachmentService)
+		router := setupTestRouter(mockService)
+
+		body, contentType := multipartBody(t, "file", "scan.png", bytes.Repeat([]byte("x"), 1<<20+1))
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/trips/5/attachments", body)
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Contains(t, w.Body.String(), "1 MB or smaller")
+		mockService.AssertNotCalled(t, "UploadAttachment")
+	})
+
+	t.Run("should map service errors", func(t *testing.T) {
+		tests := []struct {
+			err          error
+			expectedCode int
+			expectedBody string
+		}{
+			{fmt.Errorf("%w: unsupported attachment type text/plain", service.ErrValidation), http.StatusBadRequest, "unsupported attachment type"},
+			{fmt.Errorf("trip 5 %w", service.ErrNotFound), http.StatusNotFound, "Trip not found"},
+			{errors.New("bucket unavailable"), http.StatusInternalServerError, "Internal server error"},
+		}
+
+		for _, tt := range tests {
+			mockService := new(MockAttachmentService)
+			router := setupTestRouter(mockService)
+			mockService.On("UploadAttachment", mock.Anything, uint(5), "notes.txt", mock.Anything, int64(5)).Return(nil, tt.err)
+
+			body, contentType := multipartBody(t, "file", "notes.txt", []byte("hello"))
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/trips/5/attachments", body)
+			req.Header.Set("Content-Type", contentType)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+		}
+	})
+}
+
+func TestAttachmentHandler_GetAttachments(t *testing.T) {
+	mockService := new(MockAttachmentService)
+	router := setupTestRouter(mockService)
+
+	mockService.On("GetAttachments", mock.Anything, uint(5)).Return([]domain.Attachment{
+		{ID: 1, TripID: 5, FileName: "receipt.pdf", StorageKey: "trips/5/abc"},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/trips/5/attachments", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"attachments":[{"id":1`)
+	assert.NotContains(t, w.Body.String(), "trips/5/abc")
+}
+
+func TestAttachmentHandler_DownloadAttachment(t *testing.T) {
+	t.Run("should stream the content as a download", func(t *testing.T) {
+		mockService := new(MockAttachmentService)
+		router := setupTestRouter(mockService)
+
+		attachment := &domain.Attachment{ID: 2, TripID: 5, FileName: "parking ticket.pdf", ContentType: "application/pdf", Size: 8}
+		mockService.On("OpenAttachment", mock.Anything, uint(5), uint(2)).
+			Return(attachment, io.NopCloser(strings.NewReader("%PDF-1.7")), nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/trips/5/attachments/2", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "%PDF-1.7", w.Body.String())
+		assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="parking ticket.pdf"`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	})
+
+	t.Run("should return not found for a missing attachment", func(t *testing.T) {
+		mockService := new(MockAttachmentService)
+		router := setupTestRouter(mockService)
+
+		mockService.On("OpenAttachment", mock.Anything, uint(5), uint(9)).
+			Return(nil, nil, fmt.Errorf("attachment 9 %w", service.ErrNotFound))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/trips/5/attachments/9", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Attachment not found")
+	})
+
+	t.Run("should reject an invalid attachment ID", func(t *testing.T) {
+		router := setupTestRouter(new(MockAttachmentService))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/trips/5/attachments/abc", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid attachment ID")
+	})
+}
+
+func TestAttachmentHandler_DeleteAttachment(t *testing.T) {
+	mockService := new(MockAttachmentService)
+	router := setupTestRouter(mockService)
+
+	mockService.On("DeleteAttachment", mock.Anything, uint(5), uint(2)).Return(nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/trips/5/attachments/2", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockService.AssertExpectations(t)
+}
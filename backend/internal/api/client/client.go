@@ -1,10 +1,13 @@
 package client
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/oscar/mileagetracker/internal/api/common"
+	"github.com/oscar/mileagetracker/internal/domain"
 	"github.com/oscar/mileagetracker/internal/service"
 )
 
@@ -30,3 +33,33 @@ func (h *Handler) GetSuggestions(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"clients": clients})
 }
+
+// UpdateClient updates a client's settings, currently its billing currency
+func (h *Handler) UpdateClient(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		common.RespondWithBadRequestError(c, "Invalid client ID")
+		return
+	}
+
+	var req domain.UpdateClientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondWithBadRequestError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	client, err := h.clientService.UpdateClient(c.Request.Context(), uint(id), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrValidation):
+			common.RespondWithBadRequestError(c, err.Error())
+		case errors.Is(err, service.ErrNotFound):
+			common.RespondWithNotFoundError(c, "Client")
+		default:
+			common.RespondWithInternalError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, client)
+}
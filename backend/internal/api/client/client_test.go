@@ -1,6 +1,7 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -32,6 +34,14 @@ func (m *MockClientService) GetSuggestions(ctx context.Context, query string) ([
 	return args.Get(0).([]domain.Client), args.Error(1)
 }
 
+func (m *MockClientService) UpdateClient(ctx context.Context, id uint, req domain.UpdateClientRequest) (*domain.Client, error) {
+	args := m.Called(ctx, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Client), args.Error(1)
+}
+
 func setupTestRouter(clientService *MockClientService) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
@@ -41,6 +51,7 @@ func setupTestRouter(clientService *MockClientService) *gin.Engine {
 	api := router.Group("/api/v1")
 	{
 		api.GET("/clients/suggestions", handler.GetSuggestions)
+		api.PUT("/clients/:id", handler.UpdateClient)
 	}
 
 	return router
@@ -281,3 +292,52 @@ func TestClientHandler_GetSuggestions(t *testing.T) {
 		mockService.AssertExpectations(t)
 	})
 }
+
+func TestClientHandler_UpdateClient(t *testing.T) {
+	t.Run("should update the client currency", func(t *testing.T) {
+		mockService := new(MockClientService)
+		router := setupTestRouter(mockService)
+
+		mockService.On("UpdateClient", mock.Anything, uint(4), domain.UpdateClientRequest{Currency: "cad"}).
+			Return(&domain.Client{ID: 4, Name: "Maple Corp", Currency: "CAD"}, nil)
+
+		req, _ := http.NewRequest("PUT", "/api/v1/clients/4", bytes.NewBufferString(`{"currency":"cad"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"currency":"CAD"`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return bad request for an invalid currency", func(t *testing.T) {
+		mockService := new(MockClientService)
+		router := setupTestRouter(mockService)
+
+		mockService.On("UpdateClient", mock.Anything, uint(4), mock.Anything).
+			Return(nil, fmt.Errorf("%w: currency must be a 3-letter ISO 4217 code", service.ErrValidation))
+
+		req, _ := http.NewRequest("PUT", "/api/v1/clients/4", bytes.NewBufferString(`{"currency":"dollars"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should return not found for an unknown client", func(t *testing.T) {
+		mockService := new(MockClientService)
+		router := setupTestRouter(mockService)
+
+		mockService.On("UpdateClient", mock.Anything, uint(99), mock.Anything).
+			Return(nil, fmt.Errorf("client 99 %w", service.ErrNotFound))
+
+		req, _ := http.NewRequest("PUT", "/api/v1/clients/99", bytes.NewBufferString(`{"currency":"CAD"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
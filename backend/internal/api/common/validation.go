@@ -0,0 +1,27 @@
+package common
+
+import (
+	"reflect"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+func init() {
+	// Let binding tags such as required and min apply to money and rate
+	// fields
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+			switch value := field.Interface().(type) {
+			case domain.Money:
+				return value.Float64()
+			case decimal.Decimal:
+				f, _ := value.Float64()
+				return f
+			}
+			return nil
+		}, domain.Money{}, decimal.Decimal{})
+	}
+}
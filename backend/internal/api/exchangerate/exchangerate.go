@@ -0,0 +1,135 @@
+package exchangerate
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oscar/mileagetracker/internal/api/common"
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/service"
+)
+
+// maxRatesBytes caps the size of an uploaded exchange rate file; the full
+// ECB history is well under it
+const maxRatesBytes = 20 << 20
+
+type Handler struct {
+	rateService service.ExchangeRateService
+}
+
+func NewHandler(rateService service.ExchangeRateService) *Handler {
+	return &Handler{
+		rateService: rateService,
+	}
+}
+
+// respondWithServiceError maps service errors onto HTTP responses
+func respondWithServiceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrValidation):
+		common.RespondWithBadRequestError(c, err.Error())
+	case errors.Is(err, service.ErrNotFound):
+		common.RespondWithNotFoundError(c, "Exchange rate")
+	default:
+		common.RespondWithInternalError(c, err)
+	}
+}
+
+// GetRates lists exchange rates, newest first
+func (h *Handler) GetRates(c *gin.Context) {
+	query := domain.ExchangeRateQuery{
+		Base:     c.Query("base"),
+		Quote:    c.Query("quote"),
+		DateFrom: c.Query("date_from"),
+		DateTo:   c.Query("date_to"),
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			common.RespondWithBadRequestError(c, "limit must be a positive integer")
+			return
+		}
+		query.Limit = limit
+	}
+
+	rates, err := h.rateService.GetRates(c.Request.Context(), query)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"exchange_rates": rates})
+}
+
+// SetRate records a manually entered rate, replacing any rate for the same
+// pair and date
+func (h *Handler) SetRate(c *gin.Context) {
+	var req domain.ExchangeRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondWithBadRequestError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	rate, err := h.rateService.SetRate(c.Request.Context(), req)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rate)
+}
+
+// ImportRates loads an uploaded ECB XML or CSV exchange rate file
+func (h *Handler) ImportRates(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxRatesBytes+1<<20)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		common.RespondWithBadRequestError(c, "An exchange rate file is required in the 'file' field")
+		return
+	}
+	if fileHeader.Size > maxRatesBytes {
+		common.RespondWithError(c, http.StatusRequestEntityTooLarge, "Exchange rate file must be 20 MB or smaller")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		common.RespondWithInternalError(c, err)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		common.RespondWithInternalError(c, err)
+		return
+	}
+
+	result, err := h.rateService.ImportRates(c.Request.Context(), data)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// DeleteRate deletes an exchange rate
+func (h *Handler) DeleteRate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		common.RespondWithBadRequestError(c, "Invalid exchange rate ID")
+		return
+	}
+
+	if err := h.rateService.DeleteRate(c.Request.Context(), uint(id)); err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,221 @@
+package exchangerate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/service"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockExchangeRateService implements the ExchangeRateService interface for testing
+type MockExchangeRateService struct {
+	mock.Mock
+}
+
+func (m *MockExchangeRateService) GetRates(ctx context.Context, query domain.ExchangeRateQuery) ([]domain.ExchangeRate, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ExchangeRate), args.Error(1)
+}
+
+func (m *MockExchangeRateService) SetRate(ctx context.Context, req domain.ExchangeRateRequest) (*domain.ExchangeRate, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ExchangeRate), args.Error(1)
+}
+
+func (m *MockExchangeRateService) DeleteRate(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockExchangeRateService) ImportRates(ctx context.Context, data []byte) (*domain.ExchangeRateImportResult, error) {
+	args := m.Called(ctx, data)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ExchangeRateImportResult), args.Error(1)
+}
+
+func (m *MockExchangeRateService) Converter(ctx context.Context, currencies []string, to string) (*service.CurrencyConverter, error) {
+	args := m.Called(ctx, currencies, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.CurrencyConverter), args.Error(1)
+}
+
+func setupTestRouter(rateService *MockExchangeRateService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	handler := NewHandler(rateService)
+
+	api := router.Group("/api/v1")
+	{
+		api.GET("/exchange-rates", handler.GetRates)
+		api.PUT("/exchange-rates", handler.SetRate)
+		api.POST("/exchange-rates/import", handler.ImportRates)
+		api.DELETE("/exchange-rates/:id", handler.DeleteRate)
+	}
+
+	return router
+}
+
+func TestExchangeRateHandler_GetRates(t *testing.T) {
+	t.Run("should list rates matching the query", func(t *testing.T) {
+		mockService := new(MockExchangeRateService)
+		router := setupTestRouter(mockService)
+
+		mockService.On("GetRates", mock.Anything, domain.ExchangeRateQuery{Base: "USD", DateFrom: "2025-01-01", Limit: 10}).
+			Return([]domain.ExchangeRate{{ID: 1, Date: "2025-01-15", Base: "USD", Quote: "CAD", Rate: decimal.RequireFromString("1.43")}}, nil)
+
+		req, _ := http.NewRequest("GET", "/api/v1/exchange-rates?base=USD&date_from=2025-01-01&limit=10", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response map[string][]domain.ExchangeRate
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response["exchange_rates"], 1)
+	})
+
+	t.Run("should reject an invalid limit", func(t *testing.T) {
+		mockService := new(MockExchangeRateService)
+		router := setupTestRouter(mockService)
+
+		req, _ := http.NewRequest("GET", "/api/v1/exchange-rates?limit=none", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "GetRates", mock.Anything, mock.Anything)
+	})
+}
+
+func TestExchangeRateHandler_SetRate(t *testing.T) {
+	t.Run("should save a rate", func(t *testing.T) {
+		mockService := new(MockExchangeRateService)
+		router := setupTestRouter(mockService)
+
+		request := domain.ExchangeRateRequest{Date: "2025-01-15", Base: "USD", Quote: "CAD", Rate: decimal.RequireFromString("1.43")}
+		mockService.On("SetRate", mock.Anything, request).
+			Return(&domain.ExchangeRate{ID: 1, Date: "2025-01-15", Base: "USD", Quote: "CAD", Rate: decimal.RequireFromString("1.43"), Source: "manual"}, nil)
+
+		body, _ := json.Marshal(request)
+		req, _ := http.NewRequest("PUT", "/api/v1/exchange-rates", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return validation errors as bad requests", func(t *testing.T) {
+		mockService := new(MockExchangeRateService)
+		router := setupTestRouter(mockService)
+
+		mockService.On("SetRate", mock.Anything, mock.Anything).
+			Return(nil, fmt.Errorf("%w: base and quote currencies must differ", service.ErrValidation))
+
+		req, _ := http.NewRequest("PUT", "/api/v1/exchange-rates",
+			bytes.NewBufferString(`{"date":"2025-01-15","base":"USD","quote":"USD","rate":1}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should require a positive rate", func(t *testing.T) {
+		mockService := new(MockExchangeRateService)
+		router := setupTestRouter(mockService)
+
+		req, _ := http.NewRequest("PUT", "/api/v1/exchange-rates",
+			bytes.NewBufferString(`{"date":"2025-01-15","base":"USD","quote":"CAD","rate":-1}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "SetRate", mock.Anything, mock.Anything)
+	})
+}
+
+func TestExchangeRateHandler_ImportRates(t *testing.T) {
+	t.Run("should import an uploaded file", func(t *testing.T) {
+		mockService := new(MockExchangeRateService)
+		router := setupTestRouter(mockService)
+
+		data := []byte("date,base,quote,rate\n2025-01-15,USD,CAD,1.43\n")
+		mockService.On("ImportRates", mock.Anything, data).
+			Return(&domain.ExchangeRateImportResult{Format: "csv", Imported: 1, DateFrom: "2025-01-15", DateTo: "2025-01-15"}, nil)
+
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, _ := writer.CreateFormFile("file", "rates.csv")
+		_, _ = part.Write(data)
+		writer.Close()
+
+		req, _ := http.NewRequest("POST", "/api/v1/exchange-rates/import", &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"imported":1`)
+	})
+
+	t.Run("should require a file", func(t *testing.T) {
+		mockService := new(MockExchangeRateService)
+		router := setupTestRouter(mockService)
+
+		req, _ := http.NewRequest("POST", "/api/v1/exchange-rates/import", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestExchangeRateHandler_DeleteRate(t *testing.T) {
+	t.Run("should delete a rate", func(t *testing.T) {
+		mockService := new(MockExchangeRateService)
+		router := setupTestRouter(mockService)
+		mockService.On("DeleteRate", mock.Anything, uint(3)).Return(nil)
+
+		req, _ := http.NewRequest("DELETE", "/api/v1/exchange-rates/3", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("should return not found for an unknown rate", func(t *testing.T) {
+		mockService := new(MockExchangeRateService)
+		router := setupTestRouter(mockService)
+		mockService.On("DeleteRate", mock.Anything, uint(9)).Return(fmt.Errorf("exchange rate 9 %w", service.ErrNotFound))
+
+		req, _ := http.NewRequest("DELETE", "/api/v1/exchange-rates/9", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -1,11 +1,21 @@
 package health
 
 import (
+	"context"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// pingTimeout bounds the database check of a readiness probe
+const pingTimeout = 2 * time.Second
+
+// Pinger checks that a dependency can be reached
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
 type HealthResponse struct {
 	Status  string `json:"status"`
 	Version string `json:"version"`
@@ -20,11 +30,13 @@ type ReadinessResponse struct {
 
 type Handler struct {
 	version string
+	db      Pinger
 }
 
-func NewHandler(version string) *Handler {
+func NewHandler(version string, db Pinger) *Handler {
 	return &Handler{
 		version: version,
+		db:      db,
 	}
 }
 
@@ -35,15 +47,24 @@ func (h *Handler) HealthHandler(c *gin.Context) {
 	})
 }
 
+// ReadinessHandler reports ready only while the database answers a ping
 func (h *Handler) ReadinessHandler(c *gin.Context) {
-	services := map[string]string{
-		"database": "healthy",
+	ctx, cancel := context.WithTimeout(c.Request.Context(), pingTimeout)
+	defer cancel()
+
+	if err := h.db.Ping(ctx); err != nil {
+		c.JSON(http.StatusServiceUnavailable, ReadinessResponse{
+			Status:   "not ready",
+			Version:  h.version,
+			Services: map[string]string{"database": "unhealthy"},
+		})
+		return
 	}
 
 	c.JSON(http.StatusOK, ReadinessResponse{
 		Status:   "ready",
 		Version:  h.version,
-		Services: services,
+		Services: map[string]string{"database": "healthy"},
 	})
 }
 
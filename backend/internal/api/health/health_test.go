@@ -1,7 +1,9 @@
 package health
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -10,12 +12,21 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+// stubPinger answers pings with err
+type stubPinger struct {
+	err error
+}
+
+func (p stubPinger) Ping(ctx context.Context) error {
+	return p.err
+}
+
 func setupTestRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
 
 	// Test with versioned handler
-	healthHandler := NewHandler("test-version-1.0.0")
+	healthHandler := NewHandler("test-version-1.0.0", stubPinger{})
 	router.GET("/health", healthHandler.HealthHandler)
 	router.GET("/ready", healthHandler.ReadinessHandler)
 
@@ -279,6 +290,25 @@ func TestReadinessHandler(t *testing.T) {
 	})
 }
 
+func TestReadinessHandler_DatabaseUnreachable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := NewHandler("test-version-1.0.0", stubPinger{err: errors.New("connection refused")})
+	router.GET("/ready", handler.ReadinessHandler)
+
+	req, _ := http.NewRequest("GET", "/ready", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var response ReadinessResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "not ready", response.Status)
+	assert.Equal(t, "unhealthy", response.Services["database"])
+}
+
 func TestHealthAndReadinessHandlers_Integration(t *testing.T) {
 	router := setupTestRouter()
 
@@ -325,14 +355,14 @@ func TestHealthAndReadinessHandlers_Integration(t *testing.T) {
 func TestHealthHandlerStructure(t *testing.T) {
 	t.Run("NewHandler should create handler with correct version", func(t *testing.T) {
 		version := "v2.1.0"
-		handler := NewHandler(version)
+		handler := NewHandler(version, stubPinger{})
 		assert.NotNil(t, handler)
 		assert.Equal(t, version, handler.version)
 	})
 
 	t.Run("Handler methods should return correct version", func(t *testing.T) {
 		version := "v3.0.0-beta"
-		handler := NewHandler(version)
+		handler := NewHandler(version, stubPinger{})
 
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
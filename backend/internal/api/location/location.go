@@ -0,0 +1,172 @@
+package location
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oscar/mileagetracker/internal/api/common"
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/service"
+)
+
+type Handler struct {
+	locationService service.LocationService
+}
+
+func NewHandler(locationService service.LocationService) *Handler {
+	return &Handler{
+		locationService: locationService,
+	}
+}
+
+// respondWithServiceError maps service errors onto HTTP responses
+func respondWithServiceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrValidation):
+		common.RespondWithBadRequestError(c, err.Error())
+	case errors.Is(err, service.ErrNotFound):
+		common.RespondWithNotFoundError(c, "Location")
+	default:
+		common.RespondWithInternalError(c, err)
+	}
+}
+
+func parseID(c *gin.Context, param string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(param), 10, 32)
+	if err != nil {
+		common.RespondWithBadRequestError(c, "Invalid location ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// GetLocations retrieves all saved locations
+func (h *Handler) GetLocations(c *gin.Context) {
+	locations, err := h.locationService.GetLocations(c.Request.Context())
+	if err != nil {
+		common.RespondWithInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"locations": locations})
+}
+
+// GetLocationByID retrieves a specific location by ID
+func (h *Handler) GetLocationByID(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	location, err := h.locationService.GetLocation(c.Request.Context(), id)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, location)
+}
+
+// CreateLocation saves a new location to the address book
+func (h *Handler) CreateLocation(c *gin.Context) {
+	var req domain.CreateLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondWithBadRequestError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	location, err := h.locationService.CreateLocation(c.Request.Context(), req)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, location)
+}
+
+// UpdateLocation updates an existing location
+func (h *Handler) UpdateLocation(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondWithBadRequestError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	location, err := h.locationService.UpdateLocation(c.Request.Context(), id, req)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, location)
+}
+
+// DeleteLocation deletes a location and its recorded distances
+func (h *Handler) DeleteLocation(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.locationService.DeleteLocation(c.Request.Context(), id); err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetDistances retrieves the matrix of recorded distances
+func (h *Handler) GetDistances(c *gin.Context) {
+	distances, err := h.locationService.GetDistances(c.Request.Context())
+	if err != nil {
+		common.RespondWithInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"distances": distances})
+}
+
+// SetDistance records the distance between two locations
+func (h *Handler) SetDistance(c *gin.Context) {
+	var req domain.SetLocationDistanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondWithBadRequestError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	distance, err := h.locationService.SetDistance(c.Request.Context(), req)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, distance)
+}
+
+// ResolveDistance returns the recorded or estimated distance between two locations
+func (h *Handler) ResolveDistance(c *gin.Context) {
+	fromID, ok := parseID(c, "id")
+	if !ok {
+		return
+	}
+	toID, ok := parseID(c, "toId")
+	if !ok {
+		return
+	}
+
+	distance, err := h.locationService.ResolveDistance(c.Request.Context(), fromID, toID)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, distance)
+}
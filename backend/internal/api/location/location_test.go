@@ -0,0 +1,239 @@
+package location
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockLocationService implements the LocationService interface for testing
+type MockLocationService struct {
+	mock.Mock
+}
+
+func (m *MockLocationService) CreateLocation(ctx context.Context, req domain.CreateLocationRequest) (*domain.Location, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Location), args.Error(1)
+}
+
+func (m *MockLocationService) UpdateLocation(ctx context.Context, id uint, req domain.UpdateLocationRequest) (*domain.Location, error) {
+	args := m.Called(ctx, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Location), args.Error(1)
+}
+
+func (m *MockLocationService) DeleteLocation(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockLocationService) GetLocation(ctx context.Context, id uint) (*domain.Location, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Location), args.Error(1)
+}
+
+func (m *MockLocationService) GetLocations(ctx context.Context) ([]domain.Location, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Location), args.Error(1)
+}
+
+func (m *MockLocationService) SetDistance(ctx context.Context, req domain.SetLocationDistanceRequest) (*domain.LocationDistance, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LocationDistance), args.Error(1)
+}
+
+func (m *MockLocationService) GetDistances(ctx context.Context) ([]domain.LocationDistance, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.LocationDistance), args.Error(1)
+}
+
+func (m *MockLocationService) ResolveDistance(ctx context.Context, fromID, toID uint) (*domain.ResolvedDistance, error) {
+	args := m.Called(ctx, fromID, toID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ResolvedDistance), args.Error(1)
+}
+
+func setupTestRouter(locationService *MockLocationService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	handler := NewHandler(locationService)
+
+	api := router.Group("/api/v1")
+	{
+		api.GET("/locations", handler.GetLocations)
+		api.POST("/locations", handler.CreateLocation)
+		api.GET("/locations/distances", handler.GetDistances)
+		api.PUT("/locations/distances", handler.SetDistance)
+		api.GET("/locations/:id", handler.GetLocationByID)
+		api.PUT("/locations/:id", handler.UpdateLocation)
+		api.DELETE("/locations/:id", handler.DeleteLocation)
+		api.GET("/locations/:id/distance/:toId", handler.ResolveDistance)
+	}
+
+	return router
+}
+
+func TestLocationHandler_CreateLocation(t *testing.T) {
+	t.Run("should create location successfully", func(t *testing.T) {
+		mockService := new(MockLocationService)
+		router := setupTestRouter(mockService)
+
+		requestBody := domain.CreateLocationRequest{Label: "Office", Address: "1 Main St"}
+		mockService.On("CreateLocation", mock.Anything, requestBody).
+			Return(&domain.Location{ID: 1, Label: "Office", Address: "1 Main St"}, nil)
+
+		jsonData, _ := json.Marshal(requestBody)
+		req, _ := http.NewRequest("POST", "/api/v1/locations", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return 400 for out of range latitude", func(t *testing.T) {
+		mockService := new(MockLocationService)
+		router := setupTestRouter(mockService)
+
+		req, _ := http.NewRequest("POST", "/api/v1/locations",
+			bytes.NewBufferString(`{"label":"Office","latitude":91,"longitude":0}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should return 400 for service validation error", func(t *testing.T) {
+		mockService := new(MockLocationService)
+		router := setupTestRouter(mockService)
+
+		mockService.On("CreateLocation", mock.Anything, mock.Anything).
+			Return(nil, fmt.Errorf("%w: latitude and longitude must be provided together", service.ErrValidation))
+
+		req, _ := http.NewRequest("POST", "/api/v1/locations", bytes.NewBufferString(`{"label":"Office","latitude":10}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "provided together")
+	})
+}
+
+func TestLocationHandler_GetLocationByID(t *testing.T) {
+	t.Run("should return 404 for unknown location", func(t *testing.T) {
+		mockService := new(MockLocationService)
+		router := setupTestRouter(mockService)
+
+		mockService.On("GetLocation", mock.Anything, uint(7)).Return(nil, fmt.Errorf("location 7 %w", service.ErrNotFound))
+
+		req, _ := http.NewRequest("GET", "/api/v1/locations/7", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("should return 400 for invalid ID", func(t *testing.T) {
+		router := setupTestRouter(new(MockLocationService))
+
+		req, _ := http.NewRequest("GET", "/api/v1/locations/abc", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestLocationHandler_Distances(t *testing.T) {
+	t.Run("should record distance", func(t *testing.T) {
+		mockService := new(MockLocationService)
+		router := setupTestRouter(mockService)
+
+		requestBody := domain.SetLocationDistanceRequest{FromLocationID: 1, ToLocationID: 2, Miles: 12.5}
+		mockService.On("SetDistance", mock.Anything, requestBody).
+			Return(&domain.LocationDistance{ID: 1, FromLocationID: 1, ToLocationID: 2, Miles: 12.5}, nil)
+
+		jsonData, _ := json.Marshal(requestBody)
+		req, _ := http.NewRequest("PUT", "/api/v1/locations/distances", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should list recorded distances", func(t *testing.T) {
+		mockService := new(MockLocationService)
+		router := setupTestRouter(mockService)
+
+		mockService.On("GetDistances", mock.Anything).
+			Return([]domain.LocationDistance{{ID: 1, FromLocationID: 1, ToLocationID: 2, Miles: 12.5}}, nil)
+
+		req, _ := http.NewRequest("GET", "/api/v1/locations/distances", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string][]domain.LocationDistance
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response["distances"], 1)
+	})
+
+	t.Run("should resolve distance between locations", func(t *testing.T) {
+		mockService := new(MockLocationService)
+		router := setupTestRouter(mockService)
+
+		mockService.On("ResolveDistance", mock.Anything, uint(1), uint(2)).Return(&domain.ResolvedDistance{
+			FromLocationID: 1,
+			ToLocationID:   2,
+			Miles:          14.2,
+			Source:         domain.DistanceSourceEstimate,
+		}, nil)
+
+		req, _ := http.NewRequest("GET", "/api/v1/locations/1/distance/2", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response domain.ResolvedDistance
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 14.2, response.Miles)
+		assert.Equal(t, domain.DistanceSourceEstimate, response.Source)
+	})
+}
@@ -1,6 +1,7 @@
 package settings
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -19,9 +20,14 @@ func NewHandler(settingsService service.SettingsService) *Handler {
 	}
 }
 
-// GetSettings retrieves current settings
+// GetSettings retrieves current settings, with the mileage rate per the
+// requested unit
 func (h *Handler) GetSettings(c *gin.Context) {
-	settings, err := h.settingsService.GetSettings(c.Request.Context())
+	settings, err := h.settingsService.GetSettings(c.Request.Context(), c.Query("unit"))
+	if errors.Is(err, service.ErrValidation) {
+		common.RespondWithBadRequestError(c, err.Error())
+		return
+	}
 	if err != nil {
 		common.RespondWithInternalError(c, err)
 		return
@@ -30,7 +36,8 @@ func (h *Handler) GetSettings(c *gin.Context) {
 	c.JSON(http.StatusOK, settings)
 }
 
-// UpdateSettings updates application settings
+// UpdateSettings updates the settings given in the request body, leaving
+// the others unchanged
 func (h *Handler) UpdateSettings(c *gin.Context) {
 	var req domain.UpdateSettingsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -39,10 +46,20 @@ func (h *Handler) UpdateSettings(c *gin.Context) {
 	}
 
 	settings, err := h.settingsService.UpdateSettings(c.Request.Context(), req)
-	if err != nil {
+	if errors.Is(err, service.ErrValidation) {
 		common.RespondWithBadRequestError(c, "Invalid request data: "+err.Error())
 		return
 	}
+	if err != nil {
+		common.RespondWithInternalError(c, err)
+		return
+	}
 
 	c.JSON(http.StatusOK, settings)
 }
+
+// GetDefinitions lists the settings with their types, defaults and
+// descriptions
+func (h *Handler) GetDefinitions(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"settings": h.settingsService.GetDefinitions()})
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -20,20 +21,30 @@ type MockSettingsService struct {
 	mock.Mock
 }
 
-func (m *MockSettingsService) GetSettings(ctx context.Context) (*domain.SettingsResponse, error) {
-	args := m.Called(ctx)
+func (m *MockSettingsService) GetSettings(ctx context.Context, unit string) (domain.SettingsResponse, error) {
+	args := m.Called(ctx, unit)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*domain.SettingsResponse), args.Error(1)
+	return args.Get(0).(domain.SettingsResponse), args.Error(1)
 }
 
-func (m *MockSettingsService) UpdateSettings(ctx context.Context, req domain.UpdateSettingsRequest) (*domain.SettingsResponse, error) {
+func (m *MockSettingsService) UpdateSettings(ctx context.Context, req domain.UpdateSettingsRequest) (domain.SettingsResponse, error) {
 	args := m.Called(ctx, req)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*domain.SettingsResponse), args.Error(1)
+	return args.Get(0).(domain.SettingsResponse), args.Error(1)
+}
+
+func (m *MockSettingsService) GetDefinitions() []domain.SettingDefinition {
+	args := m.Called()
+	return args.Get(0).([]domain.SettingDefinition)
+}
+
+func (m *MockSettingsService) SeedDefaults(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
 }
 
 func setupTestRouter(settingsService *MockSettingsService) *gin.Engine {
@@ -46,6 +57,7 @@ func setupTestRouter(settingsService *MockSettingsService) *gin.Engine {
 	{
 		api.GET("/settings", handler.GetSettings)
 		api.PUT("/settings", handler.UpdateSettings)
+		api.GET("/settings/definitions", handler.GetDefinitions)
 	}
 
 	return router
@@ -57,11 +69,9 @@ func TestSettingsHandler_GetSettings(t *testing.T) {
 		mockService := new(MockSettingsService)
 		router := setupTestRouter(mockService)
 
-		expectedSettings := &domain.SettingsResponse{
-			MileageRate: 0.67,
-		}
+		expectedSettings := domain.SettingsResponse{"mileage_rate": domain.MustMoney("0.67")}
 
-		mockService.On("GetSettings", mock.Anything).Return(expectedSettings, nil)
+		mockService.On("GetSettings", mock.Anything, "").Return(expectedSettings, nil)
 
 		// Execute
 		req, _ := http.NewRequest("GET", "/api/v1/settings", nil)
@@ -71,10 +81,10 @@ func TestSettingsHandler_GetSettings(t *testing.T) {
 		// Assert
 		assert.Equal(t, http.StatusOK, w.Code)
 
-		var response domain.SettingsResponse
+		var response map[string]interface{}
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Equal(t, expectedSettings.MileageRate, response.MileageRate)
+		assert.Equal(t, "0.67", response["mileage_rate"])
 
 		mockService.AssertExpectations(t)
 	})
@@ -84,11 +94,9 @@ func TestSettingsHandler_GetSettings(t *testing.T) {
 		mockService := new(MockSettingsService)
 		router := setupTestRouter(mockService)
 
-		expectedSettings := &domain.SettingsResponse{
-			MileageRate: 0.67,
-		}
+		expectedSettings := domain.SettingsResponse{"mileage_rate": domain.MustMoney("0.67")}
 
-		mockService.On("GetSettings", mock.Anything).Return(expectedSettings, nil)
+		mockService.On("GetSettings", mock.Anything, "").Return(expectedSettings, nil)
 
 		// Execute
 		req, _ := http.NewRequest("GET", "/api/v1/settings", nil)
@@ -98,10 +106,10 @@ func TestSettingsHandler_GetSettings(t *testing.T) {
 		// Assert
 		assert.Equal(t, http.StatusOK, w.Code)
 
-		var response domain.SettingsResponse
+		var response map[string]interface{}
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Equal(t, 0.67, response.MileageRate)
+		assert.Equal(t, "0.67", response["mileage_rate"])
 
 		mockService.AssertExpectations(t)
 	})
@@ -111,7 +119,7 @@ func TestSettingsHandler_GetSettings(t *testing.T) {
 		mockService := new(MockSettingsService)
 		router := setupTestRouter(mockService)
 
-		mockService.On("GetSettings", mock.Anything).Return(nil, fmt.Errorf("database connection failed"))
+		mockService.On("GetSettings", mock.Anything, "").Return(nil, fmt.Errorf("database connection failed"))
 
 		// Execute
 		req, _ := http.NewRequest("GET", "/api/v1/settings", nil)
@@ -129,11 +137,9 @@ func TestSettingsHandler_GetSettings(t *testing.T) {
 		mockService := new(MockSettingsService)
 		router := setupTestRouter(mockService)
 
-		expectedSettings := &domain.SettingsResponse{
-			MileageRate: 0.58,
-		}
+		expectedSettings := domain.SettingsResponse{"mileage_rate": domain.MustMoney("0.58")}
 
-		mockService.On("GetSettings", mock.Anything).Return(expectedSettings, nil)
+		mockService.On("GetSettings", mock.Anything, "").Return(expectedSettings, nil)
 
 		// Execute
 		req, _ := http.NewRequest("GET", "/api/v1/settings", nil)
@@ -143,13 +149,47 @@ func TestSettingsHandler_GetSettings(t *testing.T) {
 		// Assert
 		assert.Equal(t, http.StatusOK, w.Code)
 
-		var response domain.SettingsResponse
+		var response map[string]interface{}
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Equal(t, 0.58, response.MileageRate)
+		assert.Equal(t, "0.58", response["mileage_rate"])
 
 		mockService.AssertExpectations(t)
 	})
+
+	t.Run("should pass the requested unit", func(t *testing.T) {
+		mockService := new(MockSettingsService)
+		router := setupTestRouter(mockService)
+
+		expectedSettings := domain.SettingsResponse{
+			"mileage_rate":  domain.MustMoney("0.4163"),
+			"distance_unit": "mi",
+			"rate_unit":     "km",
+		}
+		mockService.On("GetSettings", mock.Anything, "km").Return(expectedSettings, nil)
+
+		req, _ := http.NewRequest("GET", "/api/v1/settings?unit=km", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"rate_unit":"km"`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return 400 for an unknown unit", func(t *testing.T) {
+		mockService := new(MockSettingsService)
+		router := setupTestRouter(mockService)
+
+		mockService.On("GetSettings", mock.Anything, "yards").
+			Return(nil, fmt.Errorf("%w: unit must be mi or km", service.ErrValidation))
+
+		req, _ := http.NewRequest("GET", "/api/v1/settings?unit=yards", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
 }
 
 func TestSettingsHandler_UpdateSettings(t *testing.T) {
@@ -158,13 +198,9 @@ func TestSettingsHandler_UpdateSettings(t *testing.T) {
 		mockService := new(MockSettingsService)
 		router := setupTestRouter(mockService)
 
-		requestBody := domain.UpdateSettingsRequest{
-			MileageRate: 0.58,
-		}
+		requestBody := domain.UpdateSettingsRequest{"mileage_rate": json.RawMessage(`"0.58"`)}
 
-		expectedSettings := &domain.SettingsResponse{
-			MileageRate: 0.58,
-		}
+		expectedSettings := domain.SettingsResponse{"mileage_rate": domain.MustMoney("0.58")}
 
 		mockService.On("UpdateSettings", mock.Anything, requestBody).Return(expectedSettings, nil)
 
@@ -179,10 +215,10 @@ func TestSettingsHandler_UpdateSettings(t *testing.T) {
 		// Assert
 		assert.Equal(t, http.StatusOK, w.Code)
 
-		var response domain.SettingsResponse
+		var response map[string]interface{}
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Equal(t, expectedSettings.MileageRate, response.MileageRate)
+		assert.Equal(t, "0.58", response["mileage_rate"])
 
 		mockService.AssertExpectations(t)
 	})
@@ -192,13 +228,9 @@ func TestSettingsHandler_UpdateSettings(t *testing.T) {
 		mockService := new(MockSettingsService)
 		router := setupTestRouter(mockService)
 
-		requestBody := domain.UpdateSettingsRequest{
-			MileageRate: 0.655,
-		}
+		requestBody := domain.UpdateSettingsRequest{"mileage_rate": json.RawMessage(`"0.655"`)}
 
-		expectedSettings := &domain.SettingsResponse{
-			MileageRate: 0.655,
-		}
+		expectedSettings := domain.SettingsResponse{"mileage_rate": domain.MustMoney("0.655")}
 
 		mockService.On("UpdateSettings", mock.Anything, requestBody).Return(expectedSettings, nil)
 
@@ -213,10 +245,10 @@ func TestSettingsHandler_UpdateSettings(t *testing.T) {
 		// Assert
 		assert.Equal(t, http.StatusOK, w.Code)
 
-		var response domain.SettingsResponse
+		var response map[string]interface{}
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Equal(t, 0.655, response.MileageRate)
+		assert.Equal(t, "0.655", response["mileage_rate"])
 
 		mockService.AssertExpectations(t)
 	})
@@ -238,57 +270,45 @@ func TestSettingsHandler_UpdateSettings(t *testing.T) {
 		assert.Contains(t, w.Body.String(), "Invalid request data")
 	})
 
-	t.Run("should return 400 for missing required field", func(t *testing.T) {
-		// Setup
+	t.Run("should pass an empty update to the service", func(t *testing.T) {
 		mockService := new(MockSettingsService)
 		router := setupTestRouter(mockService)
 
-		// Execute
+		expectedSettings := domain.SettingsResponse{"mileage_rate": domain.MustMoney("0.67")}
+		mockService.On("UpdateSettings", mock.Anything, domain.UpdateSettingsRequest{}).Return(expectedSettings, nil)
+
 		req, _ := http.NewRequest("PUT", "/api/v1/settings", bytes.NewBuffer([]byte("{}")))
 		req.Header.Set("Content-Type", "application/json")
 
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
 
-		// Assert
-		assert.Equal(t, http.StatusBadRequest, w.Code)
-		assert.Contains(t, w.Body.String(), "Invalid request data")
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
 	})
 
-	t.Run("should return 400 for negative mileage rate", func(t *testing.T) {
-		// Setup
-		mockService := new(MockSettingsService)
-		router := setupTestRouter(mockService)
+	t.Run("should return 400 for invalid setting values", func(t *testing.T) {
+		for body, message := range map[string]string{
+			`{"mileage_rate": -0.5}`:                                "mileage_rate must not be negative",
+			`{"mileage_rate": 0.67, "fiscal_year_start_month": 13}`: "fiscal_year_start_month must be between 1 and 12",
+			`{"mileage_rate": "invalid"}`:                           "mileage_rate must be a decimal",
+			`{"odometer": 1}`:                                       "unknown setting",
+		} {
+			mockService := new(MockSettingsService)
+			router := setupTestRouter(mockService)
 
-		// Execute
-		jsonData := []byte(`{"mileage_rate": -0.5}`)
-		req, _ := http.NewRequest("PUT", "/api/v1/settings", bytes.NewBuffer(jsonData))
-		req.Header.Set("Content-Type", "application/json")
+			mockService.On("UpdateSettings", mock.Anything, mock.Anything).
+				Return(nil, fmt.Errorf("%w: %s", service.ErrValidation, message))
 
-		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+			req, _ := http.NewRequest("PUT", "/api/v1/settings", bytes.NewBuffer([]byte(body)))
+			req.Header.Set("Content-Type", "application/json")
 
-		// Assert
-		assert.Equal(t, http.StatusBadRequest, w.Code)
-		assert.Contains(t, w.Body.String(), "Invalid request data")
-	})
-
-	t.Run("should return 400 for invalid data type", func(t *testing.T) {
-		// Setup
-		mockService := new(MockSettingsService)
-		router := setupTestRouter(mockService)
-
-		// Execute
-		jsonData := []byte(`{"mileage_rate": "invalid"}`)
-		req, _ := http.NewRequest("PUT", "/api/v1/settings", bytes.NewBuffer(jsonData))
-		req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
 
-		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
-
-		// Assert
-		assert.Equal(t, http.StatusBadRequest, w.Code)
-		assert.Contains(t, w.Body.String(), "Invalid request data")
+			assert.Equal(t, http.StatusBadRequest, w.Code, body)
+			assert.Contains(t, w.Body.String(), message, body)
+		}
 	})
 
 	t.Run("should handle service error", func(t *testing.T) {
@@ -296,11 +316,9 @@ func TestSettingsHandler_UpdateSettings(t *testing.T) {
 		mockService := new(MockSettingsService)
 		router := setupTestRouter(mockService)
 
-		requestBody := domain.UpdateSettingsRequest{
-			MileageRate: 0.58,
-		}
+		requestBody := domain.UpdateSettingsRequest{"mileage_rate": json.RawMessage(`"0.58"`)}
 
-		mockService.On("UpdateSettings", mock.Anything, requestBody).Return(nil, fmt.Errorf("validation error"))
+		mockService.On("UpdateSettings", mock.Anything, requestBody).Return(nil, fmt.Errorf("database connection failed"))
 
 		// Execute
 		jsonData, _ := json.Marshal(requestBody)
@@ -311,8 +329,8 @@ func TestSettingsHandler_UpdateSettings(t *testing.T) {
 		router.ServeHTTP(w, req)
 
 		// Assert
-		assert.Equal(t, http.StatusBadRequest, w.Code)
-		assert.Contains(t, w.Body.String(), "Invalid request data")
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.NotContains(t, w.Body.String(), "database connection failed")
 
 		mockService.AssertExpectations(t)
 	})
@@ -322,13 +340,9 @@ func TestSettingsHandler_UpdateSettings(t *testing.T) {
 		mockService := new(MockSettingsService)
 		router := setupTestRouter(mockService)
 
-		requestBody := domain.UpdateSettingsRequest{
-			MileageRate: 999.99,
-		}
+		requestBody := domain.UpdateSettingsRequest{"mileage_rate": json.RawMessage(`"999.99"`)}
 
-		expectedSettings := &domain.SettingsResponse{
-			MileageRate: 999.99,
-		}
+		expectedSettings := domain.SettingsResponse{"mileage_rate": domain.MustMoney("999.99")}
 
 		mockService.On("UpdateSettings", mock.Anything, requestBody).Return(expectedSettings, nil)
 
@@ -343,10 +357,10 @@ func TestSettingsHandler_UpdateSettings(t *testing.T) {
 		// Assert
 		assert.Equal(t, http.StatusOK, w.Code)
 
-		var response domain.SettingsResponse
+		var response map[string]interface{}
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Equal(t, 999.99, response.MileageRate)
+		assert.Equal(t, "999.99", response["mileage_rate"])
 
 		mockService.AssertExpectations(t)
 	})
@@ -356,13 +370,9 @@ func TestSettingsHandler_UpdateSettings(t *testing.T) {
 		mockService := new(MockSettingsService)
 		router := setupTestRouter(mockService)
 
-		requestBody := domain.UpdateSettingsRequest{
-			MileageRate: 0.58,
-		}
+		requestBody := domain.UpdateSettingsRequest{"mileage_rate": json.RawMessage(`0.58`)}
 
-		expectedSettings := &domain.SettingsResponse{
-			MileageRate: 0.58,
-		}
+		expectedSettings := domain.SettingsResponse{"mileage_rate": domain.MustMoney("0.58")}
 
 		mockService.On("UpdateSettings", mock.Anything, requestBody).Return(expectedSettings, nil)
 
@@ -380,3 +390,24 @@ func TestSettingsHandler_UpdateSettings(t *testing.T) {
 		mockService.AssertExpectations(t)
 	})
 }
+
+func TestSettingsHandler_GetDefinitions(t *testing.T) {
+	mockService := new(MockSettingsService)
+	router := setupTestRouter(mockService)
+
+	mockService.On("GetDefinitions").Return([]domain.SettingDefinition{
+		{Key: "mileage_rate", Type: domain.SettingTypeDecimal, Default: "0.67", Description: "Amount paid per mile driven."},
+	})
+
+	req, _ := http.NewRequest("GET", "/api/v1/settings/definitions", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"settings": [{
+		"key": "mileage_rate",
+		"type": "decimal",
+		"default": "0.67",
+		"description": "Amount paid per mile driven."
+	}]}`, w.Body.String())
+}
@@ -0,0 +1,124 @@
+package tag
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oscar/mileagetracker/internal/api/common"
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/service"
+)
+
+type Handler struct {
+	tagService service.TagService
+}
+
+func NewHandler(tagService service.TagService) *Handler {
+	return &Handler{
+		tagService: tagService,
+	}
+}
+
+// respondWithServiceError maps service errors onto HTTP responses
+func respondWithServiceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrValidation):
+		common.RespondWithBadRequestError(c, err.Error())
+	case errors.Is(err, service.ErrNotFound):
+		common.RespondWithNotFoundError(c, "Tag")
+	default:
+		common.RespondWithInternalError(c, err)
+	}
+}
+
+func parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		common.RespondWithBadRequestError(c, "Invalid tag ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// GetTags retrieves all tags
+func (h *Handler) GetTags(c *gin.Context) {
+	tags, err := h.tagService.GetTags(c.Request.Context())
+	if err != nil {
+		common.RespondWithInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tags": tags})
+}
+
+// GetTagByID retrieves a specific tag by ID
+func (h *Handler) GetTagByID(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	tag, err := h.tagService.GetTag(c.Request.Context(), id)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// CreateTag creates a new tag
+func (h *Handler) CreateTag(c *gin.Context) {
+	var req domain.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondWithBadRequestError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	tag, err := h.tagService.CreateTag(c.Request.Context(), req)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, tag)
+}
+
+// UpdateTag renames a tag
+func (h *Handler) UpdateTag(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var req domain.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondWithBadRequestError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	tag, err := h.tagService.UpdateTag(c.Request.Context(), id, req)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// DeleteTag deletes a tag and removes it from every trip
+func (h *Handler) DeleteTag(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.tagService.DeleteTag(c.Request.Context(), id); err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,208 @@
+package tag
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTagService implements the TagService interface for testing
+type MockTagService struct {
+	mock.Mock
+}
+
+func (m *MockTagService) GetTags(ctx context.Context) ([]domain.Tag, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Tag), args.Error(1)
+}
+
+func (m *MockTagService) GetTag(ctx context.Context, id uint) (*domain.Tag, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Tag), args.Error(1)
+}
+
+func (m *MockTagService) CreateTag(ctx context.Context, req domain.TagRequest) (*domain.Tag, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Tag), args.Error(1)
+}
+
+func (m *MockTagService) UpdateTag(ctx context.Context, id uint, req domain.TagRequest) (*domain.Tag, error) {
+	args := m.Called(ctx, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Tag), args.Error(1)
+}
+
+func (m *MockTagService) DeleteTag(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockTagService) ResolveTags(ctx context.Context, names []string) ([]domain.Tag, error) {
+	args := m.Called(ctx, names)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Tag), args.Error(1)
+}
+
+func setupTestRouter(tagService *MockTagService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	handler := NewHandler(tagService)
+
+	api := router.Group("/api/v1")
+	{
+		api.GET("/tags", handler.GetTags)
+		api.POST("/tags", handler.CreateTag)
+		api.GET("/tags/:id", handler.GetTagByID)
+		api.PUT("/tags/:id", handler.UpdateTag)
+		api.DELETE("/tags/:id", handler.DeleteTag)
+	}
+
+	return router
+}
+
+func TestTagHandler_CreateTag(t *testing.T) {
+	t.Run("should create tag successfully", func(t *testing.T) {
+		mockService := new(MockTagService)
+		router := setupTestRouter(mockService)
+
+		requestBody := domain.TagRequest{Name: "Site Visit"}
+		mockService.On("CreateTag", mock.Anything, requestBody).Return(&domain.Tag{ID: 1, Name: "site-visit"}, nil)
+
+		jsonData, _ := json.Marshal(requestBody)
+		req, _ := http.NewRequest("POST", "/api/v1/tags", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"name":"site-visit"`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return 400 for missing name", func(t *testing.T) {
+		router := setupTestRouter(new(MockTagService))
+
+		req, _ := http.NewRequest("POST", "/api/v1/tags", bytes.NewBufferString(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should return 400 for duplicate name", func(t *testing.T) {
+		mockService := new(MockTagService)
+		router := setupTestRouter(mockService)
+
+		mockService.On("CreateTag", mock.Anything, mock.Anything).
+			Return(nil, fmt.Errorf("%w: a tag named %q already exists", service.ErrValidation, "billable"))
+
+		req, _ := http.NewRequest("POST", "/api/v1/tags", bytes.NewBufferString(`{"name":"billable"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "already exists")
+	})
+}
+
+func TestTagHandler_GetTags(t *testing.T) {
+	mockService := new(MockTagService)
+	router := setupTestRouter(mockService)
+
+	mockService.On("GetTags", mock.Anything).Return([]domain.Tag{{ID: 1, Name: "billable"}}, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/tags", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Tags []domain.Tag `json:"tags"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Tags, 1)
+}
+
+func TestTagHandler_UpdateTag(t *testing.T) {
+	t.Run("should rename tag", func(t *testing.T) {
+		mockService := new(MockTagService)
+		router := setupTestRouter(mockService)
+
+		mockService.On("UpdateTag", mock.Anything, uint(1), domain.TagRequest{Name: "client-visit"}).
+			Return(&domain.Tag{ID: 1, Name: "client-visit"}, nil)
+
+		req, _ := http.NewRequest("PUT", "/api/v1/tags/1", bytes.NewBufferString(`{"name":"client-visit"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return 404 for unknown tag", func(t *testing.T) {
+		mockService := new(MockTagService)
+		router := setupTestRouter(mockService)
+
+		mockService.On("UpdateTag", mock.Anything, uint(7), mock.Anything).Return(nil, fmt.Errorf("tag 7 %w", service.ErrNotFound))
+
+		req, _ := http.NewRequest("PUT", "/api/v1/tags/7", bytes.NewBufferString(`{"name":"x"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestTagHandler_DeleteTag(t *testing.T) {
+	t.Run("should delete tag", func(t *testing.T) {
+		mockService := new(MockTagService)
+		router := setupTestRouter(mockService)
+
+		mockService.On("DeleteTag", mock.Anything, uint(1)).Return(nil)
+
+		req, _ := http.NewRequest("DELETE", "/api/v1/tags/1", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("should return 400 for invalid ID", func(t *testing.T) {
+		router := setupTestRouter(new(MockTagService))
+
+		req, _ := http.NewRequest("DELETE", "/api/v1/tags/abc", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,75 @@
+package trackimport
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oscar/mileagetracker/internal/api/common"
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/service"
+)
+
+// maxTrackBytes caps the size of an uploaded track file
+const maxTrackBytes = 10 << 20
+
+type Handler struct {
+	importService service.TrackImportService
+}
+
+func NewHandler(importService service.TrackImportService) *Handler {
+	return &Handler{
+		importService: importService,
+	}
+}
+
+// ImportTrack creates trips from an uploaded GPX or GeoJSON track
+func (h *Handler) ImportTrack(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxTrackBytes+1<<20)
+
+	var req domain.TrackImportRequest
+	if err := c.ShouldBind(&req); err != nil {
+		common.RespondWithBadRequestError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		common.RespondWithBadRequestError(c, "A track file is required in the 'file' field")
+		return
+	}
+	if fileHeader.Size > maxTrackBytes {
+		common.RespondWithBadRequestError(c, "Track file must be 10 MB or smaller")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		common.RespondWithInternalError(c, err)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		common.RespondWithInternalError(c, err)
+		return
+	}
+
+	result, err := h.importService.ImportTrack(c.Request.Context(), fileHeader.Filename, data, req)
+	if err != nil {
+		if errors.Is(err, service.ErrValidation) {
+			common.RespondWithBadRequestError(c, err.Error())
+			return
+		}
+		common.RespondWithInternalError(c, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if req.DryRun {
+		status = http.StatusOK
+	}
+	c.JSON(status, result)
+}
@@ -0,0 +1,166 @@
+package trackimport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTrackImportService implements the TrackImportService interface for testing
+type MockTrackImportService struct {
+	mock.Mock
+}
+
+func (m *MockTrackImportService) ImportTrack(ctx context.Context, filename string, data []byte, req domain.TrackImportRequest) (*domain.TrackImportResponse, error) {
+	args := m.Called(ctx, filename, data, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.TrackImportResponse), args.Error(1)
+}
+
+func setupTestRouter(importService *MockTrackImportService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	handler := NewHandler(importService)
+
+	api := router.Group("/api/v1")
+	{
+		api.POST("/trips/import", handler.ImportTrack)
+	}
+
+	return router
+}
+
+func newUploadRequest(t *testing.T, fields map[string]string, filename string, content []byte) *http.Request {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for key, value := range fields {
+		assert.NoError(t, writer.WriteField(key, value))
+	}
+	if filename != "" {
+		part, err := writer.CreateFormFile("file", filename)
+		assert.NoError(t, err)
+		_, err = part.Write(content)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, writer.Close())
+
+	req, _ := http.NewRequest("POST", "/api/v1/trips/import", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestTrackImportHandler_ImportTrack(t *testing.T) {
+	track := []byte(`<gpx><trk><trkseg></trkseg></trk></gpx>`)
+
+	t.Run("should import uploaded track", func(t *testing.T) {
+		mockService := new(MockTrackImportService)
+		router := setupTestRouter(mockService)
+
+		minTripMiles := 0.5
+		expectedReq := domain.TrackImportRequest{
+			ClientName:   "Acme Corp",
+			MinTripMiles: &minTripMiles,
+		}
+		result := &domain.TrackImportResponse{
+			PointsRead: 12,
+			Segments:   []domain.ImportedSegment{{Miles: 10, Points: 12}},
+			Trips:      []domain.Trip{{ID: 1, ClientName: "Acme Corp", TripDate: "2025-03-10", Miles: 10}},
+		}
+		mockService.On("ImportTrack", mock.Anything, "drive.gpx", track, expectedReq).Return(result, nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newUploadRequest(t, map[string]string{
+			"client_name":    "Acme Corp",
+			"min_trip_miles": "0.5",
+		}, "drive.gpx", track))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response domain.TrackImportResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 12, response.PointsRead)
+		assert.Len(t, response.Trips, 1)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return 200 for dry run", func(t *testing.T) {
+		mockService := new(MockTrackImportService)
+		router := setupTestRouter(mockService)
+
+		expectedReq := domain.TrackImportRequest{ClientName: "Acme Corp", DryRun: true}
+		mockService.On("ImportTrack", mock.Anything, "drive.gpx", track, expectedReq).
+			Return(&domain.TrackImportResponse{Segments: []domain.ImportedSegment{}, Trips: []domain.Trip{}}, nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newUploadRequest(t, map[string]string{
+			"client_name": "Acme Corp",
+			"dry_run":     "true",
+		}, "drive.gpx", track))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should require a file", func(t *testing.T) {
+		mockService := new(MockTrackImportService)
+		router := setupTestRouter(mockService)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newUploadRequest(t, map[string]string{"client_name": "Acme Corp"}, "", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "ImportTrack", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should require a client name", func(t *testing.T) {
+		mockService := new(MockTrackImportService)
+		router := setupTestRouter(mockService)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newUploadRequest(t, map[string]string{}, "drive.gpx", track))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should map validation errors to 400", func(t *testing.T) {
+		mockService := new(MockTrackImportService)
+		router := setupTestRouter(mockService)
+
+		mockService.On("ImportTrack", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, fmt.Errorf("%w: unsupported track format", service.ErrValidation))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newUploadRequest(t, map[string]string{"client_name": "Acme Corp"}, "notes.txt", []byte("hello")))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should map other errors to 500", func(t *testing.T) {
+		mockService := new(MockTrackImportService)
+		router := setupTestRouter(mockService)
+
+		mockService.On("ImportTrack", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, fmt.Errorf("database error"))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newUploadRequest(t, map[string]string{"client_name": "Acme Corp"}, "drive.gpx", track))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
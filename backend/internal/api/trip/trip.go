@@ -2,6 +2,7 @@ package trip
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
@@ -15,14 +16,36 @@ import (
 
 type Handler struct {
 	tripService service.TripService
+	viewService service.ViewService
 }
 
-func NewHandler(tripService service.TripService) *Handler {
+func NewHandler(tripService service.TripService, viewService service.ViewService) *Handler {
 	return &Handler{
 		tripService: tripService,
+		viewService: viewService,
 	}
 }
 
+// respondWithServiceError maps service errors onto HTTP responses
+func respondWithServiceError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrValidation) {
+		common.RespondWithBadRequestError(c, err.Error())
+		return
+	}
+	common.RespondWithInternalError(c, err)
+}
+
+// sortableFields lists the fields accepted by the sort parameter
+var sortableFields = strings.Join([]string{
+	string(domain.SortByTripDate),
+	string(domain.SortByMiles),
+	string(domain.SortByClientName),
+	string(domain.SortByCreatedAt),
+	string(domain.SortByUpdatedAt),
+	string(domain.SortByAmount),
+	string(domain.SortByStartTime),
+}, ", ")
+
 // parseFilters extracts and validates filter parameters from query string
 func (h *Handler) parseFilters(c *gin.Context) (domain.TripFilters, error) {
 	filters := domain.TripFilters{}
@@ -71,6 +94,84 @@ func (h *Handler) parseFilters(c *gin.Context) (domain.TripFilters, error) {
 		filters.MaxMiles = &maxMiles
 	}
 
+	// Client lists - repeated parameters, since client names may contain commas
+	filters.Clients = queryValues(c, "clients")
+	filters.ExcludeClients = queryValues(c, "exclude_clients")
+
+	// Tag lists - "tags=billable,conference" or repeated parameters
+	filters.Tags = parseTagList(c, "tags")
+	filters.AllTags = parseTagList(c, "all_tags")
+	filters.ExcludeTags = parseTagList(c, "exclude_tags")
+
+	// ID lists - "ids=1,2,3" or repeated parameters
+	var err error
+	if filters.IDs, err = parseIDList(c, "ids"); err != nil {
+		return filters, err
+	}
+	if filters.ExcludeIDs, err = parseIDList(c, "exclude_ids"); err != nil {
+		return filters, err
+	}
+
+	// Notes presence filter
+	if hasNotesStr := c.Query("has_notes"); hasNotesStr != "" {
+		hasNotes, err := strconv.ParseBool(hasNotesStr)
+		if err != nil {
+			return filters, errors.New("has_notes must be true or false")
+		}
+		filters.HasNotes = &hasNotes
+	}
+
+	// Record timestamp bounds - dates are resolved in the business timezone
+	// by the service, so only the format is checked here
+	bounds := []struct {
+		name  string
+		value *string
+	}{
+		{"created_after", &filters.CreatedAfter},
+		{"created_before", &filters.CreatedBefore},
+		{"updated_after", &filters.UpdatedAfter},
+		{"updated_before", &filters.UpdatedBefore},
+	}
+	for _, bound := range bounds {
+		value := strings.TrimSpace(c.Query(bound.name))
+		if value == "" {
+			continue
+		}
+		if _, err := domain.ParseTimeBound(value, time.UTC); err != nil {
+			return filters, fmt.Errorf("%s must be a YYYY-MM-DD date or an RFC 3339 timestamp", bound.name)
+		}
+		*bound.value = value
+	}
+
+	// Distance unit - "unit=km"
+	if unitStr := c.Query("unit"); unitStr != "" {
+		unit, ok := domain.ParseDistanceUnit(unitStr)
+		if !ok {
+			return filters, errors.New("unit must be mi or km")
+		}
+		filters.Unit = unit
+	}
+
+	// Sort keys - "sort=-trip_date,miles" or repeated sort parameters
+	for _, param := range c.QueryArray("sort") {
+		for _, value := range strings.Split(param, ",") {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+			key, err := domain.ParseSortKey(value)
+			if err != nil {
+				return filters, fmt.Errorf("sort must be a comma-separated list of %s, each optionally prefixed with - for descending order", sortableFields)
+			}
+			for _, existing := range filters.Sort {
+				if existing.Field == key.Field {
+					return filters, fmt.Errorf("sort lists %s more than once", key.Field)
+				}
+			}
+			filters.Sort = append(filters.Sort, key)
+		}
+	}
+
 	// Validate that min_miles is not greater than max_miles
 	if filters.MinMiles != nil && filters.MaxMiles != nil && *filters.MinMiles > *filters.MaxMiles {
 		return filters, errors.New("min_miles cannot be greater than max_miles")
@@ -88,6 +189,145 @@ func (h *Handler) parseFilters(c *gin.Context) (domain.TripFilters, error) {
 	return filters, nil
 }
 
+// queryValues returns the trimmed, non-empty values of a repeatable parameter
+func queryValues(c *gin.Context, name string) []string {
+	var values []string
+	for _, value := range c.QueryArray(name) {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// parseTagList returns the lowercased tag names of a parameter holding
+// comma-separated names, which may also be repeated
+func parseTagList(c *gin.Context, name string) []string {
+	var tags []string
+	for _, param := range c.QueryArray(name) {
+		for _, value := range strings.Split(param, ",") {
+			if value = strings.ToLower(strings.TrimSpace(value)); value != "" {
+				tags = append(tags, value)
+			}
+		}
+	}
+	return tags
+}
+
+// parseIDList parses a parameter holding comma-separated trip IDs, which may
+// also be repeated
+func parseIDList(c *gin.Context, name string) ([]uint, error) {
+	var ids []uint
+	for _, param := range c.QueryArray(name) {
+		for _, value := range strings.Split(param, ",") {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+			id, err := strconv.ParseUint(value, 10, 32)
+			if err != nil || id == 0 {
+				return nil, fmt.Errorf("%s must be a comma-separated list of trip IDs", name)
+			}
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids, nil
+}
+
+// applyView starts from the filters of the saved view given by the view
+// parameter, if any, and overrides them with the filters given as query
+// parameters. It responds with an error itself and returns false when the
+// view cannot be applied.
+func (h *Handler) applyView(c *gin.Context, filters domain.TripFilters) (domain.TripFilters, bool) {
+	viewParam := c.Query("view")
+	if viewParam == "" {
+		return filters, true
+	}
+
+	id, err := strconv.ParseUint(viewParam, 10, 32)
+	if err != nil || id == 0 {
+		common.RespondWithBadRequestError(c, "view must be a saved view ID")
+		return filters, false
+	}
+
+	viewFilters, err := h.viewService.ResolveFilters(c.Request.Context(), uint(id))
+	if errors.Is(err, service.ErrNotFound) {
+		common.RespondWithNotFoundError(c, "Saved view")
+		return filters, false
+	}
+	if err != nil {
+		respondWithServiceError(c, err)
+		return filters, false
+	}
+
+	return mergeFilters(viewFilters, filters), true
+}
+
+// mergeFilters returns base with every filter set in override replaced
+func mergeFilters(base, override domain.TripFilters) domain.TripFilters {
+	if override.Search != "" {
+		base.Search = override.Search
+	}
+	if override.Client != "" {
+		base.Client = override.Client
+	}
+	if override.DateFrom != "" {
+		base.DateFrom = override.DateFrom
+	}
+	if override.DateTo != "" {
+		base.DateTo = override.DateTo
+	}
+	if override.MinMiles != nil {
+		base.MinMiles = override.MinMiles
+	}
+	if override.MaxMiles != nil {
+		base.MaxMiles = override.MaxMiles
+	}
+	if len(override.Clients) > 0 {
+		base.Clients = override.Clients
+	}
+	if len(override.ExcludeClients) > 0 {
+		base.ExcludeClients = override.ExcludeClients
+	}
+	if len(override.IDs) > 0 {
+		base.IDs = override.IDs
+	}
+	if len(override.ExcludeIDs) > 0 {
+		base.ExcludeIDs = override.ExcludeIDs
+	}
+	if len(override.Tags) > 0 {
+		base.Tags = override.Tags
+	}
+	if len(override.AllTags) > 0 {
+		base.AllTags = override.AllTags
+	}
+	if len(override.ExcludeTags) > 0 {
+		base.ExcludeTags = override.ExcludeTags
+	}
+	if override.HasNotes != nil {
+		base.HasNotes = override.HasNotes
+	}
+	if override.CreatedAfter != "" {
+		base.CreatedAfter = override.CreatedAfter
+	}
+	if override.CreatedBefore != "" {
+		base.CreatedBefore = override.CreatedBefore
+	}
+	if override.UpdatedAfter != "" {
+		base.UpdatedAfter = override.UpdatedAfter
+	}
+	if override.UpdatedBefore != "" {
+		base.UpdatedBefore = override.UpdatedBefore
+	}
+	if len(override.Sort) > 0 {
+		base.Sort = override.Sort
+	}
+	if override.Unit != "" {
+		base.Unit = override.Unit
+	}
+	return base
+}
+
 // CreateTrip creates a new trip
 func (h *Handler) CreateTrip(c *gin.Context) {
 	var req domain.CreateTripRequest
@@ -98,14 +338,15 @@ func (h *Handler) CreateTrip(c *gin.Context) {
 
 	trip, err := h.tripService.CreateTrip(c.Request.Context(), req)
 	if err != nil {
-		common.RespondWithInternalError(c, err)
+		respondWithServiceError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, trip)
 }
 
-// GetTrips retrieves trips with pagination and filtering
+// GetTrips retrieves trips with filtering, paginated by page and limit or,
+// when a cursor parameter is given, by cursor
 func (h *Handler) GetTrips(c *gin.Context) {
 	page := 1
 	limit := 10
@@ -129,9 +370,32 @@ func (h *Handler) GetTrips(c *gin.Context) {
 		return
 	}
 
+	filters, ok := h.applyView(c, filters)
+	if !ok {
+		return
+	}
+
+	// A cursor parameter, even an empty one, selects cursor pagination
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		if c.Query("page") != "" {
+			common.RespondWithBadRequestError(c, "cursor and page cannot be combined")
+			return
+		}
+
+		tripPage, err := h.tripService.GetTripPage(c.Request.Context(), cursor, limit, filters)
+		if err != nil {
+			respondWithServiceError(c, err)
+			return
+		}
+		tripPage.Filters = filters
+
+		c.JSON(http.StatusOK, tripPage)
+		return
+	}
+
 	trips, total, err := h.tripService.GetTrips(c.Request.Context(), page, limit, filters)
 	if err != nil {
-		common.RespondWithInternalError(c, err)
+		respondWithServiceError(c, err)
 		return
 	}
 
@@ -143,6 +407,7 @@ func (h *Handler) GetTrips(c *gin.Context) {
 		"page":        page,
 		"limit":       limit,
 		"total_pages": totalPages,
+		"filters":     filters,
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -164,7 +429,7 @@ func (h *Handler) UpdateTrip(c *gin.Context) {
 
 	trip, err := h.tripService.UpdateTrip(c.Request.Context(), uint(id), req)
 	if err != nil {
-		common.RespondWithInternalError(c, err)
+		respondWithServiceError(c, err)
 		return
 	}
 
@@ -188,14 +453,84 @@ func (h *Handler) DeleteTrip(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
-// GetSummary retrieves the 6-month summary
+// GetSummary totals trips per day, week, month, quarter or year, optionally
+// broken down by client or location. Without parameters it covers the last
+// 6 months.
 func (h *Handler) GetSummary(c *gin.Context) {
-	summary, err := h.tripService.GetSummary(c.Request.Context())
+	filters, err := h.parseFilters(c)
+	if err != nil {
+		common.RespondWithBadRequestError(c, err.Error())
+		return
+	}
+
+	filters, ok := h.applyView(c, filters)
+	if !ok {
+		return
+	}
+
+	query := domain.SummaryQuery{
+		From:        c.Query("from"),
+		To:          c.Query("to"),
+		Granularity: domain.Granularity(strings.ToLower(c.Query("granularity"))),
+		Filters:     filters,
+		GroupBy:     domain.SummaryDimension(strings.ToLower(c.Query("group_by"))),
+		Currency:    c.Query("currency"),
+		Unit:        filters.Unit,
+	}
+
+	if topStr := c.Query("top"); topStr != "" {
+		top, err := strconv.Atoi(topStr)
+		if err != nil || top < 1 {
+			common.RespondWithBadRequestError(c, "top must be a positive integer")
+			return
+		}
+		query.Top = top
+	}
+
+	summary, err := h.tripService.GetSummary(c.Request.Context(), query)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetDashboard returns the year-to-date figures for the home screen
+func (h *Handler) GetDashboard(c *gin.Context) {
+	dashboard, err := h.tripService.GetDashboard(c.Request.Context())
 	if err != nil {
 		common.RespondWithInternalError(c, err)
 		return
 	}
 
+	c.JSON(http.StatusOK, dashboard)
+}
+
+// GetTaxSummary returns the deductible totals per fiscal year, in the
+// requested currency and unit if any. Without parameters it covers the current
+// fiscal year.
+func (h *Handler) GetTaxSummary(c *gin.Context) {
+	var years [2]int
+	for i, name := range []string{"from_year", "to_year"} {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		year, err := strconv.Atoi(value)
+		if err != nil || year < 1 {
+			common.RespondWithBadRequestError(c, name+" must be a positive integer")
+			return
+		}
+		years[i] = year
+	}
+
+	summary, err := h.tripService.GetTaxSummary(c.Request.Context(), years[0], years[1], c.Query("currency"), c.Query("unit"))
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
 	c.JSON(http.StatusOK, summary)
 }
 
@@ -7,10 +7,12 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -28,6 +30,14 @@ func (m *MockTripService) CreateTrip(ctx context.Context, req domain.CreateTripR
 	return args.Get(0).(*domain.Trip), args.Error(1)
 }
 
+func (m *MockTripService) CreateTrips(ctx context.Context, reqs []domain.CreateTripRequest) ([]domain.Trip, error) {
+	args := m.Called(ctx, reqs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Trip), args.Error(1)
+}
+
 func (m *MockTripService) UpdateTrip(ctx context.Context, id uint, req domain.UpdateTripRequest) (*domain.Trip, error) {
 	args := m.Called(ctx, id, req)
 	if args.Get(0) == nil {
@@ -54,19 +64,59 @@ func (m *MockTripService) GetTrips(ctx context.Context, page, limit int, filters
 	return args.Get(0).([]domain.Trip), args.Get(1).(int64), args.Error(2)
 }
 
-func (m *MockTripService) GetSummary(ctx context.Context) (*domain.SummaryResponse, error) {
-	args := m.Called(ctx)
+func (m *MockTripService) GetTripPage(ctx context.Context, cursor string, limit int, filters domain.TripFilters) (*domain.TripPage, error) {
+	args := m.Called(ctx, cursor, limit, filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.TripPage), args.Error(1)
+}
+
+func (m *MockTripService) GetSummary(ctx context.Context, query domain.SummaryQuery) (*domain.SummaryResponse, error) {
+	args := m.Called(ctx, query)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.SummaryResponse), args.Error(1)
 }
 
+func (m *MockTripService) GetDashboard(ctx context.Context) (*domain.DashboardResponse, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.DashboardResponse), args.Error(1)
+}
+
+func (m *MockTripService) GetTaxSummary(ctx context.Context, fromYear, toYear int, currency, unit string) (*domain.TaxSummaryResponse, error) {
+	args := m.Called(ctx, fromYear, toYear, currency, unit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.TaxSummaryResponse), args.Error(1)
+}
+
+// MockViewService implements the ViewService interface for testing; the
+// trip handler only resolves view filters
+type MockViewService struct {
+	service.ViewService
+	mock.Mock
+}
+
+func (m *MockViewService) ResolveFilters(ctx context.Context, id uint) (domain.TripFilters, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.TripFilters), args.Error(1)
+}
+
 func setupTestRouter(tripService *MockTripService) *gin.Engine {
+	return setupTestRouterWithViews(tripService, new(MockViewService))
+}
+
+func setupTestRouterWithViews(tripService *MockTripService, viewService *MockViewService) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
 
-	handler := NewHandler(tripService)
+	handler := NewHandler(tripService, viewService)
 
 	api := router.Group("/api/v1")
 	{
@@ -76,6 +126,8 @@ func setupTestRouter(tripService *MockTripService) *gin.Engine {
 		api.PUT("/trips/:id", handler.UpdateTrip)
 		api.DELETE("/trips/:id", handler.DeleteTrip)
 		api.GET("/trips/summary", handler.GetSummary)
+		api.GET("/dashboard", handler.GetDashboard)
+		api.GET("/tax-summary", handler.GetTaxSummary)
 	}
 
 	return router
@@ -139,6 +191,18 @@ func TestTripHandler_CreateTrip(t *testing.T) {
 		assert.Contains(t, w.Body.String(), "Invalid request data")
 	})
 
+	t.Run("should return 400 for an expense without a positive amount", func(t *testing.T) {
+		body := `{"client_name":"Acme Corp","trip_date":"2025-01-15","miles":10,"expenses":[{"type":"toll","amount":-4}]}`
+		req, _ := http.NewRequest("POST", "/api/v1/trips", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Amount")
+	})
+
 	t.Run("should handle service error", func(t *testing.T) {
 		// Setup
 		requestBody := domain.CreateTripRequest{
@@ -165,6 +229,62 @@ func TestTripHandler_CreateTrip(t *testing.T) {
 	})
 }
 
+func TestTripHandler_CreateTripFromLocations(t *testing.T) {
+	t.Run("should accept missing miles when both locations are given", func(t *testing.T) {
+		mockService := new(MockTripService)
+		router := setupTestRouter(mockService)
+
+		fromID, toID := uint(1), uint(2)
+		requestBody := domain.CreateTripRequest{
+			ClientName:     "Acme Corp",
+			TripDate:       "2025-01-15",
+			FromLocationID: &fromID,
+			ToLocationID:   &toID,
+		}
+
+		mockService.On("CreateTrip", mock.Anything, requestBody).
+			Return(&domain.Trip{ID: 1, ClientName: "Acme Corp", Miles: 18.4, FromLocationID: &fromID, ToLocationID: &toID}, nil)
+
+		jsonData, _ := json.Marshal(requestBody)
+		req, _ := http.NewRequest("POST", "/api/v1/trips", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should require miles when no locations are given", func(t *testing.T) {
+		router := setupTestRouter(new(MockTripService))
+
+		req, _ := http.NewRequest("POST", "/api/v1/trips",
+			bytes.NewBufferString(`{"client_name":"Acme Corp","trip_date":"2025-01-15"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should return 400 for service validation error", func(t *testing.T) {
+		mockService := new(MockTripService)
+		router := setupTestRouter(mockService)
+
+		mockService.On("CreateTrip", mock.Anything, mock.Anything).
+			Return(nil, fmt.Errorf("%w: location 9 not found", service.ErrValidation))
+
+		req, _ := http.NewRequest("POST", "/api/v1/trips",
+			bytes.NewBufferString(`{"client_name":"Acme Corp","trip_date":"2025-01-15","from_location_id":9,"to_location_id":2}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "location 9 not found")
+	})
+}
+
 func TestTripHandler_GetTrips(t *testing.T) {
 	t.Run("should get trips with default pagination", func(t *testing.T) {
 		// Setup
@@ -235,6 +355,73 @@ func TestTripHandler_GetTrips(t *testing.T) {
 	})
 }
 
+func TestTripHandler_GetTrips_Cursor(t *testing.T) {
+	t.Run("should start cursor pagination with an empty cursor", func(t *testing.T) {
+		mockService := new(MockTripService)
+		router := setupTestRouter(mockService)
+
+		expected := &domain.TripPage{
+			Trips:      []domain.Trip{{ID: 2}, {ID: 1}},
+			Limit:      2,
+			NextCursor: "abc",
+			HasMore:    true,
+		}
+		mockService.On("GetTripPage", mock.Anything, "", 2, domain.TripFilters{}).Return(expected, nil)
+
+		req, _ := http.NewRequest("GET", "/api/v1/trips?cursor=&limit=2", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "abc", response["next_cursor"])
+		assert.Equal(t, true, response["has_more"])
+		assert.NotContains(t, response, "total")
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should pass the cursor and filters", func(t *testing.T) {
+		mockService := new(MockTripService)
+		router := setupTestRouter(mockService)
+
+		mockService.On("GetTripPage", mock.Anything, "abc", 10, domain.TripFilters{Client: "Acme"}).
+			Return(&domain.TripPage{Trips: []domain.Trip{}, Limit: 10}, nil)
+
+		req, _ := http.NewRequest("GET", "/api/v1/trips?cursor=abc&client=Acme", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should reject cursor combined with page", func(t *testing.T) {
+		mockService := new(MockTripService)
+		router := setupTestRouter(mockService)
+
+		req, _ := http.NewRequest("GET", "/api/v1/trips?cursor=abc&page=2", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should return 400 for a malformed cursor", func(t *testing.T) {
+		mockService := new(MockTripService)
+		router := setupTestRouter(mockService)
+
+		mockService.On("GetTripPage", mock.Anything, "bad", 10, domain.TripFilters{}).
+			Return(nil, fmt.Errorf("%w: malformed cursor", service.ErrValidation))
+
+		req, _ := http.NewRequest("GET", "/api/v1/trips?cursor=bad", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
 func TestTripHandler_GetTripByID(t *testing.T) {
 	mockService := new(MockTripService)
 	router := setupTestRouter(mockService)
@@ -406,12 +593,12 @@ func TestTripHandler_GetSummary(t *testing.T) {
 					Year:       2025,
 					MonthNum:   1,
 					TotalMiles: 145.5,
-					Amount:     97.49,
+					Amount:     domain.MustMoney("97.49"),
 				},
 			},
 		}
 
-		mockService.On("GetSummary", mock.Anything).Return(expectedSummary, nil)
+		mockService.On("GetSummary", mock.Anything, domain.SummaryQuery{}).Return(expectedSummary, nil)
 
 		// Execute
 		req, _ := http.NewRequest("GET", "/api/v1/trips/summary", nil)
@@ -435,7 +622,7 @@ func TestTripHandler_GetSummary(t *testing.T) {
 		mockService := new(MockTripService)
 		router := setupTestRouter(mockService)
 
-		mockService.On("GetSummary", mock.Anything).Return(nil, fmt.Errorf("database error"))
+		mockService.On("GetSummary", mock.Anything, domain.SummaryQuery{}).Return(nil, fmt.Errorf("database error"))
 
 		// Execute
 		req, _ := http.NewRequest("GET", "/api/v1/trips/summary", nil)
@@ -447,6 +634,133 @@ func TestTripHandler_GetSummary(t *testing.T) {
 
 		mockService.AssertExpectations(t)
 	})
+
+	t.Run("should pass range, granularity and filters", func(t *testing.T) {
+		mockService := new(MockTripService)
+		router := setupTestRouter(mockService)
+
+		minMiles := 5.0
+		expectedQuery := domain.SummaryQuery{
+			From:        "2025-01-01",
+			To:          "2025-03-31",
+			Granularity: domain.GranularityWeek,
+			Filters:     domain.TripFilters{Client: "Acme Corp", MinMiles: &minMiles},
+			Currency:    "CAD",
+		}
+		mockService.On("GetSummary", mock.Anything, expectedQuery).Return(&domain.SummaryResponse{
+			Granularity: domain.GranularityWeek,
+			Buckets:     []domain.SummaryBucket{{Period: "2025-W01", TotalMiles: 12}},
+		}, nil)
+
+		req, _ := http.NewRequest("GET", "/api/v1/trips/summary?from=2025-01-01&to=2025-03-31&granularity=Week&client=Acme%20Corp&min_miles=5&currency=CAD", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response domain.SummaryResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "2025-W01", response.Buckets[0].Period)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return 400 for invalid summary query", func(t *testing.T) {
+		mockService := new(MockTripService)
+		router := setupTestRouter(mockService)
+
+		mockService.On("GetSummary", mock.Anything, mock.Anything).
+			Return(nil, fmt.Errorf("%w: granularity must be one of day, week, month, quarter or year", service.ErrValidation))
+
+		req, _ := http.NewRequest("GET", "/api/v1/trips/summary?granularity=fortnight", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should pass group_by and top", func(t *testing.T) {
+		mockService := new(MockTripService)
+		router := setupTestRouter(mockService)
+
+		expectedQuery := domain.SummaryQuery{GroupBy: domain.SummaryByClient, Top: 3}
+		mockService.On("GetSummary", mock.Anything, expectedQuery).Return(&domain.SummaryResponse{
+			GroupBy: domain.SummaryByClient,
+			Groups:  []domain.SummaryGroup{{Key: "Acme Corp", Label: "Acme Corp"}},
+		}, nil)
+
+		req, _ := http.NewRequest("GET", "/api/v1/trips/summary?group_by=client&top=3", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return 400 for invalid top", func(t *testing.T) {
+		mockService := new(MockTripService)
+		router := setupTestRouter(mockService)
+
+		req, _ := http.NewRequest("GET", "/api/v1/trips/summary?group_by=client&top=0", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "GetSummary", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return 400 for invalid filters", func(t *testing.T) {
+		mockService := new(MockTripService)
+		router := setupTestRouter(mockService)
+
+		req, _ := http.NewRequest("GET", "/api/v1/trips/summary?min_miles=-1", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "GetSummary", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTripHandler_GetDashboard(t *testing.T) {
+	t.Run("should get dashboard successfully", func(t *testing.T) {
+		mockService := new(MockTripService)
+		router := setupTestRouter(mockService)
+
+		change := 25.0
+		expected := &domain.DashboardResponse{
+			AsOf: "2025-09-15",
+			YearToDate: domain.PeriodComparison{
+				Current:            domain.PeriodTotals{From: "2025-01-01", To: "2025-09-15", SummaryTotals: domain.SummaryTotals{TotalMiles: 500}},
+				MilesChangePercent: &change,
+			},
+			TopClients: []domain.ClientTotals{{ClientName: "Acme Corp", TotalMiles: 300}},
+		}
+		mockService.On("GetDashboard", mock.Anything).Return(expected, nil)
+
+		req, _ := http.NewRequest("GET", "/api/v1/dashboard", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		ytd := response["year_to_date"].(map[string]interface{})
+		assert.Equal(t, 500.0, ytd["current"].(map[string]interface{})["total_miles"])
+		assert.Equal(t, 25.0, ytd["miles_change_percent"])
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should handle service error", func(t *testing.T) {
+		mockService := new(MockTripService)
+		router := setupTestRouter(mockService)
+
+		mockService.On("GetDashboard", mock.Anything).Return(nil, fmt.Errorf("database error"))
+
+		req, _ := http.NewRequest("GET", "/api/v1/dashboard", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
 }
 
 func TestTripHandler_GetTrips_WithFilters(t *testing.T) {
@@ -675,3 +989,324 @@ func TestTripHandler_GetTrips_WithFilters(t *testing.T) {
 		mockService.AssertExpectations(t)
 	})
 }
+
+func TestTripHandler_GetTrips_ListFilters(t *testing.T) {
+	t.Run("should parse list, notes and timestamp filters", func(t *testing.T) {
+		mockService := new(MockTripService)
+		router := setupTestRouter(mockService)
+
+		hasNotes := false
+		expectedFilters := domain.TripFilters{
+			Clients:        []string{"Acme, Inc.", "Beta"},
+			ExcludeClients: []string{"Gamma"},
+			IDs:            []uint{1, 2, 3},
+			ExcludeIDs:     []uint{2},
+			HasNotes:       &hasNotes,
+			CreatedAfter:   "2025-01-01",
+			UpdatedBefore:  "2025-02-01T00:00:00Z",
+		}
+		mockService.On("GetTrips", mock.Anything, 1, 10, expectedFilters).Return([]domain.Trip{}, int64(0), nil)
+
+		query := url.Values{
+			"clients":         {"Acme, Inc.", " Beta "},
+			"exclude_clients": {"Gamma"},
+			"ids":             {"1,2", "3"},
+			"exclude_ids":     {"2"},
+			"has_notes":       {"false"},
+			"created_after":   {"2025-01-01"},
+			"updated_before":  {"2025-02-01T00:00:00Z"},
+		}
+		req, _ := http.NewRequest("GET", "/api/v1/trips?"+query.Encode(), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+
+		var response struct {
+			Filters domain.TripFilters `json:"filters"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, expectedFilters, response.Filters)
+	})
+
+	t.Run("should parse tag filters", func(t *testing.T) {
+		mockService := new(MockTripService)
+		router := setupTestRouter(mockService)
+
+		expectedFilters := domain.TripFilters{
+			Tags:        []string{"billable", "conference"},
+			AllTags:     []string{"site-visit"},
+			ExcludeTags: []string{"personal"},
+		}
+		mockService.On("GetTrips", mock.Anything, 1, 10, expectedFilters).Return([]domain.Trip{}, int64(0), nil)
+
+		req, _ := http.NewRequest("GET", "/api/v1/trips?tags=Billable,&tags=conference&all_tags=site-visit&exclude_tags=personal", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should echo the effective filters of a cursor page", func(t *testing.T) {
+		mockService := new(MockTripService)
+		router := setupTestRouter(mockService)
+
+		expectedFilters := domain.TripFilters{IDs: []uint{4}}
+		mockService.On("GetTripPage", mock.Anything, "", 10, expectedFilters).Return(&domain.TripPage{Trips: []domain.Trip{}, Limit: 10}, nil)
+
+		req, _ := http.NewRequest("GET", "/api/v1/trips?cursor=&ids=4", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"filters":{"ids":[4]}`)
+	})
+
+	t.Run("should reject invalid values", func(t *testing.T) {
+		tests := []struct {
+			query   string
+			message string
+		}{
+			{"ids=1,abc", "ids must be a comma-separated list of trip IDs"},
+			{"exclude_ids=0", "exclude_ids must be a comma-separated list of trip IDs"},
+			{"has_notes=maybe", "has_notes must be true or false"},
+			{"created_before=01/31/2025", "created_before must be a YYYY-MM-DD date or an RFC 3339 timestamp"},
+			{"updated_after=2025-01-01T10:00", "updated_after must be a YYYY-MM-DD date or an RFC 3339 timestamp"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.query, func(t *testing.T) {
+				mockService := new(MockTripService)
+				router := setupTestRouter(mockService)
+
+				req, _ := http.NewRequest("GET", "/api/v1/trips?"+tt.query, nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+				assert.Contains(t, w.Body.String(), tt.message)
+				mockService.AssertNotCalled(t, "GetTrips")
+			})
+		}
+	})
+}
+
+func TestTripHandler_GetTrips_Sort(t *testing.T) {
+	t.Run("should parse multi-key sorts", func(t *testing.T) {
+		mockService := new(MockTripService)
+		router := setupTestRouter(mockService)
+
+		expectedFilters := domain.TripFilters{Sort: []domain.SortKey{
+			{Field: domain.SortByClientName},
+			{Field: domain.SortByMiles, Desc: true},
+			{Field: domain.SortByUpdatedAt, Desc: true},
+		}}
+		mockService.On("GetTrips", mock.Anything, 1, 10, expectedFilters).Return([]domain.Trip{}, int64(0), nil)
+
+		req, _ := http.NewRequest("GET", "/api/v1/trips?sort=client_name,-miles&sort=-updated_at", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should reject fields outside the whitelist", func(t *testing.T) {
+		mockService := new(MockTripService)
+		router := setupTestRouter(mockService)
+
+		req, _ := http.NewRequest("GET", "/api/v1/trips?sort=notes", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "trip_date, miles, client_name")
+		mockService.AssertNotCalled(t, "GetTrips")
+	})
+
+	t.Run("should reject repeated fields", func(t *testing.T) {
+		mockService := new(MockTripService)
+		router := setupTestRouter(mockService)
+
+		req, _ := http.NewRequest("GET", "/api/v1/trips?sort=miles,-miles", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "more than once")
+	})
+}
+
+func TestTripHandler_GetTrips_Unit(t *testing.T) {
+	t.Run("should pass the distance unit", func(t *testing.T) {
+		mockService := new(MockTripService)
+		router := setupTestRouter(mockService)
+
+		expectedFilters := domain.TripFilters{Unit: domain.DistanceKilometers}
+		mockService.On("GetTrips", mock.Anything, 1, 10, expectedFilters).Return([]domain.Trip{}, int64(0), nil)
+
+		req, _ := http.NewRequest("GET", "/api/v1/trips?unit=KM", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should reject an unknown unit", func(t *testing.T) {
+		mockService := new(MockTripService)
+		router := setupTestRouter(mockService)
+
+		req, _ := http.NewRequest("GET", "/api/v1/trips?unit=furlongs", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "unit must be mi or km")
+		mockService.AssertNotCalled(t, "GetTrips")
+	})
+}
+
+func TestTripHandler_GetTaxSummary(t *testing.T) {
+	t.Run("should default to the current fiscal year", func(t *testing.T) {
+		mockService := new(MockTripService)
+		router := setupTestRouter(mockService)
+
+		expected := &domain.TaxSummaryResponse{
+			FiscalYearStartMonth: 7,
+			FiscalYears: []domain.FiscalYearSummary{
+				{FiscalYear: 2026, Label: "FY2026"},
+			},
+		}
+		mockService.On("GetTaxSummary", mock.Anything, 0, 0, "", "").Return(expected, nil)
+
+		req, _ := http.NewRequest("GET", "/api/v1/tax-summary", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response domain.TaxSummaryResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 7, response.FiscalYearStartMonth)
+		assert.Equal(t, "FY2026", response.FiscalYears[0].Label)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should pass the requested years", func(t *testing.T) {
+		mockService := new(MockTripService)
+		router := setupTestRouter(mockService)
+
+		mockService.On("GetTaxSummary", mock.Anything, 2023, 2025, "CAD", "km").Return(&domain.TaxSummaryResponse{}, nil)
+
+		req, _ := http.NewRequest("GET", "/api/v1/tax-summary?from_year=2023&to_year=2025&currency=CAD&unit=km", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should reject a non-numeric year", func(t *testing.T) {
+		mockService := new(MockTripService)
+		router := setupTestRouter(mockService)
+
+		req, _ := http.NewRequest("GET", "/api/v1/tax-summary?from_year=last", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "GetTaxSummary", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return 400 for validation errors", func(t *testing.T) {
+		mockService := new(MockTripService)
+		router := setupTestRouter(mockService)
+
+		mockService.On("GetTaxSummary", mock.Anything, 2025, 2023, "", "").
+			Return(nil, fmt.Errorf("%w: from_year must not be after to_year", service.ErrValidation))
+
+		req, _ := http.NewRequest("GET", "/api/v1/tax-summary?from_year=2025&to_year=2023", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestTripHandler_SavedViews(t *testing.T) {
+	minMiles := 10.0
+	viewFilters := domain.TripFilters{
+		Client:   "Acme Corp",
+		DateFrom: "2025-08-01",
+		DateTo:   "2025-08-31",
+		MinMiles: &minMiles,
+		Sort:     []domain.SortKey{{Field: domain.SortByMiles, Desc: true}},
+	}
+
+	t.Run("should list trips with the view's filters", func(t *testing.T) {
+		mockService := new(MockTripService)
+		viewService := new(MockViewService)
+		router := setupTestRouterWithViews(mockService, viewService)
+
+		viewService.On("ResolveFilters", mock.Anything, uint(3)).Return(viewFilters, nil)
+		mockService.On("GetTrips", mock.Anything, 1, 10, viewFilters).Return([]domain.Trip{}, int64(0), nil)
+
+		req, _ := http.NewRequest("GET", "/api/v1/trips?view=3", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should let query parameters override the view", func(t *testing.T) {
+		mockService := new(MockTripService)
+		viewService := new(MockViewService)
+		router := setupTestRouterWithViews(mockService, viewService)
+
+		expected := viewFilters
+		expected.Client = "Beta Inc"
+		expected.Sort = []domain.SortKey{{Field: domain.SortByTripDate}}
+
+		viewService.On("ResolveFilters", mock.Anything, uint(3)).Return(viewFilters, nil)
+		mockService.On("GetSummary", mock.Anything, mock.MatchedBy(func(query domain.SummaryQuery) bool {
+			return assert.ObjectsAreEqual(expected, query.Filters)
+		})).Return(&domain.SummaryResponse{}, nil)
+
+		req, _ := http.NewRequest("GET", "/api/v1/trips/summary?view=3&client=Beta%20Inc&sort=trip_date", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return 404 for an unknown view", func(t *testing.T) {
+		mockService := new(MockTripService)
+		viewService := new(MockViewService)
+		router := setupTestRouterWithViews(mockService, viewService)
+
+		viewService.On("ResolveFilters", mock.Anything, uint(9)).
+			Return(domain.TripFilters{}, fmt.Errorf("saved view 9 %w", service.ErrNotFound))
+
+		req, _ := http.NewRequest("GET", "/api/v1/trips?view=9", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockService.AssertNotCalled(t, "GetTrips")
+	})
+
+	t.Run("should reject a non-numeric view", func(t *testing.T) {
+		mockService := new(MockTripService)
+		router := setupTestRouter(mockService)
+
+		req, _ := http.NewRequest("GET", "/api/v1/trips?view=weekly", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,124 @@
+package view
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oscar/mileagetracker/internal/api/common"
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/service"
+)
+
+type Handler struct {
+	viewService service.ViewService
+}
+
+func NewHandler(viewService service.ViewService) *Handler {
+	return &Handler{
+		viewService: viewService,
+	}
+}
+
+// respondWithServiceError maps service errors onto HTTP responses
+func respondWithServiceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrValidation):
+		common.RespondWithBadRequestError(c, err.Error())
+	case errors.Is(err, service.ErrNotFound):
+		common.RespondWithNotFoundError(c, "Saved view")
+	default:
+		common.RespondWithInternalError(c, err)
+	}
+}
+
+func parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		common.RespondWithBadRequestError(c, "Invalid view ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// GetViews retrieves all saved views
+func (h *Handler) GetViews(c *gin.Context) {
+	views, err := h.viewService.GetViews(c.Request.Context())
+	if err != nil {
+		common.RespondWithInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"views": views})
+}
+
+// GetViewByID retrieves a specific saved view by ID
+func (h *Handler) GetViewByID(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	view, err := h.viewService.GetView(c.Request.Context(), id)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, view)
+}
+
+// CreateView saves a new named set of filters
+func (h *Handler) CreateView(c *gin.Context) {
+	var req domain.SaveViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondWithBadRequestError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	view, err := h.viewService.CreateView(c.Request.Context(), req)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, view)
+}
+
+// UpdateView replaces a saved view's name and filters
+func (h *Handler) UpdateView(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var req domain.SaveViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.RespondWithBadRequestError(c, "Invalid request data: "+err.Error())
+		return
+	}
+
+	view, err := h.viewService.UpdateView(c.Request.Context(), id, req)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, view)
+}
+
+// DeleteView deletes a saved view
+func (h *Handler) DeleteView(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.viewService.DeleteView(c.Request.Context(), id); err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
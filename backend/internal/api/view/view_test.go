@@ -0,0 +1,181 @@
+package view
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockViewService implements the ViewService interface for testing
+type MockViewService struct {
+	mock.Mock
+}
+
+func (m *MockViewService) GetViews(ctx context.Context) ([]domain.SavedView, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.SavedView), args.Error(1)
+}
+
+func (m *MockViewService) GetView(ctx context.Context, id uint) (*domain.SavedView, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SavedView), args.Error(1)
+}
+
+func (m *MockViewService) CreateView(ctx context.Context, req domain.SaveViewRequest) (*domain.SavedView, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SavedView), args.Error(1)
+}
+
+func (m *MockViewService) UpdateView(ctx context.Context, id uint, req domain.SaveViewRequest) (*domain.SavedView, error) {
+	args := m.Called(ctx, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SavedView), args.Error(1)
+}
+
+func (m *MockViewService) DeleteView(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockViewService) ResolveFilters(ctx context.Context, id uint) (domain.TripFilters, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.TripFilters), args.Error(1)
+}
+
+func setupTestRouter(viewService *MockViewService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	handler := NewHandler(viewService)
+
+	api := router.Group("/api/v1")
+	{
+		api.GET("/views", handler.GetViews)
+		api.POST("/views", handler.CreateView)
+		api.GET("/views/:id", handler.GetViewByID)
+		api.PUT("/views/:id", handler.UpdateView)
+		api.DELETE("/views/:id", handler.DeleteView)
+	}
+
+	return router
+}
+
+func TestViewHandler_CreateView(t *testing.T) {
+	t.Run("should create view successfully", func(t *testing.T) {
+		mockService := new(MockViewService)
+		router := setupTestRouter(mockService)
+
+		requestBody := domain.SaveViewRequest{
+			Name:      "Acme last month",
+			Filters:   domain.TripFilters{Client: "Acme Corp", Sort: []domain.SortKey{{Field: domain.SortByMiles, Desc: true}}},
+			DateRange: domain.DateRangeLastMonth,
+		}
+		mockService.On("CreateView", mock.Anything, requestBody).
+			Return(&domain.SavedView{ID: 1, Name: requestBody.Name, Filters: requestBody.Filters, DateRange: requestBody.DateRange}, nil)
+
+		jsonData, _ := json.Marshal(requestBody)
+		req, _ := http.NewRequest("POST", "/api/v1/views", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"sort":["-miles"]`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return 400 for missing name", func(t *testing.T) {
+		router := setupTestRouter(new(MockViewService))
+
+		req, _ := http.NewRequest("POST", "/api/v1/views", bytes.NewBufferString(`{"filters":{"client":"Acme"}}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should return 400 for service validation error", func(t *testing.T) {
+		mockService := new(MockViewService)
+		router := setupTestRouter(mockService)
+
+		mockService.On("CreateView", mock.Anything, mock.Anything).
+			Return(nil, fmt.Errorf("%w: a view named %q already exists", service.ErrValidation, "Acme"))
+
+		req, _ := http.NewRequest("POST", "/api/v1/views", bytes.NewBufferString(`{"name":"Acme"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "already exists")
+	})
+}
+
+func TestViewHandler_GetViews(t *testing.T) {
+	mockService := new(MockViewService)
+	router := setupTestRouter(mockService)
+
+	mockService.On("GetViews", mock.Anything).Return([]domain.SavedView{{ID: 1, Name: "Acme"}}, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/views", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Views []domain.SavedView `json:"views"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Views, 1)
+}
+
+func TestViewHandler_DeleteView(t *testing.T) {
+	t.Run("should delete view", func(t *testing.T) {
+		mockService := new(MockViewService)
+		router := setupTestRouter(mockService)
+
+		mockService.On("DeleteView", mock.Anything, uint(1)).Return(nil)
+
+		req, _ := http.NewRequest("DELETE", "/api/v1/views/1", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("should return 404 for unknown view", func(t *testing.T) {
+		mockService := new(MockViewService)
+		router := setupTestRouter(mockService)
+
+		mockService.On("DeleteView", mock.Anything, uint(7)).Return(fmt.Errorf("saved view 7 %w", service.ErrNotFound))
+
+		req, _ := http.NewRequest("DELETE", "/api/v1/views/7", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
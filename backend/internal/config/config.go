@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"time"
 )
 
 type Config struct {
@@ -10,15 +11,31 @@ type Config struct {
 	Server   ServerConfig
 	Logger   LoggerConfig
 	App      AppConfig
+	Routing  RoutingConfig
+	Storage  StorageConfig
 }
 
+// DatabaseConfig configures the database. Driver is "postgres" to connect
+// to the PostgreSQL server at Host or "sqlite" to use the SQLite database
+// file at Path.
 type DatabaseConfig struct {
+	Driver   string
+	Path     string
 	Host     string
 	Port     int
 	User     string
 	Password string
 	Name     string
 	SSLMode  string
+	// MigrateOnStart applies pending schema migrations when the server
+	// starts. Disable it to run "migrate up" as a separate deploy step.
+	MigrateOnStart bool
+
+	// Connection pool limits. A lifetime of 0 keeps connections open
+	// indefinitely.
+	MaxOpenConns           int
+	MaxIdleConns           int
+	ConnMaxLifetimeSeconds int
 }
 
 type ServerConfig struct {
@@ -32,17 +49,57 @@ type LoggerConfig struct {
 
 type AppConfig struct {
 	Version string
+	// Timezone is the IANA zone the business operates in. Month windows and
+	// "today" are computed in it.
+	Timezone string
+}
+
+// Location resolves the configured business timezone
+func (c AppConfig) Location() (*time.Location, error) {
+	return time.LoadLocation(c.Timezone)
+}
+
+// RoutingConfig configures the optional routing service used to calculate
+// driving distances. Routing is disabled when OSRMURL is empty.
+type RoutingConfig struct {
+	OSRMURL        string
+	Profile        string
+	TimeoutSeconds int
+}
+
+// StorageConfig configures where trip attachments are kept. Driver is
+// "local" to store them below LocalDir or "s3" to store them in an S3 or
+// S3-compatible bucket.
+type StorageConfig struct {
+	Driver            string
+	LocalDir          string
+	MaxAttachmentMB   int
+	S3Endpoint        string
+	S3Region          string
+	S3Bucket          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	S3ForcePathStyle  bool
+	S3TimeoutSeconds  int
 }
 
 func Load() *Config {
 	return &Config{
 		Database: DatabaseConfig{
+			Driver:   getEnv("DB_DRIVER", "postgres"),
+			Path:     getEnv("DB_PATH", "data/mileagetracker.db"),
 			Host:     getEnv("DB_HOST", "localhost"),
 			Port:     getEnvAsInt("DB_PORT", 5432),
 			User:     getEnv("DB_USER", "postgres"),
 			Password: getEnv("DB_PASSWORD", "postgres"),
 			Name:     getEnv("DB_NAME", "mileagetracker"),
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+
+			MigrateOnStart: getEnvAsBool("DB_MIGRATE_ON_START", true),
+
+			MaxOpenConns:           getEnvAsInt("DB_MAX_OPEN_CONNS", 100),
+			MaxIdleConns:           getEnvAsInt("DB_MAX_IDLE_CONNS", 10),
+			ConnMaxLifetimeSeconds: getEnvAsInt("DB_CONN_MAX_LIFETIME_SECONDS", 1800),
 		},
 		Server: ServerConfig{
 			Port: getEnvAsInt("SERVER_PORT", 8080),
@@ -52,7 +109,25 @@ func Load() *Config {
 			Level: getEnv("LOG_LEVEL", "debug"),
 		},
 		App: AppConfig{
-			Version: getEnv("APP_VERSION", "development"),
+			Version:  getEnv("APP_VERSION", "development"),
+			Timezone: getEnv("APP_TIMEZONE", "UTC"),
+		},
+		Routing: RoutingConfig{
+			OSRMURL:        getEnv("ROUTING_OSRM_URL", ""),
+			Profile:        getEnv("ROUTING_PROFILE", "driving"),
+			TimeoutSeconds: getEnvAsInt("ROUTING_TIMEOUT_SECONDS", 5),
+		},
+		Storage: StorageConfig{
+			Driver:            getEnv("STORAGE_DRIVER", "local"),
+			LocalDir:          getEnv("STORAGE_LOCAL_DIR", "data/attachments"),
+			MaxAttachmentMB:   getEnvAsInt("ATTACHMENT_MAX_MB", 10),
+			S3Endpoint:        getEnv("S3_ENDPOINT", ""),
+			S3Region:          getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:          getEnv("S3_BUCKET", ""),
+			S3AccessKeyID:     getEnv("S3_ACCESS_KEY_ID", ""),
+			S3SecretAccessKey: getEnv("S3_SECRET_ACCESS_KEY", ""),
+			S3ForcePathStyle:  getEnvAsBool("S3_FORCE_PATH_STYLE", false),
+			S3TimeoutSeconds:  getEnvAsInt("S3_TIMEOUT_SECONDS", 30),
 		},
 	}
 }
@@ -71,3 +146,11 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := getEnv(key, "")
+	if value, err := strconv.ParseBool(valueStr); err == nil {
+		return value
+	}
+	return defaultValue
+}
@@ -15,12 +15,18 @@ func TestLoad(t *testing.T) {
 		config := Load()
 
 		// Database defaults
+		assert.Equal(t, "postgres", config.Database.Driver)
+		assert.Equal(t, "data/mileagetracker.db", config.Database.Path)
 		assert.Equal(t, "localhost", config.Database.Host)
 		assert.Equal(t, 5432, config.Database.Port)
 		assert.Equal(t, "postgres", config.Database.User)
 		assert.Equal(t, "postgres", config.Database.Password)
 		assert.Equal(t, "mileagetracker", config.Database.Name)
 		assert.Equal(t, "disable", config.Database.SSLMode)
+		assert.True(t, config.Database.MigrateOnStart)
+		assert.Equal(t, 100, config.Database.MaxOpenConns)
+		assert.Equal(t, 10, config.Database.MaxIdleConns)
+		assert.Equal(t, 1800, config.Database.ConnMaxLifetimeSeconds)
 
 		// Server defaults
 		assert.Equal(t, 8080, config.Server.Port)
@@ -28,29 +34,66 @@ func TestLoad(t *testing.T) {
 
 		// Logger defaults
 		assert.Equal(t, "debug", config.Logger.Level)
+
+		// Routing defaults (disabled)
+		assert.Equal(t, "", config.Routing.OSRMURL)
+		assert.Equal(t, "driving", config.Routing.Profile)
+		assert.Equal(t, 5, config.Routing.TimeoutSeconds)
+
+		// App defaults
+		assert.Equal(t, "UTC", config.App.Timezone)
+
+		// Storage defaults
+		assert.Equal(t, "local", config.Storage.Driver)
+		assert.Equal(t, "data/attachments", config.Storage.LocalDir)
+		assert.Equal(t, 10, config.Storage.MaxAttachmentMB)
+		assert.Equal(t, "us-east-1", config.Storage.S3Region)
+		assert.False(t, config.Storage.S3ForcePathStyle)
+		assert.Equal(t, 30, config.Storage.S3TimeoutSeconds)
 	})
 
 	t.Run("should load with environment variables", func(t *testing.T) {
 		// Set environment variables
+		os.Setenv("DB_DRIVER", "sqlite")
+		os.Setenv("DB_PATH", "/var/lib/mileagetracker/test.db")
 		os.Setenv("DB_HOST", "test-host")
 		os.Setenv("DB_PORT", "3306")
 		os.Setenv("DB_USER", "test-user")
 		os.Setenv("DB_PASSWORD", "test-pass")
 		os.Setenv("DB_NAME", "test-db")
 		os.Setenv("DB_SSLMODE", "require")
+		os.Setenv("DB_MIGRATE_ON_START", "false")
+		os.Setenv("DB_MAX_OPEN_CONNS", "20")
+		os.Setenv("DB_MAX_IDLE_CONNS", "5")
+		os.Setenv("DB_CONN_MAX_LIFETIME_SECONDS", "300")
 		os.Setenv("SERVER_PORT", "9000")
 		os.Setenv("GIN_MODE", "release")
 		os.Setenv("LOG_LEVEL", "info")
+		os.Setenv("ROUTING_OSRM_URL", "http://osrm:5000")
+		os.Setenv("ROUTING_TIMEOUT_SECONDS", "2")
+		os.Setenv("APP_TIMEZONE", "America/Chicago")
+		os.Setenv("STORAGE_DRIVER", "s3")
+		os.Setenv("ATTACHMENT_MAX_MB", "25")
+		os.Setenv("S3_ENDPOINT", "http://minio:9000")
+		os.Setenv("S3_BUCKET", "attachments")
+		os.Setenv("S3_FORCE_PATH_STYLE", "true")
+		os.Setenv("S3_TIMEOUT_SECONDS", "60")
 
 		config := Load()
 
 		// Database from env
+		assert.Equal(t, "sqlite", config.Database.Driver)
+		assert.Equal(t, "/var/lib/mileagetracker/test.db", config.Database.Path)
 		assert.Equal(t, "test-host", config.Database.Host)
 		assert.Equal(t, 3306, config.Database.Port)
 		assert.Equal(t, "test-user", config.Database.User)
 		assert.Equal(t, "test-pass", config.Database.Password)
 		assert.Equal(t, "test-db", config.Database.Name)
 		assert.Equal(t, "require", config.Database.SSLMode)
+		assert.False(t, config.Database.MigrateOnStart)
+		assert.Equal(t, 20, config.Database.MaxOpenConns)
+		assert.Equal(t, 5, config.Database.MaxIdleConns)
+		assert.Equal(t, 300, config.Database.ConnMaxLifetimeSeconds)
 
 		// Server from env
 		assert.Equal(t, 9000, config.Server.Port)
@@ -59,11 +102,41 @@ func TestLoad(t *testing.T) {
 		// Logger from env
 		assert.Equal(t, "info", config.Logger.Level)
 
+		// Routing from env
+		assert.Equal(t, "http://osrm:5000", config.Routing.OSRMURL)
+		assert.Equal(t, 2, config.Routing.TimeoutSeconds)
+
+		// App from env
+		assert.Equal(t, "America/Chicago", config.App.Timezone)
+
+		// Storage from env
+		assert.Equal(t, "s3", config.Storage.Driver)
+		assert.Equal(t, 25, config.Storage.MaxAttachmentMB)
+		assert.Equal(t, "http://minio:9000", config.Storage.S3Endpoint)
+		assert.Equal(t, "attachments", config.Storage.S3Bucket)
+		assert.True(t, config.Storage.S3ForcePathStyle)
+		assert.Equal(t, 60, config.Storage.S3TimeoutSeconds)
+
 		// Clean up
 		clearEnvVars()
 	})
 }
 
+func TestAppConfig_Location(t *testing.T) {
+	t.Run("should resolve IANA zone names", func(t *testing.T) {
+		location, err := AppConfig{Timezone: "America/Chicago"}.Location()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "America/Chicago", location.String())
+	})
+
+	t.Run("should reject unknown zones", func(t *testing.T) {
+		_, err := AppConfig{Timezone: "Mars/Olympus_Mons"}.Location()
+
+		assert.Error(t, err)
+	})
+}
+
 func TestGetEnv(t *testing.T) {
 	t.Run("should return environment variable value", func(t *testing.T) {
 		os.Setenv("TEST_KEY", "test-value")
@@ -147,11 +220,44 @@ func TestGetEnvAsInt(t *testing.T) {
 	})
 }
 
+func TestGetEnvAsBool(t *testing.T) {
+	t.Run("should return parsed boolean from environment variable", func(t *testing.T) {
+		os.Setenv("TEST_BOOL", "true")
+
+		result := getEnvAsBool("TEST_BOOL", false)
+
+		assert.True(t, result)
+		os.Unsetenv("TEST_BOOL")
+	})
+
+	t.Run("should return default when environment variable is not set", func(t *testing.T) {
+		os.Unsetenv("TEST_BOOL")
+
+		result := getEnvAsBool("TEST_BOOL", true)
+
+		assert.True(t, result)
+	})
+
+	t.Run("should return default when environment variable is not a valid boolean", func(t *testing.T) {
+		os.Setenv("TEST_BOOL", "maybe")
+
+		result := getEnvAsBool("TEST_BOOL", false)
+
+		assert.False(t, result)
+		os.Unsetenv("TEST_BOOL")
+	})
+}
+
 // Helper function to clear environment variables used in tests
 func clearEnvVars() {
 	envVars := []string{
-		"DB_HOST", "DB_PORT", "DB_USER", "DB_PASSWORD", "DB_NAME", "DB_SSLMODE",
+		"DB_DRIVER", "DB_PATH", "DB_HOST", "DB_PORT", "DB_USER", "DB_PASSWORD", "DB_NAME", "DB_SSLMODE", "DB_MIGRATE_ON_START",
+		"DB_MAX_OPEN_CONNS", "DB_MAX_IDLE_CONNS", "DB_CONN_MAX_LIFETIME_SECONDS",
 		"SERVER_PORT", "GIN_MODE", "LOG_LEVEL",
+		"ROUTING_OSRM_URL", "ROUTING_PROFILE", "ROUTING_TIMEOUT_SECONDS",
+		"APP_TIMEZONE",
+		"STORAGE_DRIVER", "STORAGE_LOCAL_DIR", "ATTACHMENT_MAX_MB",
+		"S3_ENDPOINT", "S3_REGION", "S3_BUCKET", "S3_ACCESS_KEY_ID", "S3_SECRET_ACCESS_KEY", "S3_FORCE_PATH_STYLE", "S3_TIMEOUT_SECONDS",
 	}
 
 	for _, key := range envVars {
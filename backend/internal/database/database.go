@@ -1,53 +1,153 @@
 package database
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"time"
 
 	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 
 	"github.com/oscar/mileagetracker/internal/config"
 )
 
-var DB *gorm.DB
+// Supported database drivers
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
 
-func Init(cfg *config.DatabaseConfig) error {
-	dsn := fmt.Sprintf(
-		"host=%s user=%s password=%s dbname=%s port=%d sslmode=%s",
-		cfg.Host, cfg.User, cfg.Password, cfg.Name, cfg.Port, cfg.SSLMode,
-	)
+// sqliteBusyTimeout is how long a SQLite connection waits for another
+// connection's write lock before failing with "database is locked"
+const sqliteBusyTimeout = 5 * time.Second
+
+// pingTimeout bounds the connectivity check made when opening the database
+const pingTimeout = 5 * time.Second
+
+// Store is an open database: a GORM handle over the connection pool, shared
+// by the repositories
+type Store struct {
+	db *gorm.DB
+}
+
+// Open connects to the configured database, applies the connection pool
+// settings and checks the database answers
+func Open(cfg *config.DatabaseConfig) (*Store, error) {
+	dialector, err := openDialector(cfg)
+	if err != nil {
+		return nil, err
+	}
 
-	var err error
-	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger:  logger.Default.LogMode(logger.Info),
+		NowFunc: NowUTC,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to connect to database: %w", err)
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	sqlDB, err := DB.DB()
+	sqlDB, err := db.DB()
 	if err != nil {
-		return fmt.Errorf("failed to get underlying sql.DB: %w", err)
+		return nil, fmt.Errorf("failed to get underlying sql.DB: %w", err)
 	}
 
-	sqlDB.SetMaxIdleConns(10)
-	sqlDB.SetMaxOpenConns(100)
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetimeSeconds) * time.Second)
+
+	store := NewStore(db)
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+	if err := store.Ping(ctx); err != nil {
+		store.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return store, nil
+}
+
+// NowUTC is the GORM clock for created_at and updated_at. SQLite keeps
+// times as text carrying the writer's offset and compares them as strings,
+// so every time stored must be in UTC to compare as an instant.
+func NowUTC() time.Time {
+	return time.Now().UTC()
+}
+
+// NewStore wraps an already opened GORM handle, e.g. a test database
+func NewStore(db *gorm.DB) *Store {
+	return &Store{db: db}
+}
+
+// DB returns the GORM handle for building queries
+func (s *Store) DB() *gorm.DB {
+	return s.db
+}
+
+// Dialect names the database dialect, "postgres" or "sqlite"
+func (s *Store) Dialect() string {
+	return s.db.Dialector.Name()
+}
 
-	if err := sqlDB.Ping(); err != nil {
-		return fmt.Errorf("failed to ping database: %w", err)
+// Ping checks that the database can be reached
+func (s *Store) Ping(ctx context.Context) error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
 	}
+	return sqlDB.PingContext(ctx)
+}
+
+// Transaction runs fn in a transaction bound to ctx. The transaction is
+// committed when fn returns nil and rolled back when it returns an error or
+// panics.
+func (s *Store) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return s.db.WithContext(ctx).Transaction(fn)
+}
 
-	return nil
+// Close closes the connection pool
+func (s *Store) Close() error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
 }
 
-func Close() error {
-	if DB != nil {
-		sqlDB, err := DB.DB()
-		if err != nil {
-			return err
+// openDialector selects the GORM dialector for the configured driver. An
+// empty driver means PostgreSQL.
+func openDialector(cfg *config.DatabaseConfig) (gorm.Dialector, error) {
+	switch cfg.Driver {
+	case DriverPostgres, "":
+		dsn := fmt.Sprintf(
+			"host=%s user=%s password=%s dbname=%s port=%d sslmode=%s",
+			cfg.Host, cfg.User, cfg.Password, cfg.Name, cfg.Port, cfg.SSLMode,
+		)
+		return postgres.Open(dsn), nil
+
+	case DriverSQLite:
+		if cfg.Path == "" {
+			return nil, fmt.Errorf("sqlite database path is required")
+		}
+		if err := os.MkdirAll(filepath.Dir(cfg.Path), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create sqlite database directory: %w", err)
 		}
-		return sqlDB.Close()
+		return sqlite.Open(sqliteDSN(cfg.Path)), nil
 	}
-	return nil
+
+	return nil, fmt.Errorf("unsupported database driver %q", cfg.Driver)
+}
+
+// sqliteDSN opens the database file in WAL mode, so readers don't block the
+// writer, with foreign keys enforced. Transactions take the write lock when
+// they begin rather than on their first write, so concurrent writers wait out
+// the busy timeout instead of failing mid-transaction.
+func sqliteDSN(path string) string {
+	return fmt.Sprintf(
+		"file:%s?_journal_mode=WAL&_busy_timeout=%d&_foreign_keys=on&_txlock=immediate",
+		path, sqliteBusyTimeout.Milliseconds(),
+	)
 }
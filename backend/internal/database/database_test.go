@@ -1,21 +1,48 @@
 package database
 
 import (
+	"context"
+	"errors"
+	"path/filepath"
 	"testing"
 
 	"github.com/oscar/mileagetracker/internal/config"
 	"github.com/stretchr/testify/assert"
-	"gorm.io/driver/sqlite"
+	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
-func TestInit(t *testing.T) {
-	// Save original DB to restore later
-	originalDB := DB
+// openTestStore opens a SQLite store with a single table to write to
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
 
+	store, err := Open(&config.DatabaseConfig{
+		Driver:       DriverSQLite,
+		Path:         filepath.Join(t.TempDir(), "mileagetracker.db"),
+		MaxOpenConns: 4,
+		MaxIdleConns: 2,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		store.Close()
+	})
+
+	require.NoError(t, store.DB().Exec("CREATE TABLE notes (id INTEGER PRIMARY KEY, body TEXT NOT NULL)").Error)
+	return store
+}
+
+func countNotes(t *testing.T, store *Store) int64 {
+	t.Helper()
+
+	var count int64
+	require.NoError(t, store.DB().Table("notes").Count(&count).Error)
+	return count
+}
+
+func TestOpen(t *testing.T) {
 	t.Run("should fail with invalid database config", func(t *testing.T) {
 		cfg := &config.DatabaseConfig{
+			Driver:   DriverPostgres,
 			Host:     "invalid-host",
 			Port:     9999,
 			User:     "invalid-user",
@@ -24,128 +51,127 @@ func TestInit(t *testing.T) {
 			SSLMode:  "disable",
 		}
 
-		err := Init(cfg)
+		store, err := Open(cfg)
 
+		assert.Nil(t, store)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to connect to database")
 	})
 
-	t.Run("should build correct DSN from config", func(t *testing.T) {
-		cfg := &config.DatabaseConfig{
-			Host:     "test-host",
-			Port:     5432,
-			User:     "test-user",
-			Password: "test-pass",
-			Name:     "test-db",
-			SSLMode:  "require",
-		}
-
-		// We can't actually connect but we can test the DSN construction
-		// This test documents the expected DSN format
-		expectedDSN := "host=test-host user=test-user password=test-pass dbname=test-db port=5432 sslmode=require"
-
-		// Test the DSN construction logic by calling Init with invalid config
-		// and checking the error message contains our expected format
-		err := Init(cfg)
-		assert.Error(t, err) // Should fail to connect
-
-		t.Logf("Expected DSN format: %s", expectedDSN)
-		t.Logf("Error (expected): %v", err)
+	t.Run("should open a SQLite database file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "data", "mileagetracker.db")
+
+		store, err := Open(&config.DatabaseConfig{Driver: DriverSQLite, Path: path, MaxOpenConns: 8, MaxIdleConns: 2})
+		require.NoError(t, err)
+		defer store.Close()
+
+		assert.FileExists(t, path)
+		assert.Equal(t, DriverSQLite, store.Dialect())
+		var journalMode string
+		var busyTimeout, foreignKeys int
+		require.NoError(t, store.DB().Raw("PRAGMA journal_mode").Scan(&journalMode).Error)
+		require.NoError(t, store.DB().Raw("PRAGMA busy_timeout").Scan(&busyTimeout).Error)
+		require.NoError(t, store.DB().Raw("PRAGMA foreign_keys").Scan(&foreignKeys).Error)
+		assert.Equal(t, "wal", journalMode)
+		assert.Equal(t, int(sqliteBusyTimeout.Milliseconds()), busyTimeout)
+		assert.Equal(t, 1, foreignKeys)
 	})
 
-	t.Run("should initialize with SQLite for unit testing", func(t *testing.T) {
-		// Create an in-memory SQLite database for testing
-		// This demonstrates that the database initialization logic works
-		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
-			Logger: logger.Default.LogMode(logger.Silent),
+	t.Run("should apply the connection pool settings", func(t *testing.T) {
+		store, err := Open(&config.DatabaseConfig{
+			Driver:                 DriverSQLite,
+			Path:                   filepath.Join(t.TempDir(), "mileagetracker.db"),
+			MaxOpenConns:           3,
+			MaxIdleConns:           1,
+			ConnMaxLifetimeSeconds: 60,
 		})
-		assert.NoError(t, err)
+		require.NoError(t, err)
+		defer store.Close()
 
-		// Set the global DB variable to simulate successful Init
-		DB = db
-
-		// Test that we can get the underlying sql.DB
-		sqlDB, err := DB.DB()
-		assert.NoError(t, err)
-		assert.NotNil(t, sqlDB)
-
-		// Test ping
-		err = sqlDB.Ping()
-		assert.NoError(t, err)
+		sqlDB, err := store.DB().DB()
+		require.NoError(t, err)
+		assert.Equal(t, 3, sqlDB.Stats().MaxOpenConnections)
+	})
 
-		// Test connection pool settings (these would normally be set in Init)
-		sqlDB.SetMaxIdleConns(10)
-		sqlDB.SetMaxOpenConns(100)
+	t.Run("should require a SQLite database path", func(t *testing.T) {
+		_, err := Open(&config.DatabaseConfig{Driver: DriverSQLite})
 
-		// SQLite may not report connection pool stats the same way as PostgreSQL
-		stats := sqlDB.Stats()
-		t.Logf("Database stats: MaxIdleClosed=%d", stats.MaxIdleClosed)
+		assert.EqualError(t, err, "sqlite database path is required")
 	})
 
-	// Restore original DB
-	t.Cleanup(func() {
-		DB = originalDB
+	t.Run("should fail for an unsupported driver", func(t *testing.T) {
+		_, err := Open(&config.DatabaseConfig{Driver: "mysql"})
+
+		assert.EqualError(t, err, `unsupported database driver "mysql"`)
 	})
 }
 
-func TestClose(t *testing.T) {
-	t.Run("should handle nil DB gracefully", func(t *testing.T) {
-		originalDB := DB
-		DB = nil
+func TestStore_Ping(t *testing.T) {
+	t.Run("should reach an open database", func(t *testing.T) {
+		store := openTestStore(t)
 
-		err := Close()
+		assert.NoError(t, store.Ping(context.Background()))
+	})
 
-		assert.NoError(t, err)
+	t.Run("should fail once closed", func(t *testing.T) {
+		store := openTestStore(t)
+		require.NoError(t, store.Close())
 
-		// Restore
-		DB = originalDB
+		assert.Error(t, store.Ping(context.Background()))
 	})
+}
 
-	t.Run("should close valid database connection", func(t *testing.T) {
-		// Create test database
-		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
-			Logger: logger.Default.LogMode(logger.Silent),
-		})
-		assert.NoError(t, err)
+func TestStore_Transaction(t *testing.T) {
+	ctx := context.Background()
 
-		originalDB := DB
-		DB = db
+	t.Run("should commit when the function succeeds", func(t *testing.T) {
+		store := openTestStore(t)
 
-		// Should close without error
-		err = Close()
-		assert.NoError(t, err)
+		err := store.Transaction(ctx, func(tx *gorm.DB) error {
+			return tx.Exec("INSERT INTO notes (body) VALUES ('kept')").Error
+		})
 
-		// Restore
-		DB = originalDB
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), countNotes(t, store))
 	})
-}
 
-func TestDatabaseConnectionPool(t *testing.T) {
-	t.Run("should document connection pool configuration", func(t *testing.T) {
-		// This test documents the expected connection pool settings
-		// In a real PostgreSQL connection, these would be set in Init()
+	t.Run("should roll back and return the function's error", func(t *testing.T) {
+		store := openTestStore(t)
+		failure := errors.New("failed")
 
-		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
-			Logger: logger.Default.LogMode(logger.Silent),
+		err := store.Transaction(ctx, func(tx *gorm.DB) error {
+			if err := tx.Exec("INSERT INTO notes (body) VALUES ('discarded')").Error; err != nil {
+				return err
+			}
+			return failure
 		})
-		assert.NoError(t, err)
 
-		sqlDB, err := db.DB()
-		assert.NoError(t, err)
+		assert.ErrorIs(t, err, failure)
+		assert.Equal(t, int64(0), countNotes(t, store))
+	})
 
-		// Apply the same settings as Init() function
-		sqlDB.SetMaxIdleConns(10)
-		sqlDB.SetMaxOpenConns(100)
+	t.Run("should roll back when the function panics", func(t *testing.T) {
+		store := openTestStore(t)
 
-		// Verify settings are applied
-		stats := sqlDB.Stats()
-		t.Logf("Max idle connections: %d", stats.MaxIdleClosed)
-		t.Logf("Max open connections: %d", stats.MaxOpenConnections)
+		assert.Panics(t, func() {
+			_ = store.Transaction(ctx, func(tx *gorm.DB) error {
+				tx.Exec("INSERT INTO notes (body) VALUES ('discarded')")
+				panic("boom")
+			})
+		})
+		assert.Equal(t, int64(0), countNotes(t, store))
+	})
 
-		// These values should match the Init() function settings
-		// Note: SQLite may not report the same stats as PostgreSQL
-		t.Logf("Connection pool settings applied successfully")
+	t.Run("should not start after the context is done", func(t *testing.T) {
+		store := openTestStore(t)
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		err := store.Transaction(cancelled, func(tx *gorm.DB) error {
+			return tx.Exec("INSERT INTO notes (body) VALUES ('discarded')").Error
+		})
 
-		sqlDB.Close()
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, int64(0), countNotes(t, store))
 	})
 }
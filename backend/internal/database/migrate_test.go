@@ -0,0 +1,428 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/oscar/mileagetracker/migrations"
+)
+
+func testMigrations() fstest.MapFS {
+	return fstest.MapFS{
+		"001_create_clients.up.sql":   {Data: []byte("CREATE TABLE clients (id INTEGER PRIMARY KEY, name TEXT NOT NULL);")},
+		"001_create_clients.down.sql": {Data: []byte("DROP TABLE clients;")},
+		"002_create_trips.up.sql": {Data: []byte(`
+			CREATE TABLE trips (id INTEGER PRIMARY KEY, client_id INTEGER);
+			CREATE INDEX idx_trips_client_id ON trips(client_id);`)},
+		"002_create_trips.down.sql":   {Data: []byte("DROP TABLE trips;")},
+		"003_add_trip_notes.up.sql":   {Data: []byte("ALTER TABLE trips ADD COLUMN notes TEXT;")},
+		"003_add_trip_notes.down.sql": {Data: []byte("ALTER TABLE trips DROP COLUMN notes;")},
+		"README.md":                   {Data: []byte("not a migration")},
+	}
+}
+
+func setupMigrationDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	// A file database, since every pooled connection to :memory: would see
+	// its own empty database
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "migrate.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, err := db.DB()
+		if err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func appliedVersions(t *testing.T, m *Migrator) []uint {
+	t.Helper()
+
+	statuses, err := m.Status(context.Background())
+	require.NoError(t, err)
+
+	versions := []uint{}
+	for _, status := range statuses {
+		if status.AppliedAt != nil {
+			versions = append(versions, status.Version)
+		}
+	}
+	return versions
+}
+
+func migrationNames(migrations []Migration) []string {
+	names := []string{}
+	for _, migration := range migrations {
+		names = append(names, migration.Name)
+	}
+	return names
+}
+
+func TestLoadMigrations(t *testing.T) {
+	t.Run("should pair up and down scripts in version order", func(t *testing.T) {
+		loaded, err := LoadMigrations(testMigrations())
+
+		require.NoError(t, err)
+		require.Len(t, loaded, 3)
+		assert.Equal(t, []string{"create_clients", "create_trips", "add_trip_notes"}, migrationNames(loaded))
+		assert.Equal(t, uint(2), loaded[1].Version)
+		assert.Contains(t, loaded[1].Up, "CREATE TABLE trips")
+		assert.Equal(t, "DROP TABLE trips;", loaded[1].Down)
+	})
+
+	t.Run("should reject a version without an up script", func(t *testing.T) {
+		fsys := testMigrations()
+		delete(fsys, "003_add_trip_notes.up.sql")
+
+		_, err := LoadMigrations(fsys)
+
+		assert.ErrorContains(t, err, "003_add_trip_notes has no up script")
+	})
+
+	t.Run("should reject two migrations with the same version", func(t *testing.T) {
+		fsys := testMigrations()
+		fsys["003_add_trip_times.up.sql"] = &fstest.MapFile{Data: []byte("SELECT 1;")}
+
+		_, err := LoadMigrations(fsys)
+
+		assert.ErrorContains(t, err, "migration version 3 is used by both")
+	})
+
+	t.Run("should load the same versions for every dialect", func(t *testing.T) {
+		var names []string
+		for _, dialect := range migrations.Dialects {
+			files, err := migrations.For(dialect)
+			require.NoError(t, err)
+			loaded, err := LoadMigrations(files)
+			require.NoError(t, err)
+			require.NotEmpty(t, loaded)
+
+			for i, migration := range loaded {
+				assert.Equal(t, uint(i+1), migration.Version, "%s migration versions should have no gaps", dialect)
+				assert.NotEmpty(t, migration.Down, "%s migration %03d_%s should have a down script", dialect, migration.Version, migration.Name)
+			}
+			if names == nil {
+				names = migrationNames(loaded)
+			} else {
+				assert.Equal(t, names, migrationNames(loaded), "%s migrations should match the other dialects", dialect)
+			}
+		}
+	})
+}
+
+func TestSQLiteMigrations(t *testing.T) {
+	ctx := context.Background()
+	db, err := gorm.Open(sqlite.Open(sqliteDSN(filepath.Join(t.TempDir(), "mileagetracker.db"))), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	files, err := migrations.For(DriverSQLite)
+	require.NoError(t, err)
+	m, err := NewMigrator(db, files)
+	require.NoError(t, err)
+
+	t.Run("should apply every migration", func(t *testing.T) {
+		_, err := m.Up(ctx)
+
+		require.NoError(t, err)
+		for _, table := range []string{"clients", "trips", "settings", "locations", "location_distances", "route_distance_cache", "saved_views", "tags", "trip_tags", "attachments", "expenses", "exchange_rates"} {
+			assert.True(t, db.Migrator().HasTable(table), "table %s should exist", table)
+		}
+		assert.True(t, db.Migrator().HasColumn("trips", "start_time"))
+		assert.True(t, db.Migrator().HasColumn("trips", "from_location_id"))
+		assert.True(t, db.Migrator().HasColumn("clients", "currency"))
+	})
+
+	t.Run("should revert every migration", func(t *testing.T) {
+		require.NoError(t, db.Exec("INSERT INTO clients (name) VALUES ('Acme')").Error)
+		require.NoError(t, db.Exec("INSERT INTO trips (client_name, trip_date, miles) VALUES ('Acme', '2024-01-15', 12.5)").Error)
+
+		// Stop before the trips table goes to check its rows survive
+		_, err := m.Goto(ctx, 2)
+		require.NoError(t, err)
+		var count int64
+		require.NoError(t, db.Table("trips").Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+		assert.False(t, db.Migrator().HasColumn("trips", "from_location_id"))
+
+		_, err = m.Goto(ctx, 0)
+
+		require.NoError(t, err)
+		assert.False(t, db.Migrator().HasTable("clients"))
+		assert.False(t, db.Migrator().HasTable("trips"))
+	})
+
+	t.Run("should apply every migration again", func(t *testing.T) {
+		_, err := m.Up(ctx)
+
+		require.NoError(t, err)
+		assert.True(t, db.Migrator().HasTable("exchange_rates"))
+	})
+}
+
+func TestSQLiteMigrations_TimesInUTC(t *testing.T) {
+	ctx := context.Background()
+	db := setupMigrationDB(t)
+	files, err := migrations.For(DriverSQLite)
+	require.NoError(t, err)
+	m, err := NewMigrator(db, files)
+	require.NoError(t, err)
+	_, err = m.Goto(ctx, 19)
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`INSERT INTO trips (client_name, trip_date, miles, created_at, updated_at, start_time)
+		VALUES ('Acme', '2024-01-15', 12.5, '2024-01-15 22:27:25.89-04:00', '2024-01-16 08:00:00+05:30', NULL)`).Error)
+
+	_, err = m.Up(ctx)
+	require.NoError(t, err)
+
+	var row struct {
+		CreatedAt string
+		UpdatedAt string
+		StartTime *string
+	}
+	require.NoError(t, db.Raw("SELECT CAST(created_at AS TEXT) AS created_at, CAST(updated_at AS TEXT) AS updated_at, start_time FROM trips").Scan(&row).Error)
+	assert.Equal(t, "2024-01-16 02:27:25.890+00:00", row.CreatedAt)
+	assert.Equal(t, "2024-01-16 02:30:00.000+00:00", row.UpdatedAt)
+	assert.Nil(t, row.StartTime)
+}
+
+func TestMigrator_Up(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should apply pending migrations once", func(t *testing.T) {
+		db := setupMigrationDB(t)
+		m, err := NewMigrator(db, testMigrations())
+		require.NoError(t, err)
+
+		applied, err := m.Up(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"create_clients", "create_trips", "add_trip_notes"}, migrationNames(applied))
+		assert.True(t, db.Migrator().HasColumn("trips", "notes"))
+		assert.True(t, db.Migrator().HasIndex("trips", "idx_trips_client_id"))
+		assert.Equal(t, []uint{1, 2, 3}, appliedVersions(t, m))
+
+		applied, err = m.Up(ctx)
+
+		require.NoError(t, err)
+		assert.Empty(t, applied)
+	})
+
+	t.Run("should only apply migrations added since the last run", func(t *testing.T) {
+		db := setupMigrationDB(t)
+		fsys := testMigrations()
+		delete(fsys, "003_add_trip_notes.up.sql")
+		delete(fsys, "003_add_trip_notes.down.sql")
+		m, err := NewMigrator(db, fsys)
+		require.NoError(t, err)
+		_, err = m.Up(ctx)
+		require.NoError(t, err)
+
+		m, err = NewMigrator(db, testMigrations())
+		require.NoError(t, err)
+		applied, err := m.Up(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"add_trip_notes"}, migrationNames(applied))
+	})
+
+	t.Run("should roll back and not record a failing migration", func(t *testing.T) {
+		db := setupMigrationDB(t)
+		fsys := testMigrations()
+		fsys["003_add_trip_notes.up.sql"] = &fstest.MapFile{Data: []byte(`
+			CREATE TABLE trip_notes (id INTEGER PRIMARY KEY);
+			ALTER TABLE missing ADD COLUMN notes TEXT;`)}
+		m, err := NewMigrator(db, fsys)
+		require.NoError(t, err)
+
+		applied, err := m.Up(ctx)
+
+		assert.ErrorContains(t, err, "failed to apply migration 003_add_trip_notes")
+		assert.Equal(t, []string{"create_clients", "create_trips"}, migrationNames(applied))
+		assert.False(t, db.Migrator().HasTable("trip_notes"))
+		assert.Equal(t, []uint{1, 2}, appliedVersions(t, m))
+	})
+
+	t.Run("should migrate on the connection holding the lock", func(t *testing.T) {
+		db := setupMigrationDB(t)
+		sqlDB, err := db.DB()
+		require.NoError(t, err)
+		// With a single connection, any statement sent past the locked one
+		// would wait forever
+		sqlDB.SetMaxOpenConns(1)
+		m, err := NewMigrator(db, testMigrations())
+		require.NoError(t, err)
+		timeoutCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		applied, err := m.Up(timeoutCtx)
+
+		require.NoError(t, err)
+		assert.Len(t, applied, 3)
+		assert.Equal(t, []uint{1, 2, 3}, appliedVersions(t, m))
+	})
+}
+
+func TestMigrator_Down(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should revert the most recent migrations", func(t *testing.T) {
+		db := setupMigrationDB(t)
+		m, err := NewMigrator(db, testMigrations())
+		require.NoError(t, err)
+		_, err = m.Up(ctx)
+		require.NoError(t, err)
+
+		reverted, err := m.Down(ctx, 2)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"add_trip_notes", "create_trips"}, migrationNames(reverted))
+		assert.False(t, db.Migrator().HasTable("trips"))
+		assert.True(t, db.Migrator().HasTable("clients"))
+		assert.Equal(t, []uint{1}, appliedVersions(t, m))
+	})
+
+	t.Run("should stop when nothing is left to revert", func(t *testing.T) {
+		db := setupMigrationDB(t)
+		m, err := NewMigrator(db, testMigrations())
+		require.NoError(t, err)
+		_, err = m.Up(ctx)
+		require.NoError(t, err)
+
+		reverted, err := m.Down(ctx, 10)
+
+		require.NoError(t, err)
+		assert.Len(t, reverted, 3)
+		assert.Empty(t, appliedVersions(t, m))
+	})
+
+	t.Run("should fail for a migration without a down script", func(t *testing.T) {
+		db := setupMigrationDB(t)
+		fsys := testMigrations()
+		delete(fsys, "003_add_trip_notes.down.sql")
+		m, err := NewMigrator(db, fsys)
+		require.NoError(t, err)
+		_, err = m.Up(ctx)
+		require.NoError(t, err)
+
+		_, err = m.Down(ctx, 1)
+
+		assert.ErrorContains(t, err, "migration 003_add_trip_notes has no down script")
+		assert.Equal(t, []uint{1, 2, 3}, appliedVersions(t, m))
+	})
+}
+
+func TestMigrator_Goto(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should migrate up to the version", func(t *testing.T) {
+		db := setupMigrationDB(t)
+		m, err := NewMigrator(db, testMigrations())
+		require.NoError(t, err)
+
+		migrated, err := m.Goto(ctx, 2)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"create_clients", "create_trips"}, migrationNames(migrated))
+		version, err := m.Version(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint(2), version)
+		assert.Equal(t, uint(3), m.Latest())
+	})
+
+	t.Run("should migrate down to the version", func(t *testing.T) {
+		db := setupMigrationDB(t)
+		m, err := NewMigrator(db, testMigrations())
+		require.NoError(t, err)
+		_, err = m.Up(ctx)
+		require.NoError(t, err)
+
+		migrated, err := m.Goto(ctx, 1)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"add_trip_notes", "create_trips"}, migrationNames(migrated))
+		assert.Equal(t, []uint{1}, appliedVersions(t, m))
+	})
+
+	t.Run("should revert everything for version 0", func(t *testing.T) {
+		db := setupMigrationDB(t)
+		m, err := NewMigrator(db, testMigrations())
+		require.NoError(t, err)
+		_, err = m.Up(ctx)
+		require.NoError(t, err)
+
+		_, err = m.Goto(ctx, 0)
+
+		require.NoError(t, err)
+		assert.Empty(t, appliedVersions(t, m))
+		assert.False(t, db.Migrator().HasTable("clients"))
+	})
+
+	t.Run("should reject an unknown version", func(t *testing.T) {
+		db := setupMigrationDB(t)
+		m, err := NewMigrator(db, testMigrations())
+		require.NoError(t, err)
+
+		_, err = m.Goto(ctx, 7)
+
+		assert.ErrorIs(t, err, ErrUnknownMigration)
+		assert.Empty(t, appliedVersions(t, m))
+	})
+}
+
+func TestMigrator_Status(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should report pending and applied migrations", func(t *testing.T) {
+		db := setupMigrationDB(t)
+		m, err := NewMigrator(db, testMigrations())
+		require.NoError(t, err)
+		_, err = m.Goto(ctx, 1)
+		require.NoError(t, err)
+
+		statuses, err := m.Status(ctx)
+
+		require.NoError(t, err)
+		require.Len(t, statuses, 3)
+		assert.Equal(t, "create_clients", statuses[0].Name)
+		assert.NotNil(t, statuses[0].AppliedAt)
+		assert.Nil(t, statuses[1].AppliedAt)
+		assert.Nil(t, statuses[2].AppliedAt)
+	})
+
+	t.Run("should report applied versions that have no files", func(t *testing.T) {
+		db := setupMigrationDB(t)
+		m, err := NewMigrator(db, testMigrations())
+		require.NoError(t, err)
+		_, err = m.Up(ctx)
+		require.NoError(t, err)
+
+		fsys := testMigrations()
+		delete(fsys, "003_add_trip_notes.up.sql")
+		delete(fsys, "003_add_trip_notes.down.sql")
+		m, err = NewMigrator(db, fsys)
+		require.NoError(t, err)
+
+		statuses, err := m.Status(ctx)
+
+		require.NoError(t, err)
+		require.Len(t, statuses, 3)
+		assert.Equal(t, uint(3), statuses[2].Version)
+		assert.Equal(t, "add_trip_notes", statuses[2].Name)
+		assert.True(t, statuses[2].Missing)
+		assert.NotNil(t, statuses[2].AppliedAt)
+	})
+}
@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// Attachment is a file such as a receipt or parking ticket kept alongside a
+// trip. The content lives in a blob store under StorageKey.
+type Attachment struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	TripID      uint      `json:"trip_id" gorm:"not null;index"`
+	FileName    string    `json:"file_name" gorm:"type:varchar(255);not null"`
+	ContentType string    `json:"content_type" gorm:"type:varchar(100);not null"`
+	Size        int64     `json:"size" gorm:"not null"` // In bytes
+	StorageKey  string    `json:"-" gorm:"type:varchar(255);uniqueIndex;not null"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (Attachment) TableName() string {
+	return "attachments"
+}
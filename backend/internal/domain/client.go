@@ -5,9 +5,15 @@ import "time"
 type Client struct {
 	ID        uint      `json:"id" gorm:"primaryKey"`
 	Name      string    `json:"name" gorm:"type:varchar(30);not null;uniqueIndex"`
+	Currency  string    `json:"currency" gorm:"type:varchar(3)"` // ISO 4217 code the client reimburses in; empty for the mileage rate currency
 	CreatedAt time.Time `json:"created_at"`
 }
 
 func (Client) TableName() string {
 	return "clients"
 }
+
+// UpdateClientRequest represents the data needed to update a client
+type UpdateClientRequest struct {
+	Currency string `json:"currency"` // ISO 4217 code; empty to use the mileage rate currency
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultCurrency is the ISO 4217 currency used until a mileage rate
+// currency is configured
+const DefaultCurrency = "USD"
+
+// ExchangeRate is the value of one unit of Base in Quote on a given day,
+// e.g. 1 USD = 1.3650 CAD on 2025-01-15. Conversions use the latest rate
+// on or before the date of the trip being converted.
+type ExchangeRate struct {
+	ID        uint            `json:"id" gorm:"primaryKey"`
+	Date      string          `json:"date" gorm:"type:date;not null;uniqueIndex:idx_exchange_rates_pair_date,priority:3"` // YYYY-MM-DD
+	Base      string          `json:"base" gorm:"type:varchar(3);not null;uniqueIndex:idx_exchange_rates_pair_date,priority:1"`
+	Quote     string          `json:"quote" gorm:"type:varchar(3);not null;uniqueIndex:idx_exchange_rates_pair_date,priority:2"`
+	Rate      decimal.Decimal `json:"rate" gorm:"type:decimal(18,8);not null"` // "1.3650"
+	Source    string          `json:"source" gorm:"type:varchar(20);not null"` // "manual", "csv" or "ecb"
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+func (ExchangeRate) TableName() string {
+	return "exchange_rates"
+}
+
+// Exchange rate sources
+const (
+	ExchangeRateManual = "manual"
+	ExchangeRateCSV    = "csv"
+	ExchangeRateECB    = "ecb"
+)
+
+// ExchangeRateRequest represents one manually entered exchange rate.
+// Entering a rate for a pair and date that already has one replaces it.
+type ExchangeRateRequest struct {
+	Date  string          `json:"date" binding:"required"` // YYYY-MM-DD
+	Base  string          `json:"base" binding:"required"`
+	Quote string          `json:"quote" binding:"required"`
+	Rate  decimal.Decimal `json:"rate" binding:"required,gt=0"` // A string such as "1.3650" or a number
+}
+
+// ExchangeRateQuery selects the exchange rates to list. Empty fields match
+// every rate.
+type ExchangeRateQuery struct {
+	Base     string // ISO 4217 code
+	Quote    string // ISO 4217 code
+	DateFrom string // YYYY-MM-DD, inclusive
+	DateTo   string // YYYY-MM-DD, inclusive
+	Limit    int
+}
+
+// ExchangeRateImportResult summarizes an imported exchange rate file
+type ExchangeRateImportResult struct {
+	Format   string `json:"format"`    // "csv" or "ecb"
+	Imported int    `json:"imported"`  // Rates added or replaced
+	DateFrom string `json:"date_from"` // Earliest date in the file (YYYY-MM-DD)
+	DateTo   string `json:"date_to"`   // Latest date in the file (YYYY-MM-DD)
+}
+
+// ExpenseTotalRow totals the expenses of one currency on one trip date,
+// optionally within one summary group, as aggregated by the repository
+type ExpenseTotalRow struct {
+	Group        string // Set when grouped; see SummaryGroupRow
+	TripDate     string // YYYY-MM-DD
+	Currency     string
+	Amount       Money
+	ExpenseCount int64
+}
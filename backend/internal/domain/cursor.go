@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// TripCursor marks a position in the trips list for cursor pagination. It
+// holds the sort key values of the last trip of a page, with the trip ID
+// breaking ties.
+type TripCursor struct {
+	Sort   string   `json:"s"` // SortSignature of the sort the cursor belongs to
+	Values []string `json:"v"` // One per sort key, as returned by SortField.Value
+	ID     uint     `json:"i"`
+}
+
+// TripPage is one page of the trips list in cursor pagination
+type TripPage struct {
+	Trips      []Trip `json:"trips"`
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"` // Pass as cursor to get the next page; empty on the last page
+	HasMore    bool   `json:"has_more"`
+
+	// Filters are the filters in effect, including those of a saved view
+	Filters TripFilters `json:"filters"`
+}
+
+// CursorAfter returns the cursor of the position right after the trip
+// in the given sort
+func CursorAfter(trip Trip, sort []SortKey) TripCursor {
+	values := make([]string, len(sort))
+	for i, key := range sort {
+		values[i] = key.Field.Value(trip)
+	}
+	return TripCursor{Sort: SortSignature(sort), Values: values, ID: trip.ID}
+}
+
+// Encode returns the cursor as an opaque, URL-safe token
+func (c TripCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeTripCursor parses a token returned by Encode, checking that it
+// belongs to the given sort
+func DecodeTripCursor(token string, sort []SortKey) (TripCursor, error) {
+	var cursor TripCursor
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cursor, errors.New("malformed cursor")
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == 0 {
+		return cursor, errors.New("malformed cursor")
+	}
+	if cursor.Sort != SortSignature(sort) {
+		return cursor, errors.New("cursor belongs to a different sort")
+	}
+	if len(cursor.Values) != len(sort) {
+		return cursor, errors.New("malformed cursor")
+	}
+	for i, key := range sort {
+		if _, err := key.Field.ParseValue(cursor.Values[i]); err != nil {
+			return cursor, errors.New("malformed cursor")
+		}
+	}
+
+	return cursor, nil
+}
@@ -0,0 +1,44 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTripCursor_RoundTrip(t *testing.T) {
+	trip := domain.Trip{
+		ID:         42,
+		ClientName: "Acme Corp",
+		TripDate:   "2025-01-15T00:00:00Z",
+		Miles:      12.5,
+		CreatedAt:  time.Date(2025, 1, 15, 10, 30, 0, 123456000, time.UTC),
+	}
+	sort := []domain.SortKey{
+		{Field: domain.SortByTripDate, Desc: true},
+		{Field: domain.SortByMiles},
+		{Field: domain.SortByCreatedAt, Desc: true},
+	}
+
+	cursor := domain.CursorAfter(trip, sort)
+	assert.Equal(t, []string{"2025-01-15", "12.5", "2025-01-15T10:30:00.123456Z"}, cursor.Values)
+
+	decoded, err := domain.DecodeTripCursor(cursor.Encode(), sort)
+	assert.NoError(t, err)
+	assert.Equal(t, cursor, decoded)
+}
+
+func TestDecodeTripCursor_Malformed(t *testing.T) {
+	sort := domain.DefaultTripSort
+	valid := domain.CursorAfter(domain.Trip{ID: 1, TripDate: "2025-01-15"}, sort).Encode()
+
+	for _, token := range []string{"%%%", "bm90IGpzb24", domain.TripCursor{Sort: "-trip_date,-start_time,-created_at", Values: []string{"2025", "", "x"}, ID: 1}.Encode()} {
+		_, err := domain.DecodeTripCursor(token, sort)
+		assert.Error(t, err, token)
+	}
+
+	_, err := domain.DecodeTripCursor(valid, []domain.SortKey{{Field: domain.SortByMiles}})
+	assert.EqualError(t, err, "cursor belongs to a different sort")
+}
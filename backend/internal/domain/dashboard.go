@@ -0,0 +1,46 @@
+package domain
+
+// PeriodTotals holds the totals for a date range
+type PeriodTotals struct {
+	From string `json:"from"` // YYYY-MM-DD, inclusive
+	To   string `json:"to"`   // YYYY-MM-DD, inclusive
+	SummaryTotals
+}
+
+// PeriodComparison compares a period with an earlier one of the same length
+type PeriodComparison struct {
+	Current  PeriodTotals `json:"current"`
+	Previous PeriodTotals `json:"previous"`
+	// Percentage change in miles; nil when the previous period had none
+	MilesChangePercent *float64 `json:"miles_change_percent"`
+}
+
+// ClientTotals holds one client's totals
+type ClientTotals struct {
+	ClientName string  `json:"client_name"`
+	TripCount  int64   `json:"trip_count"`
+	TotalMiles float64 `json:"total_miles"`
+	Amount     Money   `json:"amount"`
+}
+
+// WeekdayTotals holds the totals for one day of the week
+type WeekdayTotals struct {
+	Weekday    string  `json:"weekday"` // "Monday"
+	TripCount  int64   `json:"trip_count"`
+	TotalMiles float64 `json:"total_miles"`
+}
+
+// DashboardResponse gathers the figures shown on the home screen
+type DashboardResponse struct {
+	AsOf     string       `json:"as_of"`    // Today's date in the business timezone (YYYY-MM-DD)
+	Timezone string       `json:"timezone"` // IANA zone "today" was computed in
+	Currency string       `json:"currency"` // ISO 4217 code of every amount, the mileage rate currency
+	Unit     DistanceUnit `json:"unit"`     // Unit of every total_distance, the distance_unit setting
+
+	YearToDate  PeriodComparison `json:"year_to_date"`  // Compared with the same dates last year
+	MonthToDate PeriodComparison `json:"month_to_date"` // Compared with the same days of last month
+
+	AverageMilesPerTrip float64         `json:"average_miles_per_trip"` // Year to date
+	TopClients          []ClientTotals  `json:"top_clients"`            // Year to date, most miles first
+	Weekdays            []WeekdayTotals `json:"weekdays"`               // Year to date, Monday first
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// DistanceUnit is the unit distances and mileage rates are expressed in.
+// Trips are stored in miles whatever unit they were entered in.
+type DistanceUnit string
+
+const (
+	DistanceMiles      DistanceUnit = "mi"
+	DistanceKilometers DistanceUnit = "km"
+)
+
+// DefaultDistanceUnit is used until a distance unit is configured
+const DefaultDistanceUnit = DistanceMiles
+
+// KilometersPerMile is the exact length of an international mile
+const KilometersPerMile = 1.609344
+
+var kilometersPerMile = decimal.RequireFromString("1.609344")
+
+// ParseDistanceUnit parses a unit name such as "mi", "miles", "km" or
+// "kilometres", case insensitively. A blank value parses as the empty unit.
+func ParseDistanceUnit(value string) (DistanceUnit, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "":
+		return "", true
+	case "mi", "mile", "miles":
+		return DistanceMiles, true
+	case "km", "kilometer", "kilometers", "kilometre", "kilometres":
+		return DistanceKilometers, true
+	}
+	return "", false
+}
+
+// ToMiles converts a distance in u to miles
+func (u DistanceUnit) ToMiles(distance float64) float64 {
+	if u == DistanceKilometers {
+		return distance / KilometersPerMile
+	}
+	return distance
+}
+
+// FromMiles converts a distance in miles to u
+func (u DistanceUnit) FromMiles(miles float64) float64 {
+	if u == DistanceKilometers {
+		return miles * KilometersPerMile
+	}
+	return miles
+}
+
+// RateFromPerMile converts a rate per mile to a rate per u. A rate per
+// kilometer is lower than the same rate per mile.
+func (u DistanceUnit) RateFromPerMile(rate Money) Money {
+	if u == DistanceKilometers {
+		return rate.Div(kilometersPerMile)
+	}
+	return rate
+}
+
+// RateToPerMile converts a rate per u to a rate per mile, exactly
+func (u DistanceUnit) RateToPerMile(rate Money) Money {
+	if u == DistanceKilometers {
+		return rate.Mul(kilometersPerMile)
+	}
+	return rate
+}
@@ -0,0 +1,37 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDistanceUnit(t *testing.T) {
+	for value, expected := range map[string]domain.DistanceUnit{
+		"":           "",
+		"mi":         domain.DistanceMiles,
+		"Miles":      domain.DistanceMiles,
+		" KM ":       domain.DistanceKilometers,
+		"kilometres": domain.DistanceKilometers,
+	} {
+		unit, ok := domain.ParseDistanceUnit(value)
+		assert.True(t, ok, value)
+		assert.Equal(t, expected, unit, value)
+	}
+
+	_, ok := domain.ParseDistanceUnit("nm")
+	assert.False(t, ok)
+}
+
+func TestDistanceUnit_Conversions(t *testing.T) {
+	km := domain.DistanceKilometers
+	assert.InDelta(t, 100, km.FromMiles(62.137119), 0.00001)
+	assert.InDelta(t, 62.137119, km.ToMiles(100), 0.00001)
+	assert.Equal(t, "0.4163", km.RateFromPerMile(domain.MustMoney("0.67")).Round(4).String())
+	assert.Equal(t, domain.MustMoney("0.67592448"), km.RateToPerMile(domain.MustMoney("0.42")))
+
+	mi := domain.DistanceMiles
+	assert.Equal(t, 12.5, mi.FromMiles(12.5))
+	assert.Equal(t, domain.MustMoney("0.67"), mi.RateFromPerMile(domain.MustMoney("0.67")))
+}
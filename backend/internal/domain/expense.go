@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+// ExpenseType is the kind of cost an expense covers
+type ExpenseType string
+
+const (
+	ExpenseToll    ExpenseType = "toll"
+	ExpenseParking ExpenseType = "parking"
+	ExpenseFuel    ExpenseType = "fuel"
+	ExpenseOther   ExpenseType = "other"
+)
+
+// Valid reports whether t is one of the supported expense types
+func (t ExpenseType) Valid() bool {
+	switch t {
+	case ExpenseToll, ExpenseParking, ExpenseFuel, ExpenseOther:
+		return true
+	}
+	return false
+}
+
+// Expense is a cost incurred on a trip, such as a toll or a parking fee,
+// that is reimbursed on top of the mileage amount
+type Expense struct {
+	ID        uint        `json:"id" gorm:"primaryKey"`
+	TripID    uint        `json:"trip_id" gorm:"not null;index"`
+	Type      ExpenseType `json:"type" gorm:"type:varchar(20);not null"`
+	Amount    Money       `json:"amount" gorm:"type:decimal(10,2);not null"`
+	Currency  string      `json:"currency" gorm:"type:varchar(3);not null"` // ISO 4217 code, e.g. "USD"
+	Note      string      `json:"note" gorm:"type:text"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+func (Expense) TableName() string {
+	return "expenses"
+}
+
+// ExpenseRequest represents one expense in a trip create or update request
+type ExpenseRequest struct {
+	Type     ExpenseType `json:"type" binding:"required"`
+	Amount   Money       `json:"amount" binding:"required,gt=0"`
+	Currency string      `json:"currency"` // ISO 4217 code; defaults to the client's currency
+	Note     string      `json:"note" binding:"max=500"`
+}
@@ -0,0 +1,75 @@
+package domain
+
+import "time"
+
+// Location is a saved address that trips can start from or end at
+type Location struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Label     string    `json:"label" gorm:"type:varchar(50);not null;uniqueIndex"`
+	Address   string    `json:"address" gorm:"type:text"`
+	Latitude  *float64  `json:"latitude,omitempty" gorm:"type:decimal(9,6)"`
+	Longitude *float64  `json:"longitude,omitempty" gorm:"type:decimal(9,6)"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (Location) TableName() string {
+	return "locations"
+}
+
+// HasCoordinates reports whether the location has both latitude and longitude
+func (l *Location) HasCoordinates() bool {
+	return l.Latitude != nil && l.Longitude != nil
+}
+
+// LocationDistance is a recorded driving distance between two saved locations
+type LocationDistance struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	FromLocationID uint      `json:"from_location_id" gorm:"not null;uniqueIndex:idx_location_distances_pair"`
+	ToLocationID   uint      `json:"to_location_id" gorm:"not null;uniqueIndex:idx_location_distances_pair"`
+	Miles          float64   `json:"miles" gorm:"type:decimal(8,2);not null"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func (LocationDistance) TableName() string {
+	return "location_distances"
+}
+
+// Distance sources reported when resolving the distance between two locations
+const (
+	DistanceSourceRecorded = "recorded"
+	DistanceSourceRouted   = "routed"
+	DistanceSourceEstimate = "estimate"
+)
+
+// CreateLocationRequest represents the data needed to create a new location
+type CreateLocationRequest struct {
+	Label     string   `json:"label" binding:"required,max=50"`
+	Address   string   `json:"address"`
+	Latitude  *float64 `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" binding:"omitempty,min=-180,max=180"`
+}
+
+// UpdateLocationRequest represents the data needed to update a location
+type UpdateLocationRequest struct {
+	Label     string   `json:"label" binding:"required,max=50"`
+	Address   string   `json:"address"`
+	Latitude  *float64 `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" binding:"omitempty,min=-180,max=180"`
+}
+
+// SetLocationDistanceRequest records the distance between two saved locations
+type SetLocationDistanceRequest struct {
+	FromLocationID uint    `json:"from_location_id" binding:"required"`
+	ToLocationID   uint    `json:"to_location_id" binding:"required"`
+	Miles          float64 `json:"miles" binding:"required,gt=0"`
+}
+
+// ResolvedDistance is the distance between two locations and where it came from
+type ResolvedDistance struct {
+	FromLocationID uint    `json:"from_location_id"`
+	ToLocationID   uint    `json:"to_location_id"`
+	Miles          float64 `json:"miles"`
+	Source         string  `json:"source"` // "recorded", "routed" or "estimate"
+}
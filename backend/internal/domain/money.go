@@ -0,0 +1,172 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Money is an amount of money or a rate per mile, held as a decimal so
+// amounts add up to the cent. It is encoded in JSON as a string such as
+// "97.49" and decoded from either a string or a number.
+//
+// Values are kept normalized, without trailing zeros, so equal amounts
+// are deeply equal.
+type Money struct {
+	d decimal.Decimal
+}
+
+// CentPlaces is the number of decimals amounts are rounded to
+const CentPlaces = 2
+
+// NewMoney parses a decimal string such as "0.67"
+func NewMoney(value string) (Money, error) {
+	d, err := decimal.NewFromString(value)
+	if err != nil {
+		return Money{}, fmt.Errorf("invalid amount %q", value)
+	}
+	return MoneyFromDecimal(d), nil
+}
+
+// MustMoney is NewMoney for constants; it panics on invalid input
+func MustMoney(value string) Money {
+	m, err := NewMoney(value)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// MoneyFromFloat converts f using its shortest decimal representation,
+// so 0.1 becomes exactly 0.1
+func MoneyFromFloat(f float64) Money {
+	return MoneyFromDecimal(decimal.NewFromFloat(f))
+}
+
+// MoneyFromDecimal wraps d
+func MoneyFromDecimal(d decimal.Decimal) Money {
+	if d.IsZero() {
+		return Money{}
+	}
+	coefficient := d.Coefficient()
+	if !coefficient.IsInt64() {
+		return Money{d: d}
+	}
+	value, exp := coefficient.Int64(), d.Exponent()
+	for value%10 == 0 {
+		value /= 10
+		exp++
+	}
+	return Money{d: decimal.New(value, exp)}
+}
+
+// Decimal returns m as a decimal
+func (m Money) Decimal() decimal.Decimal {
+	return m.d
+}
+
+// Add returns m + other
+func (m Money) Add(other Money) Money {
+	return MoneyFromDecimal(m.d.Add(other.d))
+}
+
+// Mul returns m scaled by factor, e.g. a rate times miles
+func (m Money) Mul(factor decimal.Decimal) Money {
+	return MoneyFromDecimal(m.d.Mul(factor))
+}
+
+// Div returns m divided by divisor to 16 significant decimals
+func (m Money) Div(divisor decimal.Decimal) Money {
+	return MoneyFromDecimal(m.d.Div(divisor))
+}
+
+// Round rounds m to places decimals, halves away from zero
+func (m Money) Round(places int32) Money {
+	return MoneyFromDecimal(m.d.Round(places))
+}
+
+// RoundCents rounds m to whole cents
+func (m Money) RoundCents() Money {
+	return m.Round(CentPlaces)
+}
+
+func (m Money) IsZero() bool {
+	return m.d.IsZero()
+}
+
+func (m Money) IsNegative() bool {
+	return m.d.IsNegative()
+}
+
+func (m Money) IsPositive() bool {
+	return m.d.IsPositive()
+}
+
+// Float64 returns the nearest float64, for display and validation only
+func (m Money) Float64() float64 {
+	f, _ := m.d.Float64()
+	return f
+}
+
+// String formats m with at least two decimals: "97.50", "0.4163"
+func (m Money) String() string {
+	places := -m.d.Exponent()
+	if places < CentPlaces {
+		places = CentPlaces
+	}
+	return m.d.StringFixed(places)
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + m.String() + `"`), nil
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var d decimal.Decimal
+	if err := d.UnmarshalJSON(data); err != nil {
+		return fmt.Errorf("invalid amount %s", data)
+	}
+	*m = MoneyFromDecimal(d)
+	return nil
+}
+
+// Scan reads a DECIMAL column
+func (m *Money) Scan(value interface{}) error {
+	var d decimal.Decimal
+	if err := d.Scan(value); err != nil {
+		return err
+	}
+	*m = MoneyFromDecimal(d)
+	return nil
+}
+
+// Value stores m as a decimal string, so no precision is lost on the way
+// to a DECIMAL column
+func (m Money) Value() (driver.Value, error) {
+	return m.d.String(), nil
+}
+
+// AmountRounding is where mileage amounts are rounded to the cent
+type AmountRounding string
+
+const (
+	// RoundPerTrip rounds every trip's amount, then adds them up, as
+	// payroll systems paying trip by trip do
+	RoundPerTrip AmountRounding = "per_trip"
+
+	// RoundPerPeriod adds up unrounded amounts and rounds each period's
+	// total once
+	RoundPerPeriod AmountRounding = "per_period"
+)
+
+// DefaultAmountRounding is used until a rounding rule is configured
+const DefaultAmountRounding = RoundPerPeriod
+
+// Valid reports whether r is one of the supported rounding rules
+func (r AmountRounding) Valid() bool {
+	return r == RoundPerTrip || r == RoundPerPeriod
+}
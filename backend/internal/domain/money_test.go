@@ -0,0 +1,49 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoney_Normalized(t *testing.T) {
+	assert.Equal(t, domain.MustMoney("67"), domain.MustMoney("67.00"))
+	assert.Equal(t, domain.MustMoney("0.3"), domain.MustMoney("0.1").Add(domain.MustMoney("0.2")))
+	assert.Equal(t, domain.Money{}, domain.MustMoney("0.00"))
+	assert.Equal(t, domain.MustMoney("0.1"), domain.MoneyFromFloat(0.1))
+}
+
+func TestMoney_String(t *testing.T) {
+	assert.Equal(t, "97.50", domain.MustMoney("97.5").String())
+	assert.Equal(t, "0.00", domain.Money{}.String())
+	assert.Equal(t, "0.4163", domain.MustMoney("0.4163").String())
+	assert.Equal(t, "-1.23", domain.MustMoney("-1.23").String())
+}
+
+func TestMoney_RoundCents(t *testing.T) {
+	assert.Equal(t, domain.MustMoney("0.01"), domain.MustMoney("0.005").RoundCents())
+	assert.Equal(t, domain.MustMoney("-0.01"), domain.MustMoney("-0.005").RoundCents())
+	assert.Equal(t, domain.MustMoney("10.05"), domain.MustMoney("0.67").Mul(domain.MustMoney("15").Decimal()).RoundCents())
+}
+
+func TestMoney_JSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Amount domain.Money `json:"amount"`
+	}{domain.MustMoney("1234567890.1")})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount": "1234567890.10"}`, string(data))
+
+	var decoded struct {
+		Amount domain.Money `json:"amount"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"amount": "0.655"}`), &decoded))
+	assert.Equal(t, domain.MustMoney("0.655"), decoded.Amount)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"amount": 0.58}`), &decoded))
+	assert.Equal(t, domain.MustMoney("0.58"), decoded.Amount)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"amount": "abc"}`), &decoded))
+}
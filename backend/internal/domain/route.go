@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// CachedRouteDistance stores a driving distance returned by a routing
+// provider so the same pair of coordinates is only looked up once
+type CachedRouteDistance struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Provider  string    `json:"provider" gorm:"type:varchar(30);not null;uniqueIndex:idx_route_distance_cache_key"`
+	FromLat   float64   `json:"from_lat" gorm:"type:decimal(9,6);not null;uniqueIndex:idx_route_distance_cache_key"`
+	FromLon   float64   `json:"from_lon" gorm:"type:decimal(9,6);not null;uniqueIndex:idx_route_distance_cache_key"`
+	ToLat     float64   `json:"to_lat" gorm:"type:decimal(9,6);not null;uniqueIndex:idx_route_distance_cache_key"`
+	ToLon     float64   `json:"to_lon" gorm:"type:decimal(9,6);not null;uniqueIndex:idx_route_distance_cache_key"`
+	Miles     float64   `json:"miles" gorm:"type:decimal(8,2);not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (CachedRouteDistance) TableName() string {
+	return "route_distance_cache"
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type Settings struct {
 	ID        uint      `json:"id" gorm:"primaryKey"`
@@ -13,12 +16,35 @@ func (Settings) TableName() string {
 	return "settings"
 }
 
-// UpdateSettingsRequest represents the data needed to update settings
-type UpdateSettingsRequest struct {
-	MileageRate float64 `json:"mileage_rate" binding:"required,min=0"`
-}
+// SettingType is the type of a setting's value. Values are stored as
+// strings whatever their type.
+type SettingType string
+
+const (
+	SettingTypeDecimal SettingType = "decimal" // A JSON string such as "0.67"
+	SettingTypeInteger SettingType = "integer"
+	SettingTypeString  SettingType = "string"
+)
+
+// SettingDefinition declares a setting key the API reads and writes
+type SettingDefinition struct {
+	Key         string      `json:"key"`
+	Type        SettingType `json:"type"`
+	Default     string      `json:"default"`          // In stored form, e.g. "0.67"
+	Values      []string    `json:"values,omitempty"` // Allowed values, if limited to a set
+	Description string      `json:"description"`
 
-// SettingsResponse represents the settings returned to the client
-type SettingsResponse struct {
-	MileageRate float64 `json:"mileage_rate"`
+	// Parse validates a value in stored form and returns its canonical
+	// stored form. When nil, any value of Type among Values is accepted.
+	Parse func(value string) (string, error) `json:"-"`
 }
+
+// UpdateSettingsRequest maps setting keys to their new values, encoded as
+// in SettingsResponse. Settings left out are unchanged. rate_unit is the
+// unit a mileage_rate is given per; it defaults to the distance unit.
+type UpdateSettingsRequest map[string]json.RawMessage
+
+// SettingsResponse maps every setting key to its value: a Money for
+// decimal settings, an int for integer ones and a string otherwise.
+// rate_unit is the unit mileage_rate is given per.
+type SettingsResponse map[string]interface{}
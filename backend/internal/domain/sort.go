@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SortField is a trip attribute the trips list can be sorted by
+type SortField string
+
+const (
+	SortByTripDate   SortField = "trip_date"
+	SortByMiles      SortField = "miles"
+	SortByClientName SortField = "client_name" // Case-insensitive
+	SortByCreatedAt  SortField = "created_at"
+	SortByUpdatedAt  SortField = "updated_at"
+	SortByAmount     SortField = "amount"     // Miles times the mileage rate
+	SortByStartTime  SortField = "start_time" // Trips without one sort as the earliest
+)
+
+// Valid reports whether f is one of the sortable fields
+func (f SortField) Valid() bool {
+	switch f {
+	case SortByTripDate, SortByMiles, SortByClientName, SortByCreatedAt, SortByUpdatedAt, SortByAmount, SortByStartTime:
+		return true
+	}
+	return false
+}
+
+// SortKey is one key of a multi-key sort. It is written as the field name,
+// prefixed with "-" for descending order.
+type SortKey struct {
+	Field SortField
+	Desc  bool
+}
+
+// DefaultTripSort is the order trips are listed in when no sort is given,
+// by both page and cursor pagination: newest trips first, and within a day
+// latest start first, then the trips without a start time, newest first
+var DefaultTripSort = []SortKey{
+	{Field: SortByTripDate, Desc: true},
+	{Field: SortByStartTime, Desc: true},
+	{Field: SortByCreatedAt, Desc: true},
+}
+
+// ParseSortKey parses "miles" or "-miles"
+func ParseSortKey(s string) (SortKey, error) {
+	key := SortKey{Field: SortField(strings.TrimPrefix(s, "-")), Desc: strings.HasPrefix(s, "-")}
+	if !key.Field.Valid() {
+		return key, fmt.Errorf("cannot sort by %q", key.Field)
+	}
+	return key, nil
+}
+
+func (k SortKey) String() string {
+	if k.Desc {
+		return "-" + string(k.Field)
+	}
+	return string(k.Field)
+}
+
+func (k SortKey) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+func (k *SortKey) UnmarshalText(text []byte) error {
+	key, err := ParseSortKey(string(text))
+	if err != nil {
+		return err
+	}
+	*k = key
+	return nil
+}
+
+// SortSignature identifies a sort, e.g. "-trip_date,miles"
+func SortSignature(keys []SortKey) string {
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = key.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// Value returns the trip's value of the field in the form cursors store it
+func (f SortField) Value(trip Trip) string {
+	switch f {
+	case SortByTripDate:
+		// Some drivers return dates as full timestamps
+		if len(trip.TripDate) > len("2006-01-02") {
+			return trip.TripDate[:len("2006-01-02")]
+		}
+		return trip.TripDate
+	case SortByMiles, SortByAmount:
+		return strconv.FormatFloat(trip.Miles, 'f', -1, 64)
+	case SortByClientName:
+		return trip.ClientName
+	case SortByCreatedAt:
+		return trip.CreatedAt.Format(time.RFC3339Nano)
+	case SortByUpdatedAt:
+		return trip.UpdatedAt.Format(time.RFC3339Nano)
+	case SortByStartTime:
+		if trip.StartTime == nil {
+			return ""
+		}
+		return trip.StartTime.Format(time.RFC3339Nano)
+	}
+	return ""
+}
+
+// ParseValue converts a value returned by Value back into the type the
+// database compares it as. A missing start time parses as nil.
+func (f SortField) ParseValue(value string) (interface{}, error) {
+	switch f {
+	case SortByTripDate:
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	case SortByMiles, SortByAmount:
+		return strconv.ParseFloat(value, 64)
+	case SortByCreatedAt, SortByUpdatedAt:
+		return time.Parse(time.RFC3339Nano, value)
+	case SortByStartTime:
+		if value == "" {
+			return nil, nil
+		}
+		return time.Parse(time.RFC3339Nano, value)
+	case SortByClientName:
+		return value, nil
+	}
+	return nil, fmt.Errorf("cannot sort by %q", f)
+}
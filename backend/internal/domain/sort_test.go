@@ -0,0 +1,41 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSortKey(t *testing.T) {
+	key, err := domain.ParseSortKey("-miles")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.SortKey{Field: domain.SortByMiles, Desc: true}, key)
+
+	key, err = domain.ParseSortKey("client_name")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.SortKey{Field: domain.SortByClientName}, key)
+
+	for _, value := range []string{"notes", "--miles", "", "Miles"} {
+		_, err := domain.ParseSortKey(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestTripFilters_SortJSON(t *testing.T) {
+	filters := domain.TripFilters{Sort: []domain.SortKey{
+		{Field: domain.SortByAmount, Desc: true},
+		{Field: domain.SortByTripDate},
+	}}
+
+	data, err := json.Marshal(filters)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"sort":["-amount","trip_date"]}`, string(data))
+
+	var decoded domain.TripFilters
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, filters, decoded)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"sort":["notes"]}`), &decoded))
+}
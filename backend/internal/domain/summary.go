@@ -1,15 +1,182 @@
 package domain
 
+// Granularity is the size of the buckets a summary is grouped into
+type Granularity string
+
+const (
+	GranularityDay     Granularity = "day"
+	GranularityWeek    Granularity = "week" // ISO weeks, starting Monday
+	GranularityMonth   Granularity = "month"
+	GranularityQuarter Granularity = "quarter"
+	GranularityYear    Granularity = "year"
+)
+
+// Valid reports whether g is one of the supported granularities
+func (g Granularity) Valid() bool {
+	switch g {
+	case GranularityDay, GranularityWeek, GranularityMonth, GranularityQuarter, GranularityYear:
+		return true
+	}
+	return false
+}
+
+// SummaryDimension is a categorical trip attribute a summary can be
+// broken down by
+type SummaryDimension string
+
+const (
+	SummaryByClient       SummaryDimension = "client"
+	SummaryByFromLocation SummaryDimension = "from_location"
+	SummaryByToLocation   SummaryDimension = "to_location"
+
+	// SummaryByTag counts a trip towards each of its tags, so group totals
+	// can add up to more than the bucket totals
+	SummaryByTag SummaryDimension = "tag"
+)
+
+// Valid reports whether d is one of the supported dimensions
+func (d SummaryDimension) Valid() bool {
+	switch d {
+	case SummaryByClient, SummaryByFromLocation, SummaryByToLocation, SummaryByTag:
+		return true
+	}
+	return false
+}
+
+// SummaryQuery selects the trips and buckets for a summary. Empty fields
+// fall back to the six most recent buckets of monthly granularity.
+type SummaryQuery struct {
+	From        string      // YYYY-MM-DD, inclusive
+	To          string      // YYYY-MM-DD, inclusive
+	Granularity Granularity // Defaults to month
+	Filters     TripFilters
+
+	GroupBy SummaryDimension // Optional breakdown of every bucket
+	Top     int              // Groups kept before the rest are merged into "Other"; defaults to 5
+
+	Currency string       // ISO 4217 code amounts are converted to; defaults to the mileage rate currency
+	Unit     DistanceUnit // Unit of TotalDistance; defaults to the distance_unit setting
+}
+
+// SummaryBucket holds the totals for one period of a summary
+type SummaryBucket struct {
+	Period       string  `json:"period"`        // "2025-01-15", "2025-W03", "2025-01", "2025-Q1" or "2025"
+	Label        string  `json:"label"`         // "January 2025"
+	StartDate    string  `json:"start_date"`    // First day of the period (YYYY-MM-DD)
+	EndDate      string  `json:"end_date"`      // Last day of the period (YYYY-MM-DD)
+	TripCount    int64   `json:"trip_count"`    // 12
+	TotalMiles   float64 `json:"total_miles"`   // 145.50
+	TotalMinutes float64 `json:"total_minutes"` // 95, from trips with start and end times
+	Amount       Money   `json:"amount"`        // "97.49", the mileage amount
+
+	TotalDistance float64 `json:"total_distance"` // 234.16, TotalMiles in the summary unit
+
+	ExpenseAmount       Money `json:"expense_amount"`                 // "18.50", expenses converted to the summary currency
+	UnconvertedExpenses int64 `json:"unconverted_expenses,omitempty"` // Expenses without an exchange rate, left out of ExpenseAmount
+	TotalAmount         Money `json:"total_amount"`                   // "115.99", mileage amount plus expenses
+
+	// TripMiles lists the miles of every trip in the bucket, so amounts can
+	// be rounded trip by trip; only aggregated when rounding per trip
+	TripMiles []float64 `json:"-"`
+}
+
+// Totals returns the bucket's figures as totals
+func (b SummaryBucket) Totals() SummaryTotals {
+	return SummaryTotals{
+		TripCount:           b.TripCount,
+		TotalMiles:          b.TotalMiles,
+		TotalMinutes:        b.TotalMinutes,
+		Amount:              b.Amount,
+		TotalDistance:       b.TotalDistance,
+		ExpenseAmount:       b.ExpenseAmount,
+		UnconvertedExpenses: b.UnconvertedExpenses,
+		TotalAmount:         b.TotalAmount,
+		TripMiles:           b.TripMiles,
+	}
+}
+
+// SummaryTotals holds the totals across every bucket of a summary
+type SummaryTotals struct {
+	TripCount    int64   `json:"trip_count"`
+	TotalMiles   float64 `json:"total_miles"`
+	TotalMinutes float64 `json:"total_minutes"`
+	Amount       Money   `json:"amount"`
+
+	TotalDistance float64 `json:"total_distance"`
+
+	ExpenseAmount       Money `json:"expense_amount"`
+	UnconvertedExpenses int64 `json:"unconverted_expenses,omitempty"`
+	TotalAmount         Money `json:"total_amount"`
+
+	TripMiles []float64 `json:"-"` // See SummaryBucket; cleared once priced
+}
+
+// SummaryGroupRow holds the totals for one group within one bucket, as
+// aggregated by the repository
+type SummaryGroupRow struct {
+	Group        string // Client name, location label or tag name; empty when the trip has none
+	StartDate    string // First day of the bucket (YYYY-MM-DD)
+	TripCount    int64
+	TotalMiles   float64
+	TotalMinutes float64
+	TripMiles    []float64 `gorm:"-"` // See SummaryBucket
+}
+
+// Totals returns the row's figures as totals, not yet priced
+func (r SummaryGroupRow) Totals() SummaryTotals {
+	return SummaryTotals{
+		TripCount:    r.TripCount,
+		TotalMiles:   r.TotalMiles,
+		TotalMinutes: r.TotalMinutes,
+		TripMiles:    r.TripMiles,
+	}
+}
+
+// SummaryGroup is one row of a grouped summary. Values line up with the
+// response's buckets.
+type SummaryGroup struct {
+	Key          string          `json:"key"`                     // Client name, location label or tag name; empty for "Other" and trips without a value
+	Label        string          `json:"label"`                   // Display name, e.g. "Acme Corp", "Unspecified" or "Other"
+	Other        bool            `json:"other,omitempty"`         // True for the group collecting everything outside the top N
+	MergedGroups int             `json:"merged_groups,omitempty"` // How many groups were merged into "Other"
+	Totals       SummaryTotals   `json:"totals"`
+	Values       []SummaryTotals `json:"values"`
+}
+
 // MonthlySummary represents the summary for a specific month
 type MonthlySummary struct {
-	Month      string  `json:"month"`       // "January 2025"
-	Year       int     `json:"year"`        // 2025
-	MonthNum   int     `json:"month_num"`   // 1-12
-	TotalMiles float64 `json:"total_miles"` // 145.50
-	Amount     float64 `json:"amount"`      // 97.49
+	Month        string  `json:"month"`         // "January 2025"
+	Year         int     `json:"year"`          // 2025
+	MonthNum     int     `json:"month_num"`     // 1-12
+	TotalMiles   float64 `json:"total_miles"`   // 145.50
+	TotalMinutes float64 `json:"total_minutes"` // 95, from trips with start and end times
+	Amount       Money   `json:"amount"`        // "97.49", the mileage amount
+
+	TotalDistance float64 `json:"total_distance"` // 234.16, in the summary unit
+
+	ExpenseAmount Money `json:"expense_amount"` // "18.50"
+	TotalAmount   Money `json:"total_amount"`   // "115.99"
 }
 
-// SummaryResponse represents the 6-month summary response
+// SummaryResponse represents a bucketed trip summary. Buckets run oldest
+// first and include empty periods.
 type SummaryResponse struct {
-	Months []MonthlySummary `json:"months"`
+	From        string          `json:"from"`
+	To          string          `json:"to"`
+	Granularity Granularity     `json:"granularity"`
+	Currency    string          `json:"currency"` // ISO 4217 code of every amount
+	Unit        DistanceUnit    `json:"unit"`     // Unit of every total_distance
+	Buckets     []SummaryBucket `json:"buckets"`
+	Totals      SummaryTotals   `json:"totals"`
+	Timezone    string          `json:"timezone"` // IANA zone "today" was computed in, e.g. "America/New_York"
+	AsOf        string          `json:"as_of"`    // Today's date in that zone (YYYY-MM-DD)
+
+	// Set when the summary is broken down with group_by; groups are ordered
+	// by total miles with "Other" last
+	GroupBy SummaryDimension `json:"group_by,omitempty"`
+	Groups  []SummaryGroup   `json:"groups,omitempty"`
+
+	// Months repeats monthly buckets newest first in the original summary
+	// format. It is only set for monthly granularity.
+	Months []MonthlySummary `json:"months,omitempty"`
 }
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// Tag is a label such as "conference", "site-visit" or "billable" that
+// trips can carry any number of
+type Tag struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"type:varchar(30);uniqueIndex;not null"` // Lowercase letters, digits, - and _
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// TagRequest represents the data needed to create or rename a tag
+type TagRequest struct {
+	Name string `json:"name" binding:"required,max=30"`
+}
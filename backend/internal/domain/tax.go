@@ -0,0 +1,23 @@
+package domain
+
+// FiscalYearSummary holds the totals for one fiscal year
+type FiscalYearSummary struct {
+	FiscalYear int    `json:"fiscal_year"` // Calendar year the fiscal year ends in
+	Label      string `json:"label"`       // "FY2025"
+	PeriodTotals
+
+	Months []SummaryBucket `json:"months"` // Every month of the fiscal year, oldest first
+}
+
+// TaxSummaryResponse holds the figures needed for year-end tax filings.
+// Amount is the deductible amount: miles times the mileage_rate setting.
+type TaxSummaryResponse struct {
+	FiscalYearStartMonth int          `json:"fiscal_year_start_month"` // 1 (January) to 12 (December)
+	Timezone             string       `json:"timezone"`                // IANA zone fiscal years are computed in
+	AsOf                 string       `json:"as_of"`                   // Today's date in the business timezone (YYYY-MM-DD)
+	Currency             string       `json:"currency"`                // ISO 4217 code of every amount
+	Unit                 DistanceUnit `json:"unit"`                    // Unit of every total_distance
+
+	FiscalYears []FiscalYearSummary `json:"fiscal_years"` // Oldest first
+	Totals      SummaryTotals       `json:"totals"`       // Across every fiscal year
+}
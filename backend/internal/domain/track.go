@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// TrackImportRequest holds the form fields sent along with a GPX or GeoJSON
+// track upload
+type TrackImportRequest struct {
+	ClientName        string   `form:"client_name" binding:"required,max=30"`
+	TripDate          string   `form:"trip_date"` // YYYY-MM-DD, required when the track has no timestamps
+	Notes             string   `form:"notes"`
+	MinMovementMeters *float64 `form:"min_movement_meters" binding:"omitempty,min=0"`
+	StopMinutes       *float64 `form:"stop_minutes" binding:"omitempty,min=0"`
+	MinTripMiles      *float64 `form:"min_trip_miles" binding:"omitempty,min=0"`
+	DryRun            bool     `form:"dry_run"`
+}
+
+// ImportedSegment describes one drive found in an uploaded track
+type ImportedSegment struct {
+	StartTime *time.Time `json:"start_time,omitempty"`
+	EndTime   *time.Time `json:"end_time,omitempty"`
+	Miles     float64    `json:"miles"`
+	Points    int        `json:"points"`
+}
+
+// TrackImportResponse reports what was found in a track and the trips
+// created from it. Trips is empty for a dry run.
+type TrackImportResponse struct {
+	PointsRead int               `json:"points_read"`
+	Segments   []ImportedSegment `json:"segments"`
+	Trips      []Trip            `json:"trips"`
+}
@@ -9,33 +9,93 @@ type Trip struct {
 	ClientID   *uint     `json:"client_id" gorm:"index"`
 	ClientName string    `json:"client_name" gorm:"type:varchar(30);not null;index"`
 	TripDate   string    `json:"trip_date" gorm:"type:date;not null;index"` // YYYY-MM-DD format
-	Miles      float64   `json:"miles" gorm:"type:decimal(8,2);not null"`
+	Miles      float64   `json:"miles" gorm:"type:decimal(10,4);not null"`  // Canonical distance, whatever unit it was entered in
 	Notes      string    `json:"notes" gorm:"type:text"`
 	CreatedAt  time.Time `json:"created_at" gorm:"index"`
 	UpdatedAt  time.Time `json:"updated_at"`
 
-	// Relationship
-	Client *Client `json:"client,omitempty" gorm:"foreignKey:ClientID"`
+	// Miles in the unit requested by the caller, rounded to 2 decimals
+	Distance float64      `json:"distance" gorm:"-"`
+	Unit     DistanceUnit `json:"unit" gorm:"-"`
+
+	// Miles times the mileage rate, rounded to the cent, in the mileage
+	// rate currency
+	Amount Money `json:"amount" gorm:"-"`
+
+	// Optional saved locations the trip started from and ended at
+	FromLocationID *uint `json:"from_location_id,omitempty" gorm:"index"`
+	ToLocationID   *uint `json:"to_location_id,omitempty" gorm:"index"`
+
+	// Optional departure and arrival times. Only the instant is stored; they
+	// may be read back in another zone than the one they were sent in.
+	StartTime *time.Time `json:"start_time,omitempty"`
+	EndTime   *time.Time `json:"end_time,omitempty"`
+
+	// Relationships
+	Client   *Client   `json:"client,omitempty" gorm:"foreignKey:ClientID"`
+	Tags     []Tag     `json:"tags,omitempty" gorm:"many2many:trip_tags;constraint:OnDelete:CASCADE"`
+	Expenses []Expense `json:"expenses,omitempty" gorm:"foreignKey:TripID;constraint:OnDelete:CASCADE"`
 }
 
 func (Trip) TableName() string {
 	return "trips"
 }
 
+// Duration returns how long the trip took, or zero when either time is missing
+func (t Trip) Duration() time.Duration {
+	if t.StartTime == nil || t.EndTime == nil {
+		return 0
+	}
+	return t.EndTime.Sub(*t.StartTime)
+}
+
 // CreateTripRequest represents the data needed to create a new trip
 type CreateTripRequest struct {
 	ClientName string  `json:"client_name" binding:"required,max=30"`
 	TripDate   string  `json:"trip_date" binding:"required"` // YYYY-MM-DD
-	Miles      float64 `json:"miles" binding:"required,min=0"`
+	Miles      float64 `json:"miles" binding:"required_without_all=Distance FromLocationID ToLocationID,min=0"`
 	Notes      string  `json:"notes"`
+
+	// Distance in Unit, an alternative to Miles; Unit defaults to the
+	// distance_unit setting
+	Distance float64 `json:"distance" binding:"min=0"`
+	Unit     string  `json:"unit"`
+
+	FromLocationID *uint `json:"from_location_id"`
+	ToLocationID   *uint `json:"to_location_id"`
+
+	StartTime *time.Time `json:"start_time"` // RFC 3339 with offset
+	EndTime   *time.Time `json:"end_time"`   // RFC 3339 with offset
+
+	Tags []string `json:"tags"` // Tag names; unknown tags are created
+
+	Expenses []ExpenseRequest `json:"expenses" binding:"omitempty,dive"` // Tolls, parking and fuel paid on the trip
 }
 
 // UpdateTripRequest represents the data needed to update a trip
 type UpdateTripRequest struct {
 	ClientName string  `json:"client_name" binding:"required,max=30"`
 	TripDate   string  `json:"trip_date" binding:"required"` // YYYY-MM-DD
-	Miles      float64 `json:"miles" binding:"required,min=0"`
+	Miles      float64 `json:"miles" binding:"required_without_all=Distance FromLocationID ToLocationID,min=0"`
 	Notes      string  `json:"notes"`
+
+	// Distance in Unit, an alternative to Miles; Unit defaults to the
+	// distance_unit setting
+	Distance float64 `json:"distance" binding:"min=0"`
+	Unit     string  `json:"unit"`
+
+	FromLocationID *uint `json:"from_location_id"`
+	ToLocationID   *uint `json:"to_location_id"`
+
+	StartTime *time.Time `json:"start_time"` // RFC 3339 with offset
+	EndTime   *time.Time `json:"end_time"`   // RFC 3339 with offset
+
+	// Tag names replacing the trip's tags; unknown tags are created. Leaving
+	// the field out keeps the current tags, an empty list removes them.
+	Tags []string `json:"tags"`
+
+	// Expenses replacing the trip's expenses, with the same rules as Tags
+	Expenses []ExpenseRequest `json:"expenses" binding:"omitempty,dive"`
 }
 
 // TripFilters represents the filters that can be applied when retrieving trips
@@ -46,4 +106,38 @@ type TripFilters struct {
 	DateTo   string   `json:"date_to,omitempty"`   // Filter trips up to this date (YYYY-MM-DD)
 	MinMiles *float64 `json:"min_miles,omitempty"` // Minimum miles filter
 	MaxMiles *float64 `json:"max_miles,omitempty"` // Maximum miles filter
+
+	Clients        []string `json:"clients,omitempty"`         // Client is any of these (case insensitive)
+	ExcludeClients []string `json:"exclude_clients,omitempty"` // Client is none of these (case insensitive)
+	IDs            []uint   `json:"ids,omitempty"`             // Trip ID is any of these
+	ExcludeIDs     []uint   `json:"exclude_ids,omitempty"`     // Trip ID is none of these
+	HasNotes       *bool    `json:"has_notes,omitempty"`       // Notes are (or are not) present and non-blank
+	Tags           []string `json:"tags,omitempty"`            // Trip has any of these tags
+	AllTags        []string `json:"all_tags,omitempty"`        // Trip has every one of these tags
+	ExcludeTags    []string `json:"exclude_tags,omitempty"`    // Trip has none of these tags
+
+	// Record timestamp bounds as RFC 3339 timestamps or YYYY-MM-DD dates,
+	// which stand for midnight in the business timezone. After bounds are
+	// inclusive and before bounds exclusive.
+	CreatedAfter  string `json:"created_after,omitempty"`
+	CreatedBefore string `json:"created_before,omitempty"`
+	UpdatedAfter  string `json:"updated_after,omitempty"`
+	UpdatedBefore string `json:"updated_before,omitempty"`
+
+	// Sort keys in priority order; ties are broken by ID. Empty keeps the
+	// default order.
+	Sort []SortKey `json:"sort,omitempty"`
+
+	// Unit trip distances are returned in; defaults to the distance_unit
+	// setting. Miles filters stay in miles.
+	Unit DistanceUnit `json:"unit,omitempty"`
+}
+
+// ParseTimeBound parses a created/updated filter bound. A date stands for
+// midnight at its start in loc.
+func ParseTimeBound(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02", value, loc)
 }
@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+// DateRange is a date window relative to today, resolved in the business
+// timezone each time a saved view is applied
+type DateRange string
+
+const (
+	DateRangeToday       DateRange = "today"
+	DateRangeYesterday   DateRange = "yesterday"
+	DateRangeThisWeek    DateRange = "this_week" // ISO weeks, starting Monday
+	DateRangeLastWeek    DateRange = "last_week"
+	DateRangeThisMonth   DateRange = "this_month"
+	DateRangeLastMonth   DateRange = "last_month"
+	DateRangeThisQuarter DateRange = "this_quarter"
+	DateRangeLastQuarter DateRange = "last_quarter"
+	DateRangeThisYear    DateRange = "this_year"
+	DateRangeLastYear    DateRange = "last_year"
+	DateRangeLast7Days   DateRange = "last_7_days" // Including today
+	DateRangeLast30Days  DateRange = "last_30_days"
+	DateRangeLast90Days  DateRange = "last_90_days"
+)
+
+// Valid reports whether r is one of the supported date ranges
+func (r DateRange) Valid() bool {
+	switch r {
+	case DateRangeToday, DateRangeYesterday, DateRangeThisWeek, DateRangeLastWeek,
+		DateRangeThisMonth, DateRangeLastMonth, DateRangeThisQuarter, DateRangeLastQuarter,
+		DateRangeThisYear, DateRangeLastYear, DateRangeLast7Days, DateRangeLast30Days, DateRangeLast90Days:
+		return true
+	}
+	return false
+}
+
+// SavedView is a named set of trip filters that can be applied to the trip
+// list and summary instead of repeating the query parameters
+type SavedView struct {
+	ID        uint        `json:"id" gorm:"primaryKey"`
+	Name      string      `json:"name" gorm:"type:varchar(100);not null;uniqueIndex"`
+	Filters   TripFilters `json:"filters" gorm:"type:text;not null;serializer:json"`
+	DateRange DateRange   `json:"date_range,omitempty" gorm:"type:varchar(20)"` // Replaces the filters' date_from and date_to
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+func (SavedView) TableName() string {
+	return "saved_views"
+}
+
+// SaveViewRequest represents the data needed to create or update a saved view
+type SaveViewRequest struct {
+	Name      string      `json:"name" binding:"required,max=100"`
+	Filters   TripFilters `json:"filters"`
+	DateRange DateRange   `json:"date_range"`
+}
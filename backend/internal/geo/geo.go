@@ -0,0 +1,48 @@
+package geo
+
+import "math"
+
+// earthRadiusMiles is the mean radius of the Earth in miles
+const earthRadiusMiles = 3958.8
+
+// MetersPerMile converts between metric distances and miles
+const MetersPerMile = 1609.344
+
+// RoadDistanceFactor approximates how much longer a driven route is than the
+// straight-line distance between two points. It is applied to haversine
+// estimates so they are closer to what an odometer would report.
+const RoadDistanceFactor = 1.2
+
+// Point is a WGS84 coordinate in decimal degrees
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// HaversineMiles returns the great-circle distance between two points in miles
+func HaversineMiles(a, b Point) float64 {
+	lat1 := toRadians(a.Lat)
+	lat2 := toRadians(b.Lat)
+	dLat := toRadians(b.Lat - a.Lat)
+	dLon := toRadians(b.Lon - a.Lon)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadiusMiles * math.Asin(math.Sqrt(h))
+}
+
+// EstimateRoadMiles estimates the driving distance between two points from
+// their straight-line distance, rounded to two decimal places
+func EstimateRoadMiles(a, b Point) float64 {
+	return RoundMiles(HaversineMiles(a, b) * RoadDistanceFactor)
+}
+
+// RoundMiles rounds a distance to the two decimal places stored for trips
+func RoundMiles(miles float64) float64 {
+	return math.Round(miles*100) / 100
+}
+
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
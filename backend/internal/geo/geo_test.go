@@ -0,0 +1,38 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHaversineMiles(t *testing.T) {
+	t.Run("should return zero for identical points", func(t *testing.T) {
+		p := Point{Lat: 40.7128, Lon: -74.0060}
+		assert.Equal(t, 0.0, HaversineMiles(p, p))
+	})
+
+	t.Run("should compute known city distance", func(t *testing.T) {
+		newYork := Point{Lat: 40.7128, Lon: -74.0060}
+		losAngeles := Point{Lat: 34.0522, Lon: -118.2437}
+
+		// Great-circle distance NYC -> LA is roughly 2445 miles
+		assert.InDelta(t, 2445.0, HaversineMiles(newYork, losAngeles), 5.0)
+	})
+
+	t.Run("should be symmetric", func(t *testing.T) {
+		a := Point{Lat: 51.5074, Lon: -0.1278}
+		b := Point{Lat: 48.8566, Lon: 2.3522}
+		assert.InDelta(t, HaversineMiles(a, b), HaversineMiles(b, a), 1e-9)
+	})
+}
+
+func TestEstimateRoadMiles(t *testing.T) {
+	a := Point{Lat: 51.5074, Lon: -0.1278}
+	b := Point{Lat: 48.8566, Lon: 2.3522}
+
+	estimate := EstimateRoadMiles(a, b)
+
+	assert.InDelta(t, HaversineMiles(a, b)*RoadDistanceFactor, estimate, 0.01)
+	assert.Equal(t, RoundMiles(estimate), estimate, "estimate should be rounded to cents of a mile")
+}
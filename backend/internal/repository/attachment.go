@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/oscar/mileagetracker/internal/database"
+	"github.com/oscar/mileagetracker/internal/domain"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type AttachmentRepository interface {
+	Create(ctx context.Context, attachment *domain.Attachment) error
+	Delete(ctx context.Context, id uint) error
+	FindByID(ctx context.Context, id uint) (*domain.Attachment, error)
+	FindByTrip(ctx context.Context, tripID uint) ([]domain.Attachment, error)
+}
+
+type attachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAttachmentRepository(store *database.Store) AttachmentRepository {
+	return &attachmentRepository{db: store.DB()}
+}
+
+func (r *attachmentRepository) Create(ctx context.Context, attachment *domain.Attachment) error {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpCreate, "attachment", zap.Uint("trip_id", attachment.TripID))()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpCreate))
+	defer cancel()
+
+	return r.db.WithContext(ctxWithTimeout).Create(attachment).Error
+}
+
+func (r *attachmentRepository) Delete(ctx context.Context, id uint) error {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpDelete, "attachment", zap.Uint("id", id))()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpDelete))
+	defer cancel()
+
+	return r.db.WithContext(ctxWithTimeout).Delete(&domain.Attachment{}, id).Error
+}
+
+func (r *attachmentRepository) FindByID(ctx context.Context, id uint) (*domain.Attachment, error) {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpFindByID, "attachment", zap.Uint("id", id))()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpFindByID))
+	defer cancel()
+
+	var attachment domain.Attachment
+	err := r.db.WithContext(ctxWithTimeout).First(&attachment, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// FindByTrip returns a trip's attachments, oldest first
+func (r *attachmentRepository) FindByTrip(ctx context.Context, tripID uint) ([]domain.Attachment, error) {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpFind, "attachment", zap.Uint("trip_id", tripID))()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpFind))
+	defer cancel()
+
+	attachments := []domain.Attachment{}
+	err := r.db.WithContext(ctxWithTimeout).
+		Where("trip_id = ?", tripID).
+		Order("created_at ASC, id ASC").
+		Find(&attachments).Error
+	return attachments, err
+}
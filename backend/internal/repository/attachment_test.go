@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/oscar/mileagetracker/internal/database"
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestAttachmentRepository(t *testing.T) {
+	db := testutils.SetupTestDB(t)
+	repo := NewAttachmentRepository(database.NewStore(db))
+	tripRepo := NewTripRepository(database.NewStore(db))
+	ctx := context.Background()
+
+	trip := &domain.Trip{ClientName: "Acme", TripDate: "2025-01-15", Miles: 10}
+	other := &domain.Trip{ClientName: "Acme", TripDate: "2025-01-16", Miles: 12}
+	require.NoError(t, tripRepo.Create(ctx, trip))
+	require.NoError(t, tripRepo.Create(ctx, other))
+
+	receipt := &domain.Attachment{TripID: trip.ID, FileName: "receipt.pdf", ContentType: "application/pdf", Size: 1024, StorageKey: "trips/1/a"}
+	ticket := &domain.Attachment{TripID: trip.ID, FileName: "ticket.png", ContentType: "image/png", Size: 2048, StorageKey: "trips/1/b"}
+	elsewhere := &domain.Attachment{TripID: other.ID, FileName: "toll.jpg", ContentType: "image/jpeg", Size: 512, StorageKey: "trips/2/c"}
+	for _, attachment := range []*domain.Attachment{receipt, ticket, elsewhere} {
+		require.NoError(t, repo.Create(ctx, attachment))
+	}
+
+	t.Run("should list a trip's attachments in upload order", func(t *testing.T) {
+		attachments, err := repo.FindByTrip(ctx, trip.ID)
+		require.NoError(t, err)
+		require.Len(t, attachments, 2)
+		assert.Equal(t, receipt.ID, attachments[0].ID)
+		assert.Equal(t, ticket.ID, attachments[1].ID)
+		assert.Equal(t, "trips/1/b", attachments[1].StorageKey)
+	})
+
+	t.Run("should return an empty list for a trip without attachments", func(t *testing.T) {
+		attachments, err := repo.FindByTrip(ctx, 999)
+		require.NoError(t, err)
+		assert.NotNil(t, attachments)
+		assert.Empty(t, attachments)
+	})
+
+	t.Run("should delete an attachment", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, ticket.ID))
+
+		_, err := repo.FindByID(ctx, ticket.ID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("should remove attachment records with their trip", func(t *testing.T) {
+		require.NoError(t, tripRepo.Delete(ctx, trip.ID))
+
+		attachments, err := repo.FindByTrip(ctx, trip.ID)
+		require.NoError(t, err)
+		assert.Empty(t, attachments)
+
+		found, err := repo.FindByID(ctx, elsewhere.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "toll.jpg", found.FileName)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"strings"
 
+	"github.com/oscar/mileagetracker/internal/database"
 	"github.com/oscar/mileagetracker/internal/domain"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -11,6 +12,8 @@ import (
 
 type ClientRepository interface {
 	Create(ctx context.Context, client *domain.Client) error
+	Update(ctx context.Context, client *domain.Client) error
+	FindByID(ctx context.Context, id uint) (*domain.Client, error)
 	FindByName(ctx context.Context, name string) (*domain.Client, error)
 	GetSuggestions(ctx context.Context, query string, limit int) ([]domain.Client, error)
 }
@@ -19,8 +22,8 @@ type clientRepository struct {
 	db *gorm.DB
 }
 
-func NewClientRepository(db *gorm.DB) ClientRepository {
-	return &clientRepository{db: db}
+func NewClientRepository(store *database.Store) ClientRepository {
+	return &clientRepository{db: store.DB()}
 }
 
 func (r *clientRepository) Create(ctx context.Context, client *domain.Client) error {
@@ -33,6 +36,31 @@ func (r *clientRepository) Create(ctx context.Context, client *domain.Client) er
 	return r.db.WithContext(ctxWithTimeout).Create(client).Error
 }
 
+func (r *clientRepository) Update(ctx context.Context, client *domain.Client) error {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpUpdate, "client", zap.Uint("id", client.ID))()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpUpdate))
+	defer cancel()
+
+	return r.db.WithContext(ctxWithTimeout).Save(client).Error
+}
+
+func (r *clientRepository) FindByID(ctx context.Context, id uint) (*domain.Client, error) {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpFindByID, "client", zap.Uint("id", id))()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpFindByID))
+	defer cancel()
+
+	var client domain.Client
+	err := r.db.WithContext(ctxWithTimeout).First(&client, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
 func (r *clientRepository) FindByName(ctx context.Context, name string) (*domain.Client, error) {
 	monitor := GetQueryPerformanceMonitor()
 	defer monitor.MonitorQuery(OpFindByName, "client", zap.String("name", name))()
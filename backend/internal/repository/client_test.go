@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/oscar/mileagetracker/internal/database"
 	"github.com/oscar/mileagetracker/internal/testutils"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
@@ -11,7 +12,7 @@ import (
 
 func TestClientRepository_Create(t *testing.T) {
 	db := testutils.SetupTestDB(t)
-	repo := NewClientRepository(db)
+	repo := NewClientRepository(database.NewStore(db))
 
 	t.Run("should create client successfully", func(t *testing.T) {
 		client := testutils.NewClientBuilder().
@@ -28,7 +29,7 @@ func TestClientRepository_Create(t *testing.T) {
 
 func TestClientRepository_FindByName(t *testing.T) {
 	db := testutils.SetupTestDB(t)
-	repo := NewClientRepository(db)
+	repo := NewClientRepository(database.NewStore(db))
 
 	t.Run("should find client by name", func(t *testing.T) {
 		// Create test client using builder
@@ -55,7 +56,7 @@ func TestClientRepository_FindByName(t *testing.T) {
 
 func TestClientRepository_GetSuggestions(t *testing.T) {
 	db := testutils.SetupTestDB(t)
-	repo := NewClientRepository(db)
+	repo := NewClientRepository(database.NewStore(db))
 
 	t.Run("should call GetSuggestions without panic", func(t *testing.T) {
 		// Create test client using builder
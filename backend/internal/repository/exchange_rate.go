@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/oscar/mileagetracker/internal/database"
+	"github.com/oscar/mileagetracker/internal/domain"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// exchangeRateBatchSize keeps bulk inserts under the bind parameter limits
+// of both databases
+const exchangeRateBatchSize = 500
+
+type ExchangeRateRepository interface {
+	Upsert(ctx context.Context, rates []domain.ExchangeRate) error
+	Delete(ctx context.Context, id uint) error
+	FindByID(ctx context.Context, id uint) (*domain.ExchangeRate, error)
+	Find(ctx context.Context, query domain.ExchangeRateQuery) ([]domain.ExchangeRate, error)
+	FindForCurrencies(ctx context.Context, currencies []string, to string) ([]domain.ExchangeRate, error)
+}
+
+type exchangeRateRepository struct {
+	db *gorm.DB
+}
+
+func NewExchangeRateRepository(store *database.Store) ExchangeRateRepository {
+	return &exchangeRateRepository{db: store.DB()}
+}
+
+// Upsert saves rates, replacing the rate of any pair already recorded for
+// the same date
+func (r *exchangeRateRepository) Upsert(ctx context.Context, rates []domain.ExchangeRate) error {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpBulkUpsert, "exchange_rate", zap.Int("count", len(rates)))()
+
+	if len(rates) == 0 {
+		return nil
+	}
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpBulkUpsert))
+	defer cancel()
+
+	now := r.db.NowFunc()
+	for i := range rates {
+		rates[i].UpdatedAt = now
+	}
+
+	return r.db.WithContext(ctxWithTimeout).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "base"}, {Name: "quote"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "source", "updated_at"}),
+	}).CreateInBatches(rates, exchangeRateBatchSize).Error
+}
+
+func (r *exchangeRateRepository) Delete(ctx context.Context, id uint) error {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpDelete, "exchange_rate", zap.Uint("id", id))()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpDelete))
+	defer cancel()
+
+	return r.db.WithContext(ctxWithTimeout).Delete(&domain.ExchangeRate{}, id).Error
+}
+
+func (r *exchangeRateRepository) FindByID(ctx context.Context, id uint) (*domain.ExchangeRate, error) {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpFindByID, "exchange_rate", zap.Uint("id", id))()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpFindByID))
+	defer cancel()
+
+	var rate domain.ExchangeRate
+	err := r.db.WithContext(ctxWithTimeout).First(&rate, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// Find lists the rates matching query, newest first
+func (r *exchangeRateRepository) Find(ctx context.Context, query domain.ExchangeRateQuery) ([]domain.ExchangeRate, error) {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpFind, "exchange_rate",
+		zap.String("base", query.Base), zap.String("quote", query.Quote))()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpFind))
+	defer cancel()
+
+	db := r.db.WithContext(ctxWithTimeout)
+	if query.Base != "" {
+		db = db.Where("base = ?", query.Base)
+	}
+	if query.Quote != "" {
+		db = db.Where("quote = ?", query.Quote)
+	}
+	if query.DateFrom != "" {
+		db = db.Where("date >= ?", query.DateFrom)
+	}
+	if query.DateTo != "" {
+		db = db.Where("date <= ?", query.DateTo)
+	}
+	if query.Limit > 0 {
+		db = db.Limit(query.Limit)
+	}
+
+	var rates []domain.ExchangeRate
+	err := db.Order("date DESC, base ASC, quote ASC").Find(&rates).Error
+	return rates, err
+}
+
+// FindForCurrencies returns every rate up to and including to that has one
+// of currencies on either side, oldest first. Rates against a third
+// currency are included so conversions can go through it.
+func (r *exchangeRateRepository) FindForCurrencies(ctx context.Context, currencies []string, to string) ([]domain.ExchangeRate, error) {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpFind, "exchange_rate",
+		zap.Strings("currencies", currencies), zap.String("to", to))()
+
+	if len(currencies) == 0 {
+		return []domain.ExchangeRate{}, nil
+	}
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpGetSummary))
+	defer cancel()
+
+	var rates []domain.ExchangeRate
+	err := r.db.WithContext(ctxWithTimeout).
+		Where("base IN ? OR quote IN ?", currencies, currencies).
+		Where("date <= ?", to).
+		Order("date ASC, base ASC, quote ASC").
+		Find(&rates).Error
+	return rates, err
+}
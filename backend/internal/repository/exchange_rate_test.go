@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/oscar/mileagetracker/internal/database"
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/testutils"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestExchangeRateRepository(t *testing.T) {
+	db := testutils.SetupTestDB(t)
+	repo := NewExchangeRateRepository(database.NewStore(db))
+	ctx := context.Background()
+
+	require.NoError(t, repo.Upsert(ctx, []domain.ExchangeRate{
+		{Date: "2025-01-15", Base: "USD", Quote: "CAD", Rate: decimal.RequireFromString("1.43"), Source: domain.ExchangeRateManual},
+		{Date: "2025-01-15", Base: "EUR", Quote: "USD", Rate: decimal.RequireFromString("1.03"), Source: domain.ExchangeRateECB},
+		{Date: "2025-01-15", Base: "EUR", Quote: "JPY", Rate: decimal.RequireFromString("161.9"), Source: domain.ExchangeRateECB},
+		{Date: "2025-02-03", Base: "USD", Quote: "CAD", Rate: decimal.RequireFromString("1.45"), Source: domain.ExchangeRateManual},
+	}))
+
+	t.Run("should replace the rate of an existing pair and date", func(t *testing.T) {
+		require.NoError(t, repo.Upsert(ctx, []domain.ExchangeRate{
+			{Date: "2025-01-15", Base: "USD", Quote: "CAD", Rate: decimal.RequireFromString("1.44"), Source: domain.ExchangeRateCSV},
+		}))
+
+		rates, err := repo.Find(ctx, domain.ExchangeRateQuery{Base: "USD", Quote: "CAD"})
+		require.NoError(t, err)
+		require.Len(t, rates, 2)
+		assert.Equal(t, "2025-02-03", rates[0].Date[:10]) // Newest first
+		assert.True(t, decimal.RequireFromString("1.44").Equal(rates[1].Rate), "rate %s", rates[1].Rate)
+		assert.Equal(t, domain.ExchangeRateCSV, rates[1].Source)
+	})
+
+	t.Run("should filter by date", func(t *testing.T) {
+		rates, err := repo.Find(ctx, domain.ExchangeRateQuery{DateFrom: "2025-02-01", DateTo: "2025-02-28"})
+		require.NoError(t, err)
+		assert.Len(t, rates, 1)
+	})
+
+	t.Run("should find rates involving the currencies", func(t *testing.T) {
+		rates, err := repo.FindForCurrencies(ctx, []string{"USD", "CAD"}, "2025-01-31")
+		require.NoError(t, err)
+		require.Len(t, rates, 2)
+		assert.Equal(t, "EUR", rates[0].Base)
+		assert.Equal(t, "USD", rates[1].Base)
+	})
+
+	t.Run("should delete a rate", func(t *testing.T) {
+		rates, err := repo.Find(ctx, domain.ExchangeRateQuery{Base: "EUR", Quote: "JPY"})
+		require.NoError(t, err)
+		require.Len(t, rates, 1)
+
+		require.NoError(t, repo.Delete(ctx, rates[0].ID))
+		_, err = repo.FindByID(ctx, rates[0].ID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/oscar/mileagetracker/internal/database"
+	"github.com/oscar/mileagetracker/internal/domain"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LocationRepository stores locations and the distances between them.
+// Create and Update return gorm.ErrDuplicatedKey when the label is taken.
+type LocationRepository interface {
+	Create(ctx context.Context, location *domain.Location) error
+	Update(ctx context.Context, location *domain.Location) error
+	Delete(ctx context.Context, id uint) error
+	FindByID(ctx context.Context, id uint) (*domain.Location, error)
+	GetAll(ctx context.Context) ([]domain.Location, error)
+	FindDistance(ctx context.Context, fromID, toID uint) (*domain.LocationDistance, error)
+	SetDistance(ctx context.Context, distance *domain.LocationDistance) error
+	GetDistances(ctx context.Context) ([]domain.LocationDistance, error)
+}
+
+type locationRepository struct {
+	store *database.Store
+	db    *gorm.DB
+}
+
+func NewLocationRepository(store *database.Store) LocationRepository {
+	return &locationRepository{store: store, db: store.DB()}
+}
+
+func (r *locationRepository) Create(ctx context.Context, location *domain.Location) error {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpCreate, "location")()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpCreate))
+	defer cancel()
+
+	err := r.db.WithContext(ctxWithTimeout).Create(location).Error
+	return translateDuplicateKey(r.db, err)
+}
+
+func (r *locationRepository) Update(ctx context.Context, location *domain.Location) error {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpUpdate, "location", zap.Uint("id", location.ID))()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpUpdate))
+	defer cancel()
+
+	err := r.db.WithContext(ctxWithTimeout).Save(location).Error
+	return translateDuplicateKey(r.db, err)
+}
+
+// Delete removes a location together with its recorded distances and clears
+// any trip references to it
+func (r *locationRepository) Delete(ctx context.Context, id uint) error {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpDelete, "location", zap.Uint("id", id))()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpDelete))
+	defer cancel()
+
+	return r.store.Transaction(ctxWithTimeout, func(tx *gorm.DB) error {
+		if err := tx.Where("from_location_id = ? OR to_location_id = ?", id, id).
+			Delete(&domain.LocationDistance{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&domain.Trip{}).Where("from_location_id = ?", id).
+			Update("from_location_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&domain.Trip{}).Where("to_location_id = ?", id).
+			Update("to_location_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&domain.Location{}, id).Error
+	})
+}
+
+func (r *locationRepository) FindByID(ctx context.Context, id uint) (*domain.Location, error) {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpFindByID, "location", zap.Uint("id", id))()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpFindByID))
+	defer cancel()
+
+	var location domain.Location
+	err := r.db.WithContext(ctxWithTimeout).First(&location, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+func (r *locationRepository) GetAll(ctx context.Context) ([]domain.Location, error) {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpGetAll, "location")()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpGetAll))
+	defer cancel()
+
+	var locations []domain.Location
+	err := r.db.WithContext(ctxWithTimeout).Order("label ASC").Find(&locations).Error
+	return locations, err
+}
+
+// FindDistance looks up a recorded distance between two locations in either
+// direction, preferring the one recorded for the requested direction
+func (r *locationRepository) FindDistance(ctx context.Context, fromID, toID uint) (*domain.LocationDistance, error) {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpFind, "location_distance", zap.Uint("from", fromID), zap.Uint("to", toID))()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpFind))
+	defer cancel()
+
+	var distance domain.LocationDistance
+	err := r.db.WithContext(ctxWithTimeout).
+		Where("(from_location_id = ? AND to_location_id = ?) OR (from_location_id = ? AND to_location_id = ?)",
+			fromID, toID, toID, fromID).
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "CASE WHEN from_location_id = ? THEN 0 ELSE 1 END",
+			Vars: []interface{}{fromID},
+		}}).
+		Take(&distance).Error
+	if err != nil {
+		return nil, err
+	}
+	return &distance, nil
+}
+
+// SetDistance records the distance between two locations, replacing any
+// existing value for the same direction. The distance is then filled in
+// from the stored row, so a replaced one keeps its ID and creation time.
+func (r *locationRepository) SetDistance(ctx context.Context, distance *domain.LocationDistance) error {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpUpdate, "location_distance",
+		zap.Uint("from", distance.FromLocationID), zap.Uint("to", distance.ToLocationID))()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpUpdate))
+	defer cancel()
+
+	distance.UpdatedAt = r.db.NowFunc()
+
+	return r.store.Transaction(ctxWithTimeout, func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "from_location_id"}, {Name: "to_location_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"miles", "updated_at"}),
+		}).Create(distance).Error
+		if err != nil {
+			return err
+		}
+
+		stored := domain.LocationDistance{}
+		err = tx.Where("from_location_id = ? AND to_location_id = ?", distance.FromLocationID, distance.ToLocationID).
+			Take(&stored).Error
+		if err != nil {
+			return err
+		}
+		*distance = stored
+		return nil
+	})
+}
+
+func (r *locationRepository) GetDistances(ctx context.Context) ([]domain.LocationDistance, error) {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpGetAll, "location_distance")()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpGetAll))
+	defer cancel()
+
+	var distances []domain.LocationDistance
+	err := r.db.WithContext(ctxWithTimeout).
+		Order("from_location_id ASC, to_location_id ASC").
+		Find(&distances).Error
+	return distances, err
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/oscar/mileagetracker/internal/database"
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+func TestLocationRepository_CRUD(t *testing.T) {
+	db := testutils.SetupTestDB(t)
+	repo := NewLocationRepository(database.NewStore(db))
+	ctx := context.Background()
+
+	t.Run("should create and find location", func(t *testing.T) {
+		location := &domain.Location{
+			Label:     "Office",
+			Address:   "1 Main St",
+			Latitude:  floatPtr(40.7128),
+			Longitude: floatPtr(-74.0060),
+		}
+
+		err := repo.Create(ctx, location)
+		assert.NoError(t, err)
+		assert.NotZero(t, location.ID)
+
+		found, err := repo.FindByID(ctx, location.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Office", found.Label)
+		assert.InDelta(t, 40.7128, *found.Latitude, 1e-6)
+	})
+
+	t.Run("should return locations ordered by label", func(t *testing.T) {
+		err := repo.Create(ctx, &domain.Location{Label: "Airport"})
+		assert.NoError(t, err)
+
+		locations, err := repo.GetAll(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, locations, 2)
+		assert.Equal(t, "Airport", locations[0].Label)
+		assert.Equal(t, "Office", locations[1].Label)
+	})
+
+	t.Run("should report a label that is already taken", func(t *testing.T) {
+		err := repo.Create(ctx, &domain.Location{Label: "Office"})
+		assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+
+		depot := &domain.Location{Label: "Depot"}
+		assert.NoError(t, repo.Create(ctx, depot))
+		depot.Label = "Office"
+		assert.ErrorIs(t, repo.Update(ctx, depot), gorm.ErrDuplicatedKey)
+	})
+
+	t.Run("should return error for non-existent location", func(t *testing.T) {
+		found, err := repo.FindByID(ctx, 99999)
+		assert.Nil(t, found)
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+	})
+}
+
+func TestLocationRepository_Distances(t *testing.T) {
+	db := testutils.SetupTestDB(t)
+	repo := NewLocationRepository(database.NewStore(db))
+	ctx := context.Background()
+
+	home := &domain.Location{Label: "Home"}
+	office := &domain.Location{Label: "Office"}
+	assert.NoError(t, repo.Create(ctx, home))
+	assert.NoError(t, repo.Create(ctx, office))
+
+	t.Run("should record and find distance in both directions", func(t *testing.T) {
+		err := repo.SetDistance(ctx, &domain.LocationDistance{
+			FromLocationID: home.ID,
+			ToLocationID:   office.ID,
+			Miles:          12.5,
+		})
+		assert.NoError(t, err)
+
+		forward, err := repo.FindDistance(ctx, home.ID, office.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 12.5, forward.Miles)
+
+		reverse, err := repo.FindDistance(ctx, office.ID, home.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 12.5, reverse.Miles)
+	})
+
+	t.Run("should prefer distance recorded for the requested direction", func(t *testing.T) {
+		err := repo.SetDistance(ctx, &domain.LocationDistance{
+			FromLocationID: office.ID,
+			ToLocationID:   home.ID,
+			Miles:          13.1,
+		})
+		assert.NoError(t, err)
+
+		reverse, err := repo.FindDistance(ctx, office.ID, home.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 13.1, reverse.Miles)
+	})
+
+	t.Run("should replace existing distance on conflict", func(t *testing.T) {
+		original, err := repo.FindDistance(ctx, home.ID, office.ID)
+		assert.NoError(t, err)
+
+		replaced := &domain.LocationDistance{
+			FromLocationID: home.ID,
+			ToLocationID:   office.ID,
+			Miles:          11.0,
+		}
+		err = repo.SetDistance(ctx, replaced)
+		assert.NoError(t, err)
+		assert.Equal(t, original.ID, replaced.ID)
+		assert.True(t, original.CreatedAt.Equal(replaced.CreatedAt))
+		assert.Equal(t, 11.0, replaced.Miles)
+
+		distances, err := repo.GetDistances(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, distances, 2)
+
+		forward, err := repo.FindDistance(ctx, home.ID, office.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 11.0, forward.Miles)
+	})
+
+	t.Run("should remove distances and trip references on delete", func(t *testing.T) {
+		trip := &domain.Trip{
+			ClientName:     "Client",
+			TripDate:       "2025-01-15",
+			Miles:          11.0,
+			FromLocationID: &home.ID,
+			ToLocationID:   &office.ID,
+		}
+		assert.NoError(t, db.Create(trip).Error)
+
+		err := repo.Delete(ctx, home.ID)
+		assert.NoError(t, err)
+
+		_, err = repo.FindDistance(ctx, home.ID, office.ID)
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+
+		var reloaded domain.Trip
+		assert.NoError(t, db.First(&reloaded, trip.ID).Error)
+		assert.Nil(t, reloaded.FromLocationID)
+		assert.NotNil(t, reloaded.ToLocationID)
+	})
+}
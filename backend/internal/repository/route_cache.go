@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/oscar/mileagetracker/internal/database"
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/geo"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RouteCacheRepository interface {
+	Find(ctx context.Context, provider string, from, to geo.Point) (*domain.CachedRouteDistance, error)
+	Save(ctx context.Context, entry *domain.CachedRouteDistance) error
+}
+
+type routeCacheRepository struct {
+	db *gorm.DB
+}
+
+func NewRouteCacheRepository(store *database.Store) RouteCacheRepository {
+	return &routeCacheRepository{db: store.DB()}
+}
+
+func (r *routeCacheRepository) Find(ctx context.Context, provider string, from, to geo.Point) (*domain.CachedRouteDistance, error) {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpFind, "route_distance_cache", zap.String("provider", provider))()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpFind))
+	defer cancel()
+
+	var entry domain.CachedRouteDistance
+	// Uses the unique index on the provider and coordinate columns
+	err := r.db.WithContext(ctxWithTimeout).
+		Where("provider = ? AND from_lat = ? AND from_lon = ? AND to_lat = ? AND to_lon = ?",
+			provider, from.Lat, from.Lon, to.Lat, to.Lon).
+		Take(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Save stores a routed distance, keeping the existing entry if another
+// request already cached the same route
+func (r *routeCacheRepository) Save(ctx context.Context, entry *domain.CachedRouteDistance) error {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpCreate, "route_distance_cache", zap.String("provider", entry.Provider))()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpCreate))
+	defer cancel()
+
+	return r.db.WithContext(ctxWithTimeout).Clauses(clause.OnConflict{DoNothing: true}).Create(entry).Error
+}
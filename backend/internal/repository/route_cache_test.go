@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/oscar/mileagetracker/internal/database"
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/geo"
+	"github.com/oscar/mileagetracker/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestRouteCacheRepository(t *testing.T) {
+	db := testutils.SetupTestDB(t)
+	repo := NewRouteCacheRepository(database.NewStore(db))
+	ctx := context.Background()
+
+	from := geo.Point{Lat: 40.7128, Lon: -74.006}
+	to := geo.Point{Lat: 40.73061, Lon: -73.93524}
+
+	t.Run("should return not found for unknown route", func(t *testing.T) {
+		entry, err := repo.Find(ctx, "osrm", from, to)
+		assert.Nil(t, entry)
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+	})
+
+	t.Run("should save and find route", func(t *testing.T) {
+		err := repo.Save(ctx, &domain.CachedRouteDistance{
+			Provider: "osrm",
+			FromLat:  from.Lat,
+			FromLon:  from.Lon,
+			ToLat:    to.Lat,
+			ToLon:    to.Lon,
+			Miles:    6.42,
+		})
+		assert.NoError(t, err)
+
+		entry, err := repo.Find(ctx, "osrm", from, to)
+		assert.NoError(t, err)
+		assert.Equal(t, 6.42, entry.Miles)
+	})
+
+	t.Run("should keep routes separate per direction and provider", func(t *testing.T) {
+		_, err := repo.Find(ctx, "osrm", to, from)
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+
+		_, err = repo.Find(ctx, "other", from, to)
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+	})
+
+	t.Run("should ignore duplicate saves", func(t *testing.T) {
+		err := repo.Save(ctx, &domain.CachedRouteDistance{
+			Provider: "osrm",
+			FromLat:  from.Lat,
+			FromLon:  from.Lon,
+			ToLat:    to.Lat,
+			ToLon:    to.Lon,
+			Miles:    7.0,
+		})
+		assert.NoError(t, err)
+
+		entry, err := repo.Find(ctx, "osrm", from, to)
+		assert.NoError(t, err)
+		assert.Equal(t, 6.42, entry.Miles)
+	})
+}
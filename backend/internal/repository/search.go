@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// searchConfig is the text search configuration used to build both the
+// search_vector column (see migrations/009) and the queries against it
+const searchConfig = "english"
+
+// stopWords are the words the english text search configuration leaves out
+// of search vectors and queries, so the substring fallback can ignore them
+// too
+var stopWords = map[string]bool{}
+
+func init() {
+	for _, word := range strings.Fields(`
+		i me my myself we our ours ourselves you your yours yourself yourselves
+		he him his himself she her hers herself it its itself they them their
+		theirs themselves what which who whom this that these those am is are
+		was were be been being have has had having do does did doing a an the
+		and but if or because as until while of at by for with about against
+		between into through during before after above below to from up down
+		in out on off over under again further then once here there when where
+		why how all any both each few more most other some such no nor not only
+		own same so than too very s t can will just don should now`) {
+		stopWords[word] = true
+	}
+}
+
+// searchTerm is one word or quoted phrase of a search string
+type searchTerm struct {
+	Text    string
+	Phrase  bool // Quoted; its words must appear next to each other
+	Negated bool // Prefixed with "-"; matching trips are excluded
+}
+
+// parseSearchTerms splits a search string into words and "quoted phrases".
+// A leading "-" negates a term. An unterminated quote runs to the end of
+// the string.
+func parseSearchTerms(search string) []searchTerm {
+	var terms []searchTerm
+
+	i := 0
+	for i < len(search) {
+		if search[i] == ' ' || search[i] == '\t' || search[i] == '\n' {
+			i++
+			continue
+		}
+
+		term := searchTerm{}
+		if search[i] == '-' {
+			term.Negated = true
+			i++
+		}
+
+		if i < len(search) && search[i] == '"' {
+			term.Phrase = true
+			i++
+			end := strings.IndexByte(search[i:], '"')
+			if end < 0 {
+				end = len(search) - i
+			}
+			term.Text = strings.Join(strings.Fields(search[i:i+end]), " ")
+			i += end + 1
+		} else {
+			end := strings.IndexAny(search[i:], " \t\n")
+			if end < 0 {
+				end = len(search) - i
+			}
+			term.Text = search[i : i+end]
+			i += end
+		}
+
+		if term.Text != "" {
+			terms = append(terms, term)
+		}
+	}
+
+	return terms
+}
+
+// stopWordsOnly reports whether the term consists of stop words alone,
+// which full-text search ignores
+func (t searchTerm) stopWordsOnly() bool {
+	for _, word := range strings.Fields(strings.ToLower(t.Text)) {
+		if !stopWords[word] {
+			return false
+		}
+	}
+	return true
+}
+
+// tsQuery combines the terms into a Postgres tsquery expression. Plain
+// words are stemmed, phrases must match in order and negated terms are
+// excluded.
+func tsQuery(terms []searchTerm) clause.Expr {
+	parts := make([]string, 0, len(terms))
+	vars := make([]interface{}, 0, len(terms))
+	for _, term := range terms {
+		part := "plainto_tsquery('" + searchConfig + "', ?)"
+		if term.Phrase {
+			part = "phraseto_tsquery('" + searchConfig + "', ?)"
+		}
+		if term.Negated {
+			part = "!!" + part
+		}
+		parts = append(parts, part)
+		vars = append(vars, term.Text)
+	}
+	return gorm.Expr("("+strings.Join(parts, " && ")+")", vars...)
+}
+
+// applySearch filters the query to trips matching the search string. On
+// Postgres this uses the indexed search_vector column; other databases fall
+// back to case-insensitive substring matching with the same syntax. Terms
+// made of stop words alone are ignored by both, so a search of nothing but
+// stop words filters nothing.
+func (r *tripRepository) applySearch(query *gorm.DB, search string) *gorm.DB {
+	terms := parseSearchTerms(search)
+	if len(terms) == 0 {
+		return query
+	}
+
+	if r.db.Dialector.Name() == "postgres" {
+		// Stop words alone leave an empty tsquery, which would match nothing
+		tsq := tsQuery(terms)
+		return query.Where("(numnode(?) = 0 OR search_vector @@ ?)", tsq, tsq)
+	}
+
+	for _, term := range terms {
+		if term.stopWordsOnly() {
+			continue
+		}
+		pattern := "%" + strings.ToLower(term.Text) + "%"
+		if term.Negated {
+			query = query.Where("NOT (LOWER(client_name) LIKE ? OR LOWER(COALESCE(notes, '')) LIKE ?)", pattern, pattern)
+		} else {
+			query = query.Where("(LOWER(client_name) LIKE ? OR LOWER(COALESCE(notes, '')) LIKE ?)", pattern, pattern)
+		}
+	}
+	return query
+}
+
+// searchRank scores how well each trip matches the search string, higher
+// being better. It returns nil when there is nothing to rank by, i.e.
+// without search terms or when the substring fallback is in use.
+func (r *tripRepository) searchRank(search string) clause.Expression {
+	if r.db.Dialector.Name() != "postgres" {
+		return nil
+	}
+	terms := parseSearchTerms(search)
+	if len(terms) == 0 {
+		return nil
+	}
+	return gorm.Expr("ts_rank(search_vector, ?)", tsQuery(terms))
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestParseSearchTerms(t *testing.T) {
+	cases := []struct {
+		name     string
+		search   string
+		expected []searchTerm
+	}{
+		{"words", "acme  meeting", []searchTerm{{Text: "acme"}, {Text: "meeting"}}},
+		{"phrase", `"site visit" acme`, []searchTerm{{Text: "site visit", Phrase: true}, {Text: "acme"}}},
+		{"negated word", "acme -lunch", []searchTerm{{Text: "acme"}, {Text: "lunch", Negated: true}}},
+		{"negated phrase", `-"year end"`, []searchTerm{{Text: "year end", Phrase: true, Negated: true}}},
+		{"unterminated quote", `acme "follow  up`, []searchTerm{{Text: "acme"}, {Text: "follow up", Phrase: true}}},
+		{"hyphenated word", "follow-up", []searchTerm{{Text: "follow-up"}}},
+		{"empty terms", `- "" `, nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, parseSearchTerms(tc.search))
+		})
+	}
+}
+
+func TestSearchTerm_StopWordsOnly(t *testing.T) {
+	assert.True(t, searchTerm{Text: "The"}.stopWordsOnly())
+	assert.True(t, searchTerm{Text: "to be", Phrase: true}.stopWordsOnly())
+	assert.False(t, searchTerm{Text: "end of year", Phrase: true}.stopWordsOnly())
+	assert.False(t, searchTerm{Text: "acme"}.stopWordsOnly())
+}
+
+func TestTripRepository_Search_Postgres(t *testing.T) {
+	// Dry run: statements are built but never sent, so no server is needed
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost sslmode=disable"}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+	repo := &tripRepository{db: db}
+
+	query := repo.buildFilteredQuery(db.Table("trips"), domain.TripFilters{Search: `acme "site visit" -lunch`})
+	stmt := query.Find(&[]domain.Trip{}).Statement
+
+	assert.Equal(t,
+		`SELECT * FROM "trips" WHERE (numnode((plainto_tsquery('english', $1) && phraseto_tsquery('english', $2) && !!plainto_tsquery('english', $3))) = 0 OR search_vector @@ (plainto_tsquery('english', $4) && phraseto_tsquery('english', $5) && !!plainto_tsquery('english', $6)))`,
+		stmt.SQL.String())
+	assert.Equal(t, []interface{}{"acme", "site visit", "lunch", "acme", "site visit", "lunch"}, stmt.Vars)
+	assert.NotNil(t, repo.searchRank("acme"))
+	assert.Nil(t, repo.searchRank(`""`))
+}
@@ -2,25 +2,32 @@ package repository
 
 import (
 	"context"
-	"time"
 
+	"github.com/oscar/mileagetracker/internal/database"
 	"github.com/oscar/mileagetracker/internal/domain"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type SettingsRepository interface {
 	GetByKey(ctx context.Context, key string) (*domain.Settings, error)
-	UpdateByKey(ctx context.Context, key, value string) error
+	// UpsertAll sets the settings in one transaction, inserting those that
+	// are not set yet
+	UpsertAll(ctx context.Context, settings []domain.Settings) error
+	// InsertMissing inserts the settings that are not set yet, leaving
+	// those already set unchanged
+	InsertMissing(ctx context.Context, settings []domain.Settings) error
 	GetAll(ctx context.Context) ([]domain.Settings, error)
 }
 
 type settingsRepository struct {
-	db *gorm.DB
+	store *database.Store
+	db    *gorm.DB
 }
 
-func NewSettingsRepository(db *gorm.DB) SettingsRepository {
-	return &settingsRepository{db: db}
+func NewSettingsRepository(store *database.Store) SettingsRepository {
+	return &settingsRepository{store: store, db: store.DB()}
 }
 
 func (r *settingsRepository) GetByKey(ctx context.Context, key string) (*domain.Settings, error) {
@@ -39,22 +46,53 @@ func (r *settingsRepository) GetByKey(ctx context.Context, key string) (*domain.
 	return &settings, nil
 }
 
-func (r *settingsRepository) UpdateByKey(ctx context.Context, key, value string) error {
+func (r *settingsRepository) UpsertAll(ctx context.Context, settings []domain.Settings) error {
 	monitor := GetQueryPerformanceMonitor()
-	defer monitor.MonitorQuery(OpUpdateByKey, "settings", zap.String("key", key))()
+	defer monitor.MonitorQuery(OpUpsert, "settings", zap.Int("count", len(settings)))()
 
-	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpUpdateByKey))
+	if len(settings) == 0 {
+		return nil
+	}
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpUpsert))
 	defer cancel()
 
-	// Update with current timestamp
-	result := r.db.WithContext(ctxWithTimeout).Model(&domain.Settings{}).
-		Where("key = ?", key).
-		Updates(map[string]interface{}{
-			"value":      value,
-			"updated_at": time.Now(),
-		})
+	now := r.db.NowFunc()
+	return r.store.Transaction(ctxWithTimeout, func(tx *gorm.DB) error {
+		for _, setting := range settings {
+			setting.UpdatedAt = now
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "key"}},
+				DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+			}).Create(&setting).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *settingsRepository) InsertMissing(ctx context.Context, settings []domain.Settings) error {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpBulkUpsert, "settings", zap.Int("count", len(settings)))()
+
+	if len(settings) == 0 {
+		return nil
+	}
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpBulkUpsert))
+	defer cancel()
+
+	now := r.db.NowFunc()
+	for i := range settings {
+		settings[i].UpdatedAt = now
+	}
 
-	return result.Error
+	return r.db.WithContext(ctxWithTimeout).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoNothing: true,
+	}).Create(&settings).Error
 }
 
 func (r *settingsRepository) GetAll(ctx context.Context) ([]domain.Settings, error) {
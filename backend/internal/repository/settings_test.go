@@ -4,21 +4,19 @@ import (
 	"context"
 	"testing"
 
+	"github.com/oscar/mileagetracker/internal/database"
 	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/testutils"
 	"github.com/stretchr/testify/assert"
-	"gorm.io/driver/sqlite"
+	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
 func setupSettingsTestDB(t *testing.T) *gorm.DB {
-	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent),
-	})
-	assert.NoError(t, err)
+	db := testutils.SetupTestDB(t)
 
-	// Migrate the schema
-	err = db.AutoMigrate(&domain.Settings{})
+	// Start without the defaults the migrations seed
+	err := db.Exec("DELETE FROM settings").Error
 	assert.NoError(t, err)
 
 	return db
@@ -26,7 +24,7 @@ func setupSettingsTestDB(t *testing.T) *gorm.DB {
 
 func TestSettingsRepository_GetByKey(t *testing.T) {
 	db := setupSettingsTestDB(t)
-	repo := NewSettingsRepository(db)
+	repo := NewSettingsRepository(database.NewStore(db))
 
 	t.Run("should get setting by key", func(t *testing.T) {
 		// Create test setting directly in DB
@@ -52,9 +50,9 @@ func TestSettingsRepository_GetByKey(t *testing.T) {
 	})
 }
 
-func TestSettingsRepository_UpdateByKey(t *testing.T) {
+func TestSettingsRepository_UpsertAll(t *testing.T) {
 	db := setupSettingsTestDB(t)
-	repo := NewSettingsRepository(db)
+	repo := NewSettingsRepository(database.NewStore(db))
 
 	t.Run("should update existing setting", func(t *testing.T) {
 		// Create initial setting
@@ -63,31 +61,69 @@ func TestSettingsRepository_UpdateByKey(t *testing.T) {
 		assert.NoError(t, err)
 
 		// Update the setting
-		err = repo.UpdateByKey(context.Background(), "update_key", "updated_value")
+		err = repo.UpsertAll(context.Background(), []domain.Settings{{Key: "update_key", Value: "updated_value"}})
 		assert.NoError(t, err)
 
 		// Verify the update
 		found, err := repo.GetByKey(context.Background(), "update_key")
 		assert.NoError(t, err)
 		assert.Equal(t, "updated_value", found.Value)
+		assert.Equal(t, setting.ID, found.ID)
 	})
 
-	t.Run("should handle upsert behavior", func(t *testing.T) {
-		// UpdateByKey uses upsert logic which may behave differently across databases
-		// In production PostgreSQL this creates if not exists, updates if exists
-		err := repo.UpdateByKey(context.Background(), "upsert_key", "upsert_value")
+	t.Run("should insert a setting that is not set", func(t *testing.T) {
+		err := repo.UpsertAll(context.Background(), []domain.Settings{{Key: "upsert_key", Value: "upsert_value"}})
+		assert.NoError(t, err)
 
-		// This may or may not work depending on the database engine
-		t.Logf("UpdateByKey upsert test: err=%v", err)
+		found, err := repo.GetByKey(context.Background(), "upsert_key")
+		assert.NoError(t, err)
+		assert.Equal(t, "upsert_value", found.Value)
+	})
 
-		// Don't assert specific behavior since it's database-dependent
-		// This test documents that upsert functionality exists
+	t.Run("should change no setting when one fails", func(t *testing.T) {
+		if db.Dialector.Name() != "sqlite" {
+			t.Skip("rejects the write with a SQLite trigger")
+		}
+		require.NoError(t, db.Exec(`CREATE TRIGGER reject_setting BEFORE INSERT ON settings
+			WHEN NEW.key = 'rejected_key' BEGIN SELECT RAISE(ABORT, 'rejected'); END`).Error)
+		defer db.Exec("DROP TRIGGER reject_setting")
+
+		err := repo.UpsertAll(context.Background(), []domain.Settings{
+			{Key: "update_key", Value: "lost_value"},
+			{Key: "rejected_key", Value: "value"},
+		})
+		assert.Error(t, err)
+
+		found, err := repo.GetByKey(context.Background(), "update_key")
+		assert.NoError(t, err)
+		assert.Equal(t, "updated_value", found.Value)
 	})
 }
 
+func TestSettingsRepository_InsertMissing(t *testing.T) {
+	db := setupSettingsTestDB(t)
+	repo := NewSettingsRepository(database.NewStore(db))
+
+	assert.NoError(t, db.Create(&domain.Settings{Key: "mileage_rate", Value: "0.7"}).Error)
+
+	err := repo.InsertMissing(context.Background(), []domain.Settings{
+		{Key: "mileage_rate", Value: "0.67"},
+		{Key: "distance_unit", Value: "mi"},
+	})
+	assert.NoError(t, err)
+
+	all, err := repo.GetAll(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, all, 2)
+	assert.Equal(t, "mi", all[0].Value)  // distance_unit, inserted
+	assert.Equal(t, "0.7", all[1].Value) // mileage_rate, left as set
+
+	assert.NoError(t, repo.InsertMissing(context.Background(), nil))
+}
+
 func TestSettingsRepository_GetAll(t *testing.T) {
 	db := setupSettingsTestDB(t)
-	repo := NewSettingsRepository(db)
+	repo := NewSettingsRepository(database.NewStore(db))
 
 	t.Run("should get all settings", func(t *testing.T) {
 		// Create test settings
@@ -121,7 +157,7 @@ func TestSettingsRepository_GetAll(t *testing.T) {
 	t.Run("should return empty slice when no settings", func(t *testing.T) {
 		// Use fresh DB
 		freshDB := setupSettingsTestDB(t)
-		freshRepo := NewSettingsRepository(freshDB)
+		freshRepo := NewSettingsRepository(database.NewStore(freshDB))
 
 		all, err := freshRepo.GetAll(context.Background())
 
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oscar/mileagetracker/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// noStartTime stands in for a missing start time, which sorts below every
+// other one. Postgres reads it as a timestamp, while SQLite, which keeps
+// times as text, compares it as a string that sorts before any date.
+const noStartTime = "-infinity"
+
+// sortColumns maps the sortable fields to the expressions they sort by.
+// Amount is miles times a single mileage rate, so it sorts like miles.
+var sortColumns = map[domain.SortField]string{
+	domain.SortByTripDate:   "trip_date",
+	domain.SortByMiles:      "miles",
+	domain.SortByClientName: "LOWER(client_name)",
+	domain.SortByCreatedAt:  "created_at",
+	domain.SortByUpdatedAt:  "updated_at",
+	domain.SortByAmount:     "miles",
+	domain.SortByStartTime:  "COALESCE(start_time, '" + noStartTime + "')",
+}
+
+// sortOrder builds the ORDER BY list for the sort keys. The trip ID breaks
+// ties in the direction of the last key, so an index on (column, id) serves
+// a single-key sort in either direction.
+func sortOrder(keys []domain.SortKey) string {
+	parts := make([]string, 0, len(keys)+1)
+	for _, key := range keys {
+		parts = append(parts, sortColumns[key.Field]+direction(key.Desc))
+	}
+	return strings.Join(append(parts, "id"+direction(tieBreakDesc(keys))), ", ")
+}
+
+// keysetCondition selects the trips after the cursor in the given sort
+func keysetCondition(keys []domain.SortKey, cursor domain.TripCursor) (clause.Expr, error) {
+	if len(cursor.Values) != len(keys) {
+		return clause.Expr{}, fmt.Errorf("cursor has %d values for %d sort keys", len(cursor.Values), len(keys))
+	}
+
+	columns := make([]string, 0, len(keys)+1)
+	params := make([]string, 0, len(keys)+1)
+	descs := make([]bool, 0, len(keys)+1)
+	vars := make([]interface{}, 0, len(keys)+1)
+	for i, key := range keys {
+		value, err := key.Field.ParseValue(cursor.Values[i])
+		if err != nil {
+			return clause.Expr{}, err
+		}
+		if value == nil {
+			value = noStartTime
+		}
+		// Stored times are in UTC, and SQLite compares them as text
+		if t, ok := value.(time.Time); ok {
+			value = t.UTC()
+		}
+		column := sortColumns[key.Field]
+		param := "?"
+		if strings.HasPrefix(column, "LOWER(") {
+			param = "LOWER(?)"
+		}
+		columns = append(columns, column)
+		params = append(params, param)
+		descs = append(descs, key.Desc)
+		vars = append(vars, value)
+	}
+	columns = append(columns, "id")
+	params = append(params, "?")
+	descs = append(descs, tieBreakDesc(keys))
+	vars = append(vars, cursor.ID)
+
+	sameDirection := true
+	for _, desc := range descs {
+		sameDirection = sameDirection && desc == descs[0]
+	}
+
+	// A row value comparison lets the database seek straight to the cursor
+	// in a matching index; mixed directions need the expanded form
+	if sameDirection {
+		sql := "(" + strings.Join(columns, ", ") + ") " + comparison(descs[0]) + " (" + strings.Join(params, ", ") + ")"
+		return gorm.Expr(sql, vars...), nil
+	}
+
+	var alternatives []string
+	var expandedVars []interface{}
+	for i := range columns {
+		var terms []string
+		for j := 0; j < i; j++ {
+			terms = append(terms, columns[j]+" = "+params[j])
+			expandedVars = append(expandedVars, vars[j])
+		}
+		terms = append(terms, columns[i]+" "+comparison(descs[i])+" "+params[i])
+		expandedVars = append(expandedVars, vars[i])
+		alternatives = append(alternatives, "("+strings.Join(terms, " AND ")+")")
+	}
+	return gorm.Expr("("+strings.Join(alternatives, " OR ")+")", expandedVars...), nil
+}
+
+func tieBreakDesc(keys []domain.SortKey) bool {
+	if len(keys) == 0 {
+		return true
+	}
+	return keys[len(keys)-1].Desc
+}
+
+func direction(desc bool) string {
+	if desc {
+		return " DESC"
+	}
+	return " ASC"
+}
+
+func comparison(desc bool) string {
+	if desc {
+		return "<"
+	}
+	return ">"
+}
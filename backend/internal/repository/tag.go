@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/oscar/mileagetracker/internal/database"
+	"github.com/oscar/mileagetracker/internal/domain"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type TagRepository interface {
+	Create(ctx context.Context, tag *domain.Tag) error
+	Update(ctx context.Context, tag *domain.Tag) error
+	Delete(ctx context.Context, id uint) error
+	FindByID(ctx context.Context, id uint) (*domain.Tag, error)
+	FindByName(ctx context.Context, name string) (*domain.Tag, error)
+	FindByNames(ctx context.Context, names []string) ([]domain.Tag, error)
+	GetAll(ctx context.Context) ([]domain.Tag, error)
+}
+
+type tagRepository struct {
+	store *database.Store
+	db    *gorm.DB
+}
+
+func NewTagRepository(store *database.Store) TagRepository {
+	return &tagRepository{store: store, db: store.DB()}
+}
+
+func (r *tagRepository) Create(ctx context.Context, tag *domain.Tag) error {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpCreate, "tag")()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpCreate))
+	defer cancel()
+
+	err := r.db.WithContext(ctxWithTimeout).Create(tag).Error
+	return translateDuplicateKey(r.db, err)
+}
+
+func (r *tagRepository) Update(ctx context.Context, tag *domain.Tag) error {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpUpdate, "tag", zap.Uint("id", tag.ID))()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpUpdate))
+	defer cancel()
+
+	err := r.db.WithContext(ctxWithTimeout).Save(tag).Error
+	return translateDuplicateKey(r.db, err)
+}
+
+// Delete deletes a tag and removes it from every trip carrying it
+func (r *tagRepository) Delete(ctx context.Context, id uint) error {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpDelete, "tag", zap.Uint("id", id))()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpDelete))
+	defer cancel()
+
+	return r.store.Transaction(ctxWithTimeout, func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM trip_tags WHERE tag_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&domain.Tag{}, id).Error
+	})
+}
+
+func (r *tagRepository) FindByID(ctx context.Context, id uint) (*domain.Tag, error) {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpFindByID, "tag", zap.Uint("id", id))()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpFindByID))
+	defer cancel()
+
+	var tag domain.Tag
+	err := r.db.WithContext(ctxWithTimeout).First(&tag, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// FindByName looks up a tag by its exact, normalized name
+func (r *tagRepository) FindByName(ctx context.Context, name string) (*domain.Tag, error) {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpFindByName, "tag", zap.String("name", name))()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpFindByName))
+	defer cancel()
+
+	var tag domain.Tag
+	err := r.db.WithContext(ctxWithTimeout).Where("name = ?", name).First(&tag).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// FindByNames returns the tags with any of the given names, ordered by
+// name. Names without a tag are skipped.
+func (r *tagRepository) FindByNames(ctx context.Context, names []string) ([]domain.Tag, error) {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpFindByName, "tag", zap.Strings("names", names))()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpFindByName))
+	defer cancel()
+
+	tags := []domain.Tag{}
+	if len(names) == 0 {
+		return tags, nil
+	}
+	err := r.db.WithContext(ctxWithTimeout).Where("name IN ?", names).Order("name ASC").Find(&tags).Error
+	return tags, err
+}
+
+func (r *tagRepository) GetAll(ctx context.Context) ([]domain.Tag, error) {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpGetAll, "tag")()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpGetAll))
+	defer cancel()
+
+	tags := []domain.Tag{}
+	err := r.db.WithContext(ctxWithTimeout).Order("name ASC").Find(&tags).Error
+	return tags, err
+}
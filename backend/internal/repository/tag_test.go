@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/oscar/mileagetracker/internal/database"
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestTagRepository_CRUD(t *testing.T) {
+	db := testutils.SetupTestDB(t)
+	repo := NewTagRepository(database.NewStore(db))
+	ctx := context.Background()
+
+	billable := &domain.Tag{Name: "billable"}
+	conference := &domain.Tag{Name: "conference"}
+	require.NoError(t, repo.Create(ctx, conference))
+	require.NoError(t, repo.Create(ctx, billable))
+
+	t.Run("should list tags by name", func(t *testing.T) {
+		tags, err := repo.GetAll(ctx)
+		require.NoError(t, err)
+		require.Len(t, tags, 2)
+		assert.Equal(t, "billable", tags[0].Name)
+		assert.Equal(t, "conference", tags[1].Name)
+	})
+
+	t.Run("should find by names, skipping unknown ones", func(t *testing.T) {
+		tags, err := repo.FindByNames(ctx, []string{"conference", "site-visit", "billable"})
+		require.NoError(t, err)
+		require.Len(t, tags, 2)
+		assert.Equal(t, billable.ID, tags[0].ID)
+
+		found, err := repo.FindByName(ctx, "conference")
+		require.NoError(t, err)
+		assert.Equal(t, conference.ID, found.ID)
+	})
+
+	t.Run("should report a name that is already taken", func(t *testing.T) {
+		assert.ErrorIs(t, repo.Create(ctx, &domain.Tag{Name: "billable"}), gorm.ErrDuplicatedKey)
+
+		renamed := *conference
+		renamed.Name = "billable"
+		assert.ErrorIs(t, repo.Update(ctx, &renamed), gorm.ErrDuplicatedKey)
+	})
+
+	t.Run("should remove a deleted tag from trips", func(t *testing.T) {
+		tripRepo := NewTripRepository(database.NewStore(db))
+		trip := &domain.Trip{ClientName: "Acme", TripDate: "2025-01-15", Miles: 10, Tags: []domain.Tag{*billable, *conference}}
+		require.NoError(t, tripRepo.Create(ctx, trip))
+
+		require.NoError(t, repo.Delete(ctx, conference.ID))
+
+		_, err := repo.FindByID(ctx, conference.ID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		found, err := tripRepo.FindByID(ctx, trip.ID)
+		require.NoError(t, err)
+		require.Len(t, found.Tags, 1)
+		assert.Equal(t, "billable", found.Tags[0].Name)
+	})
+}
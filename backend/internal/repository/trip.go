@@ -3,36 +3,45 @@ package repository
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/oscar/mileagetracker/internal/database"
 	"github.com/oscar/mileagetracker/internal/domain"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TripRepository interface {
 	Create(ctx context.Context, trip *domain.Trip) error
+	CreateAll(ctx context.Context, trips []domain.Trip) error
 	Update(ctx context.Context, trip *domain.Trip) error
 	Delete(ctx context.Context, id uint) error
 	FindByID(ctx context.Context, id uint) (*domain.Trip, error)
 	GetPaginated(ctx context.Context, page, limit int, filters domain.TripFilters) ([]domain.Trip, int64, error)
-	GetMonthlySummary(ctx context.Context, startDate, endDate string) ([]domain.MonthlySummary, error)
+	GetPageAfter(ctx context.Context, after *domain.TripCursor, limit int, filters domain.TripFilters) ([]domain.Trip, error)
+	GetSummaryBuckets(ctx context.Context, granularity domain.Granularity, from, to string, filters domain.TripFilters, tripMiles bool) ([]domain.SummaryBucket, error)
+	GetGroupedSummaryBuckets(ctx context.Context, granularity domain.Granularity, groupBy domain.SummaryDimension, from, to string, filters domain.TripFilters, tripMiles bool) ([]domain.SummaryGroupRow, error)
+	GetExpenseTotals(ctx context.Context, groupBy domain.SummaryDimension, from, to string, filters domain.TripFilters) ([]domain.ExpenseTotalRow, error)
 }
 
 type tripRepository struct {
-	db *gorm.DB
+	store *database.Store
+	db    *gorm.DB
 }
 
-func NewTripRepository(db *gorm.DB) TripRepository {
-	return &tripRepository{db: db}
+func NewTripRepository(store *database.Store) TripRepository {
+	return &tripRepository{store: store, db: store.DB()}
 }
 
 // buildFilteredQuery applies filters to a GORM query
 func (r *tripRepository) buildFilteredQuery(query *gorm.DB, filters domain.TripFilters) *gorm.DB {
-	// Search filter - search in client_name and notes
+	// Search filter - full-text search in client_name and notes
 	if filters.Search != "" {
-		searchTerm := "%" + strings.ToLower(filters.Search) + "%"
-		query = query.Where("LOWER(client_name) LIKE ? OR LOWER(notes) LIKE ?", searchTerm, searchTerm)
+		query = r.applySearch(query, filters.Search)
 	}
 
 	// Client filter - exact match (case insensitive)
@@ -56,9 +65,97 @@ func (r *tripRepository) buildFilteredQuery(query *gorm.DB, filters domain.TripF
 		query = query.Where("miles <= ?", *filters.MaxMiles)
 	}
 
+	// Client lists - case insensitive, like the single client filter
+	if len(filters.Clients) > 0 {
+		query = query.Where("LOWER(client_name) IN ?", lowerAll(filters.Clients))
+	}
+	if len(filters.ExcludeClients) > 0 {
+		query = query.Where("LOWER(client_name) NOT IN ?", lowerAll(filters.ExcludeClients))
+	}
+
+	// ID lists
+	if len(filters.IDs) > 0 {
+		query = query.Where("id IN ?", filters.IDs)
+	}
+	if len(filters.ExcludeIDs) > 0 {
+		query = query.Where("id NOT IN ?", filters.ExcludeIDs)
+	}
+
+	// Tags - subqueries rather than joins keep one row per trip
+	if len(filters.Tags) > 0 {
+		query = query.Where("id IN (?)", taggedTripIDs(r.db, filters.Tags))
+	}
+	if len(filters.AllTags) > 0 {
+		names := distinct(lowerAll(filters.AllTags))
+		query = query.Where("id IN (?)", taggedTripIDs(r.db, names).
+			Group("trip_tags.trip_id").
+			Having("COUNT(DISTINCT trip_tags.tag_id) = ?", len(names)))
+	}
+	if len(filters.ExcludeTags) > 0 {
+		query = query.Where("id NOT IN (?)", taggedTripIDs(r.db, filters.ExcludeTags))
+	}
+
+	// Notes presence - blank notes count as no notes
+	if filters.HasNotes != nil {
+		if *filters.HasNotes {
+			query = query.Where("TRIM(COALESCE(notes, '')) <> ''")
+		} else {
+			query = query.Where("TRIM(COALESCE(notes, '')) = ''")
+		}
+	}
+
+	// Record timestamp ranges - after is inclusive, before exclusive
+	query = whereTimeBound(query, "created_at >= ?", filters.CreatedAfter)
+	query = whereTimeBound(query, "created_at < ?", filters.CreatedBefore)
+	query = whereTimeBound(query, "updated_at >= ?", filters.UpdatedAfter)
+	query = whereTimeBound(query, "updated_at < ?", filters.UpdatedBefore)
+
 	return query
 }
 
+// whereTimeBound adds the condition for a created/updated bound when it is
+// set. Dates the service has not resolved to timestamps are taken as UTC.
+func whereTimeBound(query *gorm.DB, condition, value string) *gorm.DB {
+	if value == "" {
+		return query
+	}
+	bound, err := domain.ParseTimeBound(value, time.UTC)
+	if err != nil {
+		query.AddError(fmt.Errorf("invalid time bound %q: %w", value, err))
+		return query
+	}
+	return query.Where(condition, bound.UTC())
+}
+
+// taggedTripIDs selects the IDs of the trips carrying any of the named tags
+func taggedTripIDs(db *gorm.DB, names []string) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).
+		Table("trip_tags").
+		Select("trip_tags.trip_id").
+		Joins("JOIN tags ON tags.id = trip_tags.tag_id").
+		Where("tags.name IN ?", lowerAll(names))
+}
+
+func distinct(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+func lowerAll(values []string) []string {
+	lowered := make([]string, len(values))
+	for i, value := range values {
+		lowered[i] = strings.ToLower(value)
+	}
+	return lowered
+}
+
 func (r *tripRepository) Create(ctx context.Context, trip *domain.Trip) error {
 	monitor := GetQueryPerformanceMonitor()
 	defer monitor.MonitorQuery(OpCreate, "trip")()
@@ -66,7 +163,39 @@ func (r *tripRepository) Create(ctx context.Context, trip *domain.Trip) error {
 	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpCreate))
 	defer cancel()
 
-	return r.db.WithContext(ctxWithTimeout).Create(trip).Error
+	return r.store.Transaction(ctxWithTimeout, func(tx *gorm.DB) error {
+		return createTrip(tx, trip)
+	})
+}
+
+// CreateAll creates trips in a single transaction, so either every trip is
+// saved or none is
+func (r *tripRepository) CreateAll(ctx context.Context, trips []domain.Trip) error {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpCreate, "trip", zap.Int("count", len(trips)))()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpCreate))
+	defer cancel()
+
+	return r.store.Transaction(ctxWithTimeout, func(tx *gorm.DB) error {
+		for i := range trips {
+			if err := createTrip(tx, &trips[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// createTrip inserts a trip with its tags and expenses
+func createTrip(tx *gorm.DB, trip *domain.Trip) error {
+	if err := tx.Omit("Tags", "Expenses").Create(trip).Error; err != nil {
+		return err
+	}
+	if err := replaceTripTags(tx, trip.ID, trip.Tags); err != nil {
+		return err
+	}
+	return replaceTripExpenses(tx, trip.ID, trip.Expenses)
 }
 
 func (r *tripRepository) Update(ctx context.Context, trip *domain.Trip) error {
@@ -76,7 +205,47 @@ func (r *tripRepository) Update(ctx context.Context, trip *domain.Trip) error {
 	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpUpdate))
 	defer cancel()
 
-	return r.db.WithContext(ctxWithTimeout).Save(trip).Error
+	return r.store.Transaction(ctxWithTimeout, func(tx *gorm.DB) error {
+		if err := tx.Omit("Tags", "Expenses").Save(trip).Error; err != nil {
+			return err
+		}
+		if err := replaceTripTags(tx, trip.ID, trip.Tags); err != nil {
+			return err
+		}
+		return replaceTripExpenses(tx, trip.ID, trip.Expenses)
+	})
+}
+
+// replaceTripTags makes tags, which must already exist, the trip's only tags
+func replaceTripTags(tx *gorm.DB, tripID uint, tags []domain.Tag) error {
+	if err := tx.Exec("DELETE FROM trip_tags WHERE trip_id = ?", tripID).Error; err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	rows := make([]map[string]interface{}, len(tags))
+	for i, tag := range tags {
+		rows[i] = map[string]interface{}{"trip_id": tripID, "tag_id": tag.ID}
+	}
+	return tx.Table("trip_tags").Create(rows).Error
+}
+
+// replaceTripExpenses makes expenses the trip's only expenses. Expenses
+// that were already saved keep their IDs.
+func replaceTripExpenses(tx *gorm.DB, tripID uint, expenses []domain.Expense) error {
+	if err := tx.Where("trip_id = ?", tripID).Delete(&domain.Expense{}).Error; err != nil {
+		return err
+	}
+	if len(expenses) == 0 {
+		return nil
+	}
+
+	for i := range expenses {
+		expenses[i].TripID = tripID
+	}
+	return tx.Create(&expenses).Error
 }
 
 func (r *tripRepository) Delete(ctx context.Context, id uint) error {
@@ -86,7 +255,18 @@ func (r *tripRepository) Delete(ctx context.Context, id uint) error {
 	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpDelete))
 	defer cancel()
 
-	return r.db.WithContext(ctxWithTimeout).Delete(&domain.Trip{}, id).Error
+	return r.store.Transaction(ctxWithTimeout, func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM trip_tags WHERE trip_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM attachments WHERE trip_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM expenses WHERE trip_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&domain.Trip{}, id).Error
+	})
 }
 
 func (r *tripRepository) FindByID(ctx context.Context, id uint) (*domain.Trip, error) {
@@ -97,7 +277,10 @@ func (r *tripRepository) FindByID(ctx context.Context, id uint) (*domain.Trip, e
 	defer cancel()
 
 	var trip domain.Trip
-	err := r.db.WithContext(ctxWithTimeout).First(&trip, id).Error
+	err := r.db.WithContext(ctxWithTimeout).
+		Preload("Tags", orderTagsByName).
+		Preload("Expenses", orderExpenses).
+		First(&trip, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -130,9 +313,18 @@ func (r *tripRepository) GetPaginated(ctx context.Context, page, limit int, filt
 		TotalCount int64 `gorm:"column:total_count"`
 	}
 
+	order := sortOrder(domain.DefaultTripSort)
+	if len(filters.Sort) > 0 {
+		filteredQuery = filteredQuery.Order(sortOrder(filters.Sort))
+	} else if rank := r.searchRank(filters.Search); rank != nil {
+		// Best full-text matches first when searching
+		filteredQuery = filteredQuery.Clauses(clause.OrderBy{Expression: gorm.Expr("? DESC, "+order, rank)})
+	} else {
+		filteredQuery = filteredQuery.Order(order)
+	}
+
 	err := filteredQuery.
 		Select("*, COUNT(*) OVER() as total_count").
-		Order("trip_date DESC, created_at DESC").
 		Offset(offset).
 		Limit(limit).
 		Scan(&tripsWithCount).Error
@@ -152,69 +344,385 @@ func (r *tripRepository) GetPaginated(ctx context.Context, page, limit int, filt
 		total = item.TotalCount
 	}
 
-	return trips, total, err
+	// Scan does not preload, so the page's tags and expenses are fetched
+	// separately
+	if err := loadTags(r.db.WithContext(ctxWithTimeout), trips); err != nil {
+		return nil, 0, err
+	}
+	if err := loadExpenses(r.db.WithContext(ctxWithTimeout), trips); err != nil {
+		return nil, 0, err
+	}
+
+	return trips, total, nil
+}
+
+// orderTagsByName lists preloaded tags alphabetically
+func orderTagsByName(db *gorm.DB) *gorm.DB {
+	return db.Order("tags.name ASC")
+}
+
+// loadTags fills in the tags of the given trips with a single query
+func loadTags(db *gorm.DB, trips []domain.Trip) error {
+	ids := make([]uint, len(trips))
+	index := make(map[uint]int, len(trips))
+	for i, trip := range trips {
+		ids[i] = trip.ID
+		index[trip.ID] = i
+	}
+
+	var rows []struct {
+		TripID uint
+		domain.Tag
+	}
+	err := db.Table("trip_tags").
+		Select("trip_tags.trip_id, tags.*").
+		Joins("JOIN tags ON tags.id = trip_tags.tag_id").
+		Where("trip_tags.trip_id IN ?", ids).
+		Order("tags.name ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return fmt.Errorf("failed to load trip tags: %w", err)
+	}
+
+	for _, row := range rows {
+		i := index[row.TripID]
+		trips[i].Tags = append(trips[i].Tags, row.Tag)
+	}
+	return nil
+}
+
+// orderExpenses lists preloaded expenses in the order they were entered
+func orderExpenses(db *gorm.DB) *gorm.DB {
+	return db.Order("expenses.id ASC")
+}
+
+// loadExpenses fills in the expenses of the given trips with a single query
+func loadExpenses(db *gorm.DB, trips []domain.Trip) error {
+	ids := make([]uint, len(trips))
+	index := make(map[uint]int, len(trips))
+	for i, trip := range trips {
+		ids[i] = trip.ID
+		index[trip.ID] = i
+	}
+
+	var expenses []domain.Expense
+	err := db.Where("trip_id IN ?", ids).Order("id ASC").Find(&expenses).Error
+	if err != nil {
+		return fmt.Errorf("failed to load trip expenses: %w", err)
+	}
+
+	for _, expense := range expenses {
+		i := index[expense.TripID]
+		trips[i].Expenses = append(trips[i].Expenses, expense)
+	}
+	return nil
 }
 
-func (r *tripRepository) GetMonthlySummary(ctx context.Context, startDate, endDate string) ([]domain.MonthlySummary, error) {
+// GetPageAfter returns up to limit filtered trips following the cursor in
+// the filters' sort order, or in domain.DefaultTripSort when none is given.
+// A nil cursor starts at the first trip. Unlike GetPaginated it neither
+// counts nor skips rows, so its cost does not grow with the page number.
+func (r *tripRepository) GetPageAfter(ctx context.Context, after *domain.TripCursor, limit int, filters domain.TripFilters) ([]domain.Trip, error) {
 	monitor := GetQueryPerformanceMonitor()
-	defer monitor.MonitorQuery(OpGetMonthlySummary, "trip", zap.String("start_date", startDate), zap.String("end_date", endDate))()
+	defer monitor.MonitorQuery(OpGetPage, "trip", zap.Int("limit", limit))()
 
-	var results []domain.MonthlySummary
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpGetPage))
+	defer cancel()
+
+	keys := filters.Sort
+	if len(keys) == 0 {
+		keys = domain.DefaultTripSort
+	}
+
+	query := r.buildFilteredQuery(r.db.WithContext(ctxWithTimeout).Model(&domain.Trip{}), filters)
+	if after != nil {
+		condition, err := keysetCondition(keys, *after)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where(condition)
+	}
+
+	trips := []domain.Trip{}
+	err := query.
+		Preload("Tags", orderTagsByName).
+		Preload("Expenses", orderExpenses).
+		Order(sortOrder(keys)).
+		Limit(limit).
+		Find(&trips).Error
+	if err != nil {
+		return nil, err
+	}
+	return trips, nil
+}
+
+// GetSummaryBuckets totals the filtered trips between from and to, grouped
+// into buckets of the given granularity. Only buckets containing trips are
+// returned, oldest first, with StartDate set to the first day of the bucket.
+// With tripMiles, each bucket also lists the miles of its trips, for
+// rounding amounts trip by trip; otherwise TripMiles is left nil.
+func (r *tripRepository) GetSummaryBuckets(
+	ctx context.Context,
+	granularity domain.Granularity,
+	from, to string,
+	filters domain.TripFilters,
+	tripMiles bool,
+) ([]domain.SummaryBucket, error) {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpGetSummary, "trip",
+		zap.String("granularity", string(granularity)), zap.String("from", from), zap.String("to", to))()
+
+	rows, err := r.aggregateSummary(ctx, granularity, "", from, to, filters, tripMiles)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make([]domain.SummaryBucket, len(rows))
+	for i, row := range rows {
+		buckets[i] = domain.SummaryBucket{
+			StartDate:    row.StartDate,
+			TripCount:    row.TripCount,
+			TotalMiles:   row.TotalMiles,
+			TotalMinutes: row.TotalMinutes,
+			TripMiles:    row.TripMiles,
+		}
+	}
+
+	return buckets, nil
+}
+
+// GetGroupedSummaryBuckets is GetSummaryBuckets broken down by a trip
+// attribute. Each returned row holds one group's totals within one bucket.
+func (r *tripRepository) GetGroupedSummaryBuckets(
+	ctx context.Context,
+	granularity domain.Granularity,
+	groupBy domain.SummaryDimension,
+	from, to string,
+	filters domain.TripFilters,
+	tripMiles bool,
+) ([]domain.SummaryGroupRow, error) {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpGetSummary, "trip",
+		zap.String("granularity", string(granularity)), zap.String("group_by", string(groupBy)),
+		zap.String("from", from), zap.String("to", to))()
+
+	groupExpr, err := summaryGroupExpr(groupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.aggregateSummary(ctx, granularity, groupExpr, from, to, filters, tripMiles)
+}
+
+// GetExpenseTotals totals the expenses of the filtered trips between from
+// and to per trip date and currency, additionally grouped by groupBy when
+// it is not empty. Rows are ordered by date.
+func (r *tripRepository) GetExpenseTotals(
+	ctx context.Context,
+	groupBy domain.SummaryDimension,
+	from, to string,
+	filters domain.TripFilters,
+) ([]domain.ExpenseTotalRow, error) {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpGetSummary, "expense",
+		zap.String("group_by", string(groupBy)), zap.String("from", from), zap.String("to", to))()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpGetSummary))
+	defer cancel()
+
+	var groupExpr string
+	if groupBy != "" {
+		var err error
+		if groupExpr, err = summaryGroupExpr(groupBy); err != nil {
+			return nil, err
+		}
+	}
+
+	dateExpr, err := r.bucketStartExpr(domain.GranularityDay)
+	if err != nil {
+		return nil, err
+	}
+
+	selectExpr := dateExpr + " AS trip_date, " +
+		"expenses.currency AS currency, " +
+		"COALESCE(SUM(expenses.amount), 0) AS amount, " +
+		"COUNT(*) AS expense_count"
+	groupByExpr := "trip_date, currency"
+	if groupExpr != "" {
+		selectExpr += ", COALESCE(" + groupExpr + ", '') AS \"group\""
+		groupByExpr = "trip_date, \"group\", currency"
+	}
+
+	// Filter the trips before joining so the filter columns stay unambiguous
+	filtered := r.buildFilteredQuery(r.db.WithContext(ctxWithTimeout).Table("trips"), filters).
+		Where("trip_date >= ? AND trip_date <= ?", from, to)
+	query := r.db.WithContext(ctxWithTimeout).
+		Table("(?) AS trips", filtered).
+		Joins("JOIN expenses ON expenses.trip_id = trips.id")
+	if groupExpr == tagGroupExpr {
+		query = query.
+			Joins("LEFT JOIN trip_tags ON trip_tags.trip_id = trips.id").
+			Joins("LEFT JOIN tags ON tags.id = trip_tags.tag_id")
+	}
+
+	var rows []domain.ExpenseTotalRow
+	err = query.
+		Select(selectExpr).
+		Group(groupByExpr).
+		Order(groupByExpr).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expense totals: %w", err)
+	}
+
+	return rows, nil
+}
 
+// tagGroupExpr groups by tag. Trips are joined to their tags, one row per
+// tag, by the summary queries.
+const tagGroupExpr = "tags.name"
+
+// summaryGroupExpr returns the SQL expression giving a trip's group
+func summaryGroupExpr(groupBy domain.SummaryDimension) (string, error) {
+	switch groupBy {
+	case domain.SummaryByClient:
+		return "client_name", nil
+	case domain.SummaryByFromLocation:
+		// A subquery rather than a join keeps the filter columns unambiguous
+		return "(SELECT label FROM locations WHERE locations.id = trips.from_location_id)", nil
+	case domain.SummaryByToLocation:
+		return "(SELECT label FROM locations WHERE locations.id = trips.to_location_id)", nil
+	case domain.SummaryByTag:
+		return tagGroupExpr, nil
+	}
+	return "", fmt.Errorf("unsupported summary dimension %q", groupBy)
+}
+
+// aggregateSummary runs the bucketed aggregation behind both summary
+// methods, additionally grouping by groupExpr when it is not empty. The
+// list of every trip's miles is only built when tripMiles asks for it, as
+// it grows with the number of trips.
+func (r *tripRepository) aggregateSummary(
+	ctx context.Context,
+	granularity domain.Granularity,
+	groupExpr string,
+	from, to string,
+	filters domain.TripFilters,
+	tripMiles bool,
+) ([]domain.SummaryGroupRow, error) {
 	// Add query timeout for aggregation queries
-	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpGetMonthlySummary))
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpGetSummary))
 	defer cancel()
 
-	// Optimized query that works with both PostgreSQL and SQLite
-	// Uses the new composite index for better performance
-	query := `
-		SELECT 
-			strftime('%Y-%m', trip_date) as month_key,
-			CASE 
-				WHEN strftime('%m', trip_date) = '01' THEN 'January'
-				WHEN strftime('%m', trip_date) = '02' THEN 'February'
-				WHEN strftime('%m', trip_date) = '03' THEN 'March'
-				WHEN strftime('%m', trip_date) = '04' THEN 'April'
-				WHEN strftime('%m', trip_date) = '05' THEN 'May'
-				WHEN strftime('%m', trip_date) = '06' THEN 'June'
-				WHEN strftime('%m', trip_date) = '07' THEN 'July'
-				WHEN strftime('%m', trip_date) = '08' THEN 'August'
-				WHEN strftime('%m', trip_date) = '09' THEN 'September'
-				WHEN strftime('%m', trip_date) = '10' THEN 'October'
-				WHEN strftime('%m', trip_date) = '11' THEN 'November'
-				WHEN strftime('%m', trip_date) = '12' THEN 'December'
-			END || ' ' || strftime('%Y', trip_date) as month,
-			CAST(strftime('%Y', trip_date) AS INTEGER) as year,
-			CAST(strftime('%m', trip_date) AS INTEGER) as month_num,
-			COALESCE(SUM(miles), 0) as total_miles
-		FROM trips 
-		WHERE trip_date >= ? AND trip_date <= ?
-			AND trip_date IS NOT NULL
-		GROUP BY strftime('%Y-%m', trip_date), year, month_num
-		ORDER BY year DESC, month_num DESC
-	`
-
-	// Check database dialect and use appropriate query
+	bucketExpr, err := r.bucketStartExpr(granularity)
+	if err != nil {
+		return nil, err
+	}
+
+	minutesExpr := "(julianday(end_time) - julianday(start_time)) * 1440"
+	// Every trip's miles, for pricing trips one by one
+	milesListExpr := "GROUP_CONCAT(miles, ',')"
 	if r.db.Dialector.Name() == "postgres" {
-		// PostgreSQL-specific optimized query
-		query = `
-			SELECT 
-				TO_CHAR(DATE_TRUNC('month', trip_date::date), 'FMMonth YYYY') as month,
-				EXTRACT(YEAR FROM trip_date::date)::int as year,
-				EXTRACT(MONTH FROM trip_date::date)::int as month_num,
-				COALESCE(SUM(miles), 0) as total_miles
-			FROM trips 
-			WHERE trip_date >= ? AND trip_date <= ?
-				AND trip_date IS NOT NULL
-			GROUP BY DATE_TRUNC('month', trip_date::date), year, month_num
-			ORDER BY year DESC, month_num DESC
-		`
-	}
-
-	err := r.db.WithContext(ctxWithTimeout).Raw(query, startDate, endDate).Scan(&results).Error
+		minutesExpr = "EXTRACT(EPOCH FROM (end_time - start_time)) / 60"
+		milesListExpr = "STRING_AGG(CAST(miles AS TEXT), ',')"
+	}
+
+	selectExpr := bucketExpr + " AS start_date, " +
+		"COUNT(*) AS trip_count, " +
+		"COALESCE(SUM(miles), 0) AS total_miles, " +
+		"COALESCE(SUM(" + minutesExpr + "), 0) AS total_minutes"
+	if tripMiles {
+		selectExpr += ", COALESCE(" + milesListExpr + ", '') AS trip_miles"
+	}
+	groupBy := "start_date"
+	if groupExpr != "" {
+		selectExpr += ", COALESCE(" + groupExpr + ", '') AS \"group\""
+		groupBy = "start_date, \"group\""
+	}
+
+	var rows []summaryRow
+	query := r.buildFilteredQuery(r.db.WithContext(ctxWithTimeout).Table("trips"), filters).
+		Where("trip_date >= ? AND trip_date <= ?", from, to)
+	if groupExpr == tagGroupExpr {
+		// Filter the trips before joining so the filter columns stay
+		// unambiguous; untagged trips keep a single row with no tag
+		query = r.db.WithContext(ctxWithTimeout).
+			Table("(?) AS trips", query).
+			Joins("LEFT JOIN trip_tags ON trip_tags.trip_id = trips.id").
+			Joins("LEFT JOIN tags ON tags.id = trip_tags.tag_id")
+	}
+
+	err = query.
+		Select(selectExpr).
+		Group(groupBy).
+		Order(groupBy).
+		Scan(&rows).Error
 	if err != nil {
-		return nil, fmt.Errorf("failed to get monthly summary: %w", err)
+		return nil, fmt.Errorf("failed to get summary: %w", err)
 	}
 
-	return results, nil
+	result := make([]domain.SummaryGroupRow, len(rows))
+	for i, row := range rows {
+		result[i] = row.SummaryGroupRow
+		if result[i].TripMiles, err = parseMilesList(row.MilesList); err != nil {
+			return nil, fmt.Errorf("failed to get summary: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// summaryRow is a SummaryGroupRow as scanned, with the miles of its trips
+// still comma-separated
+type summaryRow struct {
+	domain.SummaryGroupRow
+	MilesList string `gorm:"column:trip_miles"`
+}
+
+// parseMilesList parses a trip_miles list, sorted since the databases
+// concatenate in no particular order
+func parseMilesList(list string) ([]float64, error) {
+	if list == "" {
+		return nil, nil
+	}
+	values := strings.Split(list, ",")
+	miles := make([]float64, len(values))
+	for i, value := range values {
+		var err error
+		if miles[i], err = strconv.ParseFloat(value, 64); err != nil {
+			return nil, fmt.Errorf("invalid trip miles %q", value)
+		}
+	}
+	sort.Float64s(miles)
+	return miles, nil
+}
+
+// bucketStartExpr returns a SQL expression giving the first day of the
+// bucket containing trip_date, formatted YYYY-MM-DD
+func (r *tripRepository) bucketStartExpr(granularity domain.Granularity) (string, error) {
+	if r.db.Dialector.Name() == "postgres" {
+		switch granularity {
+		case domain.GranularityDay:
+			return "TO_CHAR(trip_date::date, 'YYYY-MM-DD')", nil
+		case domain.GranularityWeek, domain.GranularityMonth, domain.GranularityQuarter, domain.GranularityYear:
+			// DATE_TRUNC weeks start on Monday, matching ISO weeks
+			return fmt.Sprintf("TO_CHAR(DATE_TRUNC('%s', trip_date::date), 'YYYY-MM-DD')", granularity), nil
+		}
+		return "", fmt.Errorf("unsupported summary granularity %q", granularity)
+	}
+
+	switch granularity {
+	case domain.GranularityDay:
+		return "strftime('%Y-%m-%d', trip_date)", nil
+	case domain.GranularityWeek:
+		// strftime('%w') counts from Sunday; step back to Monday
+		return "date(trip_date, '-' || ((CAST(strftime('%w', trip_date) AS INTEGER) + 6) % 7) || ' days')", nil
+	case domain.GranularityMonth:
+		return "strftime('%Y-%m-01', trip_date)", nil
+	case domain.GranularityQuarter:
+		return "printf('%s-%02d-01', strftime('%Y', trip_date), ((CAST(strftime('%m', trip_date) AS INTEGER) - 1) / 3) * 3 + 1)", nil
+	case domain.GranularityYear:
+		return "strftime('%Y-01-01', trip_date)", nil
+	}
+	return "", fmt.Errorf("unsupported summary granularity %q", granularity)
 }
@@ -2,19 +2,22 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/oscar/mileagetracker/internal/database"
 	"github.com/oscar/mileagetracker/internal/domain"
 	"github.com/oscar/mileagetracker/internal/testutils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 )
 
 func TestTripRepository_Create(t *testing.T) {
 	db := testutils.SetupTestDB(t)
-	repo := NewTripRepository(db)
+	repo := NewTripRepository(database.NewStore(db))
 
 	t.Run("should create trip successfully", func(t *testing.T) {
 		trip := testutils.NewTripBuilder().
@@ -48,9 +51,45 @@ func TestTripRepository_Create(t *testing.T) {
 	})
 }
 
+func TestTripRepository_CreateAll(t *testing.T) {
+	db := testutils.SetupTestDB(t)
+	repo := NewTripRepository(database.NewStore(db))
+	ctx := context.Background()
+
+	t.Run("should create every trip", func(t *testing.T) {
+		trips := []domain.Trip{
+			testutils.NewTripBuilder().WithClientName("Acme").WithDate("2025-01-15").WithMiles(10).Build(),
+			testutils.NewTripBuilder().WithClientName("Acme").WithDate("2025-01-16").WithMiles(20).Build(),
+		}
+
+		require.NoError(t, repo.CreateAll(ctx, trips))
+
+		assert.NotZero(t, trips[0].ID)
+		assert.NotZero(t, trips[1].ID)
+		var count int64
+		require.NoError(t, db.Model(&domain.Trip{}).Count(&count).Error)
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("should create none when one trip fails", func(t *testing.T) {
+		require.NoError(t, db.Exec("DELETE FROM trips").Error)
+		trips := []domain.Trip{
+			testutils.NewTripBuilder().WithClientName("Acme").WithDate("2025-01-15").WithMiles(10).Build(),
+			testutils.NewTripBuilder().WithClientName("Acme").WithDate("2025-01-16").WithMiles(20).Build(),
+		}
+		trips[1].Tags = []domain.Tag{{ID: 999}} // No such tag
+
+		assert.Error(t, repo.CreateAll(ctx, trips))
+
+		var count int64
+		require.NoError(t, db.Model(&domain.Trip{}).Count(&count).Error)
+		assert.Zero(t, count)
+	})
+}
+
 func TestTripRepository_FindByID(t *testing.T) {
 	db := testutils.SetupTestDB(t)
-	repo := NewTripRepository(db)
+	repo := NewTripRepository(database.NewStore(db))
 
 	t.Run("should find trip by ID", func(t *testing.T) {
 		// Create test trip using builder
@@ -80,7 +119,7 @@ func TestTripRepository_FindByID(t *testing.T) {
 
 func TestTripRepository_Update(t *testing.T) {
 	db := testutils.SetupTestDB(t)
-	repo := NewTripRepository(db)
+	repo := NewTripRepository(database.NewStore(db))
 
 	t.Run("should update trip successfully", func(t *testing.T) {
 		// Create test trip
@@ -112,7 +151,7 @@ func TestTripRepository_Update(t *testing.T) {
 
 func TestTripRepository_Delete(t *testing.T) {
 	db := testutils.SetupTestDB(t)
-	repo := NewTripRepository(db)
+	repo := NewTripRepository(database.NewStore(db))
 
 	t.Run("should delete trip successfully", func(t *testing.T) {
 		// Create test trip
@@ -145,7 +184,7 @@ func TestTripRepository_Delete(t *testing.T) {
 
 func TestTripRepository_GetPaginated(t *testing.T) {
 	db := testutils.SetupTestDB(t)
-	repo := NewTripRepository(db)
+	repo := NewTripRepository(database.NewStore(db))
 
 	// Create test data
 	testTrips := []domain.Trip{
@@ -202,42 +241,435 @@ func TestTripRepository_GetPaginated(t *testing.T) {
 	})
 }
 
-func TestTripRepository_GetMonthlySummary(t *testing.T) {
+func TestTripRepository_GetPageAfter(t *testing.T) {
 	db := testutils.SetupTestDB(t)
-	repo := NewTripRepository(db)
-
-	// For SQLite tests, we skip the monthly summary as it uses PostgreSQL-specific syntax
-	// In a real application, you'd either:
-	// 1. Use the same database engine for tests as production
-	// 2. Create database-agnostic queries
-	// 3. Have separate test implementations
-	t.Run("should call GetMonthlySummary without error", func(t *testing.T) {
-		// Create test data
-		trip := &domain.Trip{
-			ClientName: "Test Client",
-			TripDate:   "2025-01-15",
-			Miles:      100.0,
-			Notes:      "Test trip",
+	repo := NewTripRepository(database.NewStore(db))
+
+	created := time.Date(2025, 1, 20, 9, 0, 0, 0, time.UTC)
+	testTrips := []domain.Trip{
+		{ClientName: "Client A", TripDate: "2025-01-15", Miles: 10, CreatedAt: created},
+		{ClientName: "Client B", TripDate: "2025-01-15", Miles: 20, CreatedAt: created.Add(time.Hour)},
+		{ClientName: "Client C", TripDate: "2025-01-14", Miles: 30, CreatedAt: created},
+		{ClientName: "Client D", TripDate: "2025-01-14", Miles: 40, CreatedAt: created}, // Same creation time as C
+		{ClientName: "Client E", TripDate: "2025-01-13", Miles: 50, CreatedAt: created},
+	}
+	for i := range testTrips {
+		require.NoError(t, repo.Create(context.Background(), &testTrips[i]))
+	}
+
+	clientNames := func(trips []domain.Trip) []string {
+		names := []string{}
+		for _, trip := range trips {
+			names = append(names, trip.ClientName)
 		}
-		err := repo.Create(context.Background(), trip)
+		return names
+	}
+
+	t.Run("should walk every trip once in order", func(t *testing.T) {
+		var names []string
+		var after *domain.TripCursor
+		for page := 0; page < 5; page++ {
+			trips, err := repo.GetPageAfter(context.Background(), after, 2, domain.TripFilters{})
+			require.NoError(t, err)
+			if len(trips) == 0 {
+				break
+			}
+			names = append(names, clientNames(trips)...)
+			cursor := domain.CursorAfter(trips[len(trips)-1], domain.DefaultTripSort)
+			after = &cursor
+		}
+
+		assert.Equal(t, []string{"Client B", "Client A", "Client D", "Client C", "Client E"}, names)
+	})
+
+	t.Run("should not shift when trips are added before the cursor", func(t *testing.T) {
+		first, err := repo.GetPageAfter(context.Background(), nil, 2, domain.TripFilters{})
+		require.NoError(t, err)
+		cursor := domain.CursorAfter(first[1], domain.DefaultTripSort)
+
+		newer := domain.Trip{ClientName: "Client F", TripDate: "2025-01-16", Miles: 5}
+		require.NoError(t, repo.Create(context.Background(), &newer))
+
+		second, err := repo.GetPageAfter(context.Background(), &cursor, 2, domain.TripFilters{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Client D", "Client C"}, clientNames(second))
+	})
+
+	t.Run("should apply filters", func(t *testing.T) {
+		minMiles := 25.0
+		trips, err := repo.GetPageAfter(context.Background(), nil, 10, domain.TripFilters{MinMiles: &minMiles})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Client D", "Client C", "Client E"}, clientNames(trips))
+	})
+}
+
+func TestTripRepository_DefaultSort(t *testing.T) {
+	db := testutils.SetupTestDB(t)
+	repo := NewTripRepository(database.NewStore(db))
+
+	created := time.Date(2025, 1, 20, 9, 0, 0, 0, time.UTC)
+	at := func(hour int) *time.Time {
+		t := time.Date(2025, 1, 15, hour, 0, 0, 0, time.UTC)
+		return &t
+	}
+	testTrips := []domain.Trip{
+		{ClientName: "Untimed older", TripDate: "2025-01-15", Miles: 10, CreatedAt: created},
+		{ClientName: "Morning", TripDate: "2025-01-15", Miles: 10, StartTime: at(8), CreatedAt: created.Add(time.Hour)},
+		{ClientName: "Untimed newer", TripDate: "2025-01-15", Miles: 10, CreatedAt: created.Add(2 * time.Hour)},
+		{ClientName: "Afternoon", TripDate: "2025-01-15", Miles: 10, StartTime: at(14), CreatedAt: created},
+		{ClientName: "Day before", TripDate: "2025-01-14", Miles: 10, CreatedAt: created},
+	}
+	for i := range testTrips {
+		require.NoError(t, repo.Create(context.Background(), &testTrips[i]))
+	}
+	want := []string{"Afternoon", "Morning", "Untimed newer", "Untimed older", "Day before"}
+
+	clientNames := func(trips []domain.Trip) []string {
+		names := []string{}
+		for _, trip := range trips {
+			names = append(names, trip.ClientName)
+		}
+		return names
+	}
+
+	t.Run("should list timed trips first, latest start first, in pages", func(t *testing.T) {
+		trips, _, err := repo.GetPaginated(context.Background(), 1, 10, domain.TripFilters{})
+
+		require.NoError(t, err)
+		assert.Equal(t, want, clientNames(trips))
+	})
+
+	t.Run("should list the same order with cursors", func(t *testing.T) {
+		var names []string
+		var after *domain.TripCursor
+		for page := 0; page < 5; page++ {
+			trips, err := repo.GetPageAfter(context.Background(), after, 2, domain.TripFilters{})
+			require.NoError(t, err)
+			if len(trips) == 0 {
+				break
+			}
+			names = append(names, clientNames(trips)...)
+			cursor := domain.CursorAfter(trips[len(trips)-1], domain.DefaultTripSort)
+			after = &cursor
+		}
+
+		assert.Equal(t, want, names)
+	})
+}
+
+func TestTripRepository_DefaultSort_MixedOffsets(t *testing.T) {
+	db := testutils.SetupTestDB(t)
+	repo := NewTripRepository(database.NewStore(db))
+
+	// Sent with their clients' offsets, 14:00+05:00 would sort as text
+	// before 10:00Z although it is the earlier instant; the service stores
+	// both in UTC
+	at := func(hour int, offset int) *time.Time {
+		t := time.Date(2025, 1, 15, hour, 0, 0, 0, time.FixedZone("", offset*60*60)).UTC()
+		return &t
+	}
+	testTrips := []domain.Trip{
+		{ClientName: "Karachi 14:00", TripDate: "2025-01-15", Miles: 10, StartTime: at(14, 5)},
+		{ClientName: "London 10:00", TripDate: "2025-01-15", Miles: 10, StartTime: at(10, 0)},
+		{ClientName: "New York 07:00", TripDate: "2025-01-15", Miles: 10, StartTime: at(7, -5)},
+	}
+	for i := range testTrips {
+		require.NoError(t, repo.Create(context.Background(), &testTrips[i]))
+	}
+	want := []string{"New York 07:00", "London 10:00", "Karachi 14:00"}
+
+	clientNames := func(trips []domain.Trip) []string {
+		names := []string{}
+		for _, trip := range trips {
+			names = append(names, trip.ClientName)
+		}
+		return names
+	}
+
+	t.Run("should list the latest instant first in pages", func(t *testing.T) {
+		trips, _, err := repo.GetPaginated(context.Background(), 1, 10, domain.TripFilters{})
+
+		require.NoError(t, err)
+		assert.Equal(t, want, clientNames(trips))
+	})
+
+	t.Run("should list the same order with cursors", func(t *testing.T) {
+		var names []string
+		var after *domain.TripCursor
+		for page := 0; page < 5; page++ {
+			trips, err := repo.GetPageAfter(context.Background(), after, 1, domain.TripFilters{})
+			require.NoError(t, err)
+			if len(trips) == 0 {
+				break
+			}
+			names = append(names, clientNames(trips)...)
+			cursor := domain.CursorAfter(trips[len(trips)-1], domain.DefaultTripSort)
+			after = &cursor
+		}
+
+		assert.Equal(t, want, names)
+	})
+}
+
+func TestTripRepository_Sort(t *testing.T) {
+	db := testutils.SetupTestDB(t)
+	repo := NewTripRepository(database.NewStore(db))
+
+	testTrips := []domain.Trip{
+		{ClientName: "beta", TripDate: "2025-01-15", Miles: 10},
+		{ClientName: "Acme", TripDate: "2025-01-14", Miles: 30},
+		{ClientName: "Beta", TripDate: "2025-01-13", Miles: 20},
+		{ClientName: "acme", TripDate: "2025-01-12", Miles: 30},
+		{ClientName: "Gamma", TripDate: "2025-01-11", Miles: 5},
+	}
+	for i := range testTrips {
+		require.NoError(t, repo.Create(context.Background(), &testTrips[i]))
+	}
+
+	describe := func(trips []domain.Trip) []string {
+		described := []string{}
+		for _, trip := range trips {
+			described = append(described, fmt.Sprintf("%s/%g", trip.ClientName, trip.Miles))
+		}
+		return described
+	}
+
+	// Client names compare case-insensitively; the two acme trips tie on
+	// every key and fall back to ID order, which follows the last key
+	sort := []domain.SortKey{{Field: domain.SortByClientName}, {Field: domain.SortByMiles, Desc: true}}
+	expected := []string{"acme/30", "Acme/30", "Beta/20", "beta/10", "Gamma/5"}
+
+	t.Run("should sort pages by the given keys", func(t *testing.T) {
+		trips, total, err := repo.GetPaginated(context.Background(), 1, 10, domain.TripFilters{Sort: sort})
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), total)
+		assert.Equal(t, expected, describe(trips))
+	})
+
+	t.Run("should walk cursor pages with mixed directions", func(t *testing.T) {
+		filters := domain.TripFilters{Sort: sort}
+		var described []string
+		var after *domain.TripCursor
+		for page := 0; page < 5; page++ {
+			trips, err := repo.GetPageAfter(context.Background(), after, 2, filters)
+			require.NoError(t, err)
+			if len(trips) == 0 {
+				break
+			}
+			described = append(described, describe(trips)...)
+			cursor := domain.CursorAfter(trips[len(trips)-1], sort)
+			after = &cursor
+		}
+
+		assert.Equal(t, expected, described)
+	})
+
+	t.Run("should walk cursor pages in ascending order", func(t *testing.T) {
+		filters := domain.TripFilters{Sort: []domain.SortKey{{Field: domain.SortByAmount}}}
+		var described []string
+		var after *domain.TripCursor
+		for page := 0; page < 5; page++ {
+			trips, err := repo.GetPageAfter(context.Background(), after, 2, filters)
+			require.NoError(t, err)
+			if len(trips) == 0 {
+				break
+			}
+			described = append(described, describe(trips)...)
+			cursor := domain.CursorAfter(trips[len(trips)-1], filters.Sort)
+			after = &cursor
+		}
+
+		assert.Equal(t, []string{"Gamma/5", "beta/10", "Beta/20", "Acme/30", "acme/30"}, described)
+	})
+}
+
+func TestTripRepository_GetSummaryBuckets(t *testing.T) {
+	db := testutils.SetupTestDB(t)
+	repo := NewTripRepository(database.NewStore(db))
+	ctx := context.Background()
+
+	// 2025-01-13 is a Monday
+	trips := []domain.Trip{
+		{ClientName: "Acme", TripDate: "2025-01-13", Miles: 10},
+		{ClientName: "Acme", TripDate: "2025-01-19", Miles: 20},
+		{ClientName: "Globex", TripDate: "2025-01-20", Miles: 30},
+		{ClientName: "Acme", TripDate: "2025-04-02", Miles: 40},
+		{ClientName: "Acme", TripDate: "2026-02-01", Miles: 50},
+	}
+	for i := range trips {
+		assert.NoError(t, repo.Create(ctx, &trips[i]))
+	}
+
+	tests := []struct {
+		granularity domain.Granularity
+		starts      []string
+		miles       []float64
+	}{
+		{domain.GranularityDay, []string{"2025-01-13", "2025-01-19", "2025-01-20", "2025-04-02", "2026-02-01"}, []float64{10, 20, 30, 40, 50}},
+		{domain.GranularityWeek, []string{"2025-01-13", "2025-01-20", "2025-03-31", "2026-01-26"}, []float64{30, 30, 40, 50}},
+		{domain.GranularityMonth, []string{"2025-01-01", "2025-04-01", "2026-02-01"}, []float64{60, 40, 50}},
+		{domain.GranularityQuarter, []string{"2025-01-01", "2025-04-01", "2026-01-01"}, []float64{60, 40, 50}},
+		{domain.GranularityYear, []string{"2025-01-01", "2026-01-01"}, []float64{100, 50}},
+	}
+
+	for _, tt := range tests {
+		t.Run("should group by "+string(tt.granularity), func(t *testing.T) {
+			buckets, err := repo.GetSummaryBuckets(ctx, tt.granularity, "2025-01-01", "2026-12-31", domain.TripFilters{}, false)
+
+			assert.NoError(t, err)
+			starts := make([]string, len(buckets))
+			miles := make([]float64, len(buckets))
+			for i, bucket := range buckets {
+				starts[i] = bucket.StartDate
+				miles[i] = bucket.TotalMiles
+			}
+			assert.Equal(t, tt.starts, starts)
+			assert.Equal(t, tt.miles, miles)
+		})
+	}
+
+	t.Run("should apply range and filters", func(t *testing.T) {
+		buckets, err := repo.GetSummaryBuckets(ctx, domain.GranularityMonth, "2025-01-14", "2025-12-31", domain.TripFilters{Client: "acme"}, false)
+
+		assert.NoError(t, err)
+		assert.Len(t, buckets, 2)
+		assert.Equal(t, int64(1), buckets[0].TripCount)
+		assert.Equal(t, 20.0, buckets[0].TotalMiles)
+		assert.Equal(t, 40.0, buckets[1].TotalMiles)
+	})
+
+	t.Run("should list trip miles only when asked", func(t *testing.T) {
+		buckets, err := repo.GetSummaryBuckets(ctx, domain.GranularityYear, "2025-01-01", "2025-12-31", domain.TripFilters{Client: "acme"}, false)
+		require.NoError(t, err)
+		require.Len(t, buckets, 1)
+		assert.Nil(t, buckets[0].TripMiles)
+
+		buckets, err = repo.GetSummaryBuckets(ctx, domain.GranularityYear, "2025-01-01", "2025-12-31", domain.TripFilters{Client: "acme"}, true)
+		require.NoError(t, err)
+		require.Len(t, buckets, 1)
+		assert.ElementsMatch(t, []float64{10, 20, 40}, buckets[0].TripMiles)
+	})
+
+	t.Run("should reject unknown granularity", func(t *testing.T) {
+		_, err := repo.GetSummaryBuckets(ctx, domain.Granularity("fortnight"), "2025-01-01", "2025-12-31", domain.TripFilters{}, false)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestTripRepository_GetGroupedSummaryBuckets(t *testing.T) {
+	db := testutils.SetupTestDB(t)
+	repo := NewTripRepository(database.NewStore(db))
+	locationRepo := NewLocationRepository(database.NewStore(db))
+	ctx := context.Background()
+
+	home := &domain.Location{Label: "Home"}
+	office := &domain.Location{Label: "Office"}
+	assert.NoError(t, locationRepo.Create(ctx, home))
+	assert.NoError(t, locationRepo.Create(ctx, office))
+
+	trips := []domain.Trip{
+		{ClientName: "Acme", TripDate: "2025-01-13", Miles: 10, FromLocationID: &home.ID, ToLocationID: &office.ID},
+		{ClientName: "Acme", TripDate: "2025-01-19", Miles: 20, FromLocationID: &office.ID},
+		{ClientName: "Globex", TripDate: "2025-01-20", Miles: 30},
+		{ClientName: "Globex", TripDate: "2025-02-02", Miles: 40, FromLocationID: &home.ID},
+	}
+	for i := range trips {
+		assert.NoError(t, repo.Create(ctx, &trips[i]))
+	}
+
+	t.Run("should group by client within each bucket", func(t *testing.T) {
+		rows, err := repo.GetGroupedSummaryBuckets(ctx, domain.GranularityMonth, domain.SummaryByClient, "2025-01-01", "2025-12-31", domain.TripFilters{}, true)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.SummaryGroupRow{
+			{Group: "Acme", StartDate: "2025-01-01", TripCount: 2, TotalMiles: 30, TripMiles: []float64{10, 20}},
+			{Group: "Globex", StartDate: "2025-01-01", TripCount: 1, TotalMiles: 30, TripMiles: []float64{30}},
+			{Group: "Globex", StartDate: "2025-02-01", TripCount: 1, TotalMiles: 40, TripMiles: []float64{40}},
+		}, rows)
+	})
+
+	t.Run("should group by location label with an empty group for none", func(t *testing.T) {
+		rows, err := repo.GetGroupedSummaryBuckets(ctx, domain.GranularityYear, domain.SummaryByFromLocation, "2025-01-01", "2025-12-31", domain.TripFilters{}, true)
+
 		assert.NoError(t, err)
+		assert.Equal(t, []domain.SummaryGroupRow{
+			{Group: "", StartDate: "2025-01-01", TripCount: 1, TotalMiles: 30, TripMiles: []float64{30}},
+			{Group: "Home", StartDate: "2025-01-01", TripCount: 2, TotalMiles: 50, TripMiles: []float64{10, 40}},
+			{Group: "Office", StartDate: "2025-01-01", TripCount: 1, TotalMiles: 20, TripMiles: []float64{20}},
+		}, rows)
+	})
+
+	t.Run("should apply filters", func(t *testing.T) {
+		rows, err := repo.GetGroupedSummaryBuckets(ctx, domain.GranularityYear, domain.SummaryByToLocation, "2025-01-01", "2025-12-31", domain.TripFilters{Client: "Acme"}, false)
 
-		// This will fail on SQLite but works on PostgreSQL
-		// For proper integration tests, use the same DB as production
-		summaries, err := repo.GetMonthlySummary(context.Background(), "2025-01-01", "2025-01-31")
+		assert.NoError(t, err)
+		assert.Len(t, rows, 2)
+		assert.Equal(t, "Office", rows[1].Group)
+	})
 
-		// In SQLite this will return error due to PostgreSQL-specific syntax
-		// In PostgreSQL (production), this would return proper results
-		t.Logf("Monthly summary test: summaries=%v, err=%v", summaries, err)
+	t.Run("should reject unknown dimension", func(t *testing.T) {
+		_, err := repo.GetGroupedSummaryBuckets(ctx, domain.GranularityYear, domain.SummaryDimension("vehicle"), "2025-01-01", "2025-12-31", domain.TripFilters{}, false)
 
-		// Don't assert success/failure since this depends on the database engine
-		// This test serves as documentation that the method exists and can be called
+		assert.Error(t, err)
+	})
+}
+
+func TestTripRepository_TripTimes(t *testing.T) {
+	db := testutils.SetupTestDB(t)
+	repo := NewTripRepository(database.NewStore(db))
+	ctx := context.Background()
+
+	eastern := time.FixedZone("EST", -5*60*60)
+	at := func(hour, minute int) *time.Time {
+		tm := time.Date(2025, 1, 15, hour, minute, 0, 0, eastern)
+		return &tm
+	}
+
+	trips := []domain.Trip{
+		{ClientName: "Morning", TripDate: "2025-01-15", Miles: 10, StartTime: at(8, 0), EndTime: at(8, 45)},
+		{ClientName: "Untimed", TripDate: "2025-01-15", Miles: 5},
+		{ClientName: "Afternoon", TripDate: "2025-01-15", Miles: 20, StartTime: at(14, 30), EndTime: at(15, 0)},
+		{ClientName: "Open", TripDate: "2025-01-15", Miles: 7, StartTime: at(11, 0)},
+	}
+	for i := range trips {
+		assert.NoError(t, repo.Create(ctx, &trips[i]))
+	}
+
+	t.Run("should order timed trips by start time within a day", func(t *testing.T) {
+		result, _, err := repo.GetPaginated(ctx, 1, 10, domain.TripFilters{})
+
+		assert.NoError(t, err)
+		names := make([]string, len(result))
+		for i, trip := range result {
+			names[i] = trip.ClientName
+		}
+		assert.Equal(t, []string{"Afternoon", "Open", "Morning", "Untimed"}, names)
+	})
+
+	t.Run("should keep the instant of stored times", func(t *testing.T) {
+		found, err := repo.FindByID(ctx, trips[0].ID)
+
+		assert.NoError(t, err)
+		assert.True(t, found.StartTime.Equal(*at(8, 0)))
+		assert.Equal(t, 45*time.Minute, found.Duration())
+	})
+
+	t.Run("should total minutes of timed trips in the summary", func(t *testing.T) {
+		buckets, err := repo.GetSummaryBuckets(ctx, domain.GranularityMonth, "2025-01-01", "2025-01-31", domain.TripFilters{}, false)
+
+		assert.NoError(t, err)
+		assert.Len(t, buckets, 1)
+		assert.InDelta(t, 75, buckets[0].TotalMinutes, 0.01)
+		assert.Equal(t, 42.0, buckets[0].TotalMiles)
 	})
 }
 
 func TestTripRepository_GetPaginated_WithFilters(t *testing.T) {
 	db := testutils.SetupTestDB(t)
-	repo := NewTripRepository(db)
+	repo := NewTripRepository(database.NewStore(db))
 
 	// Create diverse test data
 	testTrips := []domain.Trip{
@@ -274,6 +706,45 @@ func TestTripRepository_GetPaginated_WithFilters(t *testing.T) {
 		}
 	})
 
+	t.Run("should require every search term", func(t *testing.T) {
+		trips, total, err := repo.GetPaginated(context.Background(), 1, 10, domain.TripFilters{Search: "acme follow"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, "Acme follow-up meeting", trips[0].Notes)
+	})
+
+	t.Run("should match quoted phrases", func(t *testing.T) {
+		trips, total, err := repo.GetPaginated(context.Background(), 1, 10, domain.TripFilters{Search: `"project kickoff"`})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, "Gamma LLC", trips[0].ClientName)
+
+		_, total, err = repo.GetPaginated(context.Background(), 1, 10, domain.TripFilters{Search: `"kickoff project"`})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), total)
+	})
+
+	t.Run("should exclude negated terms", func(t *testing.T) {
+		trips, total, err := repo.GetPaginated(context.Background(), 1, 10, domain.TripFilters{Search: "meeting -acme"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, "Delta Co", trips[0].ClientName)
+	})
+
+	t.Run("should ignore stop words", func(t *testing.T) {
+		_, total, err := repo.GetPaginated(context.Background(), 1, 10, domain.TripFilters{Search: `the "with" -a`})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(testTrips)), total)
+
+		_, total, err = repo.GetPaginated(context.Background(), 1, 10, domain.TripFilters{Search: "with acme"})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+	})
+
 	t.Run("should filter by exact client name", func(t *testing.T) {
 		filters := domain.TripFilters{
 			Client: "Beta Inc",
@@ -453,3 +924,333 @@ func TestTripRepository_GetPaginated_WithFilters(t *testing.T) {
 		assert.NotEqual(t, trips[1].ID, trips2[1].ID)
 	})
 }
+
+func TestTripRepository_GetPaginated_WithListFilters(t *testing.T) {
+	db := testutils.SetupTestDB(t)
+	repo := NewTripRepository(database.NewStore(db))
+
+	day := func(d int) time.Time { return time.Date(2025, time.January, d, 12, 0, 0, 0, time.UTC) }
+	testTrips := []domain.Trip{
+		{ClientName: "Acme Corp", TripDate: "2025-01-15", Miles: 100.0, Notes: "Meeting", CreatedAt: day(15), UpdatedAt: day(20)},
+		{ClientName: "Beta Inc", TripDate: "2025-01-14", Miles: 50.0, Notes: "   ", CreatedAt: day(14), UpdatedAt: day(14)},
+		{ClientName: "Gamma LLC", TripDate: "2025-01-13", Miles: 200.0, CreatedAt: day(13), UpdatedAt: day(13)},
+		{ClientName: "acme corp", TripDate: "2025-01-12", Miles: 75.0, Notes: "Follow-up", CreatedAt: day(12), UpdatedAt: day(12)},
+	}
+	for i := range testTrips {
+		require.NoError(t, repo.Create(context.Background(), &testTrips[i]))
+	}
+
+	clientsOf := func(trips []domain.Trip) []string {
+		clients := make([]string, len(trips))
+		for i, trip := range trips {
+			clients[i] = trip.ClientName
+		}
+		return clients
+	}
+	yes, no := true, false
+
+	tests := []struct {
+		name    string
+		filters domain.TripFilters
+		want    []string
+	}{
+		{"any of several clients", domain.TripFilters{Clients: []string{"ACME CORP", "Gamma LLC"}},
+			[]string{"Acme Corp", "Gamma LLC", "acme corp"}},
+		{"excluded clients", domain.TripFilters{ExcludeClients: []string{"acme corp"}},
+			[]string{"Beta Inc", "Gamma LLC"}},
+		{"trip IDs", domain.TripFilters{IDs: []uint{testTrips[1].ID, testTrips[2].ID}},
+			[]string{"Beta Inc", "Gamma LLC"}},
+		{"excluded trip IDs", domain.TripFilters{ExcludeIDs: []uint{testTrips[0].ID}},
+			[]string{"Beta Inc", "Gamma LLC", "acme corp"}},
+		{"with notes", domain.TripFilters{HasNotes: &yes},
+			[]string{"Acme Corp", "acme corp"}},
+		{"without notes, counting blank notes", domain.TripFilters{HasNotes: &no},
+			[]string{"Beta Inc", "Gamma LLC"}},
+		{"created range is half-open", domain.TripFilters{CreatedAfter: "2025-01-13T12:00:00Z", CreatedBefore: "2025-01-15T12:00:00Z"},
+			[]string{"Beta Inc", "Gamma LLC"}},
+		{"created before a date", domain.TripFilters{CreatedBefore: "2025-01-13"},
+			[]string{"acme corp"}},
+		{"updated after", domain.TripFilters{UpdatedAfter: "2025-01-16T00:00:00+01:00"},
+			[]string{"Acme Corp"}},
+		{"combined with exclusions", domain.TripFilters{Clients: []string{"acme corp"}, ExcludeIDs: []uint{testTrips[3].ID}},
+			[]string{"Acme Corp"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trips, total, err := repo.GetPaginated(context.Background(), 1, 10, tt.filters)
+
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tt.want)), total)
+			assert.Equal(t, tt.want, clientsOf(trips))
+		})
+	}
+
+	t.Run("should reject a malformed time bound", func(t *testing.T) {
+		_, _, err := repo.GetPaginated(context.Background(), 1, 10, domain.TripFilters{CreatedAfter: "yesterday"})
+
+		assert.Error(t, err)
+	})
+}
+
+// setLocalZone runs the rest of the test as if the server's time zone were
+// zone, as SQLite stores times as text in whatever offset they carry
+func setLocalZone(t *testing.T, zone *time.Location) {
+	t.Helper()
+
+	local := time.Local
+	time.Local = zone
+	t.Cleanup(func() { time.Local = local })
+}
+
+func TestTripRepository_TimeBounds_LocalZone(t *testing.T) {
+	for _, zone := range []*time.Location{
+		time.FixedZone("EDT", -4*60*60),
+		time.FixedZone("IST", 5*60*60+30*60),
+	} {
+		t.Run(zone.String(), func(t *testing.T) {
+			setLocalZone(t, zone)
+			db := testutils.SetupTestDB(t)
+			repo := NewTripRepository(database.NewStore(db))
+
+			trip := domain.Trip{ClientName: "Acme Corp", TripDate: "2025-01-15", Miles: 10}
+			require.NoError(t, repo.Create(context.Background(), &trip))
+			require.NoError(t, repo.Update(context.Background(), &trip))
+			now := time.Now()
+			before := now.Add(-time.Minute).Format(time.RFC3339Nano)
+			after := now.Add(time.Minute).Format(time.RFC3339Nano)
+
+			tests := []struct {
+				name    string
+				filters domain.TripFilters
+				want    int64
+			}{
+				{"created after a minute before", domain.TripFilters{CreatedAfter: before}, 1},
+				{"created after a minute after", domain.TripFilters{CreatedAfter: after}, 0},
+				{"created before a minute before", domain.TripFilters{CreatedBefore: before}, 0},
+				{"created before a minute after", domain.TripFilters{CreatedBefore: after}, 1},
+				{"updated after a minute before", domain.TripFilters{UpdatedAfter: before}, 1},
+				{"updated after a minute after", domain.TripFilters{UpdatedAfter: after}, 0},
+				{"updated before a minute before", domain.TripFilters{UpdatedBefore: before}, 0},
+				{"updated before a minute after", domain.TripFilters{UpdatedBefore: after}, 1},
+			}
+			for _, tt := range tests {
+				_, total, err := repo.GetPaginated(context.Background(), 1, 10, tt.filters)
+				require.NoError(t, err, tt.name)
+				assert.Equal(t, tt.want, total, tt.name)
+			}
+		})
+	}
+}
+
+func TestTripRepository_Tags(t *testing.T) {
+	db := testutils.SetupTestDB(t)
+	repo := NewTripRepository(database.NewStore(db))
+	tagRepo := NewTagRepository(database.NewStore(db))
+	ctx := context.Background()
+
+	tags := map[string]domain.Tag{}
+	for _, name := range []string{"billable", "conference", "site-visit"} {
+		tag := domain.Tag{Name: name}
+		require.NoError(t, tagRepo.Create(ctx, &tag))
+		tags[name] = tag
+	}
+
+	testTrips := []domain.Trip{
+		{ClientName: "Acme Corp", TripDate: "2025-01-15", Miles: 100.0, Tags: []domain.Tag{tags["site-visit"], tags["billable"]}},
+		{ClientName: "Beta Inc", TripDate: "2025-01-14", Miles: 50.0, Tags: []domain.Tag{tags["conference"]}},
+		{ClientName: "Gamma LLC", TripDate: "2025-01-13", Miles: 200.0},
+		{ClientName: "Delta Co", TripDate: "2025-02-12", Miles: 75.0, Tags: []domain.Tag{tags["billable"]}},
+	}
+	for i := range testTrips {
+		require.NoError(t, repo.Create(ctx, &testTrips[i]))
+	}
+
+	tagNames := func(trip domain.Trip) []string {
+		names := []string{}
+		for _, tag := range trip.Tags {
+			names = append(names, tag.Name)
+		}
+		return names
+	}
+	clientsOf := func(trips []domain.Trip) []string {
+		clients := []string{}
+		for _, trip := range trips {
+			clients = append(clients, trip.ClientName)
+		}
+		return clients
+	}
+
+	t.Run("should load tags by name with trips", func(t *testing.T) {
+		trip, err := repo.FindByID(ctx, testTrips[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"billable", "site-visit"}, tagNames(*trip))
+
+		trips, _, err := repo.GetPaginated(ctx, 1, 10, domain.TripFilters{})
+		require.NoError(t, err)
+		require.Len(t, trips, 4)
+		assert.Equal(t, []string{"billable"}, tagNames(trips[0]))
+		assert.Equal(t, []string{"billable", "site-visit"}, tagNames(trips[1]))
+		assert.Empty(t, trips[3].Tags)
+
+		page, err := repo.GetPageAfter(ctx, nil, 10, domain.TripFilters{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"conference"}, tagNames(page[2]))
+	})
+
+	t.Run("should filter by tags", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			filters domain.TripFilters
+			want    []string
+		}{
+			{"any tag", domain.TripFilters{Tags: []string{"conference", "Site-Visit"}}, []string{"Acme Corp", "Beta Inc"}},
+			{"all tags", domain.TripFilters{AllTags: []string{"billable", "site-visit", "billable"}}, []string{"Acme Corp"}},
+			{"excluded tags", domain.TripFilters{ExcludeTags: []string{"billable"}}, []string{"Beta Inc", "Gamma LLC"}},
+			{"combined", domain.TripFilters{Tags: []string{"billable"}, ExcludeTags: []string{"site-visit"}}, []string{"Delta Co"}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				trips, total, err := repo.GetPaginated(ctx, 1, 10, tt.filters)
+
+				require.NoError(t, err)
+				assert.Equal(t, int64(len(tt.want)), total)
+				assert.Equal(t, tt.want, clientsOf(trips))
+			})
+		}
+	})
+
+	t.Run("should replace tags on update", func(t *testing.T) {
+		trip, err := repo.FindByID(ctx, testTrips[1].ID)
+		require.NoError(t, err)
+
+		trip.Tags = []domain.Tag{tags["billable"]}
+		require.NoError(t, repo.Update(ctx, trip))
+
+		updated, err := repo.FindByID(ctx, trip.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"billable"}, tagNames(*updated))
+
+		updated.Tags = nil
+		require.NoError(t, repo.Update(ctx, updated))
+
+		cleared, err := repo.FindByID(ctx, trip.ID)
+		require.NoError(t, err)
+		assert.Empty(t, cleared.Tags)
+	})
+
+	t.Run("should break summaries down by tag", func(t *testing.T) {
+		rows, err := repo.GetGroupedSummaryBuckets(ctx, domain.GranularityMonth, domain.SummaryByTag,
+			"2025-01-01", "2025-02-28", domain.TripFilters{MinMiles: floatPtr(60)}, false)
+		require.NoError(t, err)
+
+		got := map[string]float64{}
+		for _, row := range rows {
+			got[row.StartDate+" "+row.Group] = row.TotalMiles
+		}
+		assert.Equal(t, map[string]float64{
+			"2025-01-01 billable":   100,
+			"2025-01-01 site-visit": 100,
+			"2025-01-01 ":           200,
+			"2025-02-01 billable":   75,
+		}, got)
+	})
+
+	t.Run("should remove tag links when a trip is deleted", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, testTrips[0].ID))
+
+		var links int64
+		require.NoError(t, db.Table("trip_tags").Where("trip_id = ?", testTrips[0].ID).Count(&links).Error)
+		assert.Zero(t, links)
+	})
+}
+
+func TestTripRepository_Expenses(t *testing.T) {
+	db := testutils.SetupTestDB(t)
+	repo := NewTripRepository(database.NewStore(db))
+	ctx := context.Background()
+
+	acme := domain.Trip{ClientName: "Acme Corp", TripDate: "2025-01-15", Miles: 100.0, Expenses: []domain.Expense{
+		{Type: domain.ExpenseToll, Amount: domain.MustMoney("4.5"), Currency: "USD"},
+		{Type: domain.ExpenseParking, Amount: domain.MustMoney("12"), Currency: "USD", Note: "Garage"},
+	}}
+	beta := domain.Trip{ClientName: "Beta Inc", TripDate: "2025-01-20", Miles: 50.0, Expenses: []domain.Expense{
+		{Type: domain.ExpenseFuel, Amount: domain.MustMoney("30"), Currency: "CAD"},
+	}}
+	gamma := domain.Trip{ClientName: "Gamma LLC", TripDate: "2025-02-03", Miles: 20.0}
+	for _, trip := range []*domain.Trip{&acme, &beta, &gamma} {
+		require.NoError(t, repo.Create(ctx, trip))
+	}
+
+	t.Run("should load expenses with trips", func(t *testing.T) {
+		trip, err := repo.FindByID(ctx, acme.ID)
+		require.NoError(t, err)
+		require.Len(t, trip.Expenses, 2)
+		assert.Equal(t, domain.ExpenseToll, trip.Expenses[0].Type)
+		assert.Equal(t, "Garage", trip.Expenses[1].Note)
+		assert.Equal(t, acme.ID, trip.Expenses[1].TripID)
+
+		trips, _, err := repo.GetPaginated(ctx, 1, 10, domain.TripFilters{})
+		require.NoError(t, err)
+		require.Len(t, trips, 3)
+		assert.Empty(t, trips[0].Expenses)
+		assert.Len(t, trips[1].Expenses, 1)
+		assert.Len(t, trips[2].Expenses, 2)
+
+		page, err := repo.GetPageAfter(ctx, nil, 10, domain.TripFilters{})
+		require.NoError(t, err)
+		assert.Equal(t, "CAD", page[1].Expenses[0].Currency)
+	})
+
+	t.Run("should total expenses per date and currency", func(t *testing.T) {
+		rows, err := repo.GetExpenseTotals(ctx, "", "2025-01-01", "2025-02-28", domain.TripFilters{})
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		assert.Equal(t, "2025-01-15", rows[0].TripDate)
+		assert.Equal(t, "USD", rows[0].Currency)
+		assert.Equal(t, domain.MustMoney("16.5"), rows[0].Amount)
+		assert.Equal(t, int64(2), rows[0].ExpenseCount)
+		assert.Equal(t, "2025-01-20", rows[1].TripDate)
+		assert.Equal(t, "CAD", rows[1].Currency)
+
+		rows, err = repo.GetExpenseTotals(ctx, domain.SummaryByClient, "2025-01-01", "2025-12-31",
+			domain.TripFilters{Clients: []string{"Acme Corp"}})
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		assert.Equal(t, "Acme Corp", rows[0].Group)
+		assert.Equal(t, domain.MustMoney("16.5"), rows[0].Amount)
+	})
+
+	t.Run("should not multiply trips by their expenses in summaries", func(t *testing.T) {
+		buckets, err := repo.GetSummaryBuckets(ctx, domain.GranularityMonth, "2025-01-01", "2025-02-28", domain.TripFilters{}, true)
+		require.NoError(t, err)
+		require.Len(t, buckets, 2)
+		assert.Equal(t, int64(2), buckets[0].TripCount)
+		assert.InDelta(t, 150.0, buckets[0].TotalMiles, 0.001)
+		assert.ElementsMatch(t, []float64{100, 50}, buckets[0].TripMiles)
+	})
+
+	t.Run("should replace expenses on update", func(t *testing.T) {
+		trip, err := repo.FindByID(ctx, acme.ID)
+		require.NoError(t, err)
+		kept := trip.Expenses[0].ID
+
+		trip.Expenses = []domain.Expense{trip.Expenses[0], {Type: domain.ExpenseOther, Amount: domain.MustMoney("3"), Currency: "USD"}}
+		require.NoError(t, repo.Update(ctx, trip))
+
+		updated, err := repo.FindByID(ctx, acme.ID)
+		require.NoError(t, err)
+		require.Len(t, updated.Expenses, 2)
+		assert.Equal(t, kept, updated.Expenses[0].ID)
+		assert.Equal(t, domain.ExpenseOther, updated.Expenses[1].Type)
+	})
+
+	t.Run("should delete expenses with their trip", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, beta.ID))
+
+		var count int64
+		require.NoError(t, db.Model(&domain.Expense{}).Where("trip_id = ?", beta.ID).Count(&count).Error)
+		assert.Zero(t, count)
+	})
+}
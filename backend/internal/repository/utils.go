@@ -2,11 +2,13 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"time"
 
 	"github.com/oscar/mileagetracker/internal/logger"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 // QueryPerformanceMonitor provides utilities for monitoring query performance
@@ -18,34 +20,38 @@ type QueryPerformanceMonitor struct {
 
 // Operation types for different database operations
 const (
-	OpCreate            = "create"
-	OpUpdate            = "update"
-	OpDelete            = "delete"
-	OpFind              = "find"
-	OpFindByID          = "find_by_id"
-	OpFindByName        = "find_by_name"
-	OpGetSuggestions    = "get_suggestions"
-	OpGetPaginated      = "get_paginated"
-	OpGetMonthlySummary = "get_monthly_summary"
-	OpGetByKey          = "get_by_key"
-	OpUpdateByKey       = "update_by_key"
-	OpGetAll            = "get_all"
+	OpCreate         = "create"
+	OpUpdate         = "update"
+	OpDelete         = "delete"
+	OpFind           = "find"
+	OpFindByID       = "find_by_id"
+	OpFindByName     = "find_by_name"
+	OpGetSuggestions = "get_suggestions"
+	OpGetPaginated   = "get_paginated"
+	OpGetPage        = "get_page"
+	OpGetSummary     = "get_summary"
+	OpGetByKey       = "get_by_key"
+	OpUpsert         = "upsert"
+	OpGetAll         = "get_all"
+	OpBulkUpsert     = "bulk_upsert"
 )
 
 // Default thresholds for different operation types (in milliseconds)
 var defaultThresholds = map[string]time.Duration{
-	OpCreate:            50 * time.Millisecond,
-	OpUpdate:            50 * time.Millisecond,
-	OpDelete:            50 * time.Millisecond,
-	OpFind:              30 * time.Millisecond,
-	OpFindByID:          30 * time.Millisecond,
-	OpFindByName:        30 * time.Millisecond,
-	OpGetSuggestions:    100 * time.Millisecond,
-	OpGetPaginated:      100 * time.Millisecond,
-	OpGetMonthlySummary: 200 * time.Millisecond,
-	OpGetByKey:          20 * time.Millisecond,
-	OpUpdateByKey:       100 * time.Millisecond,
-	OpGetAll:            50 * time.Millisecond,
+	OpCreate:         50 * time.Millisecond,
+	OpUpdate:         50 * time.Millisecond,
+	OpDelete:         50 * time.Millisecond,
+	OpFind:           30 * time.Millisecond,
+	OpFindByID:       30 * time.Millisecond,
+	OpFindByName:     30 * time.Millisecond,
+	OpGetSuggestions: 100 * time.Millisecond,
+	OpGetPaginated:   100 * time.Millisecond,
+	OpGetPage:        50 * time.Millisecond,
+	OpGetSummary:     200 * time.Millisecond,
+	OpGetByKey:       20 * time.Millisecond,
+	OpUpsert:         100 * time.Millisecond,
+	OpGetAll:         50 * time.Millisecond,
+	OpBulkUpsert:     500 * time.Millisecond,
 }
 
 var (
@@ -127,13 +133,27 @@ func GetTimeoutForOperation(operation string) time.Duration {
 	switch operation {
 	case OpFind, OpFindByID, OpFindByName, OpGetByKey:
 		return TimeoutFastRead
-	case OpCreate, OpUpdate, OpDelete, OpUpdateByKey:
+	case OpCreate, OpUpdate, OpDelete, OpUpsert:
 		return TimeoutWrite
-	case OpGetPaginated, OpGetSuggestions, OpGetAll:
+	case OpGetPaginated, OpGetPage, OpGetSuggestions, OpGetAll:
 		return TimeoutComplexRead
-	case OpGetMonthlySummary:
+	case OpGetSummary, OpBulkUpsert:
 		return TimeoutAggregation
 	default:
 		return TimeoutRead
 	}
 }
+
+// translateDuplicateKey returns gorm.ErrDuplicatedKey in place of a unique
+// constraint violation reported by the database, and any other error as is
+func translateDuplicateKey(db *gorm.DB, err error) error {
+	if err == nil {
+		return nil
+	}
+	if translator, ok := db.Dialector.(gorm.ErrorTranslator); ok {
+		if errors.Is(translator.Translate(err), gorm.ErrDuplicatedKey) {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	return err
+}
@@ -95,11 +95,11 @@ func TestGetTimeoutForOperation(t *testing.T) {
 		{OpCreate, TimeoutWrite},
 		{OpUpdate, TimeoutWrite},
 		{OpDelete, TimeoutWrite},
-		{OpUpdateByKey, TimeoutWrite},
+		{OpUpsert, TimeoutWrite},
 		{OpGetPaginated, TimeoutComplexRead},
 		{OpGetSuggestions, TimeoutComplexRead},
 		{OpGetAll, TimeoutComplexRead},
-		{OpGetMonthlySummary, TimeoutAggregation},
+		{OpGetSummary, TimeoutAggregation},
 		{"unknown_operation", TimeoutRead},
 	}
 
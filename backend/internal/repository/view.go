@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/oscar/mileagetracker/internal/database"
+	"github.com/oscar/mileagetracker/internal/domain"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type SavedViewRepository interface {
+	Create(ctx context.Context, view *domain.SavedView) error
+	Update(ctx context.Context, view *domain.SavedView) error
+	Delete(ctx context.Context, id uint) error
+	FindByID(ctx context.Context, id uint) (*domain.SavedView, error)
+	FindByName(ctx context.Context, name string) (*domain.SavedView, error)
+	GetAll(ctx context.Context) ([]domain.SavedView, error)
+}
+
+type savedViewRepository struct {
+	db *gorm.DB
+}
+
+func NewSavedViewRepository(store *database.Store) SavedViewRepository {
+	return &savedViewRepository{db: store.DB()}
+}
+
+func (r *savedViewRepository) Create(ctx context.Context, view *domain.SavedView) error {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpCreate, "saved_view")()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpCreate))
+	defer cancel()
+
+	err := r.db.WithContext(ctxWithTimeout).Create(view).Error
+	return translateDuplicateKey(r.db, err)
+}
+
+func (r *savedViewRepository) Update(ctx context.Context, view *domain.SavedView) error {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpUpdate, "saved_view", zap.Uint("id", view.ID))()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpUpdate))
+	defer cancel()
+
+	err := r.db.WithContext(ctxWithTimeout).Save(view).Error
+	return translateDuplicateKey(r.db, err)
+}
+
+func (r *savedViewRepository) Delete(ctx context.Context, id uint) error {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpDelete, "saved_view", zap.Uint("id", id))()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpDelete))
+	defer cancel()
+
+	return r.db.WithContext(ctxWithTimeout).Delete(&domain.SavedView{}, id).Error
+}
+
+func (r *savedViewRepository) FindByID(ctx context.Context, id uint) (*domain.SavedView, error) {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpFindByID, "saved_view", zap.Uint("id", id))()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpFindByID))
+	defer cancel()
+
+	var view domain.SavedView
+	err := r.db.WithContext(ctxWithTimeout).First(&view, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+
+// FindByName looks up a view by its exact name
+func (r *savedViewRepository) FindByName(ctx context.Context, name string) (*domain.SavedView, error) {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpFindByName, "saved_view", zap.String("name", name))()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpFindByName))
+	defer cancel()
+
+	var view domain.SavedView
+	err := r.db.WithContext(ctxWithTimeout).Where("name = ?", name).First(&view).Error
+	if err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+
+func (r *savedViewRepository) GetAll(ctx context.Context) ([]domain.SavedView, error) {
+	monitor := GetQueryPerformanceMonitor()
+	defer monitor.MonitorQuery(OpGetAll, "saved_view")()
+
+	ctxWithTimeout, cancel := WithTimeout(ctx, GetTimeoutForOperation(OpGetAll))
+	defer cancel()
+
+	views := []domain.SavedView{}
+	err := r.db.WithContext(ctxWithTimeout).Order("name ASC").Find(&views).Error
+	return views, err
+}
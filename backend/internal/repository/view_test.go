@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/oscar/mileagetracker/internal/database"
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestSavedViewRepository_CRUD(t *testing.T) {
+	db := testutils.SetupTestDB(t)
+	repo := NewSavedViewRepository(database.NewStore(db))
+	ctx := context.Background()
+
+	view := &domain.SavedView{
+		Name: "Acme last month",
+		Filters: domain.TripFilters{
+			Client:   "Acme Corp",
+			MinMiles: floatPtr(5),
+			Sort:     []domain.SortKey{{Field: domain.SortByMiles, Desc: true}},
+		},
+		DateRange: domain.DateRangeLastMonth,
+	}
+
+	t.Run("should store filters as JSON", func(t *testing.T) {
+		require.NoError(t, repo.Create(ctx, view))
+		assert.NotZero(t, view.ID)
+
+		found, err := repo.FindByID(ctx, view.ID)
+		require.NoError(t, err)
+		assert.Equal(t, view.Filters, found.Filters)
+		assert.Equal(t, domain.DateRangeLastMonth, found.DateRange)
+
+		var stored string
+		require.NoError(t, db.Raw("SELECT filters FROM saved_views WHERE id = ?", view.ID).Scan(&stored).Error)
+		assert.JSONEq(t, `{"client":"Acme Corp","min_miles":5,"sort":["-miles"]}`, stored)
+	})
+
+	t.Run("should find by name and list by name", func(t *testing.T) {
+		require.NoError(t, repo.Create(ctx, &domain.SavedView{Name: "All long trips", Filters: domain.TripFilters{MinMiles: floatPtr(100)}}))
+
+		found, err := repo.FindByName(ctx, "Acme last month")
+		require.NoError(t, err)
+		assert.Equal(t, view.ID, found.ID)
+
+		views, err := repo.GetAll(ctx)
+		require.NoError(t, err)
+		assert.Len(t, views, 2)
+		assert.Equal(t, "Acme last month", views[0].Name)
+		assert.Equal(t, "All long trips", views[1].Name)
+	})
+
+	t.Run("should report a name that is already taken", func(t *testing.T) {
+		assert.ErrorIs(t, repo.Create(ctx, &domain.SavedView{Name: "Acme last month"}), gorm.ErrDuplicatedKey)
+
+		renamed := *view
+		renamed.Name = "All long trips"
+		assert.ErrorIs(t, repo.Update(ctx, &renamed), gorm.ErrDuplicatedKey)
+	})
+
+	t.Run("should update and delete", func(t *testing.T) {
+		view.Filters.Client = "Beta Inc"
+		require.NoError(t, repo.Update(ctx, view))
+
+		found, err := repo.FindByID(ctx, view.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Beta Inc", found.Filters.Client)
+
+		require.NoError(t, repo.Delete(ctx, view.ID))
+		_, err = repo.FindByID(ctx, view.ID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
@@ -0,0 +1,83 @@
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/oscar/mileagetracker/internal/geo"
+)
+
+// OSRMProvider calculates driving distances using an OSRM-compatible
+// routing API such as a self-hosted osrm-backend
+type OSRMProvider struct {
+	baseURL string
+	profile string
+	client  *http.Client
+}
+
+// NewOSRMProvider creates a provider for the OSRM server at baseURL using
+// the given routing profile (for example "driving")
+func NewOSRMProvider(baseURL, profile string, timeout time.Duration) *OSRMProvider {
+	return &OSRMProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		profile: profile,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// osrmRouteResponse is the subset of the OSRM route service response we use
+type osrmRouteResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Routes  []struct {
+		Distance float64 `json:"distance"` // meters
+	} `json:"routes"`
+}
+
+func (p *OSRMProvider) Name() string {
+	return "osrm"
+}
+
+// Distance returns the length in miles of the fastest route between two points
+func (p *OSRMProvider) Distance(ctx context.Context, from, to geo.Point) (float64, error) {
+	// OSRM expects coordinates as lon,lat pairs separated by semicolons
+	coordinates := formatCoordinate(from) + ";" + formatCoordinate(to)
+	endpoint := fmt.Sprintf("%s/route/v1/%s/%s?overview=false&alternatives=false",
+		p.baseURL, url.PathEscape(p.profile), coordinates)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, http.NoBody)
+	if err != nil {
+		return 0, fmt.Errorf("failed to build OSRM request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("OSRM request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body osrmRouteResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, fmt.Errorf("failed to decode OSRM response (status %d): %w", resp.StatusCode, err)
+	}
+
+	if resp.StatusCode != http.StatusOK || body.Code != "Ok" {
+		return 0, fmt.Errorf("OSRM returned %s (status %d): %s", body.Code, resp.StatusCode, body.Message)
+	}
+	if len(body.Routes) == 0 {
+		return 0, fmt.Errorf("OSRM returned no routes")
+	}
+
+	return body.Routes[0].Distance / geo.MetersPerMile, nil
+}
+
+func formatCoordinate(p geo.Point) string {
+	return strconv.FormatFloat(p.Lon, 'f', -1, 64) + "," + strconv.FormatFloat(p.Lat, 'f', -1, 64)
+}
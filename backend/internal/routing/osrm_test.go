@@ -0,0 +1,76 @@
+package routing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/oscar/mileagetracker/internal/geo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOSRMProvider_Distance(t *testing.T) {
+	from := geo.Point{Lat: 40.7128, Lon: -74.006}
+	to := geo.Point{Lat: 40.73061, Lon: -73.935242}
+
+	t.Run("should request route and convert meters to miles", func(t *testing.T) {
+		var requestedPath, requestedQuery string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestedPath = r.URL.Path
+			requestedQuery = r.URL.RawQuery
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"code":"Ok","routes":[{"distance":16093.44,"duration":900}]}`))
+		}))
+		defer server.Close()
+
+		provider := NewOSRMProvider(server.URL+"/", "driving", time.Second)
+		miles, err := provider.Distance(context.Background(), from, to)
+
+		assert.NoError(t, err)
+		assert.InDelta(t, 10.0, miles, 1e-9)
+		assert.Equal(t, "/route/v1/driving/-74.006,40.7128;-73.935242,40.73061", requestedPath)
+		assert.Contains(t, requestedQuery, "overview=false")
+		assert.Equal(t, "osrm", provider.Name())
+	})
+
+	t.Run("should return error when no route is found", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":"NoRoute","message":"Impossible route between points"}`))
+		}))
+		defer server.Close()
+
+		provider := NewOSRMProvider(server.URL, "driving", time.Second)
+		_, err := provider.Distance(context.Background(), from, to)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "NoRoute")
+	})
+
+	t.Run("should return error for malformed response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`not json`))
+		}))
+		defer server.Close()
+
+		provider := NewOSRMProvider(server.URL, "driving", time.Second)
+		_, err := provider.Distance(context.Background(), from, to)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "decode")
+	})
+
+	t.Run("should respect timeout", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+		}))
+		defer server.Close()
+
+		provider := NewOSRMProvider(server.URL, "driving", 20*time.Millisecond)
+		_, err := provider.Distance(context.Background(), from, to)
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/logger"
+	"github.com/oscar/mileagetracker/internal/repository"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// BlobStore keeps the content of attachments. Implementations live in the
+// storage package.
+type BlobStore interface {
+	// Put stores size bytes read from content under key
+	Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error
+	// Get opens the content stored under key. The error wraps
+	// fs.ErrNotExist when there is none.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the content stored under key. Deleting a missing key
+	// succeeds.
+	Delete(ctx context.Context, key string) error
+}
+
+// attachmentContentTypes are the file types accepted as attachments. The
+// type is detected from the content rather than trusted from the client.
+var attachmentContentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+}
+
+type AttachmentService interface {
+	UploadAttachment(ctx context.Context, tripID uint, fileName string, content io.Reader, size int64) (*domain.Attachment, error)
+	GetAttachments(ctx context.Context, tripID uint) ([]domain.Attachment, error)
+	OpenAttachment(ctx context.Context, tripID, id uint) (*domain.Attachment, io.ReadCloser, error)
+	DeleteAttachment(ctx context.Context, tripID, id uint) error
+	FindTripAttachments(ctx context.Context, tripID uint) ([]domain.Attachment, error)
+	RemoveContent(ctx context.Context, attachments []domain.Attachment)
+}
+
+type attachmentService struct {
+	attachmentRepo repository.AttachmentRepository
+	tripRepo       repository.TripRepository
+	blobs          BlobStore
+	maxBytes       int64
+}
+
+func NewAttachmentService(
+	attachmentRepo repository.AttachmentRepository,
+	tripRepo repository.TripRepository,
+	blobs BlobStore,
+	maxBytes int64,
+) AttachmentService {
+	return &attachmentService{
+		attachmentRepo: attachmentRepo,
+		tripRepo:       tripRepo,
+		blobs:          blobs,
+		maxBytes:       maxBytes,
+	}
+}
+
+func (s *attachmentService) UploadAttachment(ctx context.Context, tripID uint, fileName string, content io.Reader, size int64) (*domain.Attachment, error) {
+	if err := s.ensureTrip(ctx, tripID); err != nil {
+		return nil, err
+	}
+
+	if size <= 0 {
+		return nil, fmt.Errorf("%w: attachment is empty", ErrValidation)
+	}
+	if size > s.maxBytes {
+		return nil, fmt.Errorf("%w: attachment must be %d MB or smaller", ErrValidation, s.maxBytes>>20)
+	}
+
+	// Sniff the type from the first bytes, then put them back in front of
+	// the rest of the content
+	head := make([]byte, 512)
+	n, err := io.ReadFull(content, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	if !attachmentContentTypes[contentType] {
+		return nil, fmt.Errorf("%w: unsupported attachment type %s, expected a JPEG, PNG, GIF or WebP image or a PDF", ErrValidation, contentType)
+	}
+
+	key, err := newStorageKey(tripID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.blobs.Put(ctx, key, io.MultiReader(bytes.NewReader(head), content), size, contentType); err != nil {
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+
+	attachment := &domain.Attachment{
+		TripID:      tripID,
+		FileName:    sanitizeFileName(fileName),
+		ContentType: contentType,
+		Size:        size,
+		StorageKey:  key,
+	}
+	if err := s.attachmentRepo.Create(ctx, attachment); err != nil {
+		// Don't leave the stored content behind without a record
+		if deleteErr := s.blobs.Delete(ctx, key); deleteErr != nil {
+			logger.Warn("Failed to remove orphaned attachment content",
+				zap.String("key", key), zap.Error(deleteErr))
+		}
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+func (s *attachmentService) GetAttachments(ctx context.Context, tripID uint) ([]domain.Attachment, error) {
+	if err := s.ensureTrip(ctx, tripID); err != nil {
+		return nil, err
+	}
+	return s.attachmentRepo.FindByTrip(ctx, tripID)
+}
+
+// OpenAttachment returns an attachment with its content, which the caller
+// must close
+func (s *attachmentService) OpenAttachment(ctx context.Context, tripID, id uint) (*domain.Attachment, io.ReadCloser, error) {
+	attachment, err := s.findAttachment(ctx, tripID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.blobs.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read attachment %d: %w", id, err)
+	}
+	return attachment, content, nil
+}
+
+// DeleteAttachment removes the content before the record, so a failed
+// delete can simply be retried
+func (s *attachmentService) DeleteAttachment(ctx context.Context, tripID, id uint) error {
+	attachment, err := s.findAttachment(ctx, tripID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.blobs.Delete(ctx, attachment.StorageKey); err != nil {
+		return fmt.Errorf("failed to delete attachment content: %w", err)
+	}
+	return s.attachmentRepo.Delete(ctx, id)
+}
+
+// FindTripAttachments returns a trip's attachments without checking the
+// trip exists, for collecting them ahead of the trip being deleted
+func (s *attachmentService) FindTripAttachments(ctx context.Context, tripID uint) ([]domain.Attachment, error) {
+	return s.attachmentRepo.FindByTrip(ctx, tripID)
+}
+
+// RemoveContent removes the stored content of attachments whose records
+// are already deleted. Nothing refers to the content any more, so a failure
+// is only logged.
+func (s *attachmentService) RemoveContent(ctx context.Context, attachments []domain.Attachment) {
+	for _, attachment := range attachments {
+		if err := s.blobs.Delete(ctx, attachment.StorageKey); err != nil {
+			logger.Warn("Failed to remove orphaned attachment content",
+				zap.String("key", attachment.StorageKey), zap.Error(err))
+		}
+	}
+}
+
+func (s *attachmentService) ensureTrip(ctx context.Context, tripID uint) error {
+	_, err := s.tripRepo.FindByID(ctx, tripID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("trip %d %w", tripID, ErrNotFound)
+	}
+	return err
+}
+
+// findAttachment looks up an attachment, treating one that belongs to a
+// different trip as missing
+func (s *attachmentService) findAttachment(ctx context.Context, tripID, id uint) (*domain.Attachment, error) {
+	attachment, err := s.attachmentRepo.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && attachment.TripID != tripID) {
+		return nil, fmt.Errorf("attachment %d %w", id, ErrNotFound)
+	}
+	return attachment, err
+}
+
+// newStorageKey returns an unguessable key under the trip's prefix
+func newStorageKey(tripID uint) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("failed to generate storage key: %w", err)
+	}
+	return fmt.Sprintf("trips/%d/%s", tripID, hex.EncodeToString(random)), nil
+}
+
+// sanitizeFileName keeps the base name of an uploaded file without control
+// characters, so it is safe to echo back in a Content-Disposition header
+func sanitizeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	if len(name) > 255 {
+		// Drop any rune cut in half by the truncation
+		name = strings.ToValidUTF8(name[:255], "")
+	}
+	return name
+}
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockAttachmentRepository implements the AttachmentRepository interface for testing
+type MockAttachmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockAttachmentRepository) Create(ctx context.Context, attachment *domain.Attachment) error {
+	args := m.Called(ctx, attachment)
+	return args.Error(0)
+}
+
+func (m *MockAttachmentRepository) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAttachmentRepository) FindByID(ctx context.Context, id uint) (*domain.Attachment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) FindByTrip(ctx context.Context, tripID uint) ([]domain.Attachment, error) {
+	args := m.Called(ctx, tripID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Attachment), args.Error(1)
+}
+
+// memoryBlobStore is an in-memory BlobStore for testing
+type memoryBlobStore struct {
+	blobs     map[string][]byte
+	deleteErr error
+}
+
+func newMemoryBlobStore() *memoryBlobStore {
+	return &memoryBlobStore{blobs: map[string][]byte{}}
+}
+
+func (s *memoryBlobStore) Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) != size {
+		return fmt.Errorf("size mismatch: got %d bytes, want %d", len(data), size)
+	}
+	s.blobs[key] = data
+	return nil
+}
+
+func (s *memoryBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	data, ok := s.blobs[key]
+	if !ok {
+		return nil, fs.ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *memoryBlobStore) Delete(ctx context.Context, key string) error {
+	if s.deleteErr != nil {
+		return s.deleteErr
+	}
+	delete(s.blobs, key)
+	return nil
+}
+
+// pngContent is a PNG signature followed by filler bytes
+var pngContent = append([]byte("\x89PNG\x0D\x0A\x1A\x0A"), bytes.Repeat([]byte{0}, 600)...)
+
+func TestAttachmentService_UploadAttachment(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should store content and record the detected type", func(t *testing.T) {
+		tripRepo := new(MockTripRepository)
+		attachmentRepo := new(MockAttachmentRepository)
+		blobs := newMemoryBlobStore()
+		svc := NewAttachmentService(attachmentRepo, tripRepo, blobs, 1<<20)
+
+		tripRepo.On("FindByID", ctx, uint(7)).Return(&domain.Trip{ID: 7}, nil)
+		attachmentRepo.On("Create", ctx, mock.AnythingOfType("*domain.Attachment")).Return(nil)
+
+		attachment, err := svc.UploadAttachment(ctx, 7, `C:\scans\receipt.png`, bytes.NewReader(pngContent), int64(len(pngContent)))
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(7), attachment.TripID)
+		assert.Equal(t, "receipt.png", attachment.FileName)
+		assert.Equal(t, "image/png", attachment.ContentType)
+		assert.Equal(t, int64(len(pngContent)), attachment.Size)
+		assert.True(t, strings.HasPrefix(attachment.StorageKey, "trips/7/"))
+		assert.Equal(t, pngContent, blobs.blobs[attachment.StorageKey])
+		attachmentRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return not found for a missing trip", func(t *testing.T) {
+		tripRepo := new(MockTripRepository)
+		svc := NewAttachmentService(new(MockAttachmentRepository), tripRepo, newMemoryBlobStore(), 1<<20)
+
+		tripRepo.On("FindByID", ctx, uint(9)).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := svc.UploadAttachment(ctx, 9, "receipt.png", bytes.NewReader(pngContent), int64(len(pngContent)))
+
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("should reject empty, oversized and unsupported files", func(t *testing.T) {
+		tripRepo := new(MockTripRepository)
+		blobs := newMemoryBlobStore()
+		svc := NewAttachmentService(new(MockAttachmentRepository), tripRepo, blobs, 512)
+
+		tripRepo.On("FindByID", ctx, uint(7)).Return(&domain.Trip{ID: 7}, nil)
+
+		_, err := svc.UploadAttachment(ctx, 7, "empty.png", bytes.NewReader(nil), 0)
+		assert.ErrorIs(t, err, ErrValidation)
+
+		_, err = svc.UploadAttachment(ctx, 7, "big.png", bytes.NewReader(pngContent), int64(len(pngContent)))
+		assert.ErrorIs(t, err, ErrValidation)
+
+		script := []byte("<html><script>alert(1)</script></html>")
+		_, err = svc.UploadAttachment(ctx, 7, "receipt.png", bytes.NewReader(script), int64(len(script)))
+		assert.ErrorIs(t, err, ErrValidation)
+		assert.Contains(t, err.Error(), "text/html")
+
+		assert.Empty(t, blobs.blobs)
+	})
+
+	t.Run("should remove stored content when the record cannot be saved", func(t *testing.T) {
+		tripRepo := new(MockTripRepository)
+		attachmentRepo := new(MockAttachmentRepository)
+		blobs := newMemoryBlobStore()
+		svc := NewAttachmentService(attachmentRepo, tripRepo, blobs, 1<<20)
+
+		tripRepo.On("FindByID", ctx, uint(7)).Return(&domain.Trip{ID: 7}, nil)
+		attachmentRepo.On("Create", ctx, mock.Anything).Return(errors.New("insert failed"))
+
+		_, err := svc.UploadAttachment(ctx, 7, "receipt.png", bytes.NewReader(pngContent), int64(len(pngContent)))
+
+		assert.EqualError(t, err, "insert failed")
+		assert.Empty(t, blobs.blobs)
+	})
+}
+
+func TestAttachmentService_OpenAttachment(t *testing.T) {
+	ctx := context.Background()
+	attachmentRepo := new(MockAttachmentRepository)
+	blobs := newMemoryBlobStore()
+	blobs.blobs["trips/7/abc"] = []byte("%PDF-1.7")
+	svc := NewAttachmentService(attachmentRepo, new(MockTripRepository), blobs, 1<<20)
+
+	attachmentRepo.On("FindByID", ctx, uint(3)).Return(&domain.Attachment{ID: 3, TripID: 7, StorageKey: "trips/7/abc"}, nil)
+	attachmentRepo.On("FindByID", ctx, uint(4)).Return(nil, gorm.ErrRecordNotFound)
+
+	t.Run("should return the attachment with its content", func(t *testing.T) {
+		attachment, content, err := svc.OpenAttachment(ctx, 7, 3)
+		assert.NoError(t, err)
+		defer content.Close()
+
+		data, _ := io.ReadAll(content)
+		assert.Equal(t, uint(3), attachment.ID)
+		assert.Equal(t, "%PDF-1.7", string(data))
+	})
+
+	t.Run("should hide attachments of other trips", func(t *testing.T) {
+		_, _, err := svc.OpenAttachment(ctx, 8, 3)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("should return not found for a missing attachment", func(t *testing.T) {
+		_, _, err := svc.OpenAttachment(ctx, 7, 4)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestAttachmentService_DeleteAttachment(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should delete content and record", func(t *testing.T) {
+		attachmentRepo := new(MockAttachmentRepository)
+		blobs := newMemoryBlobStore()
+		blobs.blobs["trips/7/abc"] = []byte("x")
+		svc := NewAttachmentService(attachmentRepo, new(MockTripRepository), blobs, 1<<20)
+
+		attachmentRepo.On("FindByID", ctx, uint(3)).Return(&domain.Attachment{ID: 3, TripID: 7, StorageKey: "trips/7/abc"}, nil)
+		attachmentRepo.On("Delete", ctx, uint(3)).Return(nil)
+
+		assert.NoError(t, svc.DeleteAttachment(ctx, 7, 3))
+		assert.Empty(t, blobs.blobs)
+		attachmentRepo.AssertExpectations(t)
+	})
+
+	t.Run("should keep the record when the content cannot be deleted", func(t *testing.T) {
+		attachmentRepo := new(MockAttachmentRepository)
+		blobs := newMemoryBlobStore()
+		blobs.deleteErr = errors.New("bucket unavailable")
+		svc := NewAttachmentService(attachmentRepo, new(MockTripRepository), blobs, 1<<20)
+
+		attachmentRepo.On("FindByID", ctx, uint(3)).Return(&domain.Attachment{ID: 3, TripID: 7, StorageKey: "trips/7/abc"}, nil)
+
+		err := svc.DeleteAttachment(ctx, 7, 3)
+
+		assert.Error(t, err)
+		attachmentRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTripService_DeleteTrip_RemovesAttachments(t *testing.T) {
+	ctx := context.Background()
+	// The trip repository deletes the attachment records with the trip
+	setup := func() (*MockTripRepository, *memoryBlobStore, TripService) {
+		tripRepo := new(MockTripRepository)
+		attachmentRepo := new(MockAttachmentRepository)
+		blobs := newMemoryBlobStore()
+		blobs.blobs["trips/7/a"] = []byte("a")
+		blobs.blobs["trips/7/b"] = []byte("b")
+		attachmentRepo.On("FindByTrip", ctx, uint(7)).Return([]domain.Attachment{
+			{ID: 1, TripID: 7, StorageKey: "trips/7/a"},
+			{ID: 2, TripID: 7, StorageKey: "trips/7/b"},
+		}, nil)
+		attachmentService := NewAttachmentService(attachmentRepo, tripRepo, blobs, 1<<20)
+		tripService := NewTripService(tripRepo, new(MockTripClientService), newMockTripSettingsRepository(), nil, nil, attachmentService, nil, time.UTC)
+		return tripRepo, blobs, tripService
+	}
+
+	t.Run("should remove the content after deleting the trip", func(t *testing.T) {
+		tripRepo, blobs, tripService := setup()
+		tripRepo.On("Delete", ctx, uint(7)).Return(nil)
+
+		assert.NoError(t, tripService.DeleteTrip(ctx, 7))
+		assert.Empty(t, blobs.blobs)
+		tripRepo.AssertExpectations(t)
+	})
+
+	t.Run("should keep the content when the trip cannot be deleted", func(t *testing.T) {
+		tripRepo, blobs, tripService := setup()
+		tripRepo.On("Delete", ctx, uint(7)).Return(errors.New("database delete error"))
+
+		assert.Error(t, tripService.DeleteTrip(ctx, 7))
+		assert.Len(t, blobs.blobs, 2)
+	})
+
+	t.Run("should delete the trip when the content cannot be removed", func(t *testing.T) {
+		if err := logger.Init("development"); err != nil {
+			t.Fatal(err)
+		}
+		tripRepo, blobs, tripService := setup()
+		blobs.deleteErr = errors.New("bucket unavailable")
+		tripRepo.On("Delete", ctx, uint(7)).Return(nil)
+
+		assert.NoError(t, tripService.DeleteTrip(ctx, 7))
+		tripRepo.AssertExpectations(t)
+	})
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/oscar/mileagetracker/internal/domain"
@@ -12,6 +14,7 @@ import (
 type ClientService interface {
 	GetOrCreateClient(ctx context.Context, name string) (*domain.Client, error)
 	GetSuggestions(ctx context.Context, query string) ([]domain.Client, error)
+	UpdateClient(ctx context.Context, id uint, req domain.UpdateClientRequest) (*domain.Client, error)
 }
 
 type clientService struct {
@@ -60,3 +63,25 @@ func (s *clientService) GetSuggestions(ctx context.Context, query string) ([]dom
 
 	return s.clientRepo.GetSuggestions(ctx, query, 10)
 }
+
+// UpdateClient sets the currency a client reimburses in
+func (s *clientService) UpdateClient(ctx context.Context, id uint, req domain.UpdateClientRequest) (*domain.Client, error) {
+	currency, err := parseCurrency(req.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := s.clientRepo.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("client %d %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	client.Currency = currency
+	if err := s.clientRepo.Update(ctx, client); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
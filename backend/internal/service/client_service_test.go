@@ -143,6 +143,19 @@ func (m *MockClientRepository) Create(ctx context.Context, client *domain.Client
 	return args.Error(0)
 }
 
+func (m *MockClientRepository) Update(ctx context.Context, client *domain.Client) error {
+	args := m.Called(ctx, client)
+	return args.Error(0)
+}
+
+func (m *MockClientRepository) FindByID(ctx context.Context, id uint) (*domain.Client, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Client), args.Error(1)
+}
+
 func (m *MockClientRepository) FindByName(ctx context.Context, name string) (*domain.Client, error) {
 	args := m.Called(ctx, name)
 	if args.Get(0) == nil {
@@ -452,3 +465,40 @@ func TestClientService_GetOrCreateClient_EdgeCases(t *testing.T) {
 		mockClientRepo.AssertExpectations(t)
 	})
 }
+
+func TestClientService_UpdateClient(t *testing.T) {
+	t.Run("should set the client's currency", func(t *testing.T) {
+		mockClientRepo := new(MockClientRepository)
+		clientService := NewClientService(mockClientRepo)
+
+		mockClientRepo.On("FindByID", mock.Anything, uint(1)).Return(&domain.Client{ID: 1, Name: "Maple Ltd"}, nil)
+		mockClientRepo.On("Update", mock.Anything, &domain.Client{ID: 1, Name: "Maple Ltd", Currency: "CAD"}).Return(nil)
+
+		result, err := clientService.UpdateClient(context.Background(), 1, domain.UpdateClientRequest{Currency: "cad"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "CAD", result.Currency)
+		mockClientRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject an invalid currency", func(t *testing.T) {
+		mockClientRepo := new(MockClientRepository)
+		clientService := NewClientService(mockClientRepo)
+
+		_, err := clientService.UpdateClient(context.Background(), 1, domain.UpdateClientRequest{Currency: "CA"})
+
+		assert.ErrorIs(t, err, ErrValidation)
+		mockClientRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return not found for an unknown client", func(t *testing.T) {
+		mockClientRepo := new(MockClientRepository)
+		clientService := NewClientService(mockClientRepo)
+
+		mockClientRepo.On("FindByID", mock.Anything, uint(9)).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := clientService.UpdateClient(context.Background(), 9, domain.UpdateClientRequest{})
+
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
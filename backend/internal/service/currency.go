@@ -0,0 +1,440 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/repository"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const (
+	mileageRateCurrencyKey = "mileage_rate_currency"
+
+	defaultExchangeRateLimit = 100
+	maxExchangeRateLimit     = 1000
+)
+
+// currencyPattern matches ISO 4217 alphabetic currency codes
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// parseCurrency uppercases a currency code. A blank code is returned empty
+// so callers can apply their own default.
+func parseCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return "", nil
+	}
+	if !currencyPattern.MatchString(code) {
+		return "", fmt.Errorf("%w: currency %q must be a three-letter ISO 4217 code", ErrValidation, code)
+	}
+	return code, nil
+}
+
+// mileageRateCurrency returns the currency the mileage rate is set in
+func mileageRateCurrency(ctx context.Context, settingsRepo repository.SettingsRepository) (string, error) {
+	return settingValue(ctx, settingsRepo, mileageRateCurrencySetting)
+}
+
+type ExchangeRateService interface {
+	GetRates(ctx context.Context, query domain.ExchangeRateQuery) ([]domain.ExchangeRate, error)
+	SetRate(ctx context.Context, req domain.ExchangeRateRequest) (*domain.ExchangeRate, error)
+	DeleteRate(ctx context.Context, id uint) error
+	ImportRates(ctx context.Context, data []byte) (*domain.ExchangeRateImportResult, error)
+
+	// Converter loads what is needed to convert between currencies on any
+	// date up to and including to
+	Converter(ctx context.Context, currencies []string, to string) (*CurrencyConverter, error)
+}
+
+type exchangeRateService struct {
+	rateRepo repository.ExchangeRateRepository
+}
+
+func NewExchangeRateService(rateRepo repository.ExchangeRateRepository) ExchangeRateService {
+	return &exchangeRateService{
+		rateRepo: rateRepo,
+	}
+}
+
+func (s *exchangeRateService) GetRates(ctx context.Context, query domain.ExchangeRateQuery) ([]domain.ExchangeRate, error) {
+	var err error
+	if query.Base, err = parseCurrency(query.Base); err != nil {
+		return nil, err
+	}
+	if query.Quote, err = parseCurrency(query.Quote); err != nil {
+		return nil, err
+	}
+	for name, date := range map[string]string{"date_from": query.DateFrom, "date_to": query.DateTo} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return nil, fmt.Errorf("%w: %s must be in YYYY-MM-DD format", ErrValidation, name)
+		}
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultExchangeRateLimit
+	}
+	if query.Limit < 0 || query.Limit > maxExchangeRateLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrValidation, maxExchangeRateLimit)
+	}
+
+	return s.rateRepo.Find(ctx, query)
+}
+
+// SetRate records a manually entered rate, replacing any rate already
+// recorded for the pair on that date
+func (s *exchangeRateService) SetRate(ctx context.Context, req domain.ExchangeRateRequest) (*domain.ExchangeRate, error) {
+	rate, err := newExchangeRate(req.Date, req.Base, req.Quote, req.Rate, domain.ExchangeRateManual)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.rateRepo.Upsert(ctx, []domain.ExchangeRate{rate}); err != nil {
+		return nil, err
+	}
+
+	saved, err := s.rateRepo.Find(ctx, domain.ExchangeRateQuery{
+		Base: rate.Base, Quote: rate.Quote, DateFrom: rate.Date, DateTo: rate.Date, Limit: 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(saved) == 0 {
+		return nil, fmt.Errorf("exchange rate %s/%s on %s was not saved", rate.Base, rate.Quote, rate.Date)
+	}
+	return &saved[0], nil
+}
+
+func (s *exchangeRateService) DeleteRate(ctx context.Context, id uint) error {
+	if _, err := s.rateRepo.FindByID(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("exchange rate %d %w", id, ErrNotFound)
+		}
+		return err
+	}
+	return s.rateRepo.Delete(ctx, id)
+}
+
+// ImportRates loads an exchange rate file. ECB reference rate files, as
+// XML or in the wide CSV layout of eurofxref.csv, give the value of 1 EUR
+// in each currency. Other CSV files need date, base, quote and rate
+// columns. Rates already recorded for a pair and date are replaced.
+func (s *exchangeRateService) ImportRates(ctx context.Context, data []byte) (*domain.ExchangeRateImportResult, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // UTF-8 byte order mark
+
+	var rates []domain.ExchangeRate
+	var format string
+	var err error
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '<' {
+		format = domain.ExchangeRateECB
+		rates, err = parseECBXML(data)
+	} else {
+		format, rates, err = parseExchangeRateCSV(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("%w: the file contains no exchange rates", ErrValidation)
+	}
+
+	if err := s.rateRepo.Upsert(ctx, rates); err != nil {
+		return nil, err
+	}
+
+	result := &domain.ExchangeRateImportResult{
+		Format:   format,
+		Imported: len(rates),
+		DateFrom: rates[0].Date,
+		DateTo:   rates[0].Date,
+	}
+	for _, rate := range rates[1:] {
+		if rate.Date < result.DateFrom {
+			result.DateFrom = rate.Date
+		}
+		if rate.Date > result.DateTo {
+			result.DateTo = rate.Date
+		}
+	}
+	return result, nil
+}
+
+func (s *exchangeRateService) Converter(ctx context.Context, currencies []string, to string) (*CurrencyConverter, error) {
+	rates, err := s.rateRepo.FindForCurrencies(ctx, currencies, to)
+	if err != nil {
+		return nil, err
+	}
+	return NewCurrencyConverter(rates), nil
+}
+
+// newExchangeRate validates one rate
+func newExchangeRate(date, base, quote string, value decimal.Decimal, source string) (domain.ExchangeRate, error) {
+	parsed, err := parseRateDate(date)
+	if err != nil {
+		return domain.ExchangeRate{}, err
+	}
+	if base, err = parseCurrency(base); err != nil {
+		return domain.ExchangeRate{}, err
+	}
+	if quote, err = parseCurrency(quote); err != nil {
+		return domain.ExchangeRate{}, err
+	}
+	if base == "" || quote == "" {
+		return domain.ExchangeRate{}, fmt.Errorf("%w: base and quote currencies are required", ErrValidation)
+	}
+	if base == quote {
+		return domain.ExchangeRate{}, fmt.Errorf("%w: base and quote currencies must differ", ErrValidation)
+	}
+	if !value.IsPositive() {
+		return domain.ExchangeRate{}, fmt.Errorf("%w: rate must be greater than 0", ErrValidation)
+	}
+
+	return domain.ExchangeRate{
+		Date:   parsed,
+		Base:   base,
+		Quote:  quote,
+		Rate:   value,
+		Source: source,
+	}, nil
+}
+
+// parseRateDate accepts YYYY-MM-DD and the "15 January 2025" form of the
+// daily ECB CSV file
+func parseRateDate(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{"2006-01-02", "2 January 2006"} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date.Format("2006-01-02"), nil
+		}
+	}
+	return "", fmt.Errorf("%w: date %q must be in YYYY-MM-DD format", ErrValidation, value)
+}
+
+// ecbEnvelope is the layout of the ECB eurofxref XML files:
+// <Cube><Cube time="2025-01-15"><Cube currency="USD" rate="1.0298"/>...
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string `xml:"currency,attr"`
+			Rate     string `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+func parseECBXML(data []byte) ([]domain.ExchangeRate, error) {
+	var envelope ecbEnvelope
+	if err := xml.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("%w: invalid ECB XML file: %v", ErrValidation, err)
+	}
+
+	var rates []domain.ExchangeRate
+	for _, day := range envelope.Days {
+		for _, entry := range day.Rates {
+			value, err := decimal.NewFromString(strings.TrimSpace(entry.Rate))
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid rate %q for %s on %s", ErrValidation, entry.Rate, entry.Currency, day.Time)
+			}
+			rate, err := newExchangeRate(day.Time, "EUR", entry.Currency, value, domain.ExchangeRateECB)
+			if err != nil {
+				return nil, err
+			}
+			rates = append(rates, rate)
+		}
+	}
+	return rates, nil
+}
+
+// parseExchangeRateCSV reads either a date,base,quote,rate file or an ECB
+// file with a Date column followed by one column per currency
+func parseExchangeRateCSV(data []byte) (string, []domain.ExchangeRate, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: the file has no header row", ErrValidation)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	dateCol, hasDate := columns["date"]
+	if !hasDate {
+		return "", nil, fmt.Errorf("%w: the file needs a date column", ErrValidation)
+	}
+	baseCol, hasBase := columns["base"]
+	quoteCol, hasQuote := columns["quote"]
+	rateCol, hasRate := columns["rate"]
+	long := hasBase && hasQuote && hasRate
+
+	format := domain.ExchangeRateCSV
+	if !long {
+		format = domain.ExchangeRateECB
+	}
+
+	var rates []domain.ExchangeRate
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: line %d: %v", ErrValidation, line, err)
+		}
+		if len(record) <= dateCol || strings.TrimSpace(record[dateCol]) == "" {
+			continue
+		}
+
+		if long {
+			if len(record) <= baseCol || len(record) <= quoteCol || len(record) <= rateCol {
+				return "", nil, fmt.Errorf("%w: line %d: missing columns", ErrValidation, line)
+			}
+			value, err := decimal.NewFromString(strings.TrimSpace(record[rateCol]))
+			if err != nil {
+				return "", nil, fmt.Errorf("%w: line %d: invalid rate %q", ErrValidation, line, record[rateCol])
+			}
+			rate, err := newExchangeRate(record[dateCol], record[baseCol], record[quoteCol], value, format)
+			if err != nil {
+				return "", nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			rates = append(rates, rate)
+			continue
+		}
+
+		for i, value := range record {
+			currency := ""
+			if i < len(header) {
+				currency = strings.TrimSpace(header[i])
+			}
+			value = strings.TrimSpace(value)
+			// ECB files end each line with a separator and mark missing
+			// rates N/A
+			if i == dateCol || currency == "" || value == "" || value == "N/A" {
+				continue
+			}
+			parsed, err := decimal.NewFromString(value)
+			if err != nil {
+				return "", nil, fmt.Errorf("%w: line %d: invalid rate %q for %s", ErrValidation, line, value, currency)
+			}
+			rate, err := newExchangeRate(record[dateCol], "EUR", currency, parsed, format)
+			if err != nil {
+				return "", nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			rates = append(rates, rate)
+		}
+	}
+	return format, rates, nil
+}
+
+// CurrencyConverter converts amounts with the latest exchange rate on or
+// before a given date. Pairs without rates of their own are converted
+// through a currency both have rates against, such as EUR for ECB rates.
+type CurrencyConverter struct {
+	rates      map[currencyPair][]datedRate // Oldest first
+	currencies []string                     // Every currency with a rate, sorted
+}
+
+type currencyPair struct {
+	base, quote string
+}
+
+type datedRate struct {
+	date string // YYYY-MM-DD
+	rate decimal.Decimal
+}
+
+func NewCurrencyConverter(rates []domain.ExchangeRate) *CurrencyConverter {
+	c := &CurrencyConverter{rates: make(map[currencyPair][]datedRate)}
+	seen := make(map[string]bool)
+	for _, rate := range rates {
+		pair := currencyPair{rate.Base, rate.Quote}
+		// Dates read back from a DATE column may carry a time part
+		date := rate.Date
+		if len(date) > len("2006-01-02") {
+			date = date[:len("2006-01-02")]
+		}
+		c.rates[pair] = append(c.rates[pair], datedRate{date: date, rate: rate.Rate})
+		for _, currency := range []string{rate.Base, rate.Quote} {
+			if !seen[currency] {
+				seen[currency] = true
+				c.currencies = append(c.currencies, currency)
+			}
+		}
+	}
+	for pair := range c.rates {
+		sort.SliceStable(c.rates[pair], func(i, j int) bool {
+			return c.rates[pair][i].date < c.rates[pair][j].date
+		})
+	}
+	sort.Strings(c.currencies)
+	return c
+}
+
+// Convert converts amount from one currency to another as of date
+// (YYYY-MM-DD). It reports false when no rate is known on or before date.
+// A nil converter only converts amounts that are already in the target
+// currency.
+func (c *CurrencyConverter) Convert(amount domain.Money, from, to, date string) (domain.Money, bool) {
+	if from == to {
+		return amount, true
+	}
+	if c == nil {
+		return domain.Money{}, false
+	}
+
+	if rate, ok := c.rate(from, to, date); ok {
+		return amount.Mul(rate), true
+	}
+	for _, via := range c.currencies {
+		if via == from || via == to {
+			continue
+		}
+		first, ok := c.rate(from, via, date)
+		if !ok {
+			continue
+		}
+		if second, ok := c.rate(via, to, date); ok {
+			return amount.Mul(first).Mul(second), true
+		}
+	}
+	return domain.Money{}, false
+}
+
+// rate returns the latest direct or inverted rate for a pair on or before
+// date, preferring whichever was recorded more recently
+func (c *CurrencyConverter) rate(from, to, date string) (decimal.Decimal, bool) {
+	direct, hasDirect := latestRate(c.rates[currencyPair{from, to}], date)
+	inverse, hasInverse := latestRate(c.rates[currencyPair{to, from}], date)
+	switch {
+	case hasDirect && (!hasInverse || direct.date >= inverse.date):
+		return direct.rate, true
+	case hasInverse:
+		return decimal.NewFromInt(1).Div(inverse.rate), true
+	}
+	return decimal.Decimal{}, false
+}
+
+func latestRate(rates []datedRate, date string) (datedRate, bool) {
+	// Index of the first rate after date
+	i := sort.Search(len(rates), func(i int) bool { return rates[i].date > date })
+	if i == 0 {
+		return datedRate{}, false
+	}
+	return rates[i-1], true
+}
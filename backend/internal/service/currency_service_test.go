@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockExchangeRateRepository implements the ExchangeRateRepository interface for testing
+type MockExchangeRateRepository struct {
+	mock.Mock
+}
+
+func (m *MockExchangeRateRepository) Upsert(ctx context.Context, rates []domain.ExchangeRate) error {
+	args := m.Called(ctx, rates)
+	return args.Error(0)
+}
+
+func (m *MockExchangeRateRepository) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockExchangeRateRepository) FindByID(ctx context.Context, id uint) (*domain.ExchangeRate, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ExchangeRate), args.Error(1)
+}
+
+func (m *MockExchangeRateRepository) Find(ctx context.Context, query domain.ExchangeRateQuery) ([]domain.ExchangeRate, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ExchangeRate), args.Error(1)
+}
+
+func (m *MockExchangeRateRepository) FindForCurrencies(ctx context.Context, currencies []string, to string) ([]domain.ExchangeRate, error) {
+	args := m.Called(ctx, currencies, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ExchangeRate), args.Error(1)
+}
+
+// MockExchangeRateService implements the ExchangeRateService interface for testing
+type MockExchangeRateService struct {
+	mock.Mock
+}
+
+func (m *MockExchangeRateService) GetRates(ctx context.Context, query domain.ExchangeRateQuery) ([]domain.ExchangeRate, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ExchangeRate), args.Error(1)
+}
+
+func (m *MockExchangeRateService) SetRate(ctx context.Context, req domain.ExchangeRateRequest) (*domain.ExchangeRate, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ExchangeRate), args.Error(1)
+}
+
+func (m *MockExchangeRateService) DeleteRate(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockExchangeRateService) ImportRates(ctx context.Context, data []byte) (*domain.ExchangeRateImportResult, error) {
+	args := m.Called(ctx, data)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ExchangeRateImportResult), args.Error(1)
+}
+
+func (m *MockExchangeRateService) Converter(ctx context.Context, currencies []string, to string) (*CurrencyConverter, error) {
+	args := m.Called(ctx, currencies, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*CurrencyConverter), args.Error(1)
+}
+
+func TestCurrencyConverter_Convert(t *testing.T) {
+	converter := NewCurrencyConverter([]domain.ExchangeRate{
+		{Date: "2025-01-02", Base: "USD", Quote: "CAD", Rate: decimal.RequireFromString("1.44")},
+		{Date: "2025-01-15", Base: "USD", Quote: "CAD", Rate: decimal.RequireFromString("1.43")},
+		{Date: "2025-01-15T00:00:00Z", Base: "EUR", Quote: "USD", Rate: decimal.RequireFromString("1.03")},
+		{Date: "2025-01-15", Base: "EUR", Quote: "GBP", Rate: decimal.RequireFromString("0.84")},
+		{Date: "2025-01-20", Base: "CAD", Quote: "USD", Rate: decimal.RequireFromString("0.7")},
+	})
+
+	tests := []struct {
+		name     string
+		from, to string
+		date     string
+		expected float64
+		ok       bool
+	}{
+		{"same currency", "CAD", "CAD", "2000-01-01", 100, true},
+		{"latest rate on or before the date", "USD", "CAD", "2025-01-16", 143, true},
+		{"rate of the day", "USD", "CAD", "2025-01-02", 144, true},
+		{"inverted rate", "CAD", "USD", "2025-01-16", 100 / 1.43, true},
+		{"more recent inverted rate", "USD", "CAD", "2025-01-25", 100 / 0.7, true},
+		{"through a third currency", "GBP", "USD", "2025-01-15", 100 / 0.84 * 1.03, true},
+		{"before the first rate", "USD", "CAD", "2025-01-01", 0, false},
+		{"unknown currency", "USD", "JPY", "2025-01-16", 0, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			amount, ok := converter.Convert(domain.MustMoney("100"), tc.from, tc.to, tc.date)
+			assert.Equal(t, tc.ok, ok)
+			assert.InDelta(t, tc.expected, amount.Float64(), 0.0001)
+		})
+	}
+
+	t.Run("should convert exactly at the stored rate", func(t *testing.T) {
+		precise := NewCurrencyConverter([]domain.ExchangeRate{
+			{Date: "2025-01-15", Base: "USD", Quote: "CAD", Rate: decimal.RequireFromString("1.4385")},
+		})
+
+		amount, ok := precise.Convert(domain.MustMoney("0.10"), "USD", "CAD", "2025-01-15")
+		assert.True(t, ok)
+		assert.Equal(t, domain.MustMoney("0.14385"), amount)
+	})
+
+	t.Run("nil converter only converts the same currency", func(t *testing.T) {
+		var none *CurrencyConverter
+		amount, ok := none.Convert(domain.MustMoney("5"), "USD", "USD", "2025-01-15")
+		assert.True(t, ok)
+		assert.Equal(t, domain.MustMoney("5"), amount)
+
+		_, ok = none.Convert(domain.MustMoney("5"), "USD", "CAD", "2025-01-15")
+		assert.False(t, ok)
+	})
+}
+
+func TestExchangeRateService_ImportRates(t *testing.T) {
+	t.Run("should import an ECB XML file", func(t *testing.T) {
+		mockRepo := new(MockExchangeRateRepository)
+		mockRepo.On("Upsert", mock.Anything, []domain.ExchangeRate{
+			{Date: "2025-01-15", Base: "EUR", Quote: "USD", Rate: decimal.RequireFromString("1.0298"), Source: domain.ExchangeRateECB},
+			{Date: "2025-01-15", Base: "EUR", Quote: "CAD", Rate: decimal.RequireFromString("1.4783"), Source: domain.ExchangeRateECB},
+			{Date: "2025-01-14", Base: "EUR", Quote: "USD", Rate: decimal.RequireFromString("1.0245"), Source: domain.ExchangeRateECB},
+		}).Return(nil)
+
+		result, err := NewExchangeRateService(mockRepo).ImportRates(context.Background(), []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<Cube>
+		<Cube time="2025-01-15">
+			<Cube currency="USD" rate="1.0298"/>
+			<Cube currency="CAD" rate="1.4783"/>
+		</Cube>
+		<Cube time="2025-01-14">
+			<Cube currency="USD" rate="1.0245"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`))
+
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.ExchangeRateImportResult{Format: "ecb", Imported: 3, DateFrom: "2025-01-14", DateTo: "2025-01-15"}, result)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should import an ECB CSV file", func(t *testing.T) {
+		mockRepo := new(MockExchangeRateRepository)
+		mockRepo.On("Upsert", mock.Anything, []domain.ExchangeRate{
+			{Date: "2025-01-15", Base: "EUR", Quote: "USD", Rate: decimal.RequireFromString("1.0298"), Source: domain.ExchangeRateECB},
+			{Date: "2025-01-15", Base: "EUR", Quote: "CAD", Rate: decimal.RequireFromString("1.4783"), Source: domain.ExchangeRateECB},
+		}).Return(nil)
+
+		result, err := NewExchangeRateService(mockRepo).ImportRates(context.Background(),
+			[]byte("Date, USD, CAD, CYP, \n15 January 2025, 1.0298, 1.4783, N/A, \n"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Imported)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should import a CSV file of pairs", func(t *testing.T) {
+		mockRepo := new(MockExchangeRateRepository)
+		mockRepo.On("Upsert", mock.Anything, []domain.ExchangeRate{
+			{Date: "2025-01-15", Base: "USD", Quote: "CAD", Rate: decimal.RequireFromString("1.43"), Source: domain.ExchangeRateCSV},
+		}).Return(nil)
+
+		result, err := NewExchangeRateService(mockRepo).ImportRates(context.Background(),
+			[]byte("\xef\xbb\xbfRate,Date,Base,Quote\n1.43,2025-01-15,usd,cad\n"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "csv", result.Format)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject invalid files", func(t *testing.T) {
+		for _, data := range []string{
+			"",
+			"<Envelope><Cube>",
+			"base,quote,rate\nUSD,CAD,1.43\n",
+			"date,base,quote,rate\n2025-01-15,USD,CAD,-1\n",
+			"date,base,quote,rate\n2025-01-15,USD,USD,1\n",
+			"date,base,quote,rate\n01/15/2025,USD,CAD,1.43\n",
+			"date,base,quote,rate\n",
+		} {
+			mockRepo := new(MockExchangeRateRepository)
+			_, err := NewExchangeRateService(mockRepo).ImportRates(context.Background(), []byte(data))
+			assert.ErrorIs(t, err, ErrValidation, data)
+			mockRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+		}
+	})
+}
+
+func TestExchangeRateService_SetRate(t *testing.T) {
+	t.Run("should save a manual rate", func(t *testing.T) {
+		mockRepo := new(MockExchangeRateRepository)
+		saved := domain.ExchangeRate{ID: 3, Date: "2025-01-15", Base: "USD", Quote: "CAD", Rate: decimal.RequireFromString("1.43"), Source: domain.ExchangeRateManual}
+		mockRepo.On("Upsert", mock.Anything, []domain.ExchangeRate{
+			{Date: "2025-01-15", Base: "USD", Quote: "CAD", Rate: decimal.RequireFromString("1.43"), Source: domain.ExchangeRateManual},
+		}).Return(nil)
+		mockRepo.On("Find", mock.Anything, domain.ExchangeRateQuery{
+			Base: "USD", Quote: "CAD", DateFrom: "2025-01-15", DateTo: "2025-01-15", Limit: 1,
+		}).Return([]domain.ExchangeRate{saved}, nil)
+
+		result, err := NewExchangeRateService(mockRepo).SetRate(context.Background(), domain.ExchangeRateRequest{
+			Date: "2025-01-15", Base: "usd", Quote: "cad", Rate: decimal.RequireFromString("1.43"),
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &saved, result)
+	})
+
+	t.Run("should reject an invalid pair", func(t *testing.T) {
+		_, err := NewExchangeRateService(new(MockExchangeRateRepository)).SetRate(context.Background(), domain.ExchangeRateRequest{
+			Date: "2025-01-15", Base: "USD", Quote: "usd", Rate: decimal.RequireFromString("1"),
+		})
+		assert.ErrorIs(t, err, ErrValidation)
+	})
+}
+
+func TestExchangeRateService_DeleteRate(t *testing.T) {
+	mockRepo := new(MockExchangeRateRepository)
+	mockRepo.On("FindByID", mock.Anything, uint(9)).Return(nil, gorm.ErrRecordNotFound)
+
+	err := NewExchangeRateService(mockRepo).DeleteRate(context.Background(), 9)
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
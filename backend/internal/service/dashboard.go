@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/oscar/mileagetracker/internal/domain"
+)
+
+// dashboardTopClients is how many clients the dashboard lists
+const dashboardTopClients = 5
+
+// GetDashboard computes the home screen figures. Everything except the
+// client ranking is derived from one daily series running from January 1st
+// last year to today.
+func (s *tripService) GetDashboard(ctx context.Context) (*domain.DashboardResponse, error) {
+	today := s.today()
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, s.location)
+
+	yearStart := time.Date(today.Year(), time.January, 1, 0, 0, 0, 0, s.location)
+	lastYearStart := yearStart.AddDate(-1, 0, 0)
+	lastYearToday := sameDayClamped(today.Year()-1, today.Month(), today.Day(), s.location)
+
+	monthStart := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, s.location)
+	lastMonthStart := monthStart.AddDate(0, -1, 0)
+	lastMonthToday := sameDayClamped(lastMonthStart.Year(), lastMonthStart.Month(), today.Day(), s.location)
+
+	expenses, err := s.tripRepo.GetExpenseTotals(ctx, "",
+		lastYearStart.Format("2006-01-02"), today.Format("2006-01-02"), domain.TripFilters{})
+	if err != nil {
+		return nil, err
+	}
+
+	// The dashboard reports in the mileage rate currency
+	pricer, err := s.newSummaryPricer(ctx, "", "", today, expenses)
+	if err != nil {
+		return nil, err
+	}
+
+	days, err := s.tripRepo.GetSummaryBuckets(ctx, domain.GranularityDay,
+		lastYearStart.Format("2006-01-02"), today.Format("2006-01-02"), domain.TripFilters{}, pricer.tripMiles())
+	if err != nil {
+		return nil, err
+	}
+
+	clients, err := s.tripRepo.GetGroupedSummaryBuckets(ctx, domain.GranularityYear, domain.SummaryByClient,
+		yearStart.Format("2006-01-02"), today.Format("2006-01-02"), domain.TripFilters{}, pricer.tripMiles())
+	if err != nil {
+		return nil, err
+	}
+
+	ytd := newPeriodTotals(yearStart, today)
+	lastYtd := newPeriodTotals(lastYearStart, lastYearToday)
+	mtd := newPeriodTotals(monthStart, today)
+	lastMtd := newPeriodTotals(lastMonthStart, lastMonthToday)
+	periods := []*domain.PeriodTotals{&ytd, &lastYtd, &mtd, &lastMtd}
+
+	weekdays := make([]domain.WeekdayTotals, 7)
+	for i := range weekdays {
+		weekdays[i].Weekday = time.Weekday((i + 1) % 7).String()
+	}
+
+	addToPeriods := func(date string, totals domain.SummaryTotals) {
+		for _, period := range periods {
+			// Dates are YYYY-MM-DD so they compare correctly as strings
+			if date >= period.From && date <= period.To {
+				addSummaryTotals(&period.SummaryTotals, totals)
+			}
+		}
+	}
+	for _, row := range expenses {
+		addToPeriods(row.TripDate, pricer.expenses(row))
+	}
+
+	for _, day := range days {
+		priced, err := pricer.trips(day.Totals(), day.StartDate)
+		if err != nil {
+			return nil, err
+		}
+		addToPeriods(day.StartDate, priced)
+
+		if day.StartDate >= ytd.From {
+			date, err := time.Parse("2006-01-02", day.StartDate)
+			if err != nil {
+				continue
+			}
+			i := (int(date.Weekday()) + 6) % 7 // Monday first
+			weekdays[i].TripCount += day.TripCount
+			weekdays[i].TotalMiles += day.TotalMiles
+		}
+	}
+
+	for _, period := range periods {
+		roundTotals(&period.SummaryTotals)
+	}
+
+	topClients := make([]domain.ClientTotals, 0, len(clients))
+	for _, row := range clients {
+		priced, err := pricer.trips(row.Totals(), row.StartDate)
+		if err != nil {
+			return nil, err
+		}
+		topClients = append(topClients, domain.ClientTotals{
+			ClientName: row.Group,
+			TripCount:  row.TripCount,
+			TotalMiles: row.TotalMiles,
+			Amount:     priced.Amount.RoundCents(),
+		})
+	}
+	sort.SliceStable(topClients, func(i, j int) bool {
+		if topClients[i].TotalMiles != topClients[j].TotalMiles {
+			return topClients[i].TotalMiles > topClients[j].TotalMiles
+		}
+		return topClients[i].ClientName < topClients[j].ClientName
+	})
+	if len(topClients) > dashboardTopClients {
+		topClients = topClients[:dashboardTopClients]
+	}
+
+	var averageMiles float64
+	if ytd.TripCount > 0 {
+		averageMiles = ytd.TotalMiles / float64(ytd.TripCount)
+	}
+
+	return &domain.DashboardResponse{
+		AsOf:                today.Format("2006-01-02"),
+		Timezone:            s.location.String(),
+		Currency:            pricer.currency,
+		Unit:                pricer.unit,
+		YearToDate:          comparePeriods(ytd, lastYtd),
+		MonthToDate:         comparePeriods(mtd, lastMtd),
+		AverageMilesPerTrip: averageMiles,
+		TopClients:          topClients,
+		Weekdays:            weekdays,
+	}, nil
+}
+
+func newPeriodTotals(from, to time.Time) domain.PeriodTotals {
+	return domain.PeriodTotals{
+		From: from.Format("2006-01-02"),
+		To:   to.Format("2006-01-02"),
+	}
+}
+
+func comparePeriods(current, previous domain.PeriodTotals) domain.PeriodComparison {
+	comparison := domain.PeriodComparison{
+		Current:  current,
+		Previous: previous,
+	}
+	if previous.TotalMiles > 0 {
+		change := (current.TotalMiles - previous.TotalMiles) / previous.TotalMiles * 100
+		comparison.MilesChangePercent = &change
+	}
+	return comparison
+}
+
+// sameDayClamped returns the given day of a month, moved back to the last
+// day of the month when it does not exist (e.g. February 29th or 31st)
+func sameDayClamped(year int, month time.Month, day int, location *time.Location) time.Time {
+	lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, location).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, location)
+}
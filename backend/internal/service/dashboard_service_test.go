@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTripService_GetDashboard(t *testing.T) {
+	newService := func(tripRepo *MockTripRepository) TripService {
+		settingsRepo := newMockTripSettingsRepository()
+		stubSetting(settingsRepo, "mileage_rate").Return(&domain.Settings{Key: "mileage_rate", Value: "0.5"}, nil)
+		settingsRepo.On("GetByKey", mock.Anything, "mileage_rate_currency").Return(&domain.Settings{Key: "mileage_rate_currency", Value: "USD"}, nil).Maybe()
+		// summaryTestNow is Monday 2025-09-15
+		return newSummaryTestService(tripRepo, new(MockTripClientService), settingsRepo)
+	}
+
+	t.Run("should compute year and month to date comparisons", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		mockTripRepo.On("GetExpenseTotals", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.ExpenseTotalRow{}, nil).Maybe()
+		mockTripRepo.On("GetSummaryBuckets", mock.Anything, domain.GranularityDay, "2024-01-01", "2025-09-15", domain.TripFilters{}, false).
+			Return([]domain.SummaryBucket{
+				{StartDate: "2024-03-10", TripCount: 1, TotalMiles: 100},
+				{StartDate: "2024-09-16", TripCount: 1, TotalMiles: 999}, // after the same date last year
+				{StartDate: "2025-02-03", TripCount: 2, TotalMiles: 60},  // Monday
+				{StartDate: "2025-08-10", TripCount: 1, TotalMiles: 20},  // Sunday, within the same days last month
+				{StartDate: "2025-08-20", TripCount: 1, TotalMiles: 5},   // Wednesday
+				{StartDate: "2025-09-15", TripCount: 1, TotalMiles: 40},  // Monday
+			}, nil)
+		mockTripRepo.On("GetGroupedSummaryBuckets", mock.Anything, domain.GranularityYear, domain.SummaryByClient, "2025-01-01", "2025-09-15", domain.TripFilters{}, false).
+			Return([]domain.SummaryGroupRow{
+				{Group: "A", StartDate: "2025-01-01", TripCount: 1, TotalMiles: 5},
+				{Group: "B", StartDate: "2025-01-01", TripCount: 1, TotalMiles: 50},
+				{Group: "C", StartDate: "2025-01-01", TripCount: 1, TotalMiles: 30},
+				{Group: "D", StartDate: "2025-01-01", TripCount: 1, TotalMiles: 20},
+				{Group: "E", StartDate: "2025-01-01", TripCount: 1, TotalMiles: 10},
+				{Group: "F", StartDate: "2025-01-01", TripCount: 1, TotalMiles: 10},
+			}, nil)
+
+		result, err := newService(mockTripRepo).GetDashboard(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2025-09-15", result.AsOf)
+		assert.Equal(t, "UTC", result.Timezone)
+		assert.Equal(t, domain.DistanceMiles, result.Unit)
+
+		assert.Equal(t, domain.PeriodTotals{From: "2025-01-01", To: "2025-09-15",
+			SummaryTotals: domain.SummaryTotals{TripCount: 5, TotalMiles: 125, Amount: domain.MustMoney("62.5"), TotalDistance: 125, TotalAmount: domain.MustMoney("62.5")}}, result.YearToDate.Current)
+		assert.Equal(t, "2024-09-15", result.YearToDate.Previous.To)
+		assert.Equal(t, 100.0, result.YearToDate.Previous.TotalMiles)
+		assert.Equal(t, 25.0, *result.YearToDate.MilesChangePercent)
+
+		assert.Equal(t, "2025-09-01", result.MonthToDate.Current.From)
+		assert.Equal(t, 40.0, result.MonthToDate.Current.TotalMiles)
+		assert.Equal(t, "2025-08-15", result.MonthToDate.Previous.To)
+		assert.Equal(t, 20.0, result.MonthToDate.Previous.TotalMiles)
+		assert.Equal(t, 100.0, *result.MonthToDate.MilesChangePercent)
+
+		assert.Equal(t, 25.0, result.AverageMilesPerTrip)
+
+		clients := []string{}
+		for _, client := range result.TopClients {
+			clients = append(clients, client.ClientName)
+		}
+		assert.Equal(t, []string{"B", "C", "D", "E", "F"}, clients)
+		assert.Equal(t, domain.MustMoney("25"), result.TopClients[0].Amount)
+
+		assert.Len(t, result.Weekdays, 7)
+		assert.Equal(t, domain.WeekdayTotals{Weekday: "Monday", TripCount: 3, TotalMiles: 100}, result.Weekdays[0])
+		assert.Equal(t, 5.0, result.Weekdays[2].TotalMiles)
+		assert.Equal(t, domain.WeekdayTotals{Weekday: "Sunday", TripCount: 1, TotalMiles: 20}, result.Weekdays[6])
+		mockTripRepo.AssertExpectations(t)
+	})
+
+	t.Run("should leave change empty without earlier trips", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		mockTripRepo.On("GetExpenseTotals", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.ExpenseTotalRow{}, nil).Maybe()
+		mockTripRepo.On("GetSummaryBuckets", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return([]domain.SummaryBucket{}, nil)
+		mockTripRepo.On("GetGroupedSummaryBuckets", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return([]domain.SummaryGroupRow{}, nil)
+
+		result, err := newService(mockTripRepo).GetDashboard(context.Background())
+
+		assert.NoError(t, err)
+		assert.Nil(t, result.YearToDate.MilesChangePercent)
+		assert.Equal(t, 0.0, result.AverageMilesPerTrip)
+		assert.Empty(t, result.TopClients)
+	})
+
+	t.Run("should return repository errors", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		mockTripRepo.On("GetExpenseTotals", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.ExpenseTotalRow{}, nil).Maybe()
+		mockTripRepo.On("GetSummaryBuckets", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, fmt.Errorf("database error"))
+
+		_, err := newService(mockTripRepo).GetDashboard(context.Background())
+
+		assert.Error(t, err)
+	})
+}
+
+func TestSameDayClamped(t *testing.T) {
+	assert.Equal(t, "2025-02-28", sameDayClamped(2025, time.February, 29, time.UTC).Format("2006-01-02"))
+	assert.Equal(t, "2025-04-30", sameDayClamped(2025, time.April, 31, time.UTC).Format("2006-01-02"))
+	assert.Equal(t, "2024-02-29", sameDayClamped(2024, time.February, 29, time.UTC).Format("2006-01-02"))
+}
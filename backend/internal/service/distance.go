@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/geo"
+	"github.com/oscar/mileagetracker/internal/logger"
+	"github.com/oscar/mileagetracker/internal/repository"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// DistanceProvider calculates the driving distance in miles between two
+// points. Implementations typically call out to a routing service.
+type DistanceProvider interface {
+	Name() string
+	Distance(ctx context.Context, from, to geo.Point) (float64, error)
+}
+
+// cacheCoordinatePrecision rounds coordinates used as cache keys to five
+// decimal places (about one meter) so tiny differences still hit the cache
+const cacheCoordinatePrecision = 1e5
+
+type cachedDistanceProvider struct {
+	provider  DistanceProvider
+	cacheRepo repository.RouteCacheRepository
+}
+
+// NewCachedDistanceProvider wraps a provider so that every distance it
+// returns is stored in the database and reused for later lookups
+func NewCachedDistanceProvider(provider DistanceProvider, cacheRepo repository.RouteCacheRepository) DistanceProvider {
+	return &cachedDistanceProvider{
+		provider:  provider,
+		cacheRepo: cacheRepo,
+	}
+}
+
+func (p *cachedDistanceProvider) Name() string {
+	return p.provider.Name()
+}
+
+func (p *cachedDistanceProvider) Distance(ctx context.Context, from, to geo.Point) (float64, error) {
+	from = roundCacheKey(from)
+	to = roundCacheKey(to)
+
+	cached, err := p.cacheRepo.Find(ctx, p.provider.Name(), from, to)
+	if err == nil {
+		return cached.Miles, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, err
+	}
+
+	miles, err := p.provider.Distance(ctx, from, to)
+	if err != nil {
+		return 0, err
+	}
+	miles = geo.RoundMiles(miles)
+
+	err = p.cacheRepo.Save(ctx, &domain.CachedRouteDistance{
+		Provider: p.provider.Name(),
+		FromLat:  from.Lat,
+		FromLon:  from.Lon,
+		ToLat:    to.Lat,
+		ToLon:    to.Lon,
+		Miles:    miles,
+	})
+	if err != nil {
+		// The distance is still good, it just has to be looked up again next time
+		logger.Warn("Failed to cache routed distance",
+			zap.String("provider", p.provider.Name()), zap.Error(err))
+	}
+
+	return miles, nil
+}
+
+func roundCacheKey(p geo.Point) geo.Point {
+	return geo.Point{
+		Lat: math.Round(p.Lat*cacheCoordinatePrecision) / cacheCoordinatePrecision,
+		Lon: math.Round(p.Lon*cacheCoordinatePrecision) / cacheCoordinatePrecision,
+	}
+}
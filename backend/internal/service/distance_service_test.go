@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/geo"
+	"github.com/oscar/mileagetracker/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockDistanceProvider implements the DistanceProvider interface for testing
+type MockDistanceProvider struct {
+	mock.Mock
+}
+
+func (m *MockDistanceProvider) Name() string {
+	return "mock"
+}
+
+func (m *MockDistanceProvider) Distance(ctx context.Context, from, to geo.Point) (float64, error) {
+	args := m.Called(ctx, from, to)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+// MockRouteCacheRepository implements the RouteCacheRepository interface for testing
+type MockRouteCacheRepository struct {
+	mock.Mock
+}
+
+func (m *MockRouteCacheRepository) Find(ctx context.Context, provider string, from, to geo.Point) (*domain.CachedRouteDistance, error) {
+	args := m.Called(ctx, provider, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CachedRouteDistance), args.Error(1)
+}
+
+func (m *MockRouteCacheRepository) Save(ctx context.Context, entry *domain.CachedRouteDistance) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func TestCachedDistanceProvider_Distance(t *testing.T) {
+	if err := logger.Init("development"); err != nil {
+		t.Fatal(err)
+	}
+
+	from := geo.Point{Lat: 40.7128004, Lon: -74.0060001}
+	to := geo.Point{Lat: 40.73061, Lon: -73.93524}
+	fromKey := geo.Point{Lat: 40.7128, Lon: -74.006}
+
+	t.Run("should return cached distance without calling provider", func(t *testing.T) {
+		mockProvider := new(MockDistanceProvider)
+		mockCache := new(MockRouteCacheRepository)
+		provider := NewCachedDistanceProvider(mockProvider, mockCache)
+
+		mockCache.On("Find", mock.Anything, "mock", fromKey, to).Return(&domain.CachedRouteDistance{Miles: 9.8}, nil)
+
+		miles, err := provider.Distance(context.Background(), from, to)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 9.8, miles)
+		mockProvider.AssertNotCalled(t, "Distance", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should call provider and store result on cache miss", func(t *testing.T) {
+		mockProvider := new(MockDistanceProvider)
+		mockCache := new(MockRouteCacheRepository)
+		provider := NewCachedDistanceProvider(mockProvider, mockCache)
+
+		mockCache.On("Find", mock.Anything, "mock", fromKey, to).Return(nil, gorm.ErrRecordNotFound)
+		mockProvider.On("Distance", mock.Anything, fromKey, to).Return(9.8765, nil)
+		mockCache.On("Save", mock.Anything, mock.MatchedBy(func(entry *domain.CachedRouteDistance) bool {
+			return entry.Provider == "mock" && entry.FromLat == fromKey.Lat && entry.Miles == 9.88
+		})).Return(nil)
+
+		miles, err := provider.Distance(context.Background(), from, to)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 9.88, miles)
+		mockCache.AssertExpectations(t)
+		mockProvider.AssertExpectations(t)
+	})
+
+	t.Run("should still return distance when caching fails", func(t *testing.T) {
+		mockProvider := new(MockDistanceProvider)
+		mockCache := new(MockRouteCacheRepository)
+		provider := NewCachedDistanceProvider(mockProvider, mockCache)
+
+		mockCache.On("Find", mock.Anything, "mock", fromKey, to).Return(nil, gorm.ErrRecordNotFound)
+		mockProvider.On("Distance", mock.Anything, fromKey, to).Return(5.0, nil)
+		mockCache.On("Save", mock.Anything, mock.Anything).Return(fmt.Errorf("disk full"))
+
+		miles, err := provider.Distance(context.Background(), from, to)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 5.0, miles)
+	})
+
+	t.Run("should return provider errors", func(t *testing.T) {
+		mockProvider := new(MockDistanceProvider)
+		mockCache := new(MockRouteCacheRepository)
+		provider := NewCachedDistanceProvider(mockProvider, mockCache)
+
+		mockCache.On("Find", mock.Anything, "mock", fromKey, to).Return(nil, gorm.ErrRecordNotFound)
+		mockProvider.On("Distance", mock.Anything, fromKey, to).Return(0.0, fmt.Errorf("routing service unavailable"))
+
+		_, err := provider.Distance(context.Background(), from, to)
+
+		assert.Error(t, err)
+		mockCache.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,12 @@
+package service
+
+import "errors"
+
+var (
+	// ErrValidation marks errors caused by invalid input rather than a
+	// failing dependency, so handlers can answer with a 400
+	ErrValidation = errors.New("validation failed")
+
+	// ErrNotFound marks errors caused by a referenced record not existing
+	ErrNotFound = errors.New("not found")
+)
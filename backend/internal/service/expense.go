@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/oscar/mileagetracker/internal/domain"
+)
+
+// buildExpenses validates the expenses of a trip request. Amounts are
+// rounded to cents, as they are stored. Expenses entered without a
+// currency are left without one for fillExpenseCurrencies.
+func buildExpenses(reqs []domain.ExpenseRequest) ([]domain.Expense, error) {
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+
+	expenses := make([]domain.Expense, len(reqs))
+	for i, req := range reqs {
+		if !req.Type.Valid() {
+			return nil, fmt.Errorf("%w: expense type must be one of toll, parking, fuel or other", ErrValidation)
+		}
+
+		amount := req.Amount.RoundCents()
+		if !amount.IsPositive() {
+			return nil, fmt.Errorf("%w: expense amount must be at least 0.01", ErrValidation)
+		}
+
+		currency, err := parseCurrency(req.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		expenses[i] = domain.Expense{
+			Type:     req.Type,
+			Amount:   amount,
+			Currency: currency,
+			Note:     strings.TrimSpace(req.Note),
+		}
+	}
+	return expenses, nil
+}
+
+// fillExpenseCurrencies sets the currency of expenses entered without one
+// to the client's currency, or to the mileage rate currency when the
+// client has none
+func (s *tripService) fillExpenseCurrencies(ctx context.Context, expenses []domain.Expense, client *domain.Client) error {
+	var currency string
+	for i := range expenses {
+		if expenses[i].Currency != "" {
+			continue
+		}
+		if currency == "" {
+			currency = client.Currency
+			if currency == "" {
+				var err error
+				if currency, err = mileageRateCurrency(ctx, s.settingsRepo); err != nil {
+					return err
+				}
+			}
+		}
+		expenses[i].Currency = currency
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/geo"
+	"github.com/oscar/mileagetracker/internal/logger"
+	"github.com/oscar/mileagetracker/internal/repository"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type LocationService interface {
+	CreateLocation(ctx context.Context, req domain.CreateLocationRequest) (*domain.Location, error)
+	UpdateLocation(ctx context.Context, id uint, req domain.UpdateLocationRequest) (*domain.Location, error)
+	DeleteLocation(ctx context.Context, id uint) error
+	GetLocation(ctx context.Context, id uint) (*domain.Location, error)
+	GetLocations(ctx context.Context) ([]domain.Location, error)
+	SetDistance(ctx context.Context, req domain.SetLocationDistanceRequest) (*domain.LocationDistance, error)
+	GetDistances(ctx context.Context) ([]domain.LocationDistance, error)
+	ResolveDistance(ctx context.Context, fromID, toID uint) (*domain.ResolvedDistance, error)
+}
+
+type locationService struct {
+	locationRepo     repository.LocationRepository
+	distanceProvider DistanceProvider
+}
+
+// NewLocationService creates a location service. The distance provider is
+// optional; without one, unrecorded distances fall back to an estimate.
+func NewLocationService(locationRepo repository.LocationRepository, distanceProvider DistanceProvider) LocationService {
+	return &locationService{
+		locationRepo:     locationRepo,
+		distanceProvider: distanceProvider,
+	}
+}
+
+func (s *locationService) CreateLocation(ctx context.Context, req domain.CreateLocationRequest) (*domain.Location, error) {
+	if err := validateCoordinates(req.Latitude, req.Longitude); err != nil {
+		return nil, err
+	}
+
+	location := &domain.Location{
+		Label:     strings.TrimSpace(req.Label),
+		Address:   strings.TrimSpace(req.Address),
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+	}
+
+	if err := s.locationRepo.Create(ctx, location); err != nil {
+		return nil, labelTaken(location.Label, err)
+	}
+
+	return location, nil
+}
+
+func (s *locationService) UpdateLocation(ctx context.Context, id uint, req domain.UpdateLocationRequest) (*domain.Location, error) {
+	if err := validateCoordinates(req.Latitude, req.Longitude); err != nil {
+		return nil, err
+	}
+
+	location, err := s.GetLocation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	location.Label = strings.TrimSpace(req.Label)
+	location.Address = strings.TrimSpace(req.Address)
+	location.Latitude = req.Latitude
+	location.Longitude = req.Longitude
+
+	if err := s.locationRepo.Update(ctx, location); err != nil {
+		return nil, labelTaken(location.Label, err)
+	}
+
+	return location, nil
+}
+
+// labelTaken reports a location label already in use as a validation error
+func labelTaken(label string, err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return fmt.Errorf("%w: a location labeled %q already exists", ErrValidation, label)
+	}
+	return err
+}
+
+func (s *locationService) DeleteLocation(ctx context.Context, id uint) error {
+	return s.locationRepo.Delete(ctx, id)
+}
+
+func (s *locationService) GetLocation(ctx context.Context, id uint) (*domain.Location, error) {
+	location, err := s.locationRepo.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("location %d %w", id, ErrNotFound)
+	}
+	return location, err
+}
+
+func (s *locationService) GetLocations(ctx context.Context) ([]domain.Location, error) {
+	return s.locationRepo.GetAll(ctx)
+}
+
+func (s *locationService) SetDistance(ctx context.Context, req domain.SetLocationDistanceRequest) (*domain.LocationDistance, error) {
+	if req.FromLocationID == req.ToLocationID {
+		return nil, fmt.Errorf("%w: from and to locations must be different", ErrValidation)
+	}
+
+	// Make sure both ends exist before recording a distance between them
+	for _, id := range []uint{req.FromLocationID, req.ToLocationID} {
+		if _, err := s.GetLocation(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	distance := &domain.LocationDistance{
+		FromLocationID: req.FromLocationID,
+		ToLocationID:   req.ToLocationID,
+		Miles:          geo.RoundMiles(req.Miles),
+	}
+
+	if err := s.locationRepo.SetDistance(ctx, distance); err != nil {
+		return nil, err
+	}
+
+	return distance, nil
+}
+
+func (s *locationService) GetDistances(ctx context.Context) ([]domain.LocationDistance, error) {
+	return s.locationRepo.GetDistances(ctx)
+}
+
+// ResolveDistance returns the recorded distance between two locations. When
+// none is recorded it asks the distance provider, and falls back to a
+// haversine-based estimate if there is no provider or the provider fails.
+func (s *locationService) ResolveDistance(ctx context.Context, fromID, toID uint) (*domain.ResolvedDistance, error) {
+	if fromID == toID {
+		return nil, fmt.Errorf("%w: from and to locations must be different", ErrValidation)
+	}
+
+	from, err := s.GetLocation(ctx, fromID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.GetLocation(ctx, toID)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := &domain.ResolvedDistance{
+		FromLocationID: fromID,
+		ToLocationID:   toID,
+	}
+
+	recorded, err := s.locationRepo.FindDistance(ctx, fromID, toID)
+	if err == nil {
+		resolved.Miles = recorded.Miles
+		resolved.Source = domain.DistanceSourceRecorded
+		return resolved, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if !from.HasCoordinates() || !to.HasCoordinates() {
+		return nil, fmt.Errorf("%w: no recorded distance between %q and %q and coordinates are missing",
+			ErrValidation, from.Label, to.Label)
+	}
+
+	fromPoint := geo.Point{Lat: *from.Latitude, Lon: *from.Longitude}
+	toPoint := geo.Point{Lat: *to.Latitude, Lon: *to.Longitude}
+
+	if s.distanceProvider != nil {
+		miles, err := s.distanceProvider.Distance(ctx, fromPoint, toPoint)
+		if err == nil {
+			resolved.Miles = miles
+			resolved.Source = domain.DistanceSourceRouted
+			return resolved, nil
+		}
+		logger.Warn("Distance provider failed, falling back to estimate",
+			zap.String("provider", s.distanceProvider.Name()),
+			zap.Uint("from_location_id", fromID),
+			zap.Uint("to_location_id", toID),
+			zap.Error(err))
+	}
+
+	resolved.Miles = geo.EstimateRoadMiles(fromPoint, toPoint)
+	resolved.Source = domain.DistanceSourceEstimate
+
+	return resolved, nil
+}
+
+func validateCoordinates(lat, lon *float64) error {
+	if (lat == nil) != (lon == nil) {
+		return fmt.Errorf("%w: latitude and longitude must be provided together", ErrValidation)
+	}
+	return nil
+}
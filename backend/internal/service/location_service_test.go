@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockLocationRepository implements the LocationRepository interface for testing
+type MockLocationRepository struct {
+	mock.Mock
+}
+
+func (m *MockLocationRepository) Create(ctx context.Context, location *domain.Location) error {
+	args := m.Called(ctx, location)
+	return args.Error(0)
+}
+
+func (m *MockLocationRepository) Update(ctx context.Context, location *domain.Location) error {
+	args := m.Called(ctx, location)
+	return args.Error(0)
+}
+
+func (m *MockLocationRepository) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockLocationRepository) FindByID(ctx context.Context, id uint) (*domain.Location, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Location), args.Error(1)
+}
+
+func (m *MockLocationRepository) GetAll(ctx context.Context) ([]domain.Location, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Location), args.Error(1)
+}
+
+func (m *MockLocationRepository) FindDistance(ctx context.Context, fromID, toID uint) (*domain.LocationDistance, error) {
+	args := m.Called(ctx, fromID, toID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LocationDistance), args.Error(1)
+}
+
+func (m *MockLocationRepository) SetDistance(ctx context.Context, distance *domain.LocationDistance) error {
+	args := m.Called(ctx, distance)
+	return args.Error(0)
+}
+
+func (m *MockLocationRepository) GetDistances(ctx context.Context) ([]domain.LocationDistance, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.LocationDistance), args.Error(1)
+}
+
+func coordinates(lat, lon float64) (*float64, *float64) {
+	return &lat, &lon
+}
+
+func newTestLocation(id uint, label string, withCoordinates bool) *domain.Location {
+	location := &domain.Location{ID: id, Label: label}
+	if withCoordinates {
+		// Spread test locations roughly ten miles apart along a meridian
+		location.Latitude, location.Longitude = coordinates(40.0+float64(id)*0.145, -74.0)
+	}
+	return location
+}
+
+func TestLocationService_CreateLocation(t *testing.T) {
+	t.Run("should create location with trimmed fields", func(t *testing.T) {
+		mockRepo := new(MockLocationRepository)
+		locationService := NewLocationService(mockRepo, nil)
+
+		lat, lon := coordinates(40.7, -74.0)
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Location")).Return(nil)
+
+		result, err := locationService.CreateLocation(context.Background(), domain.CreateLocationRequest{
+			Label:     "  Office ",
+			Address:   " 1 Main St ",
+			Latitude:  lat,
+			Longitude: lon,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Office", result.Label)
+		assert.Equal(t, "1 Main St", result.Address)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject latitude without longitude", func(t *testing.T) {
+		mockRepo := new(MockLocationRepository)
+		locationService := NewLocationService(mockRepo, nil)
+
+		lat, _ := coordinates(40.7, -74.0)
+		result, err := locationService.CreateLocation(context.Background(), domain.CreateLocationRequest{
+			Label:    "Office",
+			Latitude: lat,
+		})
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrValidation)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject a label that is already taken", func(t *testing.T) {
+		mockRepo := new(MockLocationRepository)
+		locationService := NewLocationService(mockRepo, nil)
+
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Location")).Return(gorm.ErrDuplicatedKey)
+
+		result, err := locationService.CreateLocation(context.Background(), domain.CreateLocationRequest{Label: " Office "})
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrValidation)
+		assert.EqualError(t, err, `validation failed: a location labeled "Office" already exists`)
+	})
+}
+
+func TestLocationService_SetDistance(t *testing.T) {
+	t.Run("should record distance between existing locations", func(t *testing.T) {
+		mockRepo := new(MockLocationRepository)
+		locationService := NewLocationService(mockRepo, nil)
+
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(newTestLocation(1, "Home", false), nil)
+		mockRepo.On("FindByID", mock.Anything, uint(2)).Return(newTestLocation(2, "Office", false), nil)
+		mockRepo.On("SetDistance", mock.Anything, mock.AnythingOfType("*domain.LocationDistance")).Return(nil)
+
+		result, err := locationService.SetDistance(context.Background(), domain.SetLocationDistanceRequest{
+			FromLocationID: 1,
+			ToLocationID:   2,
+			Miles:          12.345,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 12.35, result.Miles)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject distance to the same location", func(t *testing.T) {
+		locationService := NewLocationService(new(MockLocationRepository), nil)
+
+		_, err := locationService.SetDistance(context.Background(), domain.SetLocationDistanceRequest{
+			FromLocationID: 1,
+			ToLocationID:   1,
+			Miles:          5,
+		})
+
+		assert.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("should return not found for unknown location", func(t *testing.T) {
+		mockRepo := new(MockLocationRepository)
+		locationService := NewLocationService(mockRepo, nil)
+
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := locationService.SetDistance(context.Background(), domain.SetLocationDistanceRequest{
+			FromLocationID: 1,
+			ToLocationID:   2,
+			Miles:          5,
+		})
+
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestLocationService_ResolveDistance(t *testing.T) {
+	t.Run("should use recorded distance when available", func(t *testing.T) {
+		mockRepo := new(MockLocationRepository)
+		locationService := NewLocationService(mockRepo, nil)
+
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(newTestLocation(1, "Home", true), nil)
+		mockRepo.On("FindByID", mock.Anything, uint(2)).Return(newTestLocation(2, "Office", true), nil)
+		mockRepo.On("FindDistance", mock.Anything, uint(1), uint(2)).
+			Return(&domain.LocationDistance{FromLocationID: 1, ToLocationID: 2, Miles: 14.2}, nil)
+
+		result, err := locationService.ResolveDistance(context.Background(), 1, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 14.2, result.Miles)
+		assert.Equal(t, domain.DistanceSourceRecorded, result.Source)
+	})
+
+	t.Run("should estimate from coordinates when nothing is recorded", func(t *testing.T) {
+		mockRepo := new(MockLocationRepository)
+		locationService := NewLocationService(mockRepo, nil)
+
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(newTestLocation(1, "Home", true), nil)
+		mockRepo.On("FindByID", mock.Anything, uint(2)).Return(newTestLocation(2, "Office", true), nil)
+		mockRepo.On("FindDistance", mock.Anything, uint(1), uint(2)).Return(nil, gorm.ErrRecordNotFound)
+
+		result, err := locationService.ResolveDistance(context.Background(), 1, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.DistanceSourceEstimate, result.Source)
+		// 0.145 degrees of latitude is about 10 miles, plus the road factor
+		assert.InDelta(t, 12.0, result.Miles, 0.2)
+	})
+
+	t.Run("should use distance provider when nothing is recorded", func(t *testing.T) {
+		mockRepo := new(MockLocationRepository)
+		mockProvider := new(MockDistanceProvider)
+		locationService := NewLocationService(mockRepo, mockProvider)
+
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(newTestLocation(1, "Home", true), nil)
+		mockRepo.On("FindByID", mock.Anything, uint(2)).Return(newTestLocation(2, "Office", true), nil)
+		mockRepo.On("FindDistance", mock.Anything, uint(1), uint(2)).Return(nil, gorm.ErrRecordNotFound)
+		mockProvider.On("Distance", mock.Anything, mock.Anything, mock.Anything).Return(13.7, nil)
+
+		result, err := locationService.ResolveDistance(context.Background(), 1, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 13.7, result.Miles)
+		assert.Equal(t, domain.DistanceSourceRouted, result.Source)
+	})
+
+	t.Run("should fall back to estimate when distance provider fails", func(t *testing.T) {
+		if err := logger.Init("development"); err != nil {
+			t.Fatal(err)
+		}
+
+		mockRepo := new(MockLocationRepository)
+		mockProvider := new(MockDistanceProvider)
+		locationService := NewLocationService(mockRepo, mockProvider)
+
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(newTestLocation(1, "Home", true), nil)
+		mockRepo.On("FindByID", mock.Anything, uint(2)).Return(newTestLocation(2, "Office", true), nil)
+		mockRepo.On("FindDistance", mock.Anything, uint(1), uint(2)).Return(nil, gorm.ErrRecordNotFound)
+		mockProvider.On("Distance", mock.Anything, mock.Anything, mock.Anything).Return(0.0, fmt.Errorf("routing service unavailable"))
+
+		result, err := locationService.ResolveDistance(context.Background(), 1, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.DistanceSourceEstimate, result.Source)
+		assert.InDelta(t, 12.0, result.Miles, 0.2)
+	})
+
+	t.Run("should fail when nothing is recorded and coordinates are missing", func(t *testing.T) {
+		mockRepo := new(MockLocationRepository)
+		locationService := NewLocationService(mockRepo, nil)
+
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(newTestLocation(1, "Home", true), nil)
+		mockRepo.On("FindByID", mock.Anything, uint(2)).Return(newTestLocation(2, "Office", false), nil)
+		mockRepo.On("FindDistance", mock.Anything, uint(1), uint(2)).Return(nil, gorm.ErrRecordNotFound)
+
+		result, err := locationService.ResolveDistance(context.Background(), 1, 2)
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("should propagate repository errors", func(t *testing.T) {
+		mockRepo := new(MockLocationRepository)
+		locationService := NewLocationService(mockRepo, nil)
+
+		dbError := fmt.Errorf("database connection error")
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(newTestLocation(1, "Home", true), nil)
+		mockRepo.On("FindByID", mock.Anything, uint(2)).Return(newTestLocation(2, "Office", true), nil)
+		mockRepo.On("FindDistance", mock.Anything, uint(1), uint(2)).Return(nil, dbError)
+
+		_, err := locationService.ResolveDistance(context.Background(), 1, 2)
+
+		assert.Equal(t, dbError, err)
+	})
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/repository"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	mileageRateKey    = "mileage_rate"
+	amountRoundingKey = "amount_rounding"
+)
+
+// mileageRate returns the rate per mile
+func mileageRate(ctx context.Context, settingsRepo repository.SettingsRepository) (domain.Money, error) {
+	value, err := settingValue(ctx, settingsRepo, mileageRateSetting)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	return domain.MustMoney(value), nil
+}
+
+// amountRounding returns where mileage amounts are rounded
+func amountRounding(ctx context.Context, settingsRepo repository.SettingsRepository) (domain.AmountRounding, error) {
+	value, err := settingValue(ctx, settingsRepo, amountRoundingSetting)
+	return domain.AmountRounding(value), err
+}
+
+// mileageAmount returns miles times a rate per mile, unrounded
+func mileageAmount(miles float64, ratePerMile domain.Money) domain.Money {
+	return ratePerMile.Mul(decimal.NewFromFloat(miles))
+}
+
+// tripAmount prices a single trip, rounded to the cent
+func tripAmount(miles float64, ratePerMile domain.Money) domain.Money {
+	return mileageAmount(miles, ratePerMile).RoundCents()
+}
+
+// setTripAmounts prices every trip at the given rate per mile
+func setTripAmounts(trips []domain.Trip, ratePerMile domain.Money) {
+	for i := range trips {
+		trips[i].Amount = tripAmount(trips[i].Miles, ratePerMile)
+	}
+}
+
+// roundAmounts rounds the amounts of a period to the cent. The total is
+// the sum of the rounded amounts, so the figures shown add up.
+func roundAmounts(amount, expenseAmount, totalAmount *domain.Money) {
+	*amount = amount.RoundCents()
+	*expenseAmount = expenseAmount.RoundCents()
+	*totalAmount = amount.Add(*expenseAmount)
+}
+
+// roundBucket rounds a bucket's amounts; see roundAmounts
+func roundBucket(bucket *domain.SummaryBucket) {
+	roundAmounts(&bucket.Amount, &bucket.ExpenseAmount, &bucket.TotalAmount)
+}
+
+// roundTotals rounds totals' amounts; see roundAmounts
+func roundTotals(totals *domain.SummaryTotals) {
+	roundAmounts(&totals.Amount, &totals.ExpenseAmount, &totals.TotalAmount)
+}
@@ -2,16 +2,35 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
 
 	"github.com/oscar/mileagetracker/internal/domain"
 	"github.com/oscar/mileagetracker/internal/repository"
 )
 
+const (
+	fiscalYearStartMonthKey     = "fiscal_year_start_month"
+	defaultFiscalYearStartMonth = 1 // January, i.e. fiscal years match calendar years
+
+	// rateUnitKey is the unit mileage_rate is given per. It is not stored:
+	// rates are stored per mile.
+	rateUnitKey = "rate_unit"
+)
+
 type SettingsService interface {
-	GetSettings(ctx context.Context) (*domain.SettingsResponse, error)
-	UpdateSettings(ctx context.Context, req domain.UpdateSettingsRequest) (*domain.SettingsResponse, error)
+	// GetSettings returns every registered setting, with the mileage rate
+	// per unit, defaulting to the distance_unit setting
+	GetSettings(ctx context.Context, unit string) (domain.SettingsResponse, error)
+	// UpdateSettings stores the settings given and returns them all
+	UpdateSettings(ctx context.Context, req domain.UpdateSettingsRequest) (domain.SettingsResponse, error)
+	// GetDefinitions returns the registered settings
+	GetDefinitions() []domain.SettingDefinition
+	// SeedDefaults stores the default of every registered setting that is
+	// not set yet
+	SeedDefaults(ctx context.Context) error
 }
 
 type settingsService struct {
@@ -24,45 +43,126 @@ func NewSettingsService(settingsRepo repository.SettingsRepository) SettingsServ
 	}
 }
 
-func (s *settingsService) GetSettings(ctx context.Context) (*domain.SettingsResponse, error) {
-	mileageRate, err := s.settingsRepo.GetByKey(ctx, "mileage_rate")
+func (s *settingsService) GetSettings(ctx context.Context, unit string) (domain.SettingsResponse, error) {
+	rateUnit, err := parseDistanceUnit(unit)
 	if err != nil {
-		// Return default if not found
-		return &domain.SettingsResponse{
-			MileageRate: 0.67,
-		}, nil
+		return nil, err
 	}
+	return s.settings(ctx, nil, rateUnit)
+}
 
-	// Convert string value to float64
-	rate, err := strconv.ParseFloat(mileageRate.Value, 64)
-	if err != nil {
-		// Return default if invalid value
-		return &domain.SettingsResponse{
-			MileageRate: 0.67,
-		}, nil
+func (s *settingsService) UpdateSettings(ctx context.Context, req domain.UpdateSettingsRequest) (domain.SettingsResponse, error) {
+	keys := make([]string, 0, len(req))
+	for key := range req {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if _, ok := lookupSetting(key); !ok && key != rateUnitKey {
+			return nil, fmt.Errorf("%w: unknown setting %q", ErrValidation, key)
+		}
+	}
+
+	var rateUnit domain.DistanceUnit
+	if data, ok := req[rateUnitKey]; ok {
+		var name string
+		if err := json.Unmarshal(data, &name); err != nil {
+			return nil, fmt.Errorf("%w: %s must be a string", ErrValidation, rateUnitKey)
+		}
+		var err error
+		if rateUnit, err = parseDistanceUnit(name); err != nil {
+			return nil, err
+		}
+	}
+
+	updates := make(map[string]string, len(req))
+	for _, def := range settingDefinitions {
+		data, ok := req[def.Key]
+		if !ok {
+			continue
+		}
+		value, err := decodeSetting(def, data)
+		if err != nil {
+			return nil, err
+		}
+		updates[def.Key] = value
+	}
+
+	if rateUnit == "" {
+		if unit, ok := updates[distanceUnitKey]; ok {
+			rateUnit = domain.DistanceUnit(unit)
+		} else {
+			var err error
+			if rateUnit, err = distanceUnit(ctx, s.settingsRepo); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if rate, ok := updates[mileageRateKey]; ok {
+		// The rate is stored per mile, as an exact decimal string
+		updates[mileageRateKey] = rateUnit.RateToPerMile(domain.MustMoney(rate)).Decimal().String()
 	}
 
-	return &domain.SettingsResponse{
-		MileageRate: rate,
-	}, nil
+	// Store the settings together, so a failure leaves every one unchanged
+	settings := make([]domain.Settings, 0, len(updates))
+	for _, def := range settingDefinitions {
+		if value, ok := updates[def.Key]; ok {
+			settings = append(settings, domain.Settings{Key: def.Key, Value: value})
+		}
+	}
+	if len(settings) > 0 {
+		if err := s.settingsRepo.UpsertAll(ctx, settings); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.settings(ctx, updates, rateUnit)
 }
 
-func (s *settingsService) UpdateSettings(ctx context.Context, req domain.UpdateSettingsRequest) (*domain.SettingsResponse, error) {
-	// Validate mileage rate (already validated by binding, but check again for safety)
-	if req.MileageRate < 0 {
-		return nil, fmt.Errorf("mileage rate must be non-negative")
+func (s *settingsService) GetDefinitions() []domain.SettingDefinition {
+	return settingDefinitions
+}
+
+func (s *settingsService) SeedDefaults(ctx context.Context) error {
+	defaults := make([]domain.Settings, len(settingDefinitions))
+	for i, def := range settingDefinitions {
+		defaults[i] = domain.Settings{Key: def.Key, Value: def.Default}
 	}
+	return s.settingsRepo.InsertMissing(ctx, defaults)
+}
 
-	// Convert float64 to string for database storage
-	rateStr := strconv.FormatFloat(req.MileageRate, 'f', -1, 64)
+// settings returns every setting, taking values from updates over stored
+// ones, with the mileage rate per rateUnit. A blank rateUnit defaults to
+// the distance unit.
+func (s *settingsService) settings(ctx context.Context, updates map[string]string, rateUnit domain.DistanceUnit) (domain.SettingsResponse, error) {
+	values := make(map[string]string, len(settingDefinitions))
+	response := make(domain.SettingsResponse, len(settingDefinitions)+1)
+	for _, def := range settingDefinitions {
+		value, ok := updates[def.Key]
+		if !ok {
+			var err error
+			if value, err = settingValue(ctx, s.settingsRepo, def); err != nil {
+				return nil, err
+			}
+		}
+		values[def.Key] = value
+		response[def.Key] = settingJSON(def, value)
+	}
 
-	// Update the mileage rate
-	err := s.settingsRepo.UpdateByKey(ctx, "mileage_rate", rateStr)
-	if err != nil {
-		return nil, err
+	if rateUnit == "" {
+		rateUnit = domain.DistanceUnit(values[distanceUnitKey])
 	}
+	response[mileageRateKey] = ratePerUnit(domain.MustMoney(values[mileageRateKey]), rateUnit)
+	response[rateUnitKey] = string(rateUnit)
+
+	return response, nil
+}
 
-	return &domain.SettingsResponse{
-		MileageRate: req.MileageRate,
-	}, nil
+// fiscalYearStartMonth returns the month fiscal years start in
+func fiscalYearStartMonth(ctx context.Context, settingsRepo repository.SettingsRepository) (int, error) {
+	value, err := settingValue(ctx, settingsRepo, fiscalYearStartMonthSetting)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(value)
 }
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	mileageRateSetting = domain.SettingDefinition{
+		Key:         mileageRateKey,
+		Type:        domain.SettingTypeDecimal,
+		Default:     "0.67",
+		Description: "Amount paid per mile driven, in mileage_rate_currency. Stored per mile; given and returned per rate_unit.",
+		Parse:       parseMileageRate,
+	}
+	mileageRateCurrencySetting = domain.SettingDefinition{
+		Key:         mileageRateCurrencyKey,
+		Type:        domain.SettingTypeString,
+		Default:     domain.DefaultCurrency,
+		Description: "ISO 4217 code of the currency the mileage rate is set in.",
+		Parse:       parseMileageRateCurrency,
+	}
+	fiscalYearStartMonthSetting = domain.SettingDefinition{
+		Key:         fiscalYearStartMonthKey,
+		Type:        domain.SettingTypeInteger,
+		Default:     strconv.Itoa(defaultFiscalYearStartMonth),
+		Description: "Month fiscal years start in, from 1 (January) to 12.",
+		Parse:       parseFiscalYearStartMonth,
+	}
+	distanceUnitSetting = domain.SettingDefinition{
+		Key:         distanceUnitKey,
+		Type:        domain.SettingTypeString,
+		Default:     string(domain.DefaultDistanceUnit),
+		Values:      []string{string(domain.DistanceMiles), string(domain.DistanceKilometers)},
+		Description: "Default unit of distances and rates.",
+		Parse:       parseDistanceUnitSetting,
+	}
+	amountRoundingSetting = domain.SettingDefinition{
+		Key:         amountRoundingKey,
+		Type:        domain.SettingTypeString,
+		Default:     string(domain.DefaultAmountRounding),
+		Values:      []string{string(domain.RoundPerTrip), string(domain.RoundPerPeriod)},
+		Description: "Where mileage amounts are rounded to the cent: per_trip rounds every trip before adding them up, per_period rounds each period's total once.",
+	}
+)
+
+// settingDefinitions are the settings the API reads and writes, in the
+// order they are stored in
+var settingDefinitions = []domain.SettingDefinition{
+	mileageRateSetting,
+	mileageRateCurrencySetting,
+	fiscalYearStartMonthSetting,
+	distanceUnitSetting,
+	amountRoundingSetting,
+}
+
+func lookupSetting(key string) (domain.SettingDefinition, bool) {
+	for _, def := range settingDefinitions {
+		if def.Key == key {
+			return def, true
+		}
+	}
+	return domain.SettingDefinition{}, false
+}
+
+// settingValue returns the stored value of a setting in canonical form. A
+// setting that is not set has its default. An error reading it, or a
+// stored value that is invalid, is returned: it is not a client error, so
+// it doesn't wrap ErrValidation.
+func settingValue(ctx context.Context, settingsRepo repository.SettingsRepository, def domain.SettingDefinition) (string, error) {
+	setting, err := settingsRepo.GetByKey(ctx, def.Key)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return def.Default, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read setting %s: %w", def.Key, err)
+	}
+
+	value, err := parseSetting(def, setting.Value)
+	if err != nil {
+		return "", fmt.Errorf("setting %s holds an invalid value %q", def.Key, setting.Value)
+	}
+
+	return value, nil
+}
+
+// parseSetting validates a value of def in stored form and returns its
+// canonical form
+func parseSetting(def domain.SettingDefinition, value string) (string, error) {
+	if def.Parse != nil {
+		return def.Parse(value)
+	}
+
+	switch def.Type {
+	case domain.SettingTypeDecimal:
+		money, err := domain.NewMoney(value)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s must be a decimal", ErrValidation, def.Key)
+		}
+		value = money.Decimal().String()
+	case domain.SettingTypeInteger:
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return "", fmt.Errorf("%w: %s must be an integer", ErrValidation, def.Key)
+		}
+		value = strconv.Itoa(n)
+	}
+
+	if len(def.Values) > 0 && !containsString(def.Values, value) {
+		return "", fmt.Errorf("%w: %s must be %s", ErrValidation, def.Key, joinAlternatives(def.Values))
+	}
+	return value, nil
+}
+
+// decodeSetting decodes a value of def from JSON, encoded as in
+// settingJSON, and returns its canonical stored form
+func decodeSetting(def domain.SettingDefinition, data json.RawMessage) (string, error) {
+	var value string
+	switch def.Type {
+	case domain.SettingTypeDecimal:
+		var money domain.Money
+		if string(data) == "null" || json.Unmarshal(data, &money) != nil {
+			return "", fmt.Errorf("%w: %s must be a decimal", ErrValidation, def.Key)
+		}
+		value = money.Decimal().String()
+	case domain.SettingTypeInteger:
+		var n int
+		if json.Unmarshal(data, &n) != nil {
+			return "", fmt.Errorf("%w: %s must be an integer", ErrValidation, def.Key)
+		}
+		value = strconv.Itoa(n)
+	default:
+		if json.Unmarshal(data, &value) != nil || string(data) == "null" {
+			return "", fmt.Errorf("%w: %s must be a string", ErrValidation, def.Key)
+		}
+	}
+	return parseSetting(def, value)
+}
+
+// settingJSON returns a canonical stored value of def as it is encoded in
+// JSON
+func settingJSON(def domain.SettingDefinition, value string) interface{} {
+	switch def.Type {
+	case domain.SettingTypeDecimal:
+		money, _ := domain.NewMoney(value)
+		return money
+	case domain.SettingTypeInteger:
+		n, _ := strconv.Atoi(value)
+		return n
+	}
+	return value
+}
+
+func parseMileageRate(value string) (string, error) {
+	rate, err := domain.NewMoney(strings.TrimSpace(value))
+	if err != nil {
+		return "", fmt.Errorf("%w: mileage_rate must be a decimal", ErrValidation)
+	}
+	if rate.IsNegative() {
+		return "", fmt.Errorf("%w: mileage_rate must not be negative", ErrValidation)
+	}
+	return rate.Decimal().String(), nil
+}
+
+func parseMileageRateCurrency(value string) (string, error) {
+	currency, err := parseCurrency(value)
+	if err != nil {
+		return "", err
+	}
+	if currency == "" {
+		return "", fmt.Errorf("%w: mileage_rate_currency must not be blank", ErrValidation)
+	}
+	return currency, nil
+}
+
+func parseFiscalYearStartMonth(value string) (string, error) {
+	month, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || month < 1 || month > 12 {
+		return "", fmt.Errorf("%w: fiscal_year_start_month must be between 1 and 12", ErrValidation)
+	}
+	return strconv.Itoa(month), nil
+}
+
+func parseDistanceUnitSetting(value string) (string, error) {
+	unit, ok := domain.ParseDistanceUnit(value)
+	if !ok || unit == "" {
+		return "", fmt.Errorf("%w: distance_unit must be mi or km", ErrValidation)
+	}
+	return string(unit), nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// joinAlternatives joins values as "a, b or c"
+func joinAlternatives(values []string) string {
+	if len(values) == 1 {
+		return values[0]
+	}
+	return strings.Join(values[:len(values)-1], ", ") + " or " + values[len(values)-1]
+}
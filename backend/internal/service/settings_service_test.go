@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -14,6 +15,10 @@ import (
 func TestSettingsService_GetSettings(t *testing.T) {
 	mockSettingsRepo := new(MockSettingsRepository)
 	settingsService := NewSettingsService(mockSettingsRepo)
+	mockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate_currency").Return(&domain.Settings{Key: "mileage_rate_currency", Value: "USD"}, nil).Maybe()
+	mockSettingsRepo.On("GetByKey", mock.Anything, "distance_unit").Return(&domain.Settings{Key: "distance_unit", Value: "mi"}, nil).Maybe()
+	mockSettingsRepo.On("GetByKey", mock.Anything, "amount_rounding").Return(nil, gorm.ErrRecordNotFound).Maybe()
+	mockSettingsRepo.On("GetByKey", mock.Anything, "fiscal_year_start_month").Return(nil, gorm.ErrRecordNotFound).Maybe()
 
 	t.Run("should return settings successfully", func(t *testing.T) {
 		// Setup
@@ -27,12 +32,12 @@ func TestSettingsService_GetSettings(t *testing.T) {
 		mockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate").Return(mileageRateSetting, nil)
 
 		// Execute
-		result, err := settingsService.GetSettings(context.Background())
+		result, err := settingsService.GetSettings(context.Background(), "")
 
 		// Assert
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
-		assert.Equal(t, float64(0.67), result.MileageRate)
+		assert.Equal(t, domain.MustMoney("0.67"), result["mileage_rate"])
 
 		mockSettingsRepo.AssertExpectations(t)
 	})
@@ -42,12 +47,12 @@ func TestSettingsService_GetSettings(t *testing.T) {
 		mockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate").Return(nil, gorm.ErrRecordNotFound)
 
 		// Execute
-		result, err := settingsService.GetSettings(context.Background())
+		result, err := settingsService.GetSettings(context.Background(), "")
 
 		// Assert
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
-		assert.Equal(t, float64(0.67), result.MileageRate) // Default value
+		assert.Equal(t, domain.MustMoney("0.67"), result["mileage_rate"]) // Default value
 
 		mockSettingsRepo.AssertExpectations(t)
 	})
@@ -64,16 +69,55 @@ func TestSettingsService_GetSettings(t *testing.T) {
 		mockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate").Return(mileageRateSetting, nil)
 
 		// Execute
-		result, err := settingsService.GetSettings(context.Background())
+		result, err := settingsService.GetSettings(context.Background(), "")
 
 		// Assert
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
-		assert.Equal(t, float64(0.67), result.MileageRate) // Default value
+		assert.Equal(t, domain.MustMoney("0.67"), result["mileage_rate"]) // Default value
 
 		mockSettingsRepo.AssertExpectations(t)
 	})
 
+	t.Run("should return fiscal year start month", func(t *testing.T) {
+		freshMockSettingsRepo := new(MockSettingsRepository)
+		freshSettingsService := NewSettingsService(freshMockSettingsRepo)
+
+		freshMockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate_currency").Return(&domain.Settings{Key: "mileage_rate_currency", Value: "USD"}, nil).Maybe()
+		freshMockSettingsRepo.On("GetByKey", mock.Anything, "distance_unit").Return(&domain.Settings{Key: "distance_unit", Value: "mi"}, nil).Maybe()
+		freshMockSettingsRepo.On("GetByKey", mock.Anything, "amount_rounding").Return(nil, gorm.ErrRecordNotFound).Maybe()
+		freshMockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate").Return(&domain.Settings{Key: "mileage_rate", Value: "0.7"}, nil)
+		freshMockSettingsRepo.On("GetByKey", mock.Anything, "fiscal_year_start_month").
+			Return(&domain.Settings{Key: "fiscal_year_start_month", Value: "7"}, nil)
+
+		result, err := freshSettingsService.GetSettings(context.Background(), "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 7, result["fiscal_year_start_month"])
+
+		freshMockSettingsRepo.AssertExpectations(t)
+	})
+
+	t.Run("should fail on an invalid fiscal year start month", func(t *testing.T) {
+		for _, value := range []string{"0", "13", "July"} {
+			freshMockSettingsRepo := new(MockSettingsRepository)
+			freshSettingsService := NewSettingsService(freshMockSettingsRepo)
+
+			freshMockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate_currency").Return(&domain.Settings{Key: "mileage_rate_currency", Value: "USD"}, nil).Maybe()
+			freshMockSettingsRepo.On("GetByKey", mock.Anything, "distance_unit").Return(&domain.Settings{Key: "distance_unit", Value: "mi"}, nil).Maybe()
+			freshMockSettingsRepo.On("GetByKey", mock.Anything, "amount_rounding").Return(nil, gorm.ErrRecordNotFound).Maybe()
+			freshMockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate").Return(&domain.Settings{Key: "mileage_rate", Value: "0.7"}, nil)
+			freshMockSettingsRepo.On("GetByKey", mock.Anything, "fiscal_year_start_month").
+				Return(&domain.Settings{Key: "fiscal_year_start_month", Value: value}, nil)
+
+			result, err := freshSettingsService.GetSettings(context.Background(), "")
+
+			assert.Nil(t, result)
+			assert.EqualError(t, err, fmt.Sprintf("setting fiscal_year_start_month holds an invalid value %q", value))
+			assert.NotErrorIs(t, err, ErrValidation)
+		}
+	})
+
 	t.Run("should handle various invalid value formats", func(t *testing.T) {
 		testCases := []struct {
 			name  string
@@ -89,6 +133,7 @@ func TestSettingsService_GetSettings(t *testing.T) {
 			{"currency format", "$1.23"},
 			{"percentage", "12.3%"},
 			{"fraction", "1/2"},
+			{"negative", "-1.23"},
 		}
 
 		for _, tc := range testCases {
@@ -104,15 +149,20 @@ func TestSettingsService_GetSettings(t *testing.T) {
 				}
 
 				// Mock expectations
+				freshMockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate_currency").Return(&domain.Settings{Key: "mileage_rate_currency", Value: "USD"}, nil).Maybe()
+				freshMockSettingsRepo.On("GetByKey", mock.Anything, "distance_unit").Return(&domain.Settings{Key: "distance_unit", Value: "mi"}, nil).Maybe()
+				freshMockSettingsRepo.On("GetByKey", mock.Anything, "amount_rounding").Return(nil, gorm.ErrRecordNotFound).Maybe()
 				freshMockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate").Return(mileageRateSetting, nil)
+				freshMockSettingsRepo.On("GetByKey", mock.Anything, "fiscal_year_start_month").Return(nil, gorm.ErrRecordNotFound).Maybe()
 
 				// Execute
-				result, err := freshSettingsService.GetSettings(context.Background())
+				result, err := freshSettingsService.GetSettings(context.Background(), "")
 
-				// Assert
-				assert.NoError(t, err)
-				assert.NotNil(t, result)
-				assert.Equal(t, float64(0.67), result.MileageRate) // Should always default
+				// Assert - a stored value that is invalid is a server error
+				assert.Error(t, err)
+				assert.NotErrorIs(t, err, ErrValidation)
+				assert.Contains(t, err.Error(), "setting mileage_rate holds an invalid value")
+				assert.Nil(t, result)
 
 				freshMockSettingsRepo.AssertExpectations(t)
 			})
@@ -134,7 +184,6 @@ func TestSettingsService_GetSettings(t *testing.T) {
 			{"leading zeros", "00123.45", 123.45},
 			{"trailing zeros", "123.450", 123.45},
 			{"no decimal point", "123", 123.0},
-			{"negative value", "-1.23", -1.23},
 			{"very small", "1e-10", 1e-10},
 			{"very large", "1e10", 1e10},
 		}
@@ -152,15 +201,19 @@ func TestSettingsService_GetSettings(t *testing.T) {
 				}
 
 				// Mock expectations
+				freshMockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate_currency").Return(&domain.Settings{Key: "mileage_rate_currency", Value: "USD"}, nil).Maybe()
+				freshMockSettingsRepo.On("GetByKey", mock.Anything, "distance_unit").Return(&domain.Settings{Key: "distance_unit", Value: "mi"}, nil).Maybe()
+				freshMockSettingsRepo.On("GetByKey", mock.Anything, "amount_rounding").Return(nil, gorm.ErrRecordNotFound).Maybe()
 				freshMockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate").Return(mileageRateSetting, nil)
+				freshMockSettingsRepo.On("GetByKey", mock.Anything, "fiscal_year_start_month").Return(nil, gorm.ErrRecordNotFound)
 
 				// Execute
-				result, err := freshSettingsService.GetSettings(context.Background())
+				result, err := freshSettingsService.GetSettings(context.Background(), "")
 
 				// Assert
 				assert.NoError(t, err)
 				assert.NotNil(t, result)
-				assert.Equal(t, tc.expected, result.MileageRate)
+				assert.Equal(t, domain.MoneyFromFloat(tc.expected), result["mileage_rate"])
 
 				freshMockSettingsRepo.AssertExpectations(t)
 			})
@@ -182,15 +235,18 @@ func TestSettingsService_GetSettings(t *testing.T) {
 				freshSettingsService := NewSettingsService(freshMockSettingsRepo)
 
 				// Mock expectations - return non-record-not-found error
+				freshMockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate_currency").Return(&domain.Settings{Key: "mileage_rate_currency", Value: "USD"}, nil).Maybe()
+				freshMockSettingsRepo.On("GetByKey", mock.Anything, "distance_unit").Return(&domain.Settings{Key: "distance_unit", Value: "mi"}, nil).Maybe()
+				freshMockSettingsRepo.On("GetByKey", mock.Anything, "amount_rounding").Return(nil, gorm.ErrRecordNotFound).Maybe()
 				freshMockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate").Return(nil, dbError)
+				freshMockSettingsRepo.On("GetByKey", mock.Anything, "fiscal_year_start_month").Return(nil, gorm.ErrRecordNotFound).Maybe()
 
 				// Execute
-				result, err := freshSettingsService.GetSettings(context.Background())
+				result, err := freshSettingsService.GetSettings(context.Background(), "")
 
-				// Assert - should still return default, current implementation doesn't distinguish errors
-				assert.NoError(t, err)
-				assert.NotNil(t, result)
-				assert.Equal(t, float64(0.67), result.MileageRate)
+				// Assert - only a setting that is not set falls back to its default
+				assert.ErrorIs(t, err, dbError)
+				assert.Nil(t, result)
 
 				freshMockSettingsRepo.AssertExpectations(t)
 			})
@@ -198,6 +254,16 @@ func TestSettingsService_GetSettings(t *testing.T) {
 	})
 }
 
+// settingsRequest encodes values as an update request
+func settingsRequest(values map[string]interface{}) domain.UpdateSettingsRequest {
+	req := make(domain.UpdateSettingsRequest, len(values))
+	for key, value := range values {
+		data, _ := json.Marshal(value)
+		req[key] = data
+	}
+	return req
+}
+
 // MockSettingsRepository implements the SettingsRepository interface for testing
 type MockSettingsRepository struct {
 	mock.Mock
@@ -211,8 +277,13 @@ func (m *MockSettingsRepository) GetByKey(ctx context.Context, key string) (*dom
 	return args.Get(0).(*domain.Settings), args.Error(1)
 }
 
-func (m *MockSettingsRepository) UpdateByKey(ctx context.Context, key, value string) error {
-	args := m.Called(ctx, key, value)
+func (m *MockSettingsRepository) UpsertAll(ctx context.Context, settings []domain.Settings) error {
+	args := m.Called(ctx, settings)
+	return args.Error(0)
+}
+
+func (m *MockSettingsRepository) InsertMissing(ctx context.Context, settings []domain.Settings) error {
+	args := m.Called(ctx, settings)
 	return args.Error(0)
 }
 
@@ -227,15 +298,19 @@ func (m *MockSettingsRepository) GetAll(ctx context.Context) ([]domain.Settings,
 func TestSettingsService_UpdateSettings(t *testing.T) {
 	mockSettingsRepo := new(MockSettingsRepository)
 	settingsService := NewSettingsService(mockSettingsRepo)
+	mockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate_currency").Return(&domain.Settings{Key: "mileage_rate_currency", Value: "USD"}, nil).Maybe()
+	mockSettingsRepo.On("GetByKey", mock.Anything, "distance_unit").Return(&domain.Settings{Key: "distance_unit", Value: "mi"}, nil).Maybe()
+	mockSettingsRepo.On("GetByKey", mock.Anything, "amount_rounding").Return(nil, gorm.ErrRecordNotFound).Maybe()
+	mockSettingsRepo.On("GetByKey", mock.Anything, "fiscal_year_start_month").Return(nil, gorm.ErrRecordNotFound).Maybe()
 
 	t.Run("should update settings successfully", func(t *testing.T) {
 		// Setup
-		updateRequest := domain.UpdateSettingsRequest{
-			MileageRate: 0.75,
-		}
+		updateRequest := settingsRequest(map[string]interface{}{
+			"mileage_rate": domain.MustMoney("0.75"),
+		})
 
 		// Mock expectations
-		mockSettingsRepo.On("UpdateByKey", mock.Anything, "mileage_rate", "0.75").Return(nil)
+		mockSettingsRepo.On("UpsertAll", mock.Anything, []domain.Settings{{Key: "mileage_rate", Value: "0.75"}}).Return(nil)
 
 		// Execute
 		result, err := settingsService.UpdateSettings(context.Background(), updateRequest)
@@ -243,16 +318,16 @@ func TestSettingsService_UpdateSettings(t *testing.T) {
 		// Assert
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
-		assert.Equal(t, float64(0.75), result.MileageRate)
+		assert.Equal(t, domain.MustMoney("0.75"), result["mileage_rate"])
 
 		mockSettingsRepo.AssertExpectations(t)
 	})
 
 	t.Run("should handle negative mileage rate", func(t *testing.T) {
 		// Setup
-		updateRequest := domain.UpdateSettingsRequest{
-			MileageRate: -0.5,
-		}
+		updateRequest := settingsRequest(map[string]interface{}{
+			"mileage_rate": domain.MustMoney("-0.5"),
+		})
 
 		// Execute
 		result, err := settingsService.UpdateSettings(context.Background(), updateRequest)
@@ -260,7 +335,8 @@ func TestSettingsService_UpdateSettings(t *testing.T) {
 		// Assert
 		assert.Error(t, err)
 		assert.Nil(t, result)
-		assert.Contains(t, err.Error(), "mileage rate must be non-negative")
+		assert.ErrorIs(t, err, ErrValidation)
+		assert.Contains(t, err.Error(), "mileage_rate must not be negative")
 
 		// No expectations because the method should fail before calling repo
 		mockSettingsRepo.AssertExpectations(t)
@@ -268,12 +344,12 @@ func TestSettingsService_UpdateSettings(t *testing.T) {
 
 	t.Run("should handle zero mileage rate", func(t *testing.T) {
 		// Setup
-		updateRequest := domain.UpdateSettingsRequest{
-			MileageRate: 0.0,
-		}
+		updateRequest := settingsRequest(map[string]interface{}{
+			"mileage_rate": domain.MustMoney("0.0"),
+		})
 
 		// Mock expectations
-		mockSettingsRepo.On("UpdateByKey", mock.Anything, "mileage_rate", "0").Return(nil)
+		mockSettingsRepo.On("UpsertAll", mock.Anything, []domain.Settings{{Key: "mileage_rate", Value: "0"}}).Return(nil)
 
 		// Execute
 		result, err := settingsService.UpdateSettings(context.Background(), updateRequest)
@@ -281,20 +357,20 @@ func TestSettingsService_UpdateSettings(t *testing.T) {
 		// Assert
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
-		assert.Equal(t, float64(0.0), result.MileageRate)
+		assert.Equal(t, domain.MustMoney("0.0"), result["mileage_rate"])
 
 		mockSettingsRepo.AssertExpectations(t)
 	})
 
 	t.Run("should handle database error during update", func(t *testing.T) {
 		// Setup
-		updateRequest := domain.UpdateSettingsRequest{
-			MileageRate: 0.67,
-		}
+		updateRequest := settingsRequest(map[string]interface{}{
+			"mileage_rate": domain.MustMoney("0.67"),
+		})
 		dbError := gorm.ErrInvalidDB
 
 		// Mock expectations
-		mockSettingsRepo.On("UpdateByKey", mock.Anything, "mileage_rate", "0.67").Return(dbError)
+		mockSettingsRepo.On("UpsertAll", mock.Anything, []domain.Settings{{Key: "mileage_rate", Value: "0.67"}}).Return(dbError)
 
 		// Execute
 		result, err := settingsService.UpdateSettings(context.Background(), updateRequest)
@@ -309,12 +385,12 @@ func TestSettingsService_UpdateSettings(t *testing.T) {
 
 	t.Run("should handle decimal precision correctly", func(t *testing.T) {
 		// Setup
-		updateRequest := domain.UpdateSettingsRequest{
-			MileageRate: 0.655,
-		}
+		updateRequest := settingsRequest(map[string]interface{}{
+			"mileage_rate": domain.MustMoney("0.655"),
+		})
 
 		// Mock expectations
-		mockSettingsRepo.On("UpdateByKey", mock.Anything, "mileage_rate", "0.655").Return(nil)
+		mockSettingsRepo.On("UpsertAll", mock.Anything, []domain.Settings{{Key: "mileage_rate", Value: "0.655"}}).Return(nil)
 
 		// Execute
 		result, err := settingsService.UpdateSettings(context.Background(), updateRequest)
@@ -322,8 +398,311 @@ func TestSettingsService_UpdateSettings(t *testing.T) {
 		// Assert
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
-		assert.Equal(t, float64(0.655), result.MileageRate)
+		assert.Equal(t, domain.MustMoney("0.655"), result["mileage_rate"])
 
 		mockSettingsRepo.AssertExpectations(t)
 	})
+
+	t.Run("should update fiscal year start month", func(t *testing.T) {
+		freshMockSettingsRepo := new(MockSettingsRepository)
+		freshSettingsService := NewSettingsService(freshMockSettingsRepo)
+
+		startMonth := 7
+		freshMockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate_currency").Return(&domain.Settings{Key: "mileage_rate_currency", Value: "USD"}, nil).Maybe()
+		freshMockSettingsRepo.On("GetByKey", mock.Anything, "distance_unit").Return(&domain.Settings{Key: "distance_unit", Value: "mi"}, nil).Maybe()
+		freshMockSettingsRepo.On("GetByKey", mock.Anything, "amount_rounding").Return(nil, gorm.ErrRecordNotFound).Maybe()
+		freshMockSettingsRepo.On("UpsertAll", mock.Anything, []domain.Settings{{Key: "mileage_rate", Value: "0.67"}, {Key: "fiscal_year_start_month", Value: "7"}}).Return(nil)
+
+		result, err := freshSettingsService.UpdateSettings(context.Background(), settingsRequest(map[string]interface{}{
+			"mileage_rate":            domain.MustMoney("0.67"),
+			"fiscal_year_start_month": startMonth,
+		}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 7, result["fiscal_year_start_month"])
+
+		freshMockSettingsRepo.AssertExpectations(t)
+	})
+
+	t.Run("should keep fiscal year start month when omitted", func(t *testing.T) {
+		freshMockSettingsRepo := new(MockSettingsRepository)
+		freshSettingsService := NewSettingsService(freshMockSettingsRepo)
+
+		freshMockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate_currency").Return(&domain.Settings{Key: "mileage_rate_currency", Value: "USD"}, nil).Maybe()
+		freshMockSettingsRepo.On("GetByKey", mock.Anything, "distance_unit").Return(&domain.Settings{Key: "distance_unit", Value: "mi"}, nil).Maybe()
+		freshMockSettingsRepo.On("GetByKey", mock.Anything, "amount_rounding").Return(nil, gorm.ErrRecordNotFound).Maybe()
+		freshMockSettingsRepo.On("UpsertAll", mock.Anything, []domain.Settings{{Key: "mileage_rate", Value: "0.67"}}).Return(nil)
+		freshMockSettingsRepo.On("GetByKey", mock.Anything, "fiscal_year_start_month").
+			Return(&domain.Settings{Key: "fiscal_year_start_month", Value: "4"}, nil)
+
+		result, err := freshSettingsService.UpdateSettings(context.Background(), settingsRequest(map[string]interface{}{"mileage_rate": domain.MustMoney("0.67")}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, result["fiscal_year_start_month"])
+
+		freshMockSettingsRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject an invalid fiscal year start month", func(t *testing.T) {
+		freshMockSettingsRepo := new(MockSettingsRepository)
+		freshSettingsService := NewSettingsService(freshMockSettingsRepo)
+
+		startMonth := 13
+		result, err := freshSettingsService.UpdateSettings(context.Background(), settingsRequest(map[string]interface{}{
+			"mileage_rate":            domain.MustMoney("0.67"),
+			"fiscal_year_start_month": startMonth,
+		}))
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrValidation)
+		freshMockSettingsRepo.AssertNotCalled(t, "UpsertAll")
+	})
+
+	t.Run("should update the mileage rate currency", func(t *testing.T) {
+		freshMockSettingsRepo := new(MockSettingsRepository)
+		freshSettingsService := NewSettingsService(freshMockSettingsRepo)
+
+		freshMockSettingsRepo.On("UpsertAll", mock.Anything, []domain.Settings{{Key: "mileage_rate", Value: "0.72"}, {Key: "mileage_rate_currency", Value: "CAD"}}).Return(nil)
+		freshMockSettingsRepo.On("GetByKey", mock.Anything, "fiscal_year_start_month").Return(nil, gorm.ErrRecordNotFound)
+		freshMockSettingsRepo.On("GetByKey", mock.Anything, "distance_unit").Return(nil, gorm.ErrRecordNotFound)
+		freshMockSettingsRepo.On("GetByKey", mock.Anything, "amount_rounding").Return(nil, gorm.ErrRecordNotFound)
+
+		currency := " cad "
+		result, err := freshSettingsService.UpdateSettings(context.Background(), settingsRequest(map[string]interface{}{
+			"mileage_rate":          domain.MustMoney("0.72"),
+			"mileage_rate_currency": currency,
+		}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "CAD", result["mileage_rate_currency"])
+		freshMockSettingsRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject an invalid mileage rate currency", func(t *testing.T) {
+		freshMockSettingsRepo := new(MockSettingsRepository)
+		freshSettingsService := NewSettingsService(freshMockSettingsRepo)
+
+		for _, currency := range []string{"", "dollars"} {
+			result, err := freshSettingsService.UpdateSettings(context.Background(), settingsRequest(map[string]interface{}{
+				"mileage_rate":          domain.MustMoney("0.72"),
+				"mileage_rate_currency": currency,
+			}))
+
+			assert.Nil(t, result)
+			assert.ErrorIs(t, err, ErrValidation)
+		}
+		freshMockSettingsRepo.AssertNotCalled(t, "UpsertAll")
+	})
+
+	t.Run("should store a rate per kilometer per mile", func(t *testing.T) {
+		freshMockSettingsRepo := new(MockSettingsRepository)
+		freshSettingsService := NewSettingsService(freshMockSettingsRepo)
+
+		freshMockSettingsRepo.On("UpsertAll", mock.Anything, []domain.Settings{{Key: "mileage_rate", Value: "0.67592448"}, {Key: "distance_unit", Value: "km"}}).Return(nil)
+		freshMockSettingsRepo.On("GetByKey", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+
+		unit := "kilometres"
+		result, err := freshSettingsService.UpdateSettings(context.Background(), settingsRequest(map[string]interface{}{
+			"mileage_rate":  domain.MustMoney("0.42"),
+			"distance_unit": unit,
+		}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "km", result["distance_unit"])
+		assert.Equal(t, "km", result["rate_unit"])
+		assert.Equal(t, domain.MustMoney("0.42"), result["mileage_rate"])
+		freshMockSettingsRepo.AssertExpectations(t)
+	})
+
+	t.Run("should take the rate in the given rate unit", func(t *testing.T) {
+		freshMockSettingsRepo := new(MockSettingsRepository)
+		freshSettingsService := NewSettingsService(freshMockSettingsRepo)
+
+		freshMockSettingsRepo.On("UpsertAll", mock.Anything, []domain.Settings{{Key: "mileage_rate", Value: "0.67"}}).Return(nil)
+		freshMockSettingsRepo.On("GetByKey", mock.Anything, "distance_unit").Return(&domain.Settings{Key: "distance_unit", Value: "km"}, nil)
+		freshMockSettingsRepo.On("GetByKey", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+
+		result, err := freshSettingsService.UpdateSettings(context.Background(), settingsRequest(map[string]interface{}{
+			"mileage_rate": domain.MustMoney("0.67"),
+			"rate_unit":    "mi",
+		}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "km", result["distance_unit"])
+		assert.Equal(t, "mi", result["rate_unit"])
+		freshMockSettingsRepo.AssertExpectations(t) // distance_unit is left as stored
+	})
+
+	t.Run("should reject an invalid distance unit", func(t *testing.T) {
+		freshMockSettingsRepo := new(MockSettingsRepository)
+		freshSettingsService := NewSettingsService(freshMockSettingsRepo)
+
+		for _, unit := range []string{"", "yards"} {
+			result, err := freshSettingsService.UpdateSettings(context.Background(), settingsRequest(map[string]interface{}{
+				"mileage_rate":  domain.MustMoney("0.67"),
+				"distance_unit": unit,
+			}))
+
+			assert.Nil(t, result)
+			assert.ErrorIs(t, err, ErrValidation)
+		}
+		freshMockSettingsRepo.AssertNotCalled(t, "UpsertAll")
+	})
+}
+
+func TestSettingsService_GetSettingsUnit(t *testing.T) {
+	mockSettingsRepo := new(MockSettingsRepository)
+	mockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate").Return(&domain.Settings{Key: "mileage_rate", Value: "0.67"}, nil)
+	mockSettingsRepo.On("GetByKey", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+	settingsService := NewSettingsService(mockSettingsRepo)
+
+	result, err := settingsService.GetSettings(context.Background(), "km")
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.MustMoney("0.4163"), result["mileage_rate"])
+	assert.Equal(t, "km", result["rate_unit"])
+	assert.Equal(t, "mi", result["distance_unit"])
+
+	_, err = settingsService.GetSettings(context.Background(), "parsecs")
+	assert.ErrorIs(t, err, ErrValidation)
+}
+
+func TestSettingsService_AmountRounding(t *testing.T) {
+	t.Run("should default to rounding per period", func(t *testing.T) {
+		mockSettingsRepo := new(MockSettingsRepository)
+		mockSettingsRepo.On("GetByKey", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+		settingsService := NewSettingsService(mockSettingsRepo)
+
+		result, err := settingsService.GetSettings(context.Background(), "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "per_period", result["amount_rounding"])
+		assert.Equal(t, domain.MustMoney("0.67"), result["mileage_rate"])
+	})
+
+	t.Run("should store the rounding rule", func(t *testing.T) {
+		mockSettingsRepo := new(MockSettingsRepository)
+		mockSettingsRepo.On("UpsertAll", mock.Anything, []domain.Settings{{Key: "mileage_rate", Value: "0.655"}, {Key: "amount_rounding", Value: "per_trip"}}).Return(nil)
+		mockSettingsRepo.On("GetByKey", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+		settingsService := NewSettingsService(mockSettingsRepo)
+
+		rounding := "per_trip"
+		result, err := settingsService.UpdateSettings(context.Background(), settingsRequest(map[string]interface{}{
+			"mileage_rate":    domain.MustMoney("0.655"),
+			"amount_rounding": rounding,
+		}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "per_trip", result["amount_rounding"])
+		mockSettingsRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject an unknown rounding rule", func(t *testing.T) {
+		mockSettingsRepo := new(MockSettingsRepository)
+		mockSettingsRepo.On("GetByKey", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+		settingsService := NewSettingsService(mockSettingsRepo)
+
+		rounding := "per_month"
+		result, err := settingsService.UpdateSettings(context.Background(), settingsRequest(map[string]interface{}{
+			"mileage_rate":    domain.MustMoney("0.67"),
+			"amount_rounding": rounding,
+		}))
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrValidation)
+		mockSettingsRepo.AssertNotCalled(t, "UpsertAll")
+	})
+}
+
+func TestSettingsService_Registry(t *testing.T) {
+	t.Run("should declare valid defaults", func(t *testing.T) {
+		for _, def := range NewSettingsService(new(MockSettingsRepository)).GetDefinitions() {
+			value, err := parseSetting(def, def.Default)
+			assert.NoError(t, err, def.Key)
+			assert.Equal(t, def.Default, value, def.Key)
+			assert.NotEmpty(t, def.Description, def.Key)
+		}
+	})
+
+	t.Run("should return every setting", func(t *testing.T) {
+		mockSettingsRepo := new(MockSettingsRepository)
+		mockSettingsRepo.On("GetByKey", mock.Anything, "fiscal_year_start_month").Return(&domain.Settings{Key: "fiscal_year_start_month", Value: "4"}, nil)
+		mockSettingsRepo.On("GetByKey", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+
+		result, err := NewSettingsService(mockSettingsRepo).GetSettings(context.Background(), "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.SettingsResponse{
+			"mileage_rate":            domain.MustMoney("0.67"),
+			"mileage_rate_currency":   "USD",
+			"fiscal_year_start_month": 4,
+			"distance_unit":           "mi",
+			"amount_rounding":         "per_period",
+			"rate_unit":               "mi",
+		}, result)
+	})
+
+	t.Run("should update only the settings given", func(t *testing.T) {
+		mockSettingsRepo := new(MockSettingsRepository)
+		mockSettingsRepo.On("UpsertAll", mock.Anything, []domain.Settings{{Key: "fiscal_year_start_month", Value: "10"}}).Return(nil)
+		mockSettingsRepo.On("GetByKey", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+
+		result, err := NewSettingsService(mockSettingsRepo).UpdateSettings(context.Background(), settingsRequest(map[string]interface{}{
+			"fiscal_year_start_month": 10,
+		}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 10, result["fiscal_year_start_month"])
+		assert.Equal(t, domain.MustMoney("0.67"), result["mileage_rate"])
+		mockSettingsRepo.AssertExpectations(t)
+		mockSettingsRepo.AssertNumberOfCalls(t, "UpsertAll", 1)
+	})
+
+	t.Run("should reject unknown settings and values of the wrong type", func(t *testing.T) {
+		for _, body := range []string{
+			`{"odometer": 1}`,
+			`{"fiscal_year_start_month": "7"}`,
+			`{"fiscal_year_start_month": 7.5}`,
+			`{"mileage_rate": null}`,
+			`{"distance_unit": 1}`,
+			`{"rate_unit": "yards"}`,
+		} {
+			mockSettingsRepo := new(MockSettingsRepository)
+			var req domain.UpdateSettingsRequest
+			assert.NoError(t, json.Unmarshal([]byte(body), &req))
+
+			result, err := NewSettingsService(mockSettingsRepo).UpdateSettings(context.Background(), req)
+
+			assert.Nil(t, result, body)
+			assert.ErrorIs(t, err, ErrValidation, body)
+			mockSettingsRepo.AssertNotCalled(t, "UpsertAll")
+		}
+	})
+}
+
+func TestSettingsService_SeedDefaults(t *testing.T) {
+	t.Run("should insert the default of every setting", func(t *testing.T) {
+		mockSettingsRepo := new(MockSettingsRepository)
+		mockSettingsRepo.On("InsertMissing", mock.Anything, []domain.Settings{
+			{Key: "mileage_rate", Value: "0.67"},
+			{Key: "mileage_rate_currency", Value: "USD"},
+			{Key: "fiscal_year_start_month", Value: "1"},
+			{Key: "distance_unit", Value: "mi"},
+			{Key: "amount_rounding", Value: "per_period"},
+		}).Return(nil)
+
+		err := NewSettingsService(mockSettingsRepo).SeedDefaults(context.Background())
+
+		assert.NoError(t, err)
+		mockSettingsRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return database errors", func(t *testing.T) {
+		mockSettingsRepo := new(MockSettingsRepository)
+		mockSettingsRepo.On("InsertMissing", mock.Anything, mock.Anything).Return(gorm.ErrInvalidDB)
+
+		err := NewSettingsService(mockSettingsRepo).SeedDefaults(context.Background())
+
+		assert.ErrorIs(t, err, gorm.ErrInvalidDB)
+	})
 }
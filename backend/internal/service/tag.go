@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/repository"
+	"gorm.io/gorm"
+)
+
+type TagService interface {
+	GetTags(ctx context.Context) ([]domain.Tag, error)
+	GetTag(ctx context.Context, id uint) (*domain.Tag, error)
+	CreateTag(ctx context.Context, req domain.TagRequest) (*domain.Tag, error)
+	UpdateTag(ctx context.Context, id uint, req domain.TagRequest) (*domain.Tag, error)
+	DeleteTag(ctx context.Context, id uint) error
+	ResolveTags(ctx context.Context, names []string) ([]domain.Tag, error)
+}
+
+type tagService struct {
+	tagRepo repository.TagRepository
+}
+
+func NewTagService(tagRepo repository.TagRepository) TagService {
+	return &tagService{
+		tagRepo: tagRepo,
+	}
+}
+
+// tagNamePattern is the form tag names are normalized to; it keeps them
+// usable in comma-separated filter parameters
+var tagNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// normalizeTagName lowercases a tag name and joins its words with hyphens,
+// so "Site Visit" becomes "site-visit"
+func normalizeTagName(name string) (string, error) {
+	normalized := strings.Join(strings.Fields(strings.ToLower(name)), "-")
+	if normalized == "" {
+		return "", fmt.Errorf("%w: tag name must not be blank", ErrValidation)
+	}
+	if len(normalized) > 30 {
+		return "", fmt.Errorf("%w: tag name %q is longer than 30 characters", ErrValidation, normalized)
+	}
+	if !tagNamePattern.MatchString(normalized) {
+		return "", fmt.Errorf("%w: tag name %q may only contain letters, digits, - and _", ErrValidation, normalized)
+	}
+	return normalized, nil
+}
+
+func (s *tagService) GetTags(ctx context.Context) ([]domain.Tag, error) {
+	return s.tagRepo.GetAll(ctx)
+}
+
+func (s *tagService) GetTag(ctx context.Context, id uint) (*domain.Tag, error) {
+	tag, err := s.tagRepo.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("tag %d %w", id, ErrNotFound)
+	}
+	return tag, err
+}
+
+func (s *tagService) CreateTag(ctx context.Context, req domain.TagRequest) (*domain.Tag, error) {
+	name, err := s.validateName(ctx, 0, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := &domain.Tag{Name: name}
+	if err := s.tagRepo.Create(ctx, tag); err != nil {
+		return nil, nameTaken(name, err)
+	}
+
+	return tag, nil
+}
+
+// UpdateTag renames a tag; trips carrying it keep it under the new name
+func (s *tagService) UpdateTag(ctx context.Context, id uint, req domain.TagRequest) (*domain.Tag, error) {
+	tag, err := s.GetTag(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := s.validateName(ctx, id, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	tag.Name = name
+	if err := s.tagRepo.Update(ctx, tag); err != nil {
+		return nil, nameTaken(name, err)
+	}
+
+	return tag, nil
+}
+
+// DeleteTag deletes a tag and removes it from every trip
+func (s *tagService) DeleteTag(ctx context.Context, id uint) error {
+	if _, err := s.GetTag(ctx, id); err != nil {
+		return err
+	}
+	return s.tagRepo.Delete(ctx, id)
+}
+
+// ResolveTags returns the tags with the given names, ordered by name,
+// creating the ones that do not exist yet. Names are normalized and
+// duplicates dropped.
+func (s *tagService) ResolveTags(ctx context.Context, names []string) ([]domain.Tag, error) {
+	normalized := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		tagName, err := normalizeTagName(name)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[tagName] {
+			seen[tagName] = true
+			normalized = append(normalized, tagName)
+		}
+	}
+
+	tags, err := s.tagRepo.FindByNames(ctx, normalized)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) == len(normalized) {
+		return tags, nil
+	}
+
+	existing := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		existing[tag.Name] = true
+	}
+	for _, name := range normalized {
+		if existing[name] {
+			continue
+		}
+		tag := domain.Tag{Name: name}
+		err := s.tagRepo.Create(ctx, &tag)
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			// Another request created the tag since it was looked up
+			var created *domain.Tag
+			if created, err = s.tagRepo.FindByName(ctx, name); err == nil {
+				tag = *created
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return tags, nil
+}
+
+// validateName normalizes a tag name and checks it is not taken by another
+// tag. id is the tag being renamed, or 0 for a new tag.
+func (s *tagService) validateName(ctx context.Context, id uint, name string) (string, error) {
+	normalized, err := normalizeTagName(name)
+	if err != nil {
+		return "", err
+	}
+
+	existing, err := s.tagRepo.FindByName(ctx, normalized)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+	if err == nil && existing.ID != id {
+		return "", fmt.Errorf("%w: a tag named %q already exists", ErrValidation, normalized)
+	}
+
+	return normalized, nil
+}
+
+// nameTaken reports a tag name taken since validateName checked it as a
+// validation error
+func nameTaken(name string, err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return fmt.Errorf("%w: a tag named %q already exists", ErrValidation, name)
+	}
+	return err
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockTagRepository implements the TagRepository interface for testing
+type MockTagRepository struct {
+	mock.Mock
+}
+
+func (m *MockTagRepository) Create(ctx context.Context, tag *domain.Tag) error {
+	args := m.Called(ctx, tag)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) Update(ctx context.Context, tag *domain.Tag) error {
+	args := m.Called(ctx, tag)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) FindByID(ctx context.Context, id uint) (*domain.Tag, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) FindByName(ctx context.Context, name string) (*domain.Tag, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) FindByNames(ctx context.Context, names []string) ([]domain.Tag, error) {
+	args := m.Called(ctx, names)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) GetAll(ctx context.Context) ([]domain.Tag, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Tag), args.Error(1)
+}
+
+func TestNormalizeTagName(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"billable", "billable", false},
+		{"  Site  Visit ", "site-visit", false},
+		{"Q1_review", "q1_review", false},
+		{"", "", true},
+		{"a,b", "", true},
+		{"-leading", "", true},
+		{"this-tag-name-is-far-too-long-to-keep", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeTagName(tt.name)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrValidation)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTagService_CreateTag(t *testing.T) {
+	t.Run("should create tag with normalized name", func(t *testing.T) {
+		mockRepo := new(MockTagRepository)
+		tagService := NewTagService(mockRepo)
+
+		mockRepo.On("FindByName", mock.Anything, "site-visit").Return(nil, gorm.ErrRecordNotFound)
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Tag")).Return(nil)
+
+		result, err := tagService.CreateTag(context.Background(), domain.TagRequest{Name: "Site Visit"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "site-visit", result.Name)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject duplicate name", func(t *testing.T) {
+		mockRepo := new(MockTagRepository)
+		tagService := NewTagService(mockRepo)
+
+		mockRepo.On("FindByName", mock.Anything, "billable").Return(&domain.Tag{ID: 2, Name: "billable"}, nil)
+
+		result, err := tagService.CreateTag(context.Background(), domain.TagRequest{Name: "Billable"})
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrValidation)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject a name taken by a concurrent request", func(t *testing.T) {
+		mockRepo := new(MockTagRepository)
+		tagService := NewTagService(mockRepo)
+
+		mockRepo.On("FindByName", mock.Anything, "billable").Return(nil, gorm.ErrRecordNotFound)
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Tag")).Return(gorm.ErrDuplicatedKey)
+
+		result, err := tagService.CreateTag(context.Background(), domain.TagRequest{Name: "billable"})
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrValidation)
+		assert.Contains(t, err.Error(), `a tag named "billable" already exists`)
+	})
+}
+
+func TestTagService_DeleteTag(t *testing.T) {
+	mockRepo := new(MockTagRepository)
+	tagService := NewTagService(mockRepo)
+
+	mockRepo.On("FindByID", mock.Anything, uint(9)).Return(nil, gorm.ErrRecordNotFound)
+
+	err := tagService.DeleteTag(context.Background(), 9)
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestTagService_ResolveTags(t *testing.T) {
+	t.Run("should create missing tags", func(t *testing.T) {
+		mockRepo := new(MockTagRepository)
+		tagService := NewTagService(mockRepo)
+
+		mockRepo.On("FindByNames", mock.Anything, []string{"site-visit", "billable"}).
+			Return([]domain.Tag{{ID: 1, Name: "billable"}}, nil)
+		mockRepo.On("Create", mock.Anything, &domain.Tag{Name: "site-visit"}).Return(nil).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Tag).ID = 2
+		})
+
+		tags, err := tagService.ResolveTags(context.Background(), []string{"Site Visit", "billable", "BILLABLE"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Tag{{ID: 1, Name: "billable"}, {ID: 2, Name: "site-visit"}}, tags)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should use a tag created by a concurrent request", func(t *testing.T) {
+		mockRepo := new(MockTagRepository)
+		tagService := NewTagService(mockRepo)
+
+		mockRepo.On("FindByNames", mock.Anything, []string{"site-visit"}).Return([]domain.Tag{}, nil)
+		mockRepo.On("Create", mock.Anything, &domain.Tag{Name: "site-visit"}).Return(gorm.ErrDuplicatedKey)
+		mockRepo.On("FindByName", mock.Anything, "site-visit").Return(&domain.Tag{ID: 3, Name: "site-visit"}, nil)
+
+		tags, err := tagService.ResolveTags(context.Background(), []string{"site-visit"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Tag{{ID: 3, Name: "site-visit"}}, tags)
+		mockRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/oscar/mileagetracker/internal/domain"
+)
+
+// maxTaxSummaryYears caps how many fiscal years one tax summary covers
+const maxTaxSummaryYears = 20
+
+// GetTaxSummary returns the totals for fiscal years fromYear through toYear,
+// each numbered by the calendar year it ends in. Zero selects the current
+// fiscal year, a blank currency the mileage rate currency and a blank unit
+// the distance_unit setting.
+func (s *tripService) GetTaxSummary(ctx context.Context, fromYear, toYear int, currency, unit string) (*domain.TaxSummaryResponse, error) {
+	startMonth, err := fiscalYearStartMonth(ctx, s.settingsRepo)
+	if err != nil {
+		return nil, err
+	}
+	today := s.today()
+
+	current := fiscalYearOf(today, startMonth)
+	if toYear == 0 {
+		toYear = current
+		if fromYear > toYear {
+			toYear = fromYear
+		}
+	}
+	if fromYear == 0 {
+		fromYear = toYear
+	}
+	if fromYear < 1 || toYear > 9999 {
+		return nil, fmt.Errorf("%w: fiscal years must be between 1 and 9999", ErrValidation)
+	}
+	if fromYear > toYear {
+		return nil, fmt.Errorf("%w: from_year must not be after to_year", ErrValidation)
+	}
+	if toYear-fromYear+1 > maxTaxSummaryYears {
+		return nil, fmt.Errorf("%w: at most %d fiscal years can be summarized at once", ErrValidation, maxTaxSummaryYears)
+	}
+
+	from := fiscalYearStart(fromYear, startMonth, s.location)
+	to := fiscalYearStart(toYear+1, startMonth, s.location).AddDate(0, 0, -1)
+
+	expenses, err := s.tripRepo.GetExpenseTotals(ctx, "", from.Format("2006-01-02"), to.Format("2006-01-02"), domain.TripFilters{})
+	if err != nil {
+		return nil, err
+	}
+
+	pricer, err := s.newSummaryPricer(ctx, currency, unit, to, expenses)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.tripRepo.GetSummaryBuckets(ctx, pricer.granularity(domain.GranularityMonth),
+		from.Format("2006-01-02"), to.Format("2006-01-02"), domain.TripFilters{}, pricer.tripMiles())
+	if err != nil {
+		return nil, err
+	}
+
+	byMonth := make(map[string]domain.SummaryTotals, len(rows))
+	for _, row := range rows {
+		priced, err := pricer.trips(row.Totals(), row.StartDate)
+		if err != nil {
+			return nil, err
+		}
+		month := byMonth[bucketKey(row.StartDate, domain.GranularityMonth)]
+		addSummaryTotals(&month, priced)
+		byMonth[bucketKey(row.StartDate, domain.GranularityMonth)] = month
+	}
+	for _, row := range expenses {
+		month := byMonth[bucketKey(row.TripDate, domain.GranularityMonth)]
+		addSummaryTotals(&month, pricer.expenses(row))
+		byMonth[bucketKey(row.TripDate, domain.GranularityMonth)] = month
+	}
+
+	response := &domain.TaxSummaryResponse{
+		FiscalYearStartMonth: startMonth,
+		Timezone:             s.location.String(),
+		AsOf:                 today.Format("2006-01-02"),
+		Currency:             pricer.currency,
+		Unit:                 pricer.unit,
+		FiscalYears:          make([]domain.FiscalYearSummary, 0, toYear-fromYear+1),
+	}
+
+	for year := fromYear; year <= toYear; year++ {
+		start := fiscalYearStart(year, startMonth, s.location)
+		end := fiscalYearStart(year+1, startMonth, s.location).AddDate(0, 0, -1)
+
+		summary := domain.FiscalYearSummary{
+			FiscalYear:   year,
+			Label:        fmt.Sprintf("FY%d", year),
+			PeriodTotals: newPeriodTotals(start, end),
+			Months:       make([]domain.SummaryBucket, 0, 12),
+		}
+
+		for month := start; !month.After(end); month = month.AddDate(0, 1, 0) {
+			bucket := newSummaryBucket(month, domain.GranularityMonth)
+			addBucketTotals(&bucket, byMonth[bucket.StartDate])
+			roundBucket(&bucket)
+			summary.Months = append(summary.Months, bucket)
+			addSummaryTotals(&summary.SummaryTotals, bucket.Totals())
+		}
+
+		addSummaryTotals(&response.Totals, summary.SummaryTotals)
+		response.FiscalYears = append(response.FiscalYears, summary)
+	}
+
+	return response, nil
+}
+
+// fiscalYearStart returns the first day of the given fiscal year. Fiscal
+// years are numbered by the calendar year they end in, so with a July start
+// FY2025 runs from July 1st 2024 to June 30th 2025.
+func fiscalYearStart(year, startMonth int, location *time.Location) time.Time {
+	if startMonth == 1 {
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, location)
+	}
+	return time.Date(year-1, time.Month(startMonth), 1, 0, 0, 0, 0, location)
+}
+
+// fiscalYearOf returns the fiscal year the given date falls in
+func fiscalYearOf(date time.Time, startMonth int) int {
+	if startMonth > 1 && int(date.Month()) >= startMonth {
+		return date.Year() + 1
+	}
+	return date.Year()
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestTripService_GetTaxSummary(t *testing.T) {
+	newService := func(tripRepo *MockTripRepository, startMonth string) TripService {
+		settingsRepo := newMockTripSettingsRepository()
+		stubSetting(settingsRepo, "mileage_rate").Return(&domain.Settings{Key: "mileage_rate", Value: "0.5"}, nil)
+		settingsRepo.On("GetByKey", mock.Anything, "mileage_rate_currency").Return(&domain.Settings{Key: "mileage_rate_currency", Value: "USD"}, nil).Maybe()
+		if startMonth == "" {
+			settingsRepo.On("GetByKey", mock.Anything, "fiscal_year_start_month").Return(nil, gorm.ErrRecordNotFound)
+		} else {
+			settingsRepo.On("GetByKey", mock.Anything, "fiscal_year_start_month").
+				Return(&domain.Settings{Key: "fiscal_year_start_month", Value: startMonth}, nil)
+		}
+		// summaryTestNow is 2025-09-15
+		return newSummaryTestService(tripRepo, new(MockTripClientService), settingsRepo)
+	}
+
+	t.Run("should default to the current fiscal year", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		mockTripRepo.On("GetExpenseTotals", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.ExpenseTotalRow{}, nil).Maybe()
+		mockTripRepo.On("GetSummaryBuckets", mock.Anything, domain.GranularityMonth, "2025-07-01", "2026-06-30", domain.TripFilters{}, false).
+			Return([]domain.SummaryBucket{
+				{StartDate: "2025-07-01", TripCount: 2, TotalMiles: 40, TotalMinutes: 30},
+				{StartDate: "2025-09-01", TripCount: 1, TotalMiles: 10},
+			}, nil)
+
+		result, err := newService(mockTripRepo, "7").GetTaxSummary(context.Background(), 0, 0, "", "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 7, result.FiscalYearStartMonth)
+		assert.Equal(t, "2025-09-15", result.AsOf)
+		assert.Len(t, result.FiscalYears, 1)
+
+		year := result.FiscalYears[0]
+		assert.Equal(t, 2026, year.FiscalYear)
+		assert.Equal(t, "FY2026", year.Label)
+		assert.Equal(t, "2025-07-01", year.From)
+		assert.Equal(t, "2026-06-30", year.To)
+		assert.Equal(t, domain.SummaryTotals{TripCount: 3, TotalMiles: 50, TotalMinutes: 30, Amount: domain.MustMoney("25"), TotalDistance: 50, TotalAmount: domain.MustMoney("25")}, year.SummaryTotals)
+
+		assert.Len(t, year.Months, 12)
+		assert.Equal(t, "2025-07", year.Months[0].Period)
+		assert.Equal(t, domain.MustMoney("20"), year.Months[0].Amount)
+		assert.Equal(t, int64(0), year.Months[1].TripCount)
+		assert.Equal(t, "2026-06", year.Months[11].Period)
+
+		assert.Equal(t, year.SummaryTotals, result.Totals)
+		mockTripRepo.AssertExpectations(t)
+	})
+
+	t.Run("should split the range into fiscal years", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		mockTripRepo.On("GetExpenseTotals", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.ExpenseTotalRow{}, nil).Maybe()
+		mockTripRepo.On("GetSummaryBuckets", mock.Anything, domain.GranularityMonth, "2023-07-01", "2025-06-30", domain.TripFilters{}, false).
+			Return([]domain.SummaryBucket{
+				{StartDate: "2024-06-01", TripCount: 1, TotalMiles: 100},
+				{StartDate: "2024-07-01", TripCount: 1, TotalMiles: 60},
+			}, nil)
+
+		result, err := newService(mockTripRepo, "7").GetTaxSummary(context.Background(), 2024, 2025, "", "")
+
+		assert.NoError(t, err)
+		assert.Len(t, result.FiscalYears, 2)
+		assert.Equal(t, 100.0, result.FiscalYears[0].TotalMiles)
+		assert.Equal(t, "2024-06-30", result.FiscalYears[0].To)
+		assert.Equal(t, 60.0, result.FiscalYears[1].TotalMiles)
+		assert.Equal(t, "2024-07-01", result.FiscalYears[1].From)
+		assert.Equal(t, domain.SummaryTotals{TripCount: 2, TotalMiles: 160, Amount: domain.MustMoney("80"), TotalDistance: 160, TotalAmount: domain.MustMoney("80")}, result.Totals)
+	})
+
+	t.Run("should report distances and rates per kilometer", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		mockTripRepo.On("GetExpenseTotals", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.ExpenseTotalRow{}, nil).Maybe()
+		mockTripRepo.On("GetSummaryBuckets", mock.Anything, domain.GranularityMonth, "2025-07-01", "2026-06-30", domain.TripFilters{}, false).
+			Return([]domain.SummaryBucket{{StartDate: "2025-07-01", TripCount: 1, TotalMiles: 100}}, nil)
+
+		result, err := newService(mockTripRepo, "7").GetTaxSummary(context.Background(), 0, 0, "", "km")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.DistanceKilometers, result.Unit)
+		assert.InDelta(t, 160.9344, result.Totals.TotalDistance, 0.0001)
+		assert.Equal(t, domain.MustMoney("50"), result.Totals.Amount) // still 100 miles at 0.5 per mile
+	})
+
+	t.Run("should reject an unknown unit", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		mockTripRepo.On("GetExpenseTotals", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.ExpenseTotalRow{}, nil).Maybe()
+
+		_, err := newService(mockTripRepo, "7").GetTaxSummary(context.Background(), 0, 0, "", "furlongs")
+
+		assert.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("should use calendar years without a setting", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		mockTripRepo.On("GetExpenseTotals", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.ExpenseTotalRow{}, nil).Maybe()
+		mockTripRepo.On("GetSummaryBuckets", mock.Anything, domain.GranularityMonth, "2025-01-01", "2025-12-31", domain.TripFilters{}, false).
+			Return([]domain.SummaryBucket{}, nil)
+
+		result, err := newService(mockTripRepo, "").GetTaxSummary(context.Background(), 0, 0, "", "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.FiscalYearStartMonth)
+		assert.Equal(t, "FY2025", result.FiscalYears[0].Label)
+		mockTripRepo.AssertExpectations(t)
+	})
+
+	t.Run("should fail on an out of range setting", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+
+		result, err := newService(mockTripRepo, "13").GetTaxSummary(context.Background(), 0, 0, "", "")
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, `setting fiscal_year_start_month holds an invalid value "13"`)
+		mockTripRepo.AssertNotCalled(t, "GetSummaryBuckets", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject invalid ranges", func(t *testing.T) {
+		cases := []struct {
+			name     string
+			from, to int
+		}{
+			{"reversed", 2025, 2023},
+			{"too many years", 2000, 2025},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				mockTripRepo := new(MockTripRepository)
+
+				result, err := newService(mockTripRepo, "7").GetTaxSummary(context.Background(), tc.from, tc.to, "", "")
+
+				assert.Nil(t, result)
+				assert.True(t, errors.Is(err, ErrValidation))
+				mockTripRepo.AssertNotCalled(t, "GetSummaryBuckets")
+			})
+		}
+	})
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/track"
+)
+
+type TrackImportService interface {
+	ImportTrack(ctx context.Context, filename string, data []byte, req domain.TrackImportRequest) (*domain.TrackImportResponse, error)
+}
+
+type trackImportService struct {
+	tripService TripService
+	location    *time.Location
+}
+
+// NewTrackImportService creates an importer that dates trips in the given
+// business timezone
+func NewTrackImportService(tripService TripService, location *time.Location) TrackImportService {
+	return &trackImportService{
+		tripService: tripService,
+		location:    location,
+	}
+}
+
+// ImportTrack parses a GPX or GeoJSON track, splits it into drives and
+// creates one trip per drive
+func (s *trackImportService) ImportTrack(
+	ctx context.Context,
+	filename string,
+	data []byte,
+	req domain.TrackImportRequest,
+) (*domain.TrackImportResponse, error) {
+	// Anything Parse rejects is a problem with the uploaded file
+	points, err := track.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+
+	segments := track.Analyze(points, importOptions(req))
+
+	response := &domain.TrackImportResponse{
+		PointsRead: len(points),
+		Segments:   make([]domain.ImportedSegment, 0, len(segments)),
+		Trips:      []domain.Trip{},
+	}
+
+	// Build every request up front so a bad segment fails the whole import
+	// before anything is written
+	requests := make([]domain.CreateTripRequest, 0, len(segments))
+	for _, seg := range segments {
+		response.Segments = append(response.Segments, importedSegment(seg, s.location))
+
+		tripDate := req.TripDate
+		if !seg.Start.IsZero() {
+			tripDate = seg.Start.In(s.location).Format("2006-01-02")
+		}
+		if tripDate == "" {
+			return nil, fmt.Errorf("%w: trip_date is required when the track has no timestamps", ErrValidation)
+		}
+
+		tripReq := domain.CreateTripRequest{
+			ClientName: req.ClientName,
+			TripDate:   tripDate,
+			Miles:      seg.Miles,
+			Notes:      importNotes(req.Notes, filename),
+		}
+		if !seg.Start.IsZero() {
+			start, end := seg.Start.In(s.location), seg.End.In(s.location)
+			tripReq.StartTime = &start
+			tripReq.EndTime = &end
+		}
+		requests = append(requests, tripReq)
+	}
+
+	if req.DryRun {
+		return response, nil
+	}
+
+	// Create the trips together, so a failed import leaves nothing behind
+	// for a retry to duplicate
+	trips, err := s.tripService.CreateTrips(ctx, requests)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import trips: %w", err)
+	}
+	response.Trips = trips
+
+	return response, nil
+}
+
+func importOptions(req domain.TrackImportRequest) track.Options {
+	opts := track.DefaultOptions
+	if req.MinMovementMeters != nil {
+		opts.MinMovementMeters = *req.MinMovementMeters
+	}
+	if req.StopMinutes != nil {
+		opts.StopDuration = time.Duration(*req.StopMinutes * float64(time.Minute))
+	}
+	if req.MinTripMiles != nil {
+		opts.MinSegmentMiles = *req.MinTripMiles
+	}
+	return opts
+}
+
+func importedSegment(seg track.Segment, location *time.Location) domain.ImportedSegment {
+	imported := domain.ImportedSegment{
+		Miles:  seg.Miles,
+		Points: seg.Points,
+	}
+	if !seg.Start.IsZero() {
+		start, end := seg.Start.In(location), seg.End.In(location)
+		imported.StartTime = &start
+		imported.EndTime = &end
+	}
+	return imported
+}
+
+// importNotes records which file a trip came from
+func importNotes(notes, filename string) string {
+	source := "Imported from " + filepath.Base(filename)
+	if notes == "" {
+		return source
+	}
+	return notes + " (" + source + ")"
+}
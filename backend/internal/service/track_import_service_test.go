@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockImportTripService records the trips created by an import. Only
+// CreateTrips is used by the importer.
+type MockImportTripService struct {
+	TripService
+	mock.Mock
+}
+
+func (m *MockImportTripService) CreateTrips(ctx context.Context, reqs []domain.CreateTripRequest) ([]domain.Trip, error) {
+	args := m.Called(ctx, reqs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Trip), args.Error(1)
+}
+
+// twoDriveGPX builds a track with two ten-minute drives north at one mile
+// a minute, separated by a fifteen-minute stop
+func twoDriveGPX(withTimes bool) []byte {
+	start := time.Date(2025, 3, 10, 14, 0, 0, 0, time.UTC)
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0"?><gpx version="1.1"><trk><trkseg>`)
+	writePoint := func(lat float64, at time.Time) {
+		b.WriteString(fmt.Sprintf(`<trkpt lat="%.5f" lon="-73.9">`, lat))
+		if withTimes {
+			b.WriteString("<time>" + at.Format(time.RFC3339) + "</time>")
+		}
+		b.WriteString("</trkpt>")
+	}
+
+	lat := 40.0
+	for i := 0; i <= 10; i++ {
+		writePoint(lat+float64(i)*0.0145, start.Add(time.Duration(i)*time.Minute))
+	}
+	lat += 10 * 0.0145
+	stopEnd := start.Add(25 * time.Minute)
+	writePoint(lat, stopEnd)
+	for i := 1; i <= 10; i++ {
+		writePoint(lat+float64(i)*0.0145, stopEnd.Add(time.Duration(i)*time.Minute))
+	}
+	b.WriteString(`</trkseg></trk></gpx>`)
+	return []byte(b.String())
+}
+
+func TestTrackImportService_ImportTrack(t *testing.T) {
+	t.Run("should create one trip per drive", func(t *testing.T) {
+		tripService := new(MockImportTripService)
+		importService := NewTrackImportService(tripService, time.UTC)
+
+		tripService.On("CreateTrips", mock.Anything, mock.MatchedBy(func(reqs []domain.CreateTripRequest) bool {
+			if len(reqs) != 2 {
+				return false
+			}
+			for _, req := range reqs {
+				if req.ClientName != "Acme Corp" || req.TripDate != "2025-03-10" || req.Miles < 9.9 || req.Miles > 10.1 {
+					return false
+				}
+			}
+			return true
+		})).Return([]domain.Trip{
+			{ID: 1, ClientName: "Acme Corp", TripDate: "2025-03-10", Miles: 10},
+			{ID: 2, ClientName: "Acme Corp", TripDate: "2025-03-10", Miles: 10},
+		}, nil).Once()
+
+		result, err := importService.ImportTrack(context.Background(), "drive.gpx", twoDriveGPX(true), domain.TrackImportRequest{
+			ClientName: "Acme Corp",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 22, result.PointsRead)
+		assert.Len(t, result.Segments, 2)
+		assert.Len(t, result.Trips, 2)
+		assert.Equal(t, "2025-03-10T14:00:00Z", result.Segments[0].StartTime.Format(time.RFC3339))
+		tripService.AssertExpectations(t)
+
+		firstReq := tripService.Calls[0].Arguments.Get(1).([]domain.CreateTripRequest)[0]
+		assert.Equal(t, "Imported from drive.gpx", firstReq.Notes)
+		assert.Equal(t, "2025-03-10T14:00:00Z", firstReq.StartTime.Format(time.RFC3339))
+		assert.Equal(t, "2025-03-10T14:10:00Z", firstReq.EndTime.Format(time.RFC3339))
+	})
+
+	t.Run("should date trips in the business timezone", func(t *testing.T) {
+		zone := time.FixedZone("UTC+9:50", 9*60*60+50*60)
+		tripService := new(MockImportTripService)
+		importService := NewTrackImportService(tripService, zone)
+
+		// The first drive starts at 23:50 local time and the second, at
+		// 14:26 UTC, after local midnight
+		tripService.On("CreateTrips", mock.Anything, mock.MatchedBy(func(reqs []domain.CreateTripRequest) bool {
+			return len(reqs) == 2 &&
+				reqs[0].TripDate == "2025-03-10" && reqs[0].StartTime.Format("15:04") == "23:50" &&
+				reqs[1].TripDate == "2025-03-11" && reqs[1].StartTime.Format("15:04") == "00:16"
+		})).Return([]domain.Trip{{ID: 1}, {ID: 2}}, nil).Once()
+
+		_, err := importService.ImportTrack(context.Background(), "drive.gpx", twoDriveGPX(true), domain.TrackImportRequest{
+			ClientName: "Acme Corp",
+		})
+
+		assert.NoError(t, err)
+		tripService.AssertExpectations(t)
+	})
+
+	t.Run("should not create trips on a dry run", func(t *testing.T) {
+		tripService := new(MockImportTripService)
+		importService := NewTrackImportService(tripService, time.UTC)
+
+		result, err := importService.ImportTrack(context.Background(), "drive.gpx", twoDriveGPX(true), domain.TrackImportRequest{
+			ClientName: "Acme Corp",
+			DryRun:     true,
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Segments, 2)
+		assert.Empty(t, result.Trips)
+		tripService.AssertNotCalled(t, "CreateTrips", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should require trip date when track has no timestamps", func(t *testing.T) {
+		tripService := new(MockImportTripService)
+		importService := NewTrackImportService(tripService, time.UTC)
+
+		_, err := importService.ImportTrack(context.Background(), "drive.gpx", twoDriveGPX(false), domain.TrackImportRequest{
+			ClientName: "Acme Corp",
+		})
+
+		assert.ErrorIs(t, err, ErrValidation)
+		tripService.AssertNotCalled(t, "CreateTrips", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should use trip date and keep user notes for untimed tracks", func(t *testing.T) {
+		tripService := new(MockImportTripService)
+		importService := NewTrackImportService(tripService, time.UTC)
+
+		tripService.On("CreateTrips", mock.Anything, mock.MatchedBy(func(reqs []domain.CreateTripRequest) bool {
+			return len(reqs) == 1 && reqs[0].TripDate == "2025-04-01" && reqs[0].Miles > 19.9 && reqs[0].Miles < 20.1 &&
+				reqs[0].Notes == "Site visit (Imported from drive.gpx)" && reqs[0].StartTime == nil
+		})).Return([]domain.Trip{{ID: 1}}, nil).Once()
+
+		result, err := importService.ImportTrack(context.Background(), "drive.gpx", twoDriveGPX(false), domain.TrackImportRequest{
+			ClientName: "Acme Corp",
+			TripDate:   "2025-04-01",
+			Notes:      "Site visit",
+		})
+
+		// Without timestamps stops can't be detected, so the track is one drive
+		assert.NoError(t, err)
+		assert.Len(t, result.Trips, 1)
+		tripService.AssertExpectations(t)
+	})
+
+	t.Run("should reject unrecognised files", func(t *testing.T) {
+		importService := NewTrackImportService(new(MockImportTripService), time.UTC)
+
+		_, err := importService.ImportTrack(context.Background(), "notes.txt", []byte("hello"), domain.TrackImportRequest{
+			ClientName: "Acme Corp",
+		})
+
+		assert.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("should surface trip creation errors", func(t *testing.T) {
+		tripService := new(MockImportTripService)
+		importService := NewTrackImportService(tripService, time.UTC)
+
+		tripService.On("CreateTrips", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("trip 2 of 2: database error")).Once()
+
+		_, err := importService.ImportTrack(context.Background(), "drive.gpx", twoDriveGPX(true), domain.TrackImportRequest{
+			ClientName: "Acme Corp",
+		})
+
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrValidation)
+		assert.Contains(t, err.Error(), "failed to import trips: trip 2 of 2")
+	})
+}
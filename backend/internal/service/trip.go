@@ -2,8 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strconv"
+	"sort"
 	"time"
 
 	"github.com/oscar/mileagetracker/internal/domain"
@@ -12,55 +13,154 @@ import (
 
 type TripService interface {
 	CreateTrip(ctx context.Context, req domain.CreateTripRequest) (*domain.Trip, error)
+	CreateTrips(ctx context.Context, reqs []domain.CreateTripRequest) ([]domain.Trip, error)
 	UpdateTrip(ctx context.Context, id uint, req domain.UpdateTripRequest) (*domain.Trip, error)
 	DeleteTrip(ctx context.Context, id uint) error
 	GetTripByID(ctx context.Context, id uint) (*domain.Trip, error)
 	GetTrips(ctx context.Context, page, limit int, filters domain.TripFilters) ([]domain.Trip, int64, error)
-	GetSummary(ctx context.Context) (*domain.SummaryResponse, error)
+	GetTripPage(ctx context.Context, cursor string, limit int, filters domain.TripFilters) (*domain.TripPage, error)
+	GetSummary(ctx context.Context, query domain.SummaryQuery) (*domain.SummaryResponse, error)
+	GetDashboard(ctx context.Context) (*domain.DashboardResponse, error)
+	GetTaxSummary(ctx context.Context, fromYear, toYear int, currency, unit string) (*domain.TaxSummaryResponse, error)
 }
 
 type tripService struct {
-	tripRepo      repository.TripRepository
-	clientService ClientService
-	settingsRepo  repository.SettingsRepository
+	tripRepo        repository.TripRepository
+	clientService   ClientService
+	settingsRepo    repository.SettingsRepository
+	locationService LocationService
+	tagService      TagService
+
+	// attachmentService removes a trip's attachments before the trip is
+	// deleted; nil when attachments are not in use
+	attachmentService AttachmentService
+
+	// exchangeRates converts summary amounts between currencies; when nil
+	// only amounts already in the summary currency are added up
+	exchangeRates ExchangeRateService
+
+	// location is the business timezone; month windows and "today" are
+	// computed in it rather than in the server's zone
+	location *time.Location
+	now      func() time.Time
 }
 
 func NewTripService(
 	tripRepo repository.TripRepository,
 	clientService ClientService,
 	settingsRepo repository.SettingsRepository,
+	locationService LocationService,
+	tagService TagService,
+	attachmentService AttachmentService,
+	exchangeRates ExchangeRateService,
+	location *time.Location,
 ) TripService {
 	return &tripService{
-		tripRepo:      tripRepo,
-		clientService: clientService,
-		settingsRepo:  settingsRepo,
+		tripRepo:          tripRepo,
+		clientService:     clientService,
+		settingsRepo:      settingsRepo,
+		locationService:   locationService,
+		tagService:        tagService,
+		attachmentService: attachmentService,
+		exchangeRates:     exchangeRates,
+		location:          location,
+		now:               time.Now,
 	}
 }
 
 func (s *tripService) CreateTrip(ctx context.Context, req domain.CreateTripRequest) (*domain.Trip, error) {
+	trip, err := s.newTrip(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.tripRepo.Create(ctx, trip)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.withDistanceAndAmount(ctx, trip, req.Unit)
+}
+
+// CreateTrips creates several trips at once: either all of them or, when
+// any request is invalid or a trip can't be saved, none. Clients and tags
+// named by the requests are created while the requests are checked, and
+// reused when the import is retried.
+func (s *tripService) CreateTrips(ctx context.Context, reqs []domain.CreateTripRequest) ([]domain.Trip, error) {
+	trips := make([]domain.Trip, len(reqs))
+	for i, req := range reqs {
+		trip, err := s.newTrip(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("trip %d of %d: %w", i+1, len(reqs), err)
+		}
+		trips[i] = *trip
+	}
+
+	if err := s.tripRepo.CreateAll(ctx, trips); err != nil {
+		return nil, err
+	}
+
+	for i := range trips {
+		if _, err := s.withDistanceAndAmount(ctx, &trips[i], reqs[i].Unit); err != nil {
+			return nil, err
+		}
+	}
+	return trips, nil
+}
+
+// newTrip validates a create request and builds the trip it describes,
+// creating its client and any new tags
+func (s *tripService) newTrip(ctx context.Context, req domain.CreateTripRequest) (*domain.Trip, error) {
 	// Validate date format
 	if _, err := time.Parse("2006-01-02", req.TripDate); err != nil {
 		return nil, fmt.Errorf("invalid date format, expected YYYY-MM-DD")
 	}
 
-	// Get or create client
-	client, err := s.clientService.GetOrCreateClient(ctx, req.ClientName)
+	if err := s.validateTripTimes(req.TripDate, req.StartTime, req.EndTime); err != nil {
+		return nil, err
+	}
+
+	miles, err := requestMiles(ctx, s.settingsRepo, req.Miles, req.Distance, req.Unit)
+	if err != nil {
+		return nil, err
+	}
+	miles, err = s.resolveMiles(ctx, miles, req.FromLocationID, req.ToLocationID)
 	if err != nil {
 		return nil, err
 	}
 
-	trip := &domain.Trip{
-		ClientID:   &client.ID,
-		ClientName: req.ClientName,
-		TripDate:   req.TripDate,
-		Miles:      req.Miles,
-		Notes:      req.Notes,
+	tags, err := s.resolveTags(ctx, req.Tags)
+	if err != nil {
+		return nil, err
 	}
 
-	err = s.tripRepo.Create(ctx, trip)
+	expenses, err := buildExpenses(req.Expenses)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get or create client
+	client, err := s.clientService.GetOrCreateClient(ctx, req.ClientName)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.fillExpenseCurrencies(ctx, expenses, client); err != nil {
+		return nil, err
+	}
+
+	trip := &domain.Trip{
+		ClientID:       &client.ID,
+		ClientName:     req.ClientName,
+		TripDate:       req.TripDate,
+		Miles:          miles,
+		Notes:          req.Notes,
+		FromLocationID: req.FromLocationID,
+		ToLocationID:   req.ToLocationID,
+		StartTime:      inUTC(req.StartTime),
+		EndTime:        inUTC(req.EndTime),
+		Tags:           tags,
+		Expenses:       expenses,
+	}
 
 	return trip, nil
 }
@@ -71,39 +171,111 @@ func (s *tripService) UpdateTrip(ctx context.Context, id uint, req domain.Update
 		return nil, fmt.Errorf("invalid date format, expected YYYY-MM-DD")
 	}
 
+	if err := s.validateTripTimes(req.TripDate, req.StartTime, req.EndTime); err != nil {
+		return nil, err
+	}
+
 	// Get existing trip
 	trip, err := s.tripRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
+	miles, err := requestMiles(ctx, s.settingsRepo, req.Miles, req.Distance, req.Unit)
+	if err != nil {
+		return nil, err
+	}
+	miles, err = s.resolveMiles(ctx, miles, req.FromLocationID, req.ToLocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Omitted tags and expenses keep the trip's current ones
+	if req.Tags != nil {
+		if trip.Tags, err = s.resolveTags(ctx, req.Tags); err != nil {
+			return nil, err
+		}
+	}
+	if req.Expenses != nil {
+		if trip.Expenses, err = buildExpenses(req.Expenses); err != nil {
+			return nil, err
+		}
+	}
+
 	// Get or create client
 	client, err := s.clientService.GetOrCreateClient(ctx, req.ClientName)
 	if err != nil {
 		return nil, err
 	}
+	if req.Expenses != nil {
+		if err := s.fillExpenseCurrencies(ctx, trip.Expenses, client); err != nil {
+			return nil, err
+		}
+	}
 
 	// Update trip fields
 	trip.ClientID = &client.ID
 	trip.ClientName = req.ClientName
 	trip.TripDate = req.TripDate
-	trip.Miles = req.Miles
+	trip.Miles = miles
 	trip.Notes = req.Notes
+	trip.FromLocationID = req.FromLocationID
+	trip.ToLocationID = req.ToLocationID
+	trip.StartTime = inUTC(req.StartTime)
+	trip.EndTime = inUTC(req.EndTime)
 
 	err = s.tripRepo.Update(ctx, trip)
 	if err != nil {
 		return nil, err
 	}
 
-	return trip, nil
+	return s.withDistanceAndAmount(ctx, trip, req.Unit)
 }
 
+// DeleteTrip deletes the trip together with its attachment records, and
+// only then removes the attachments' content, so a failed delete never
+// leaves records pointing at missing content
 func (s *tripService) DeleteTrip(ctx context.Context, id uint) error {
-	return s.tripRepo.Delete(ctx, id)
+	var attachments []domain.Attachment
+	if s.attachmentService != nil {
+		var err error
+		if attachments, err = s.attachmentService.FindTripAttachments(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	if err := s.tripRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	if s.attachmentService != nil {
+		s.attachmentService.RemoveContent(ctx, attachments)
+	}
+	return nil
 }
 
 func (s *tripService) GetTripByID(ctx context.Context, id uint) (*domain.Trip, error) {
-	return s.tripRepo.FindByID(ctx, id)
+	trip, err := s.tripRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.withDistanceAndAmount(ctx, trip, "")
+}
+
+// withDistanceAndAmount fills in the trip's distance in the given unit,
+// defaulting to the distance_unit setting, and its amount
+func (s *tripService) withDistanceAndAmount(ctx context.Context, trip *domain.Trip, unitName string) (*domain.Trip, error) {
+	unit, err := resolveDistanceUnit(ctx, s.settingsRepo, unitName)
+	if err != nil {
+		return nil, err
+	}
+	rate, err := mileageRate(ctx, s.settingsRepo)
+	if err != nil {
+		return nil, err
+	}
+	setTripDistance(trip, unit)
+	trip.Amount = tripAmount(trip.Miles, rate)
+	return trip, nil
 }
 
 func (s *tripService) GetTrips(ctx context.Context, page, limit int, filters domain.TripFilters) ([]domain.Trip, int64, error) {
@@ -114,85 +286,688 @@ func (s *tripService) GetTrips(ctx context.Context, page, limit int, filters dom
 		limit = 10
 	}
 
-	return s.tripRepo.GetPaginated(ctx, page, limit, filters)
+	filters, err := s.resolveTimeBounds(filters)
+	if err != nil {
+		return nil, 0, err
+	}
+	unit, err := resolveDistanceUnit(ctx, s.settingsRepo, string(filters.Unit))
+	if err != nil {
+		return nil, 0, err
+	}
+	rate, err := mileageRate(ctx, s.settingsRepo)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	trips, total, err := s.tripRepo.GetPaginated(ctx, page, limit, filters)
+	if err != nil {
+		return nil, 0, err
+	}
+	setTripDistances(trips, unit)
+	setTripAmounts(trips, rate)
+
+	return trips, total, nil
+}
+
+// resolveTags looks up, or creates, the tags with the given names
+func (s *tripService) resolveTags(ctx context.Context, names []string) ([]domain.Tag, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	return s.tagService.ResolveTags(ctx, names)
+}
+
+// resolveTimeBounds turns the date-only created/updated bounds into
+// timestamps at midnight in the business timezone
+func (s *tripService) resolveTimeBounds(filters domain.TripFilters) (domain.TripFilters, error) {
+	bounds := []struct {
+		name  string
+		value *string
+	}{
+		{"created_after", &filters.CreatedAfter},
+		{"created_before", &filters.CreatedBefore},
+		{"updated_after", &filters.UpdatedAfter},
+		{"updated_before", &filters.UpdatedBefore},
+	}
+	for _, bound := range bounds {
+		if *bound.value == "" {
+			continue
+		}
+		t, err := domain.ParseTimeBound(*bound.value, s.location)
+		if err != nil {
+			return filters, fmt.Errorf("%w: %s must be a YYYY-MM-DD date or an RFC 3339 timestamp", ErrValidation, bound.name)
+		}
+		*bound.value = t.Format(time.RFC3339Nano)
+	}
+	return filters, nil
 }
 
-func (s *tripService) GetSummary(ctx context.Context) (*domain.SummaryResponse, error) {
-	// Calculate date range for last 6 months
-	now := time.Now()
-	startDate := now.AddDate(0, -5, 0).Format("2006-01-01")                                                        // Start of 6 months ago
-	endDate := time.Date(now.Year(), now.Month()+1, 0, 23, 59, 59, 999999999, now.Location()).Format("2006-01-02") // Last day of current month
+// GetTripPage returns the page of trips following the cursor token; an
+// empty token returns the first page
+func (s *tripService) GetTripPage(ctx context.Context, cursor string, limit int, filters domain.TripFilters) (*domain.TripPage, error) {
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
 
-	// Get monthly summaries from repository
-	summaries, err := s.tripRepo.GetMonthlySummary(ctx, startDate, endDate)
+	filters, err := s.resolveTimeBounds(filters)
 	if err != nil {
 		return nil, err
 	}
-
-	// Get mileage rate
-	mileageRate, err := s.getMileageRate(ctx)
+	unit, err := resolveDistanceUnit(ctx, s.settingsRepo, string(filters.Unit))
 	if err != nil {
 		return nil, err
 	}
+	rate, err := mileageRate(ctx, s.settingsRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	sort := filters.Sort
+	if len(sort) == 0 {
+		sort = domain.DefaultTripSort
+	}
 
-	// Calculate amounts for each month
-	for i := range summaries {
-		summaries[i].Amount = summaries[i].TotalMiles * mileageRate
+	var after *domain.TripCursor
+	if cursor != "" {
+		decoded, err := domain.DecodeTripCursor(cursor, sort)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrValidation, err)
+		}
+		after = &decoded
 	}
 
-	// Ensure we have 6 months of data (fill missing months with zeros)
-	fullSummaries := s.fillMissingMonths(summaries, 6)
+	// One extra row tells whether another page follows
+	trips, err := s.tripRepo.GetPageAfter(ctx, after, limit+1, filters)
+	if err != nil {
+		return nil, err
+	}
 
-	return &domain.SummaryResponse{
-		Months: fullSummaries,
-	}, nil
+	setTripDistances(trips, unit)
+	setTripAmounts(trips, rate)
+
+	page := &domain.TripPage{Trips: trips, Limit: limit}
+	if len(trips) > limit {
+		page.Trips = trips[:limit]
+		page.HasMore = true
+		page.NextCursor = domain.CursorAfter(trips[limit-1], sort).Encode()
+	}
+
+	return page, nil
 }
 
-func (s *tripService) getMileageRate(ctx context.Context) (float64, error) {
-	settings, err := s.settingsRepo.GetByKey(ctx, "mileage_rate")
+// maxSummaryBuckets bounds how many periods one summary may cover
+const maxSummaryBuckets = 1000
+
+// defaultSummaryBuckets is how many periods are shown when no range is given
+const defaultSummaryBuckets = 6
+
+// defaultSummaryGroups and maxSummaryGroups bound how many groups a grouped
+// summary lists before merging the rest into "Other"
+const (
+	defaultSummaryGroups = 5
+	maxSummaryGroups     = 50
+)
+
+func (s *tripService) GetSummary(ctx context.Context, query domain.SummaryQuery) (*domain.SummaryResponse, error) {
+	granularity := query.Granularity
+	if granularity == "" {
+		granularity = domain.GranularityMonth
+	}
+	if !granularity.Valid() {
+		return nil, fmt.Errorf("%w: granularity must be one of day, week, month, quarter or year", ErrValidation)
+	}
+	if query.GroupBy != "" && !query.GroupBy.Valid() {
+		return nil, fmt.Errorf("%w: group_by must be one of client, from_location, to_location or tag", ErrValidation)
+	}
+	if query.Top < 0 || query.Top > maxSummaryGroups {
+		return nil, fmt.Errorf("%w: top must be between 1 and %d", ErrValidation, maxSummaryGroups)
+	}
+
+	filters, err := s.resolveTimeBounds(query.Filters)
+	if err != nil {
+		return nil, err
+	}
+	query.Filters = filters
+
+	from, to, err := s.summaryRange(query.From, query.To, granularity)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make([]domain.SummaryBucket, 0)
+	for start := bucketStart(from, granularity); !start.After(to); start = nextBucket(start, granularity) {
+		if len(buckets) == maxSummaryBuckets {
+			return nil, fmt.Errorf("%w: range covers more than %d %s periods", ErrValidation, maxSummaryBuckets, granularity)
+		}
+		buckets = append(buckets, newSummaryBucket(start, granularity))
+	}
+
+	expenses, err := s.tripRepo.GetExpenseTotals(ctx, "", from.Format("2006-01-02"), to.Format("2006-01-02"), query.Filters)
 	if err != nil {
-		return 0.67, nil // default rate
+		return nil, err
 	}
 
-	rate, err := strconv.ParseFloat(settings.Value, 64)
+	pricer, err := s.newSummaryPricer(ctx, query.Currency, string(query.Unit), to, expenses)
 	if err != nil {
-		return 0.67, nil // default rate
+		return nil, err
+	}
+
+	rows, err := s.tripRepo.GetSummaryBuckets(ctx, pricer.granularity(granularity),
+		from.Format("2006-01-02"), to.Format("2006-01-02"), query.Filters, pricer.tripMiles())
+	if err != nil {
+		return nil, err
+	}
+
+	// Merge the totals into the gap-filled buckets
+	index := make(map[string]int, len(buckets))
+	for i, bucket := range buckets {
+		index[bucket.StartDate] = i
 	}
 
-	return rate, nil
+	for _, row := range rows {
+		i, ok := index[bucketKey(row.StartDate, granularity)]
+		if !ok {
+			continue
+		}
+		priced, err := pricer.trips(row.Totals(), row.StartDate)
+		if err != nil {
+			return nil, err
+		}
+		addBucketTotals(&buckets[i], priced)
+	}
+	for _, row := range expenses {
+		if i, ok := index[bucketKey(row.TripDate, granularity)]; ok {
+			addBucketTotals(&buckets[i], pricer.expenses(row))
+		}
+	}
+
+	// Totals add up the rounded buckets
+	var totals domain.SummaryTotals
+	for i := range buckets {
+		roundBucket(&buckets[i])
+		addSummaryTotals(&totals, buckets[i].Totals())
+	}
+
+	response := &domain.SummaryResponse{
+		From:        from.Format("2006-01-02"),
+		To:          to.Format("2006-01-02"),
+		Granularity: granularity,
+		Currency:    pricer.currency,
+		Unit:        pricer.unit,
+		Buckets:     buckets,
+		Totals:      totals,
+		Timezone:    s.location.String(),
+		AsOf:        s.today().Format("2006-01-02"),
+	}
+	if granularity == domain.GranularityMonth {
+		response.Months = monthlySummaries(buckets)
+	}
+
+	if query.GroupBy != "" {
+		groups, err := s.summaryGroups(ctx, query, granularity, from, to, buckets, pricer)
+		if err != nil {
+			return nil, err
+		}
+		response.GroupBy = query.GroupBy
+		response.Groups = groups
+	}
+
+	return response, nil
 }
 
-func (s *tripService) fillMissingMonths(summaries []domain.MonthlySummary, monthCount int) []domain.MonthlySummary {
-	now := time.Now()
-	result := make([]domain.MonthlySummary, 0, monthCount)
+// summaryGroups builds the pivot of groups against buckets. The groups with
+// the most miles are kept and the long tail is merged into a single "Other"
+// row so the matrix stays readable.
+func (s *tripService) summaryGroups(
+	ctx context.Context,
+	query domain.SummaryQuery,
+	granularity domain.Granularity,
+	from, to time.Time,
+	buckets []domain.SummaryBucket,
+	pricer *summaryPricer,
+) ([]domain.SummaryGroup, error) {
+	rows, err := s.tripRepo.GetGroupedSummaryBuckets(ctx, pricer.granularity(granularity), query.GroupBy,
+		from.Format("2006-01-02"), to.Format("2006-01-02"), query.Filters, pricer.tripMiles())
+	if err != nil {
+		return nil, err
+	}
+
+	expenses, err := s.tripRepo.GetExpenseTotals(ctx, query.GroupBy,
+		from.Format("2006-01-02"), to.Format("2006-01-02"), query.Filters)
+	if err != nil {
+		return nil, err
+	}
 
-	// Create a map of existing summaries
-	summaryMap := make(map[string]domain.MonthlySummary)
-	for _, summary := range summaries {
-		key := fmt.Sprintf("%d-%02d", summary.Year, summary.MonthNum)
-		summaryMap[key] = summary
+	top := query.Top
+	if top == 0 {
+		top = defaultSummaryGroups
 	}
 
-	// Generate the last 6 months
-	for i := 0; i < monthCount; i++ {
-		monthTime := now.AddDate(0, -i, 0)
-		year := monthTime.Year()
-		monthNum := int(monthTime.Month())
-		monthName := monthTime.Format("January 2006")
-		key := fmt.Sprintf("%d-%02d", year, monthNum)
+	bucketIndex := make(map[string]int, len(buckets))
+	for i, bucket := range buckets {
+		bucketIndex[bucket.StartDate] = i
+	}
 
-		if summary, exists := summaryMap[key]; exists {
-			result = append(result, summary)
-		} else {
-			result = append(result, domain.MonthlySummary{
-				Month:      monthName,
-				Year:       year,
-				MonthNum:   monthNum,
-				TotalMiles: 0,
-				Amount:     0,
+	// Collect every group's per-bucket values
+	groupIndex := make(map[string]int)
+	groups := make([]domain.SummaryGroup, 0)
+	add := func(group, date string, totals domain.SummaryTotals) {
+		b, ok := bucketIndex[bucketKey(date, granularity)]
+		if !ok {
+			return
+		}
+		g, ok := groupIndex[group]
+		if !ok {
+			g = len(groups)
+			groupIndex[group] = g
+			groups = append(groups, domain.SummaryGroup{
+				Key:    group,
+				Label:  groupLabel(group),
+				Values: make([]domain.SummaryTotals, len(buckets)),
 			})
 		}
+		addSummaryTotals(&groups[g].Values[b], totals)
+	}
+	for _, row := range rows {
+		priced, err := pricer.trips(row.Totals(), row.StartDate)
+		if err != nil {
+			return nil, err
+		}
+		add(row.Group, row.StartDate, priced)
+	}
+	for _, row := range expenses {
+		add(row.Group, row.TripDate, pricer.expenses(row))
+	}
+	for g := range groups {
+		for b := range groups[g].Values {
+			roundTotals(&groups[g].Values[b])
+			addSummaryTotals(&groups[g].Totals, groups[g].Values[b])
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Totals.TotalMiles != groups[j].Totals.TotalMiles {
+			return groups[i].Totals.TotalMiles > groups[j].Totals.TotalMiles
+		}
+		return groups[i].Key < groups[j].Key
+	})
+
+	if len(groups) <= top {
+		return groups, nil
+	}
+
+	other := domain.SummaryGroup{
+		Label:        "Other",
+		Other:        true,
+		MergedGroups: len(groups) - top,
+		Values:       make([]domain.SummaryTotals, len(buckets)),
+	}
+	for _, group := range groups[top:] {
+		for b, value := range group.Values {
+			addSummaryTotals(&other.Values[b], value)
+		}
+		addSummaryTotals(&other.Totals, group.Totals)
+	}
+
+	return append(groups[:top], other), nil
+}
+
+// addSummaryTotals adds priced totals to a running total
+func addSummaryTotals(totals *domain.SummaryTotals, add domain.SummaryTotals) {
+	totals.TripCount += add.TripCount
+	totals.TotalMiles += add.TotalMiles
+	totals.TotalMinutes += add.TotalMinutes
+	totals.Amount = totals.Amount.Add(add.Amount)
+	totals.TotalDistance += add.TotalDistance
+	totals.ExpenseAmount = totals.ExpenseAmount.Add(add.ExpenseAmount)
+	totals.UnconvertedExpenses += add.UnconvertedExpenses
+	totals.TotalAmount = totals.TotalAmount.Add(add.TotalAmount)
+}
+
+// addBucketTotals adds priced totals to a gap-filled bucket
+func addBucketTotals(bucket *domain.SummaryBucket, add domain.SummaryTotals) {
+	bucket.TripCount += add.TripCount
+	bucket.TotalMiles += add.TotalMiles
+	bucket.TotalMinutes += add.TotalMinutes
+	bucket.Amount = bucket.Amount.Add(add.Amount)
+	bucket.TotalDistance += add.TotalDistance
+	bucket.ExpenseAmount = bucket.ExpenseAmount.Add(add.ExpenseAmount)
+	bucket.UnconvertedExpenses += add.UnconvertedExpenses
+	bucket.TotalAmount = bucket.TotalAmount.Add(add.TotalAmount)
+}
+
+// bucketKey returns the start date of the bucket containing date, both
+// formatted YYYY-MM-DD
+func bucketKey(date string, granularity domain.Granularity) string {
+	parsed, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return date
+	}
+	return bucketStart(parsed, granularity).Format("2006-01-02")
+}
+
+// summaryPricer prices aggregated trips and expenses in one currency and
+// measures their distance in one unit. Amounts in other currencies are
+// converted as of the trip date. With per-period rounding amounts are
+// left unrounded for the caller to round once per period.
+type summaryPricer struct {
+	currency     string       // Currency amounts are reported in
+	mileageRate  domain.Money // Per mile
+	rateCurrency string       // Currency the mileage rate is set in
+	rounding     domain.AmountRounding
+	converter    *CurrencyConverter
+	unit         domain.DistanceUnit // Unit distances are reported in
+}
+
+// newSummaryPricer prices summaries up to and including to. A blank
+// currency selects the mileage rate currency and a blank unit the
+// distance_unit setting. Exchange rates are loaded only when the mileage
+// rate or some of the expenses need converting.
+func (s *tripService) newSummaryPricer(ctx context.Context, currency, unit string, to time.Time, expenses []domain.ExpenseTotalRow) (*summaryPricer, error) {
+	currency, err := parseCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	distanceUnit, err := resolveDistanceUnit(ctx, s.settingsRepo, unit)
+	if err != nil {
+		return nil, err
+	}
+
+	pricer := &summaryPricer{currency: currency, unit: distanceUnit}
+	if pricer.mileageRate, err = mileageRate(ctx, s.settingsRepo); err != nil {
+		return nil, err
+	}
+	if pricer.rateCurrency, err = mileageRateCurrency(ctx, s.settingsRepo); err != nil {
+		return nil, err
+	}
+	if pricer.rounding, err = amountRounding(ctx, s.settingsRepo); err != nil {
+		return nil, err
+	}
+	if pricer.currency == "" {
+		pricer.currency = pricer.rateCurrency
+	}
+
+	currencies := []string{pricer.currency}
+	seen := map[string]bool{pricer.currency: true}
+	for _, code := range append([]string{pricer.rateCurrency}, expenseCurrencies(expenses)...) {
+		if !seen[code] {
+			seen[code] = true
+			currencies = append(currencies, code)
+		}
+	}
+	if len(currencies) > 1 && s.exchangeRates != nil {
+		pricer.converter, err = s.exchangeRates.Converter(ctx, currencies, to.Format("2006-01-02"))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return pricer, nil
+}
+
+func expenseCurrencies(rows []domain.ExpenseTotalRow) []string {
+	currencies := make([]string, len(rows))
+	for i, row := range rows {
+		currencies[i] = row.Currency
+	}
+	return currencies
+}
+
+// granularity returns the granularity trips must be aggregated at to be
+// priced: per day when the mileage rate has to be converted as of each
+// trip date
+func (p *summaryPricer) granularity(granularity domain.Granularity) domain.Granularity {
+	if p.rateCurrency != p.currency {
+		return domain.GranularityDay
+	}
+	return granularity
+}
+
+// tripMiles reports whether trips must be aggregated with the miles of
+// every trip, which only rounding trip by trip needs
+func (p *summaryPricer) tripMiles() bool {
+	return p.rounding == domain.RoundPerTrip
+}
+
+// trips prices the mileage of trips aggregated from date onwards and
+// converts their distance. The mileage amount must be convertible, as
+// leaving it out would understate every total.
+func (p *summaryPricer) trips(totals domain.SummaryTotals, date string) (domain.SummaryTotals, error) {
+	var amount domain.Money
+	if p.rounding == domain.RoundPerTrip {
+		// Totals that do not list their trips are priced as one trip
+		trips := totals.TripMiles
+		if trips == nil {
+			trips = []float64{totals.TotalMiles}
+		}
+		for _, miles := range trips {
+			converted, err := p.convertMileage(tripAmount(miles, p.mileageRate), date)
+			if err != nil {
+				return totals, err
+			}
+			amount = amount.Add(converted.RoundCents())
+		}
+	} else {
+		var err error
+		if amount, err = p.convertMileage(mileageAmount(totals.TotalMiles, p.mileageRate), date); err != nil {
+			return totals, err
+		}
+	}
+
+	totals.Amount = amount
+	totals.TotalAmount = amount
+	totals.TotalDistance = p.unit.FromMiles(totals.TotalMiles)
+	totals.TripMiles = nil
+	return totals, nil
+}
+
+func (p *summaryPricer) convertMileage(amount domain.Money, date string) (domain.Money, error) {
+	converted, ok := p.converter.Convert(amount, p.rateCurrency, p.currency, date)
+	if !ok {
+		return converted, fmt.Errorf("%w: no exchange rate from %s to %s on or before %s", ErrValidation, p.rateCurrency, p.currency, date)
+	}
+	return converted, nil
+}
+
+// expenses prices expenses totalled for one trip date. Expenses without an
+// exchange rate are counted instead.
+func (p *summaryPricer) expenses(row domain.ExpenseTotalRow) domain.SummaryTotals {
+	amount, ok := p.converter.Convert(row.Amount, row.Currency, p.currency, row.TripDate)
+	if !ok {
+		return domain.SummaryTotals{UnconvertedExpenses: row.ExpenseCount}
+	}
+	if p.rounding == domain.RoundPerTrip {
+		amount = amount.RoundCents()
+	}
+	return domain.SummaryTotals{ExpenseAmount: amount, TotalAmount: amount}
+}
+
+func groupLabel(key string) string {
+	if key == "" {
+		return "Unspecified"
+	}
+	return key
+}
+
+// summaryRange parses the requested range, defaulting to the most recent
+// buckets up to and including the one containing today
+func (s *tripService) summaryRange(fromStr, toStr string, granularity domain.Granularity) (time.Time, time.Time, error) {
+	var from, to time.Time
+	var err error
+
+	if toStr != "" {
+		if to, err = time.ParseInLocation("2006-01-02", toStr, s.location); err != nil {
+			return from, to, fmt.Errorf("%w: to must be in YYYY-MM-DD format", ErrValidation)
+		}
+	} else {
+		to = nextBucket(bucketStart(s.today(), granularity), granularity).AddDate(0, 0, -1)
+	}
+
+	if fromStr != "" {
+		if from, err = time.ParseInLocation("2006-01-02", fromStr, s.location); err != nil {
+			return from, to, fmt.Errorf("%w: from must be in YYYY-MM-DD format", ErrValidation)
+		}
+	} else {
+		from = bucketStart(to, granularity)
+		for i := 1; i < defaultSummaryBuckets; i++ {
+			from = previousBucket(from, granularity)
+		}
+	}
+
+	if from.After(to) {
+		return from, to, fmt.Errorf("%w: from cannot be after to", ErrValidation)
+	}
+
+	return from, to, nil
+}
+
+// bucketStart returns midnight on the first day of the bucket containing t
+func bucketStart(t time.Time, granularity domain.Granularity) time.Time {
+	year, month, day := t.Date()
+	switch granularity {
+	case domain.GranularityWeek:
+		offset := (int(t.Weekday()) + 6) % 7 // days since Monday
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, t.Location())
+	case domain.GranularityMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+	case domain.GranularityQuarter:
+		return time.Date(year, month-(month-1)%3, 1, 0, 0, 0, 0, t.Location())
+	case domain.GranularityYear:
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	}
+}
+
+// nextBucket steps from the start of one bucket to the start of the next.
+// Stepping from the first of a month never skips short months.
+func nextBucket(start time.Time, granularity domain.Granularity) time.Time {
+	return stepBucket(start, granularity, 1)
+}
+
+func previousBucket(start time.Time, granularity domain.Granularity) time.Time {
+	return stepBucket(start, granularity, -1)
+}
+
+func stepBucket(start time.Time, granularity domain.Granularity, n int) time.Time {
+	switch granularity {
+	case domain.GranularityWeek:
+		return start.AddDate(0, 0, 7*n)
+	case domain.GranularityMonth:
+		return start.AddDate(0, n, 0)
+	case domain.GranularityQuarter:
+		return start.AddDate(0, 3*n, 0)
+	case domain.GranularityYear:
+		return start.AddDate(n, 0, 0)
+	default:
+		return start.AddDate(0, 0, n)
+	}
+}
+
+// newSummaryBucket returns an empty bucket starting at start
+func newSummaryBucket(start time.Time, granularity domain.Granularity) domain.SummaryBucket {
+	bucket := domain.SummaryBucket{
+		StartDate: start.Format("2006-01-02"),
+		EndDate:   nextBucket(start, granularity).AddDate(0, 0, -1).Format("2006-01-02"),
+	}
+
+	switch granularity {
+	case domain.GranularityWeek:
+		year, week := start.ISOWeek()
+		bucket.Period = fmt.Sprintf("%d-W%02d", year, week)
+		bucket.Label = "Week of " + start.Format("Jan 2, 2006")
+	case domain.GranularityMonth:
+		bucket.Period = start.Format("2006-01")
+		bucket.Label = start.Format("January 2006")
+	case domain.GranularityQuarter:
+		bucket.Period = fmt.Sprintf("%d-Q%d", start.Year(), (int(start.Month())-1)/3+1)
+		bucket.Label = fmt.Sprintf("Q%d %d", (int(start.Month())-1)/3+1, start.Year())
+	case domain.GranularityYear:
+		bucket.Period = start.Format("2006")
+		bucket.Label = start.Format("2006")
+	default:
+		bucket.Period = start.Format("2006-01-02")
+		bucket.Label = start.Format("Jan 2, 2006")
+	}
+
+	return bucket
+}
+
+// monthlySummaries converts monthly buckets into the original summary
+// format, newest first
+func monthlySummaries(buckets []domain.SummaryBucket) []domain.MonthlySummary {
+	months := make([]domain.MonthlySummary, 0, len(buckets))
+	for i := len(buckets) - 1; i >= 0; i-- {
+		bucket := buckets[i]
+		start, _ := time.Parse("2006-01-02", bucket.StartDate)
+		months = append(months, domain.MonthlySummary{
+			Month:         bucket.Label,
+			Year:          start.Year(),
+			MonthNum:      int(start.Month()),
+			TotalMiles:    bucket.TotalMiles,
+			TotalMinutes:  bucket.TotalMinutes,
+			Amount:        bucket.Amount,
+			TotalDistance: bucket.TotalDistance,
+			ExpenseAmount: bucket.ExpenseAmount,
+			TotalAmount:   bucket.TotalAmount,
+		})
+	}
+	return months
+}
+
+// today returns the current time in the business timezone
+func (s *tripService) today() time.Time {
+	return s.now().In(s.location)
+}
+
+// validateTripTimes checks that optional start and end times are consistent
+// with each other and with the trip date. The start time's day is taken in
+// the business timezone rather than from its own offset: Postgres keeps only
+// the instant, so a time read back from it carries the database's zone, not
+// the one it was sent with.
+func (s *tripService) validateTripTimes(tripDate string, start, end *time.Time) error {
+	if start == nil {
+		if end != nil {
+			return fmt.Errorf("%w: end_time requires start_time", ErrValidation)
+		}
+		return nil
+	}
+	if start.In(s.location).Format("2006-01-02") != tripDate {
+		return fmt.Errorf("%w: start_time must fall on trip_date", ErrValidation)
+	}
+	if end != nil && !end.After(*start) {
+		return fmt.Errorf("%w: end_time must be after start_time", ErrValidation)
+	}
+	return nil
+}
+
+// inUTC returns an optional time in UTC. Trip times are stored in UTC, since
+// SQLite keeps them as text with their offset and sorts them as strings.
+func inUTC(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+// resolveMiles returns the miles given by the caller, or fills them in from
+// the saved locations when the caller left miles empty
+func (s *tripService) resolveMiles(ctx context.Context, miles float64, fromID, toID *uint) (float64, error) {
+	if miles > 0 || (fromID == nil && toID == nil) {
+		return miles, nil
+	}
+	if fromID == nil || toID == nil {
+		return 0, fmt.Errorf("%w: both from_location_id and to_location_id are needed to calculate miles", ErrValidation)
+	}
+
+	distance, err := s.locationService.ResolveDistance(ctx, *fromID, *toID)
+	if errors.Is(err, ErrNotFound) {
+		return 0, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	if err != nil {
+		return 0, err
 	}
 
-	return result
+	return distance.Miles, nil
 }
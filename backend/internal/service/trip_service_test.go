@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"gorm.io/gorm"
@@ -22,6 +23,11 @@ func (m *MockTripRepository) Create(ctx context.Context, trip *domain.Trip) erro
 	return args.Error(0)
 }
 
+func (m *MockTripRepository) CreateAll(ctx context.Context, trips []domain.Trip) error {
+	args := m.Called(ctx, trips)
+	return args.Error(0)
+}
+
 func (m *MockTripRepository) Update(ctx context.Context, trip *domain.Trip) error {
 	args := m.Called(ctx, trip)
 	return args.Error(0)
@@ -48,12 +54,36 @@ func (m *MockTripRepository) GetPaginated(ctx context.Context, page, limit int,
 	return args.Get(0).([]domain.Trip), args.Get(1).(int64), args.Error(2)
 }
 
-func (m *MockTripRepository) GetMonthlySummary(ctx context.Context, startDate, endDate string) ([]domain.MonthlySummary, error) {
-	args := m.Called(ctx, startDate, endDate)
+func (m *MockTripRepository) GetPageAfter(ctx context.Context, after *domain.TripCursor, limit int, filters domain.TripFilters) ([]domain.Trip, error) {
+	args := m.Called(ctx, after, limit, filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Trip), args.Error(1)
+}
+
+func (m *MockTripRepository) GetSummaryBuckets(ctx context.Context, granularity domain.Granularity, from, to string, filters domain.TripFilters, tripMiles bool) ([]domain.SummaryBucket, error) {
+	args := m.Called(ctx, granularity, from, to, filters, tripMiles)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]domain.MonthlySummary), args.Error(1)
+	return args.Get(0).([]domain.SummaryBucket), args.Error(1)
+}
+
+func (m *MockTripRepository) GetGroupedSummaryBuckets(ctx context.Context, granularity domain.Granularity, groupBy domain.SummaryDimension, from, to string, filters domain.TripFilters, tripMiles bool) ([]domain.SummaryGroupRow, error) {
+	args := m.Called(ctx, granularity, groupBy, from, to, filters, tripMiles)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.SummaryGroupRow), args.Error(1)
+}
+
+func (m *MockTripRepository) GetExpenseTotals(ctx context.Context, groupBy domain.SummaryDimension, from, to string, filters domain.TripFilters) ([]domain.ExpenseTotalRow, error) {
+	args := m.Called(ctx, groupBy, from, to, filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ExpenseTotalRow), args.Error(1)
 }
 
 type MockTripClientService struct {
@@ -76,6 +106,14 @@ func (m *MockTripClientService) GetSuggestions(ctx context.Context, query string
 	return args.Get(0).([]domain.Client), args.Error(1)
 }
 
+func (m *MockTripClientService) UpdateClient(ctx context.Context, id uint, req domain.UpdateClientRequest) (*domain.Client, error) {
+	args := m.Called(ctx, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Client), args.Error(1)
+}
+
 type MockTripSettingsRepository struct {
 	mock.Mock
 }
@@ -88,8 +126,13 @@ func (m *MockTripSettingsRepository) GetByKey(ctx context.Context, key string) (
 	return args.Get(0).(*domain.Settings), args.Error(1)
 }
 
-func (m *MockTripSettingsRepository) UpdateByKey(ctx context.Context, key, value string) error {
-	args := m.Called(ctx, key, value)
+func (m *MockTripSettingsRepository) UpsertAll(ctx context.Context, settings []domain.Settings) error {
+	args := m.Called(ctx, settings)
+	return args.Error(0)
+}
+
+func (m *MockTripSettingsRepository) InsertMissing(ctx context.Context, settings []domain.Settings) error {
+	args := m.Called(ctx, settings)
 	return args.Error(0)
 }
 
@@ -101,12 +144,37 @@ func (m *MockTripSettingsRepository) GetAll(ctx context.Context) ([]domain.Setti
 	return args.Get(0).([]domain.Settings), args.Error(1)
 }
 
+// newMockTripSettingsRepository returns a settings mock reporting distances
+// in miles, the default unit
+func newMockTripSettingsRepository() *MockTripSettingsRepository {
+	settingsRepo := new(MockTripSettingsRepository)
+	settingsRepo.On("GetByKey", mock.Anything, "distance_unit").Return(&domain.Settings{Key: "distance_unit", Value: "mi"}, nil).Maybe()
+	settingsRepo.On("GetByKey", mock.Anything, "amount_rounding").Return(&domain.Settings{Key: "amount_rounding", Value: "per_period"}, nil).Maybe()
+	settingsRepo.On("GetByKey", mock.Anything, "mileage_rate").Return(&domain.Settings{Key: "mileage_rate", Value: "0.67"}, nil).Maybe()
+	return settingsRepo
+}
+
+// stubSetting replaces the stub of a setting set up by
+// newMockTripSettingsRepository
+func stubSetting(settingsRepo *MockTripSettingsRepository, key string) *mock.Call {
+	var defaults []*mock.Call
+	for _, call := range settingsRepo.ExpectedCalls {
+		if call.Method == "GetByKey" && call.Arguments.Get(1) == key {
+			defaults = append(defaults, call)
+		}
+	}
+	for _, call := range defaults {
+		call.Unset()
+	}
+	return settingsRepo.On("GetByKey", mock.Anything, key)
+}
+
 func TestTripService_CreateTrip(t *testing.T) {
 	mockTripRepo := new(MockTripRepository)
 	mockClientService := new(MockTripClientService)
-	mockSettingsRepo := new(MockTripSettingsRepository)
+	mockSettingsRepo := newMockTripSettingsRepository()
 
-	tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo)
+	tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo, nil, nil, nil, nil, time.UTC)
 
 	t.Run("should create trip successfully", func(t *testing.T) {
 		// Setup
@@ -197,8 +265,8 @@ func TestTripService_CreateTrip(t *testing.T) {
 		// Setup - create fresh mocks for this test
 		freshMockTripRepo := new(MockTripRepository)
 		freshMockClientService := new(MockTripClientService)
-		freshMockSettingsRepo := new(MockTripSettingsRepository)
-		freshTripService := NewTripService(freshMockTripRepo, freshMockClientService, freshMockSettingsRepo)
+		freshMockSettingsRepo := newMockTripSettingsRepository()
+		freshTripService := NewTripService(freshMockTripRepo, freshMockClientService, freshMockSettingsRepo, nil, nil, nil, nil, time.UTC)
 
 		req := domain.CreateTripRequest{
 			ClientName: "Test Client",
@@ -227,8 +295,8 @@ func TestTripService_CreateTrip(t *testing.T) {
 		// Setup - create fresh mocks for this test
 		freshMockTripRepo := new(MockTripRepository)
 		freshMockClientService := new(MockTripClientService)
-		freshMockSettingsRepo := new(MockTripSettingsRepository)
-		freshTripService := NewTripService(freshMockTripRepo, freshMockClientService, freshMockSettingsRepo)
+		freshMockSettingsRepo := newMockTripSettingsRepository()
+		freshTripService := NewTripService(freshMockTripRepo, freshMockClientService, freshMockSettingsRepo, nil, nil, nil, nil, time.UTC)
 
 		req := domain.CreateTripRequest{
 			ClientName: "Test Client",
@@ -345,8 +413,8 @@ func TestTripService_CreateTrip(t *testing.T) {
 					// Create fresh mocks for each test
 					freshMockTripRepo := new(MockTripRepository)
 					freshMockClientService := new(MockTripClientService)
-					freshMockSettingsRepo := new(MockTripSettingsRepository)
-					freshTripService := NewTripService(freshMockTripRepo, freshMockClientService, freshMockSettingsRepo)
+					freshMockSettingsRepo := newMockTripSettingsRepository()
+					freshTripService := NewTripService(freshMockTripRepo, freshMockClientService, freshMockSettingsRepo, nil, nil, nil, nil, time.UTC)
 
 					client := &domain.Client{
 						ID:   1,
@@ -371,8 +439,8 @@ func TestTripService_CreateTrip(t *testing.T) {
 					// Create fresh service for failed cases (no mocks needed since it fails early)
 					freshMockTripRepo := new(MockTripRepository)
 					freshMockClientService := new(MockTripClientService)
-					freshMockSettingsRepo := new(MockTripSettingsRepository)
-					freshTripService := NewTripService(freshMockTripRepo, freshMockClientService, freshMockSettingsRepo)
+					freshMockSettingsRepo := newMockTripSettingsRepository()
+					freshTripService := NewTripService(freshMockTripRepo, freshMockClientService, freshMockSettingsRepo, nil, nil, nil, nil, time.UTC)
 
 					// Execute
 					result, err := freshTripService.CreateTrip(context.Background(), tc.request)
@@ -393,8 +461,8 @@ func TestTripService_UpdateTrip(t *testing.T) {
 		// Setup - create fresh mocks for this test
 		mockTripRepo := new(MockTripRepository)
 		mockClientService := new(MockTripClientService)
-		mockSettingsRepo := new(MockTripSettingsRepository)
-		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo)
+		mockSettingsRepo := newMockTripSettingsRepository()
+		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo, nil, nil, nil, nil, time.UTC)
 
 		req := domain.UpdateTripRequest{
 			ClientName: "Updated Client",
@@ -442,8 +510,8 @@ func TestTripService_UpdateTrip(t *testing.T) {
 		// Setup - create fresh mocks for this test
 		mockTripRepo := new(MockTripRepository)
 		mockClientService := new(MockTripClientService)
-		mockSettingsRepo := new(MockTripSettingsRepository)
-		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo)
+		mockSettingsRepo := newMockTripSettingsRepository()
+		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo, nil, nil, nil, nil, time.UTC)
 
 		req := domain.UpdateTripRequest{
 			ClientName: "Test Client",
@@ -465,8 +533,8 @@ func TestTripService_UpdateTrip(t *testing.T) {
 		// Setup - create fresh mocks for this test
 		mockTripRepo := new(MockTripRepository)
 		mockClientService := new(MockTripClientService)
-		mockSettingsRepo := new(MockTripSettingsRepository)
-		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo)
+		mockSettingsRepo := newMockTripSettingsRepository()
+		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo, nil, nil, nil, nil, time.UTC)
 
 		req := domain.UpdateTripRequest{
 			ClientName: "Test Client",
@@ -493,8 +561,8 @@ func TestTripService_UpdateTrip(t *testing.T) {
 		// Setup - create fresh mocks for this test
 		mockTripRepo := new(MockTripRepository)
 		mockClientService := new(MockTripClientService)
-		mockSettingsRepo := new(MockTripSettingsRepository)
-		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo)
+		mockSettingsRepo := newMockTripSettingsRepository()
+		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo, nil, nil, nil, nil, time.UTC)
 
 		req := domain.UpdateTripRequest{
 			ClientName: "Test Client",
@@ -534,8 +602,8 @@ func TestTripService_UpdateTrip(t *testing.T) {
 		// Setup - create fresh mocks for this test
 		mockTripRepo := new(MockTripRepository)
 		mockClientService := new(MockTripClientService)
-		mockSettingsRepo := new(MockTripSettingsRepository)
-		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo)
+		mockSettingsRepo := newMockTripSettingsRepository()
+		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo, nil, nil, nil, nil, time.UTC)
 
 		req := domain.UpdateTripRequest{
 			ClientName: "Test Client",
@@ -578,14 +646,273 @@ func TestTripService_UpdateTrip(t *testing.T) {
 	})
 }
 
+func TestTripService_CreateTrips(t *testing.T) {
+	requests := []domain.CreateTripRequest{
+		{ClientName: "Test Client", TripDate: "2025-01-15", Miles: 10},
+		{ClientName: "Test Client", TripDate: "2025-01-16", Miles: 20},
+	}
+
+	t.Run("should create every trip together", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		mockClientService := new(MockTripClientService)
+		tripService := NewTripService(mockTripRepo, mockClientService, newMockTripSettingsRepository(), nil, nil, nil, nil, time.UTC)
+
+		mockClientService.On("GetOrCreateClient", mock.Anything, "Test Client").Return(&domain.Client{ID: 1, Name: "Test Client"}, nil)
+		mockTripRepo.On("CreateAll", mock.Anything, mock.MatchedBy(func(trips []domain.Trip) bool {
+			return len(trips) == 2 && trips[0].Miles == 10 && trips[1].Miles == 20
+		})).Return(nil).Once()
+
+		trips, err := tripService.CreateTrips(context.Background(), requests)
+
+		assert.NoError(t, err)
+		assert.Len(t, trips, 2)
+		assert.Equal(t, "2025-01-16", trips[1].TripDate)
+		assert.Equal(t, domain.MustMoney("13.4"), trips[1].Amount)
+		mockTripRepo.AssertExpectations(t)
+	})
+
+	t.Run("should create nothing when any request is invalid", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		mockClientService := new(MockTripClientService)
+		tripService := NewTripService(mockTripRepo, mockClientService, newMockTripSettingsRepository(), nil, nil, nil, nil, time.UTC)
+
+		mockClientService.On("GetOrCreateClient", mock.Anything, "Test Client").Return(&domain.Client{ID: 1, Name: "Test Client"}, nil)
+		invalid := append(append([]domain.CreateTripRequest{}, requests...),
+			domain.CreateTripRequest{ClientName: "Test Client", TripDate: "2025-01-17", Miles: 5, EndTime: &time.Time{}})
+
+		trips, err := tripService.CreateTrips(context.Background(), invalid)
+
+		assert.Nil(t, trips)
+		assert.ErrorIs(t, err, ErrValidation)
+		assert.Contains(t, err.Error(), "trip 3 of 3")
+		mockTripRepo.AssertNotCalled(t, "CreateAll", mock.Anything, mock.Anything)
+		mockTripRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTripService_CreateTripFromLocations(t *testing.T) {
+	fromID, toID := uint(1), uint(2)
+
+	t.Run("should fill miles from recorded location distance", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		mockClientService := new(MockTripClientService)
+		mockLocationRepo := new(MockLocationRepository)
+		tripService := NewTripService(mockTripRepo, mockClientService, newMockTripSettingsRepository(), NewLocationService(mockLocationRepo, nil), nil, nil, nil, time.UTC)
+
+		mockLocationRepo.On("FindByID", mock.Anything, fromID).Return(newTestLocation(fromID, "Home", false), nil)
+		mockLocationRepo.On("FindByID", mock.Anything, toID).Return(newTestLocation(toID, "Office", false), nil)
+		mockLocationRepo.On("FindDistance", mock.Anything, fromID, toID).
+			Return(&domain.LocationDistance{FromLocationID: fromID, ToLocationID: toID, Miles: 18.4}, nil)
+		mockClientService.On("GetOrCreateClient", mock.Anything, "Test Client").Return(&domain.Client{ID: 1, Name: "Test Client"}, nil)
+		mockTripRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Trip")).Return(nil)
+
+		result, err := tripService.CreateTrip(context.Background(), domain.CreateTripRequest{
+			ClientName:     "Test Client",
+			TripDate:       "2025-01-15",
+			FromLocationID: &fromID,
+			ToLocationID:   &toID,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 18.4, result.Miles)
+		assert.Equal(t, &fromID, result.FromLocationID)
+		assert.Equal(t, &toID, result.ToLocationID)
+		mockTripRepo.AssertExpectations(t)
+	})
+
+	t.Run("should keep explicit miles when locations are given", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		mockClientService := new(MockTripClientService)
+		mockLocationRepo := new(MockLocationRepository)
+		tripService := NewTripService(mockTripRepo, mockClientService, newMockTripSettingsRepository(), NewLocationService(mockLocationRepo, nil), nil, nil, nil, time.UTC)
+
+		mockClientService.On("GetOrCreateClient", mock.Anything, "Test Client").Return(&domain.Client{ID: 1, Name: "Test Client"}, nil)
+		mockTripRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Trip")).Return(nil)
+
+		result, err := tripService.CreateTrip(context.Background(), domain.CreateTripRequest{
+			ClientName:     "Test Client",
+			TripDate:       "2025-01-15",
+			Miles:          22.0,
+			FromLocationID: &fromID,
+			ToLocationID:   &toID,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 22.0, result.Miles)
+		mockLocationRepo.AssertNotCalled(t, "FindDistance", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should require both locations to calculate miles", func(t *testing.T) {
+		tripService := NewTripService(new(MockTripRepository), new(MockTripClientService), newMockTripSettingsRepository(), nil, nil, nil, nil, time.UTC)
+
+		result, err := tripService.CreateTrip(context.Background(), domain.CreateTripRequest{
+			ClientName:     "Test Client",
+			TripDate:       "2025-01-15",
+			FromLocationID: &fromID,
+		})
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("should report unknown location as validation error", func(t *testing.T) {
+		mockLocationRepo := new(MockLocationRepository)
+		tripService := NewTripService(new(MockTripRepository), new(MockTripClientService), newMockTripSettingsRepository(), NewLocationService(mockLocationRepo, nil), nil, nil, nil, time.UTC)
+
+		mockLocationRepo.On("FindByID", mock.Anything, fromID).Return(nil, gorm.ErrRecordNotFound)
+
+		result, err := tripService.CreateTrip(context.Background(), domain.CreateTripRequest{
+			ClientName:     "Test Client",
+			TripDate:       "2025-01-15",
+			FromLocationID: &fromID,
+			ToLocationID:   &toID,
+		})
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrValidation)
+		assert.Contains(t, err.Error(), "location 1 not found")
+	})
+}
+
+func TestTripService_TripTimes(t *testing.T) {
+	eastern := time.FixedZone("EST", -5*60*60)
+	at := func(day, hour, minute int) *time.Time {
+		tm := time.Date(2025, 1, day, hour, minute, 0, 0, eastern)
+		return &tm
+	}
+
+	t.Run("should store start and end times", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		mockClientService := new(MockTripClientService)
+		tripService := NewTripService(mockTripRepo, mockClientService, newMockTripSettingsRepository(), nil, nil, nil, nil, eastern)
+
+		mockClientService.On("GetOrCreateClient", mock.Anything, "Test Client").Return(&domain.Client{ID: 1, Name: "Test Client"}, nil)
+		mockTripRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Trip")).Return(nil)
+
+		// 20:00 EST is already the next day in UTC; the business date is what counts
+		result, err := tripService.CreateTrip(context.Background(), domain.CreateTripRequest{
+			ClientName: "Test Client",
+			TripDate:   "2025-01-15",
+			Miles:      30,
+			StartTime:  at(15, 20, 0),
+			EndTime:    at(15, 21, 15),
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 75*time.Minute, result.Duration())
+		mockTripRepo.AssertExpectations(t)
+	})
+
+	t.Run("should store times in UTC whatever offset they were sent with", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		mockClientService := new(MockTripClientService)
+		tripService := NewTripService(mockTripRepo, mockClientService, newMockTripSettingsRepository(), nil, nil, nil, nil, eastern)
+
+		// Sent with different offsets, the times only sort as instants once
+		// both are in UTC
+		start := time.Date(2025, 1, 15, 9, 30, 0, 0, eastern)
+		end := time.Date(2025, 1, 15, 13, 0, 0, 0, time.FixedZone("", -2*60*60))
+		mockClientService.On("GetOrCreateClient", mock.Anything, "Test Client").Return(&domain.Client{ID: 1, Name: "Test Client"}, nil)
+		mockTripRepo.On("Create", mock.Anything, mock.MatchedBy(func(trip *domain.Trip) bool {
+			return trip.StartTime.Location() == time.UTC && trip.StartTime.Equal(start) &&
+				trip.EndTime.Location() == time.UTC && trip.EndTime.Equal(end)
+		})).Return(nil)
+
+		_, err := tripService.CreateTrip(context.Background(), domain.CreateTripRequest{
+			ClientName: "Test Client",
+			TripDate:   "2025-01-15",
+			Miles:      30,
+			StartTime:  &start,
+			EndTime:    &end,
+		})
+
+		assert.NoError(t, err)
+		mockTripRepo.AssertExpectations(t)
+	})
+
+	t.Run("should accept times read back in another zone", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		mockClientService := new(MockTripClientService)
+		tripService := NewTripService(mockTripRepo, mockClientService, newMockTripSettingsRepository(), nil, nil, nil, nil, eastern)
+
+		// Postgres returns the stored instant in its own zone, here UTC,
+		// where 20:00 EST on the 15th is already the 16th
+		start, end := at(15, 20, 0).UTC(), at(15, 21, 15).UTC()
+		existing := &domain.Trip{ID: 1, ClientName: "Test Client", TripDate: "2025-01-15", Miles: 30, StartTime: &start, EndTime: &end}
+		mockTripRepo.On("FindByID", mock.Anything, uint(1)).Return(existing, nil)
+		mockClientService.On("GetOrCreateClient", mock.Anything, "Test Client").Return(&domain.Client{ID: 1, Name: "Test Client"}, nil)
+		mockTripRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Trip")).Return(nil)
+
+		result, err := tripService.UpdateTrip(context.Background(), 1, domain.UpdateTripRequest{
+			ClientName: existing.ClientName,
+			TripDate:   existing.TripDate,
+			Miles:      existing.Miles,
+			StartTime:  existing.StartTime,
+			EndTime:    existing.EndTime,
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, result.StartTime.Equal(*at(15, 20, 0)))
+		mockTripRepo.AssertExpectations(t)
+	})
+
+	// 10:00 on the 15th in Tokyo is still the evening of the 14th in EST
+	tokyoMorning := new(time.Time)
+	*tokyoMorning = time.Date(2025, 1, 15, 10, 0, 0, 0, time.FixedZone("JST", 9*60*60))
+
+	tests := []struct {
+		name      string
+		tripDate  string
+		startTime *time.Time
+		endTime   *time.Time
+		message   string
+	}{
+		{"end before start", "2025-01-15", at(15, 9, 0), at(15, 8, 0), "end_time must be after start_time"},
+		{"end equal to start", "2025-01-15", at(15, 9, 0), at(15, 9, 0), "end_time must be after start_time"},
+		{"end without start", "2025-01-15", nil, at(15, 9, 0), "end_time requires start_time"},
+		{"start on another day", "2025-01-14", at(15, 9, 0), nil, "start_time must fall on trip_date"},
+		{"start on another business day", "2025-01-15", tokyoMorning, nil, "start_time must fall on trip_date"},
+	}
+
+	for _, tt := range tests {
+		t.Run("should reject "+tt.name, func(t *testing.T) {
+			mockTripRepo := new(MockTripRepository)
+			tripService := NewTripService(mockTripRepo, new(MockTripClientService), newMockTripSettingsRepository(), nil, nil, nil, nil, eastern)
+
+			_, err := tripService.CreateTrip(context.Background(), domain.CreateTripRequest{
+				ClientName: "Test Client",
+				TripDate:   tt.tripDate,
+				Miles:      30,
+				StartTime:  tt.startTime,
+				EndTime:    tt.endTime,
+			})
+
+			assert.ErrorIs(t, err, ErrValidation)
+			assert.Contains(t, err.Error(), tt.message)
+
+			_, err = tripService.UpdateTrip(context.Background(), 1, domain.UpdateTripRequest{
+				ClientName: "Test Client",
+				TripDate:   tt.tripDate,
+				Miles:      30,
+				StartTime:  tt.startTime,
+				EndTime:    tt.endTime,
+			})
+
+			assert.ErrorIs(t, err, ErrValidation)
+			mockTripRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			mockTripRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+		})
+	}
+}
+
 func TestTripService_DeleteTrip(t *testing.T) {
 
 	t.Run("should delete trip successfully", func(t *testing.T) {
 		// Setup - create fresh mocks for this test
 		mockTripRepo := new(MockTripRepository)
 		mockClientService := new(MockTripClientService)
-		mockSettingsRepo := new(MockTripSettingsRepository)
-		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo)
+		mockSettingsRepo := newMockTripSettingsRepository()
+		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo, nil, nil, nil, nil, time.UTC)
 
 		// Mock expectations
 		mockTripRepo.On("Delete", mock.Anything, uint(1)).Return(nil)
@@ -603,8 +930,8 @@ func TestTripService_DeleteTrip(t *testing.T) {
 		// Setup - create fresh mocks for this test
 		mockTripRepo := new(MockTripRepository)
 		mockClientService := new(MockTripClientService)
-		mockSettingsRepo := new(MockTripSettingsRepository)
-		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo)
+		mockSettingsRepo := newMockTripSettingsRepository()
+		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo, nil, nil, nil, nil, time.UTC)
 
 		deleteError := fmt.Errorf("database delete error")
 
@@ -625,8 +952,8 @@ func TestTripService_DeleteTrip(t *testing.T) {
 		// Setup - create fresh mocks for this test
 		mockTripRepo := new(MockTripRepository)
 		mockClientService := new(MockTripClientService)
-		mockSettingsRepo := new(MockTripSettingsRepository)
-		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo)
+		mockSettingsRepo := newMockTripSettingsRepository()
+		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo, nil, nil, nil, nil, time.UTC)
 
 		// Mock expectations
 		mockTripRepo.On("Delete", mock.Anything, uint(999)).Return(gorm.ErrRecordNotFound)
@@ -648,8 +975,8 @@ func TestTripService_GetTripByID(t *testing.T) {
 		// Setup - create fresh mocks for this test
 		mockTripRepo := new(MockTripRepository)
 		mockClientService := new(MockTripClientService)
-		mockSettingsRepo := new(MockTripSettingsRepository)
-		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo)
+		mockSettingsRepo := newMockTripSettingsRepository()
+		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo, nil, nil, nil, nil, time.UTC)
 		// Setup
 		expectedTrip := &domain.Trip{
 			ID:         1,
@@ -681,8 +1008,8 @@ func TestTripService_GetTripByID(t *testing.T) {
 		// Setup - create fresh mocks for this test
 		mockTripRepo := new(MockTripRepository)
 		mockClientService := new(MockTripClientService)
-		mockSettingsRepo := new(MockTripSettingsRepository)
-		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo)
+		mockSettingsRepo := newMockTripSettingsRepository()
+		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo, nil, nil, nil, nil, time.UTC)
 
 		// Mock expectations
 		mockTripRepo.On("FindByID", mock.Anything, uint(999)).Return(nil, gorm.ErrRecordNotFound)
@@ -702,8 +1029,8 @@ func TestTripService_GetTripByID(t *testing.T) {
 		// Setup - create fresh mocks for this test
 		mockTripRepo := new(MockTripRepository)
 		mockClientService := new(MockTripClientService)
-		mockSettingsRepo := new(MockTripSettingsRepository)
-		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo)
+		mockSettingsRepo := newMockTripSettingsRepository()
+		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo, nil, nil, nil, nil, time.UTC)
 
 		dbError := fmt.Errorf("database connection error")
 
@@ -728,8 +1055,8 @@ func TestTripService_GetTrips(t *testing.T) {
 		// Setup - create fresh mocks for this test
 		mockTripRepo := new(MockTripRepository)
 		mockClientService := new(MockTripClientService)
-		mockSettingsRepo := new(MockTripSettingsRepository)
-		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo)
+		mockSettingsRepo := newMockTripSettingsRepository()
+		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo, nil, nil, nil, nil, time.UTC)
 
 		expectedTrips := []domain.Trip{
 			{
@@ -789,8 +1116,8 @@ func TestTripService_GetTrips(t *testing.T) {
 				// Setup - create fresh mocks for each sub-test
 				mockTripRepo := new(MockTripRepository)
 				mockClientService := new(MockTripClientService)
-				mockSettingsRepo := new(MockTripSettingsRepository)
-				tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo)
+				mockSettingsRepo := newMockTripSettingsRepository()
+				tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo, nil, nil, nil, nil, time.UTC)
 
 				expectedTrips := []domain.Trip{}
 				expectedTotal := int64(0)
@@ -815,8 +1142,8 @@ func TestTripService_GetTrips(t *testing.T) {
 		// Setup - create fresh mocks for this test
 		mockTripRepo := new(MockTripRepository)
 		mockClientService := new(MockTripClientService)
-		mockSettingsRepo := new(MockTripSettingsRepository)
-		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo)
+		mockSettingsRepo := newMockTripSettingsRepository()
+		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo, nil, nil, nil, nil, time.UTC)
 
 		dbError := fmt.Errorf("database connection error")
 
@@ -839,8 +1166,8 @@ func TestTripService_GetTrips(t *testing.T) {
 		// Setup - create fresh mocks for this test
 		mockTripRepo := new(MockTripRepository)
 		mockClientService := new(MockTripClientService)
-		mockSettingsRepo := new(MockTripSettingsRepository)
-		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo)
+		mockSettingsRepo := newMockTripSettingsRepository()
+		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo, nil, nil, nil, nil, time.UTC)
 
 		emptyTrips := []domain.Trip{}
 		expectedTotal := int64(0)
@@ -860,27 +1187,97 @@ func TestTripService_GetTrips(t *testing.T) {
 	})
 }
 
+func TestTripService_GetTripPage(t *testing.T) {
+	created := time.Date(2025, 1, 20, 9, 0, 0, 0, time.UTC)
+	trips := []domain.Trip{
+		{ID: 3, ClientName: "Client 3", TripDate: "2025-01-17", CreatedAt: created},
+		{ID: 2, ClientName: "Client 2", TripDate: "2025-01-16", CreatedAt: created},
+		{ID: 1, ClientName: "Client 1", TripDate: "2025-01-15", CreatedAt: created},
+	}
+
+	t.Run("should return a cursor when more trips follow", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		tripService := NewTripService(mockTripRepo, new(MockTripClientService), newMockTripSettingsRepository(), nil, nil, nil, nil, time.UTC)
+
+		mockTripRepo.On("GetPageAfter", mock.Anything, (*domain.TripCursor)(nil), 3, domain.TripFilters{}).Return(trips, nil)
+
+		page, err := tripService.GetTripPage(context.Background(), "", 2, domain.TripFilters{})
+
+		assert.NoError(t, err)
+		assert.Len(t, page.Trips, 2)
+		assert.True(t, page.HasMore)
+
+		cursor, err := domain.DecodeTripCursor(page.NextCursor, domain.DefaultTripSort)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.CursorAfter(trips[1], domain.DefaultTripSort), cursor)
+		mockTripRepo.AssertExpectations(t)
+	})
+
+	t.Run("should continue after the cursor", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		tripService := NewTripService(mockTripRepo, new(MockTripClientService), newMockTripSettingsRepository(), nil, nil, nil, nil, time.UTC)
+
+		after := domain.CursorAfter(trips[1], domain.DefaultTripSort)
+		mockTripRepo.On("GetPageAfter", mock.Anything, &after, 3, domain.TripFilters{}).Return(trips[2:], nil)
+
+		page, err := tripService.GetTripPage(context.Background(), after.Encode(), 2, domain.TripFilters{})
+
+		assert.NoError(t, err)
+		assert.Len(t, page.Trips, 1)
+		assert.False(t, page.HasMore)
+		assert.Empty(t, page.NextCursor)
+		mockTripRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject a cursor from another sort", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		tripService := NewTripService(mockTripRepo, new(MockTripClientService), newMockTripSettingsRepository(), nil, nil, nil, nil, time.UTC)
+
+		cursor := domain.CursorAfter(trips[1], domain.DefaultTripSort).Encode()
+		filters := domain.TripFilters{Sort: []domain.SortKey{{Field: domain.SortByMiles}}}
+		page, err := tripService.GetTripPage(context.Background(), cursor, 2, filters)
+
+		assert.Nil(t, page)
+		assert.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("should reject a malformed cursor", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		tripService := NewTripService(mockTripRepo, new(MockTripClientService), newMockTripSettingsRepository(), nil, nil, nil, nil, time.UTC)
+
+		page, err := tripService.GetTripPage(context.Background(), "not-a-cursor", 2, domain.TripFilters{})
+
+		assert.Nil(t, page)
+		assert.ErrorIs(t, err, ErrValidation)
+		mockTripRepo.AssertNotCalled(t, "GetPageAfter")
+	})
+}
+
+// summaryTestNow pins the clock so the six-month summary window is predictable
+var summaryTestNow = time.Date(2025, 9, 15, 12, 0, 0, 0, time.UTC)
+
+func newSummaryTestService(tripRepo *MockTripRepository, clientService *MockTripClientService, settingsRepo *MockTripSettingsRepository) TripService {
+	svc := NewTripService(tripRepo, clientService, settingsRepo, nil, nil, nil, nil, time.UTC)
+	svc.(*tripService).now = func() time.Time { return summaryTestNow }
+	return svc
+}
+
 func TestTripService_GetSummary(t *testing.T) {
 
 	t.Run("should return summary with calculated amounts", func(t *testing.T) {
 		// Setup - create fresh mocks for this test
 		mockTripRepo := new(MockTripRepository)
 		mockClientService := new(MockTripClientService)
-		mockSettingsRepo := new(MockTripSettingsRepository)
-		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo)
+		mockSettingsRepo := newMockTripSettingsRepository()
+		tripService := newSummaryTestService(mockTripRepo, mockClientService, mockSettingsRepo)
 
-		now := time.Now()
-		mockSummaries := []domain.MonthlySummary{
+		mockSummaries := []domain.SummaryBucket{
 			{
-				Month:      "September 2025",
-				Year:       2025,
-				MonthNum:   9,
+				StartDate:  "2025-09-01",
 				TotalMiles: 100.0,
 			},
 			{
-				Month:      "August 2025",
-				Year:       2025,
-				MonthNum:   8,
+				StartDate:  "2025-08-01",
 				TotalMiles: 50.0,
 			},
 		}
@@ -891,13 +1288,13 @@ func TestTripService_GetSummary(t *testing.T) {
 		}
 
 		// Mock expectations
-		startDate := now.AddDate(0, -5, 0).Format("2006-01-01")
-		endDate := time.Date(now.Year(), now.Month()+1, 0, 23, 59, 59, 999999999, now.Location()).Format("2006-01-02")
-		mockTripRepo.On("GetMonthlySummary", mock.Anything, startDate, endDate).Return(mockSummaries, nil)
-		mockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate").Return(settings, nil)
+		mockTripRepo.On("GetExpenseTotals", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.ExpenseTotalRow{}, nil).Maybe()
+		mockTripRepo.On("GetSummaryBuckets", mock.Anything, domain.GranularityMonth, "2025-04-01", "2025-09-30", domain.TripFilters{}, false).Return(mockSummaries, nil)
+		stubSetting(mockSettingsRepo, "mileage_rate").Return(settings, nil)
+		mockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate_currency").Return(&domain.Settings{Key: "mileage_rate_currency", Value: "USD"}, nil).Maybe()
 
 		// Execute
-		result, err := tripService.GetSummary(context.Background())
+		result, err := tripService.GetSummary(context.Background(), domain.SummaryQuery{})
 
 		// Assert
 		assert.NoError(t, err)
@@ -909,7 +1306,7 @@ func TestTripService_GetSummary(t *testing.T) {
 		for _, month := range result.Months {
 			if month.Month == "September 2025" {
 				assert.Equal(t, 100.0, month.TotalMiles)
-				assert.Equal(t, 67.0, month.Amount) // 100 * 0.67
+				assert.Equal(t, domain.MustMoney("67"), month.Amount) // 100 * 0.67
 				foundSep = true
 			}
 		}
@@ -923,27 +1320,24 @@ func TestTripService_GetSummary(t *testing.T) {
 		// Setup - create fresh mocks for this test
 		mockTripRepo := new(MockTripRepository)
 		mockClientService := new(MockTripClientService)
-		mockSettingsRepo := new(MockTripSettingsRepository)
-		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo)
+		mockSettingsRepo := newMockTripSettingsRepository()
+		tripService := newSummaryTestService(mockTripRepo, mockClientService, mockSettingsRepo)
 
-		now := time.Now()
-		mockSummaries := []domain.MonthlySummary{
+		mockSummaries := []domain.SummaryBucket{
 			{
-				Month:      "September 2025",
-				Year:       2025,
-				MonthNum:   9,
+				StartDate:  "2025-09-01",
 				TotalMiles: 100.0,
 			},
 		}
 
 		// Mock expectations
-		startDate := now.AddDate(0, -5, 0).Format("2006-01-01")
-		endDate := time.Date(now.Year(), now.Month()+1, 0, 23, 59, 59, 999999999, now.Location()).Format("2006-01-02")
-		mockTripRepo.On("GetMonthlySummary", mock.Anything, startDate, endDate).Return(mockSummaries, nil)
-		mockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate").Return(nil, gorm.ErrRecordNotFound)
+		mockTripRepo.On("GetExpenseTotals", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.ExpenseTotalRow{}, nil).Maybe()
+		mockTripRepo.On("GetSummaryBuckets", mock.Anything, domain.GranularityMonth, "2025-04-01", "2025-09-30", domain.TripFilters{}, false).Return(mockSummaries, nil)
+		stubSetting(mockSettingsRepo, "mileage_rate").Return(nil, gorm.ErrRecordNotFound)
+		mockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate_currency").Return(&domain.Settings{Key: "mileage_rate_currency", Value: "USD"}, nil).Maybe()
 
 		// Execute
-		result, err := tripService.GetSummary(context.Background())
+		result, err := tripService.GetSummary(context.Background(), domain.SummaryQuery{})
 
 		// Assert
 		assert.NoError(t, err)
@@ -954,7 +1348,7 @@ func TestTripService_GetSummary(t *testing.T) {
 		for _, month := range result.Months {
 			if month.Month == "September 2025" {
 				assert.Equal(t, 100.0, month.TotalMiles)
-				assert.Equal(t, 67.0, month.Amount) // 100 * 0.67 (default)
+				assert.Equal(t, domain.MustMoney("67"), month.Amount) // 100 * 0.67 (default)
 				foundSep = true
 			}
 		}
@@ -968,19 +1362,18 @@ func TestTripService_GetSummary(t *testing.T) {
 		// Setup - create fresh mocks for this test
 		mockTripRepo := new(MockTripRepository)
 		mockClientService := new(MockTripClientService)
-		mockSettingsRepo := new(MockTripSettingsRepository)
-		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo)
+		mockSettingsRepo := newMockTripSettingsRepository()
+		tripService := newSummaryTestService(mockTripRepo, mockClientService, mockSettingsRepo)
 
-		now := time.Now()
 		dbError := fmt.Errorf("database connection error")
 
 		// Mock expectations
-		startDate := now.AddDate(0, -5, 0).Format("2006-01-01")
-		endDate := time.Date(now.Year(), now.Month()+1, 0, 23, 59, 59, 999999999, now.Location()).Format("2006-01-02")
-		mockTripRepo.On("GetMonthlySummary", mock.Anything, startDate, endDate).Return(nil, dbError)
+		mockTripRepo.On("GetExpenseTotals", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.ExpenseTotalRow{}, nil).Maybe()
+		mockTripRepo.On("GetSummaryBuckets", mock.Anything, domain.GranularityMonth, "2025-04-01", "2025-09-30", domain.TripFilters{}, false).Return(nil, dbError)
+		mockSettingsRepo.On("GetByKey", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
 
 		// Execute
-		result, err := tripService.GetSummary(context.Background())
+		result, err := tripService.GetSummary(context.Background(), domain.SummaryQuery{})
 
 		// Assert
 		assert.Error(t, err)
@@ -994,11 +1387,10 @@ func TestTripService_GetSummary(t *testing.T) {
 		// Setup - create fresh mocks for this test
 		mockTripRepo := new(MockTripRepository)
 		mockClientService := new(MockTripClientService)
-		mockSettingsRepo := new(MockTripSettingsRepository)
-		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo)
+		mockSettingsRepo := newMockTripSettingsRepository()
+		tripService := newSummaryTestService(mockTripRepo, mockClientService, mockSettingsRepo)
 
-		now := time.Now()
-		emptySummaries := []domain.MonthlySummary{}
+		emptySummaries := []domain.SummaryBucket{}
 
 		settings := &domain.Settings{
 			Key:   "mileage_rate",
@@ -1006,13 +1398,13 @@ func TestTripService_GetSummary(t *testing.T) {
 		}
 
 		// Mock expectations
-		startDate := now.AddDate(0, -5, 0).Format("2006-01-01")
-		endDate := time.Date(now.Year(), now.Month()+1, 0, 23, 59, 59, 999999999, now.Location()).Format("2006-01-02")
-		mockTripRepo.On("GetMonthlySummary", mock.Anything, startDate, endDate).Return(emptySummaries, nil)
-		mockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate").Return(settings, nil)
+		mockTripRepo.On("GetExpenseTotals", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.ExpenseTotalRow{}, nil).Maybe()
+		mockTripRepo.On("GetSummaryBuckets", mock.Anything, domain.GranularityMonth, "2025-04-01", "2025-09-30", domain.TripFilters{}, false).Return(emptySummaries, nil)
+		stubSetting(mockSettingsRepo, "mileage_rate").Return(settings, nil)
+		mockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate_currency").Return(&domain.Settings{Key: "mileage_rate_currency", Value: "USD"}, nil).Maybe()
 
 		// Execute
-		result, err := tripService.GetSummary(context.Background())
+		result, err := tripService.GetSummary(context.Background(), domain.SummaryQuery{})
 
 		// Assert
 		assert.NoError(t, err)
@@ -1022,26 +1414,23 @@ func TestTripService_GetSummary(t *testing.T) {
 		// All months should have zero values
 		for _, month := range result.Months {
 			assert.Equal(t, 0.0, month.TotalMiles)
-			assert.Equal(t, 0.0, month.Amount)
+			assert.Equal(t, domain.MustMoney("0"), month.Amount)
 		}
 
 		mockTripRepo.AssertExpectations(t)
 		mockSettingsRepo.AssertExpectations(t)
 	})
 
-	t.Run("should use default rate when settings value is invalid", func(t *testing.T) {
+	t.Run("should fail when the stored rate is invalid", func(t *testing.T) {
 		// Setup - create fresh mocks for this test
 		mockTripRepo := new(MockTripRepository)
 		mockClientService := new(MockTripClientService)
-		mockSettingsRepo := new(MockTripSettingsRepository)
-		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo)
+		mockSettingsRepo := newMockTripSettingsRepository()
+		tripService := newSummaryTestService(mockTripRepo, mockClientService, mockSettingsRepo)
 
-		now := time.Now()
-		mockSummaries := []domain.MonthlySummary{
+		mockSummaries := []domain.SummaryBucket{
 			{
-				Month:      "September 2025",
-				Year:       2025,
-				MonthNum:   9,
+				StartDate:  "2025-09-01",
 				TotalMiles: 100.0,
 			},
 		}
@@ -1052,28 +1441,18 @@ func TestTripService_GetSummary(t *testing.T) {
 		}
 
 		// Mock expectations
-		startDate := now.AddDate(0, -5, 0).Format("2006-01-01")
-		endDate := time.Date(now.Year(), now.Month()+1, 0, 23, 59, 59, 999999999, now.Location()).Format("2006-01-02")
-		mockTripRepo.On("GetMonthlySummary", mock.Anything, startDate, endDate).Return(mockSummaries, nil)
-		mockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate").Return(invalidSettings, nil)
+		mockTripRepo.On("GetExpenseTotals", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.ExpenseTotalRow{}, nil).Maybe()
+		mockTripRepo.On("GetSummaryBuckets", mock.Anything, domain.GranularityMonth, "2025-04-01", "2025-09-30", domain.TripFilters{}, false).Return(mockSummaries, nil).Maybe()
+		stubSetting(mockSettingsRepo, "mileage_rate").Return(invalidSettings, nil)
+		mockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate_currency").Return(&domain.Settings{Key: "mileage_rate_currency", Value: "USD"}, nil).Maybe()
 
 		// Execute
-		result, err := tripService.GetSummary(context.Background())
+		result, err := tripService.GetSummary(context.Background(), domain.SummaryQuery{})
 
-		// Assert
-		assert.NoError(t, err)
-		assert.NotNil(t, result)
-
-		// Should use default rate of 0.67 due to invalid value
-		foundSep := false
-		for _, month := range result.Months {
-			if month.Month == "September 2025" {
-				assert.Equal(t, 100.0, month.TotalMiles)
-				assert.Equal(t, 67.0, month.Amount) // 100 * 0.67 (default)
-				foundSep = true
-			}
-		}
-		assert.True(t, foundSep, "September 2025 should be found in results")
+		// Assert - the default rate is not silently used instead
+		assert.EqualError(t, err, `setting mileage_rate holds an invalid value "not-a-number"`)
+		assert.NotErrorIs(t, err, ErrValidation)
+		assert.Nil(t, result)
 
 		mockTripRepo.AssertExpectations(t)
 		mockSettingsRepo.AssertExpectations(t)
@@ -1083,15 +1462,12 @@ func TestTripService_GetSummary(t *testing.T) {
 		// Setup - create fresh mocks for this test
 		mockTripRepo := new(MockTripRepository)
 		mockClientService := new(MockTripClientService)
-		mockSettingsRepo := new(MockTripSettingsRepository)
-		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo)
+		mockSettingsRepo := newMockTripSettingsRepository()
+		tripService := newSummaryTestService(mockTripRepo, mockClientService, mockSettingsRepo)
 
-		now := time.Now()
-		mockSummaries := []domain.MonthlySummary{
+		mockSummaries := []domain.SummaryBucket{
 			{
-				Month:      "September 2025",
-				Year:       2025,
-				MonthNum:   9,
+				StartDate:  "2025-09-01",
 				TotalMiles: 100.0,
 			},
 		}
@@ -1102,13 +1478,13 @@ func TestTripService_GetSummary(t *testing.T) {
 		}
 
 		// Mock expectations
-		startDate := now.AddDate(0, -5, 0).Format("2006-01-01")
-		endDate := time.Date(now.Year(), now.Month()+1, 0, 23, 59, 59, 999999999, now.Location()).Format("2006-01-02")
-		mockTripRepo.On("GetMonthlySummary", mock.Anything, startDate, endDate).Return(mockSummaries, nil)
-		mockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate").Return(zeroRateSettings, nil)
+		mockTripRepo.On("GetExpenseTotals", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.ExpenseTotalRow{}, nil).Maybe()
+		mockTripRepo.On("GetSummaryBuckets", mock.Anything, domain.GranularityMonth, "2025-04-01", "2025-09-30", domain.TripFilters{}, false).Return(mockSummaries, nil)
+		stubSetting(mockSettingsRepo, "mileage_rate").Return(zeroRateSettings, nil)
+		mockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate_currency").Return(&domain.Settings{Key: "mileage_rate_currency", Value: "USD"}, nil).Maybe()
 
 		// Execute
-		result, err := tripService.GetSummary(context.Background())
+		result, err := tripService.GetSummary(context.Background(), domain.SummaryQuery{})
 
 		// Assert
 		assert.NoError(t, err)
@@ -1119,7 +1495,7 @@ func TestTripService_GetSummary(t *testing.T) {
 		for _, month := range result.Months {
 			if month.Month == "September 2025" {
 				assert.Equal(t, 100.0, month.TotalMiles)
-				assert.Equal(t, 0.0, month.Amount) // 100 * 0 = 0
+				assert.Equal(t, domain.MustMoney("0"), month.Amount) // 100 * 0 = 0
 				foundSep = true
 			}
 		}
@@ -1133,15 +1509,12 @@ func TestTripService_GetSummary(t *testing.T) {
 		// Setup - create fresh mocks for this test
 		mockTripRepo := new(MockTripRepository)
 		mockClientService := new(MockTripClientService)
-		mockSettingsRepo := new(MockTripSettingsRepository)
-		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo)
+		mockSettingsRepo := newMockTripSettingsRepository()
+		tripService := newSummaryTestService(mockTripRepo, mockClientService, mockSettingsRepo)
 
-		now := time.Now()
-		mockSummaries := []domain.MonthlySummary{
+		mockSummaries := []domain.SummaryBucket{
 			{
-				Month:      "September 2025",
-				Year:       2025,
-				MonthNum:   9,
+				StartDate:  "2025-09-01",
 				TotalMiles: 100.0,
 			},
 		}
@@ -1152,13 +1525,13 @@ func TestTripService_GetSummary(t *testing.T) {
 		}
 
 		// Mock expectations
-		startDate := now.AddDate(0, -5, 0).Format("2006-01-01")
-		endDate := time.Date(now.Year(), now.Month()+1, 0, 23, 59, 59, 999999999, now.Location()).Format("2006-01-02")
-		mockTripRepo.On("GetMonthlySummary", mock.Anything, startDate, endDate).Return(mockSummaries, nil)
-		mockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate").Return(highRateSettings, nil)
+		mockTripRepo.On("GetExpenseTotals", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.ExpenseTotalRow{}, nil).Maybe()
+		mockTripRepo.On("GetSummaryBuckets", mock.Anything, domain.GranularityMonth, "2025-04-01", "2025-09-30", domain.TripFilters{}, false).Return(mockSummaries, nil)
+		stubSetting(mockSettingsRepo, "mileage_rate").Return(highRateSettings, nil)
+		mockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate_currency").Return(&domain.Settings{Key: "mileage_rate_currency", Value: "USD"}, nil).Maybe()
 
 		// Execute
-		result, err := tripService.GetSummary(context.Background())
+		result, err := tripService.GetSummary(context.Background(), domain.SummaryQuery{})
 
 		// Assert
 		assert.NoError(t, err)
@@ -1169,7 +1542,7 @@ func TestTripService_GetSummary(t *testing.T) {
 		for _, month := range result.Months {
 			if month.Month == "September 2025" {
 				assert.Equal(t, 100.0, month.TotalMiles)
-				assert.Equal(t, 99999.0, month.Amount) // 100 * 999.99 = 99999
+				assert.Equal(t, domain.MustMoney("99999"), month.Amount) // 100 * 999.99 = 99999
 				foundSep = true
 			}
 		}
@@ -1179,19 +1552,16 @@ func TestTripService_GetSummary(t *testing.T) {
 		mockSettingsRepo.AssertExpectations(t)
 	})
 
-	t.Run("should return error when getMileageRate fails with non-record-not-found error", func(t *testing.T) {
+	t.Run("should return error when the mileage rate cannot be read", func(t *testing.T) {
 		// Setup - create fresh mocks for this test
 		mockTripRepo := new(MockTripRepository)
 		mockClientService := new(MockTripClientService)
-		mockSettingsRepo := new(MockTripSettingsRepository)
-		tripService := NewTripService(mockTripRepo, mockClientService, mockSettingsRepo)
+		mockSettingsRepo := newMockTripSettingsRepository()
+		tripService := newSummaryTestService(mockTripRepo, mockClientService, mockSettingsRepo)
 
-		now := time.Now()
-		mockSummaries := []domain.MonthlySummary{
+		mockSummaries := []domain.SummaryBucket{
 			{
-				Month:      "September 2025",
-				Year:       2025,
-				MonthNum:   9,
+				StartDate:  "2025-09-01",
 				TotalMiles: 100.0,
 			},
 		}
@@ -1199,19 +1569,720 @@ func TestTripService_GetSummary(t *testing.T) {
 		settingsError := fmt.Errorf("settings database error")
 
 		// Mock expectations
-		startDate := now.AddDate(0, -5, 0).Format("2006-01-01")
-		endDate := time.Date(now.Year(), now.Month()+1, 0, 23, 59, 59, 999999999, now.Location()).Format("2006-01-02")
-		mockTripRepo.On("GetMonthlySummary", mock.Anything, startDate, endDate).Return(mockSummaries, nil)
-		mockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate").Return(nil, settingsError)
+		mockTripRepo.On("GetExpenseTotals", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.ExpenseTotalRow{}, nil).Maybe()
+		mockTripRepo.On("GetSummaryBuckets", mock.Anything, domain.GranularityMonth, "2025-04-01", "2025-09-30", domain.TripFilters{}, false).Return(mockSummaries, nil).Maybe()
+		stubSetting(mockSettingsRepo, "mileage_rate").Return(nil, settingsError)
+		mockSettingsRepo.On("GetByKey", mock.Anything, "mileage_rate_currency").Return(&domain.Settings{Key: "mileage_rate_currency", Value: "USD"}, nil).Maybe()
 
 		// Execute
-		result, err := tripService.GetSummary(context.Background())
+		result, err := tripService.GetSummary(context.Background(), domain.SummaryQuery{})
 
-		// Assert - getMileageRate handles all errors gracefully by returning default rate
-		assert.NoError(t, err)
-		assert.NotNil(t, result)
+		// Assert - only a rate that is not set falls back to the default
+		assert.ErrorIs(t, err, settingsError)
+		assert.Nil(t, result)
 
 		mockTripRepo.AssertExpectations(t)
 		mockSettingsRepo.AssertExpectations(t)
 	})
 }
+
+func TestTripService_GetSummaryTimezone(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+
+	newService := func(tripRepo *MockTripRepository, location *time.Location, now time.Time) TripService {
+		settingsRepo := newMockTripSettingsRepository()
+		stubSetting(settingsRepo, "mileage_rate").Return(&domain.Settings{Key: "mileage_rate", Value: "0.67"}, nil)
+		settingsRepo.On("GetByKey", mock.Anything, "mileage_rate_currency").Return(&domain.Settings{Key: "mileage_rate_currency", Value: "USD"}, nil).Maybe()
+		svc := NewTripService(tripRepo, new(MockTripClientService), settingsRepo, nil, nil, nil, nil, location)
+		svc.(*tripService).now = func() time.Time { return now }
+		return svc
+	}
+
+	t.Run("should compute the window in the business timezone", func(t *testing.T) {
+		// 03:00 UTC on October 1st is still September 30th in New York
+		mockTripRepo := new(MockTripRepository)
+		mockTripRepo.On("GetExpenseTotals", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.ExpenseTotalRow{}, nil).Maybe()
+		mockTripRepo.On("GetSummaryBuckets", mock.Anything, domain.GranularityMonth, "2025-04-01", "2025-09-30", domain.TripFilters{}, false).Return([]domain.SummaryBucket{}, nil)
+
+		result, err := newService(mockTripRepo, newYork, time.Date(2025, 10, 1, 3, 0, 0, 0, time.UTC)).GetSummary(context.Background(), domain.SummaryQuery{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "America/New_York", result.Timezone)
+		assert.Equal(t, "2025-09-30", result.AsOf)
+		assert.Equal(t, "September 2025", result.Months[0].Month)
+		mockTripRepo.AssertExpectations(t)
+	})
+
+	t.Run("should not skip short months on the 31st", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		mockTripRepo.On("GetExpenseTotals", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.ExpenseTotalRow{}, nil).Maybe()
+		mockTripRepo.On("GetSummaryBuckets", mock.Anything, domain.GranularityMonth, "2025-03-01", "2025-08-31", domain.TripFilters{}, false).Return([]domain.SummaryBucket{}, nil)
+
+		result, err := newService(mockTripRepo, time.UTC, time.Date(2025, 8, 31, 12, 0, 0, 0, time.UTC)).GetSummary(context.Background(), domain.SummaryQuery{})
+
+		assert.NoError(t, err)
+		months := make([]string, len(result.Months))
+		for i, month := range result.Months {
+			months[i] = month.Month
+		}
+		assert.Equal(t, []string{"August 2025", "July 2025", "June 2025", "May 2025", "April 2025", "March 2025"}, months)
+		mockTripRepo.AssertExpectations(t)
+	})
+}
+
+func TestTripService_GetSummaryRanges(t *testing.T) {
+	newService := func(tripRepo *MockTripRepository) TripService {
+		settingsRepo := newMockTripSettingsRepository()
+		stubSetting(settingsRepo, "mileage_rate").Return(&domain.Settings{Key: "mileage_rate", Value: "0.5"}, nil)
+		settingsRepo.On("GetByKey", mock.Anything, "mileage_rate_currency").Return(&domain.Settings{Key: "mileage_rate_currency", Value: "USD"}, nil).Maybe()
+		return newSummaryTestService(tripRepo, new(MockTripClientService), settingsRepo)
+	}
+
+	t.Run("should fill gaps between weekly buckets", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		filters := domain.TripFilters{Client: "Acme Corp"}
+		mockTripRepo.On("GetExpenseTotals", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.ExpenseTotalRow{}, nil).Maybe()
+		mockTripRepo.On("GetSummaryBuckets", mock.Anything, domain.GranularityWeek, "2025-09-03", "2025-09-24", filters, false).
+			Return([]domain.SummaryBucket{
+				{StartDate: "2025-09-01", TripCount: 2, TotalMiles: 40},
+				{StartDate: "2025-09-22", TripCount: 1, TotalMiles: 10, TotalMinutes: 30},
+			}, nil)
+
+		result, err := newService(mockTripRepo).GetSummary(context.Background(), domain.SummaryQuery{
+			From:        "2025-09-03",
+			To:          "2025-09-24",
+			Granularity: domain.GranularityWeek,
+			Filters:     filters,
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Buckets, 4)
+		assert.Equal(t, domain.SummaryBucket{
+			Period: "2025-W36", Label: "Week of Sep 1, 2025", StartDate: "2025-09-01", EndDate: "2025-09-07",
+			TripCount: 2, TotalMiles: 40, Amount: domain.MustMoney("20"), TotalDistance: 40, TotalAmount: domain.MustMoney("20"),
+		}, result.Buckets[0])
+		assert.Equal(t, "2025-09-08", result.Buckets[1].StartDate)
+		assert.Equal(t, 0.0, result.Buckets[1].TotalMiles)
+		assert.Equal(t, domain.SummaryTotals{TripCount: 3, TotalMiles: 50, TotalMinutes: 30, Amount: domain.MustMoney("25"), TotalDistance: 50, TotalAmount: domain.MustMoney("25")}, result.Totals)
+		assert.Empty(t, result.Months)
+		mockTripRepo.AssertExpectations(t)
+	})
+
+	t.Run("should label quarters and years", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		mockTripRepo.On("GetExpenseTotals", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.ExpenseTotalRow{}, nil).Maybe()
+		mockTripRepo.On("GetSummaryBuckets", mock.Anything, domain.GranularityQuarter, "2024-11-15", "2025-05-01", domain.TripFilters{}, false).
+			Return([]domain.SummaryBucket{}, nil)
+		mockTripRepo.On("GetSummaryBuckets", mock.Anything, domain.GranularityYear, "2023-06-01", "2025-01-01", domain.TripFilters{}, false).
+			Return([]domain.SummaryBucket{}, nil)
+
+		quarters, err := newService(mockTripRepo).GetSummary(context.Background(), domain.SummaryQuery{
+			From: "2024-11-15", To: "2025-05-01", Granularity: domain.GranularityQuarter,
+		})
+		assert.NoError(t, err)
+		periods := []string{}
+		for _, bucket := range quarters.Buckets {
+			periods = append(periods, bucket.Period+" "+bucket.Label+" "+bucket.StartDate+".."+bucket.EndDate)
+		}
+		assert.Equal(t, []string{
+			"2024-Q4 Q4 2024 2024-10-01..2024-12-31",
+			"2025-Q1 Q1 2025 2025-01-01..2025-03-31",
+			"2025-Q2 Q2 2025 2025-04-01..2025-06-30",
+		}, periods)
+
+		years, err := newService(mockTripRepo).GetSummary(context.Background(), domain.SummaryQuery{
+			From: "2023-06-01", To: "2025-01-01", Granularity: domain.GranularityYear,
+		})
+		assert.NoError(t, err)
+		assert.Len(t, years.Buckets, 3)
+		assert.Equal(t, "2023", years.Buckets[0].Label)
+	})
+
+	t.Run("should default to the six most recent buckets", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		// summaryTestNow is Monday 2025-09-15
+		mockTripRepo.On("GetExpenseTotals", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.ExpenseTotalRow{}, nil).Maybe()
+		mockTripRepo.On("GetSummaryBuckets", mock.Anything, domain.GranularityDay, "2025-09-10", "2025-09-15", domain.TripFilters{}, false).
+			Return([]domain.SummaryBucket{}, nil)
+
+		result, err := newService(mockTripRepo).GetSummary(context.Background(), domain.SummaryQuery{Granularity: domain.GranularityDay})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Buckets, 6)
+		assert.Equal(t, "2025-09-10", result.From)
+		assert.Equal(t, "2025-09-15", result.To)
+		mockTripRepo.AssertExpectations(t)
+	})
+
+	tests := []struct {
+		name  string
+		query domain.SummaryQuery
+	}{
+		{"unknown granularity", domain.SummaryQuery{Granularity: "fortnight"}},
+		{"malformed from", domain.SummaryQuery{From: "01/02/2025"}},
+		{"malformed to", domain.SummaryQuery{To: "2025-13-01"}},
+		{"from after to", domain.SummaryQuery{From: "2025-02-01", To: "2025-01-01"}},
+		{"too many buckets", domain.SummaryQuery{From: "2020-01-01", To: "2025-01-01", Granularity: domain.GranularityDay}},
+	}
+
+	for _, tt := range tests {
+		t.Run("should reject "+tt.name, func(t *testing.T) {
+			mockTripRepo := new(MockTripRepository)
+
+			_, err := newService(mockTripRepo).GetSummary(context.Background(), tt.query)
+
+			assert.ErrorIs(t, err, ErrValidation)
+			mockTripRepo.AssertNotCalled(t, "GetSummaryBuckets", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestTripService_GetGroupedSummary(t *testing.T) {
+	newService := func(tripRepo *MockTripRepository) TripService {
+		settingsRepo := newMockTripSettingsRepository()
+		stubSetting(settingsRepo, "mileage_rate").Return(&domain.Settings{Key: "mileage_rate", Value: "0.5"}, nil)
+		settingsRepo.On("GetByKey", mock.Anything, "mileage_rate_currency").Return(&domain.Settings{Key: "mileage_rate_currency", Value: "USD"}, nil).Maybe()
+		return newSummaryTestService(tripRepo, new(MockTripClientService), settingsRepo)
+	}
+
+	query := domain.SummaryQuery{From: "2025-07-01", To: "2025-09-30", GroupBy: domain.SummaryByClient, Top: 2}
+
+	expectRepo := func(rows []domain.SummaryGroupRow) *MockTripRepository {
+		mockTripRepo := new(MockTripRepository)
+		mockTripRepo.On("GetExpenseTotals", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.ExpenseTotalRow{}, nil).Maybe()
+		mockTripRepo.On("GetSummaryBuckets", mock.Anything, domain.GranularityMonth, "2025-07-01", "2025-09-30", domain.TripFilters{}, false).
+			Return([]domain.SummaryBucket{}, nil)
+		mockTripRepo.On("GetGroupedSummaryBuckets", mock.Anything, domain.GranularityMonth, domain.SummaryByClient, "2025-07-01", "2025-09-30", domain.TripFilters{}, false).
+			Return(rows, nil)
+		return mockTripRepo
+	}
+
+	t.Run("should keep the top groups and merge the rest into other", func(t *testing.T) {
+		mockTripRepo := expectRepo([]domain.SummaryGroupRow{
+			{Group: "Acme", StartDate: "2025-07-01", TripCount: 1, TotalMiles: 100},
+			{Group: "Acme", StartDate: "2025-09-01", TripCount: 1, TotalMiles: 50},
+			{Group: "Globex", StartDate: "2025-08-01", TripCount: 2, TotalMiles: 120},
+			{Group: "Initech", StartDate: "2025-08-01", TripCount: 1, TotalMiles: 30},
+			{Group: "", StartDate: "2025-09-01", TripCount: 1, TotalMiles: 10},
+		})
+
+		result, err := newService(mockTripRepo).GetSummary(context.Background(), query)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.SummaryByClient, result.GroupBy)
+		assert.Len(t, result.Groups, 3)
+
+		assert.Equal(t, "Acme", result.Groups[0].Key)
+		assert.Equal(t, domain.SummaryTotals{TripCount: 2, TotalMiles: 150, Amount: domain.MustMoney("75"), TotalDistance: 150, TotalAmount: domain.MustMoney("75")}, result.Groups[0].Totals)
+		assert.Equal(t, []domain.SummaryTotals{
+			{TripCount: 1, TotalMiles: 100, Amount: domain.MustMoney("50"), TotalDistance: 100, TotalAmount: domain.MustMoney("50")},
+			{},
+			{TripCount: 1, TotalMiles: 50, Amount: domain.MustMoney("25"), TotalDistance: 50, TotalAmount: domain.MustMoney("25")},
+		}, result.Groups[0].Values)
+		assert.Equal(t, "Globex", result.Groups[1].Key)
+
+		other := result.Groups[2]
+		assert.True(t, other.Other)
+		assert.Equal(t, "Other", other.Label)
+		assert.Equal(t, 2, other.MergedGroups)
+		assert.Equal(t, 40.0, other.Totals.TotalMiles)
+		assert.Equal(t, 30.0, other.Values[1].TotalMiles)
+		assert.Equal(t, 10.0, other.Values[2].TotalMiles)
+		mockTripRepo.AssertExpectations(t)
+	})
+
+	t.Run("should not add other when every group fits", func(t *testing.T) {
+		mockTripRepo := expectRepo([]domain.SummaryGroupRow{
+			{Group: "", StartDate: "2025-07-01", TripCount: 1, TotalMiles: 10},
+			{Group: "Acme", StartDate: "2025-07-01", TripCount: 1, TotalMiles: 10},
+		})
+
+		result, err := newService(mockTripRepo).GetSummary(context.Background(), query)
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Groups, 2)
+		// Ties are broken by key so the order is stable
+		assert.Equal(t, "Unspecified", result.Groups[0].Label)
+		assert.Equal(t, "Acme", result.Groups[1].Label)
+	})
+
+	t.Run("should reject unknown dimensions and out of range top", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		svc := newService(mockTripRepo)
+
+		_, err := svc.GetSummary(context.Background(), domain.SummaryQuery{GroupBy: "vehicle"})
+		assert.ErrorIs(t, err, ErrValidation)
+
+		_, err = svc.GetSummary(context.Background(), domain.SummaryQuery{GroupBy: domain.SummaryByClient, Top: 51})
+		assert.ErrorIs(t, err, ErrValidation)
+
+		mockTripRepo.AssertNotCalled(t, "GetSummaryBuckets", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTripService_GetTrips_TimeBounds(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+
+	t.Run("should resolve dates to midnight in the business timezone", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		tripService := NewTripService(mockTripRepo, new(MockTripClientService), newMockTripSettingsRepository(), nil, nil, nil, nil, newYork)
+
+		expectedFilters := domain.TripFilters{
+			Client:        "Acme",
+			CreatedAfter:  "2025-01-13T00:00:00-05:00",
+			UpdatedBefore: "2025-07-01T00:00:00-04:00",
+			UpdatedAfter:  "2025-06-01T08:30:00Z",
+		}
+		mockTripRepo.On("GetPaginated", mock.Anything, 1, 10, expectedFilters).Return([]domain.Trip{}, int64(0), nil)
+
+		_, _, err := tripService.GetTrips(context.Background(), 1, 10, domain.TripFilters{
+			Client:        "Acme",
+			CreatedAfter:  "2025-01-13",
+			UpdatedBefore: "2025-07-01",
+			UpdatedAfter:  "2025-06-01T08:30:00Z",
+		})
+
+		assert.NoError(t, err)
+		mockTripRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject a malformed bound", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		tripService := NewTripService(mockTripRepo, new(MockTripClientService), newMockTripSettingsRepository(), nil, nil, nil, nil, newYork)
+
+		_, _, err := tripService.GetTrips(context.Background(), 1, 10, domain.TripFilters{CreatedBefore: "01/13/2025"})
+
+		assert.ErrorIs(t, err, ErrValidation)
+		assert.Contains(t, err.Error(), "created_before")
+		mockTripRepo.AssertNotCalled(t, "GetPaginated", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTripService_TripTags(t *testing.T) {
+	t.Run("should tag a new trip", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		mockClientService := new(MockTripClientService)
+		mockTagRepo := new(MockTagRepository)
+		tripService := NewTripService(mockTripRepo, mockClientService, newMockTripSettingsRepository(), nil, NewTagService(mockTagRepo), nil, nil, time.UTC)
+
+		billable := domain.Tag{ID: 1, Name: "billable"}
+		mockTagRepo.On("FindByNames", mock.Anything, []string{"billable"}).Return([]domain.Tag{billable}, nil)
+		mockClientService.On("GetOrCreateClient", mock.Anything, "Acme").Return(&domain.Client{ID: 1, Name: "Acme"}, nil)
+		mockTripRepo.On("Create", mock.Anything, mock.MatchedBy(func(trip *domain.Trip) bool {
+			return len(trip.Tags) == 1 && trip.Tags[0] == billable
+		})).Return(nil)
+
+		result, err := tripService.CreateTrip(context.Background(), domain.CreateTripRequest{
+			ClientName: "Acme",
+			TripDate:   "2025-01-15",
+			Miles:      10,
+			Tags:       []string{"Billable"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Tag{billable}, result.Tags)
+		mockTripRepo.AssertExpectations(t)
+	})
+
+	t.Run("should keep tags when an update leaves them out", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		mockClientService := new(MockTripClientService)
+		mockTagRepo := new(MockTagRepository)
+		tripService := NewTripService(mockTripRepo, mockClientService, newMockTripSettingsRepository(), nil, NewTagService(mockTagRepo), nil, nil, time.UTC)
+
+		existing := &domain.Trip{ID: 4, ClientName: "Acme", TripDate: "2025-01-15", Miles: 10, Tags: []domain.Tag{{ID: 1, Name: "billable"}}}
+		mockTripRepo.On("FindByID", mock.Anything, uint(4)).Return(existing, nil)
+		mockClientService.On("GetOrCreateClient", mock.Anything, "Acme").Return(&domain.Client{ID: 1, Name: "Acme"}, nil)
+		mockTripRepo.On("Update", mock.Anything, existing).Return(nil)
+
+		result, err := tripService.UpdateTrip(context.Background(), 4, domain.UpdateTripRequest{
+			ClientName: "Acme",
+			TripDate:   "2025-01-16",
+			Miles:      12,
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Tags, 1)
+		mockTagRepo.AssertNotCalled(t, "FindByNames", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject invalid tag names", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		tripService := NewTripService(mockTripRepo, new(MockTripClientService), newMockTripSettingsRepository(), nil, NewTagService(new(MockTagRepository)), nil, nil, time.UTC)
+
+		_, err := tripService.CreateTrip(context.Background(), domain.CreateTripRequest{
+			ClientName: "Acme",
+			TripDate:   "2025-01-15",
+			Miles:      10,
+			Tags:       []string{"a,b"},
+		})
+
+		assert.ErrorIs(t, err, ErrValidation)
+		mockTripRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTripService_TripExpenses(t *testing.T) {
+	t.Run("should add validated expenses to a new trip", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		mockClientService := new(MockTripClientService)
+		settingsRepo := newMockTripSettingsRepository()
+		settingsRepo.On("GetByKey", mock.Anything, "mileage_rate_currency").Return(&domain.Settings{Key: "mileage_rate_currency", Value: "USD"}, nil)
+		tripService := NewTripService(mockTripRepo, mockClientService, settingsRepo, nil, nil, nil, nil, time.UTC)
+
+		mockClientService.On("GetOrCreateClient", mock.Anything, "Acme").Return(&domain.Client{ID: 1, Name: "Acme"}, nil)
+		mockTripRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Trip")).Return(nil)
+
+		result, err := tripService.CreateTrip(context.Background(), domain.CreateTripRequest{
+			ClientName: "Acme",
+			TripDate:   "2025-01-15",
+			Miles:      10,
+			Expenses: []domain.ExpenseRequest{
+				{Type: domain.ExpenseToll, Amount: domain.MustMoney("4.499")},
+				{Type: domain.ExpenseFuel, Amount: domain.MustMoney("30"), Currency: "cad", Note: " Full tank "},
+			},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Expense{
+			{Type: domain.ExpenseToll, Amount: domain.MustMoney("4.5"), Currency: "USD"},
+			{Type: domain.ExpenseFuel, Amount: domain.MustMoney("30"), Currency: "CAD", Note: "Full tank"},
+		}, result.Expenses)
+	})
+
+	t.Run("should default expense currencies to the client's currency", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		mockClientService := new(MockTripClientService)
+		tripService := NewTripService(mockTripRepo, mockClientService, newMockTripSettingsRepository(), nil, nil, nil, nil, time.UTC)
+
+		mockClientService.On("GetOrCreateClient", mock.Anything, "Maple Ltd").Return(&domain.Client{ID: 2, Name: "Maple Ltd", Currency: "CAD"}, nil)
+		mockTripRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Trip")).Return(nil)
+
+		result, err := tripService.CreateTrip(context.Background(), domain.CreateTripRequest{
+			ClientName: "Maple Ltd",
+			TripDate:   "2025-01-15",
+			Miles:      10,
+			Expenses:   []domain.ExpenseRequest{{Type: domain.ExpenseParking, Amount: domain.MustMoney("12")}},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "CAD", result.Expenses[0].Currency)
+	})
+
+	t.Run("should reject invalid expenses", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		tripService := NewTripService(mockTripRepo, new(MockTripClientService), newMockTripSettingsRepository(), nil, nil, nil, nil, time.UTC)
+
+		for _, expense := range []domain.ExpenseRequest{
+			{Type: "meals", Amount: domain.MustMoney("10")},
+			{Type: domain.ExpenseToll, Amount: domain.MustMoney("0.001")},
+			{Type: domain.ExpenseToll, Amount: domain.MustMoney("5"), Currency: "dollars"},
+		} {
+			_, err := tripService.CreateTrip(context.Background(), domain.CreateTripRequest{
+				ClientName: "Acme",
+				TripDate:   "2025-01-15",
+				Miles:      10,
+				Expenses:   []domain.ExpenseRequest{expense},
+			})
+			assert.ErrorIs(t, err, ErrValidation)
+		}
+		mockTripRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should keep expenses unless an update replaces them", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		mockClientService := new(MockTripClientService)
+		tripService := NewTripService(mockTripRepo, mockClientService, newMockTripSettingsRepository(), nil, nil, nil, nil, time.UTC)
+
+		existing := &domain.Trip{ID: 4, ClientName: "Acme", TripDate: "2025-01-15", Miles: 10,
+			Expenses: []domain.Expense{{ID: 1, TripID: 4, Type: domain.ExpenseParking, Amount: domain.MustMoney("8"), Currency: "USD"}}}
+		mockTripRepo.On("FindByID", mock.Anything, uint(4)).Return(existing, nil)
+		mockClientService.On("GetOrCreateClient", mock.Anything, "Acme").Return(&domain.Client{ID: 1, Name: "Acme"}, nil)
+		mockTripRepo.On("Update", mock.Anything, existing).Return(nil)
+
+		result, err := tripService.UpdateTrip(context.Background(), 4, domain.UpdateTripRequest{ClientName: "Acme", TripDate: "2025-01-15", Miles: 12})
+		assert.NoError(t, err)
+		assert.Len(t, result.Expenses, 1)
+
+		result, err = tripService.UpdateTrip(context.Background(), 4, domain.UpdateTripRequest{
+			ClientName: "Acme", TripDate: "2025-01-15", Miles: 12, Expenses: []domain.ExpenseRequest{},
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, result.Expenses)
+	})
+
+	t.Run("should add expenses to summary amounts", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		settingsRepo := newMockTripSettingsRepository()
+		stubSetting(settingsRepo, "mileage_rate").Return(&domain.Settings{Key: "mileage_rate", Value: "0.5"}, nil)
+		settingsRepo.On("GetByKey", mock.Anything, "mileage_rate_currency").Return(&domain.Settings{Key: "mileage_rate_currency", Value: "USD"}, nil).Maybe()
+		tripService := newSummaryTestService(mockTripRepo, new(MockTripClientService), settingsRepo)
+
+		mockTripRepo.On("GetExpenseTotals", mock.Anything, domain.SummaryDimension(""), "2025-01-01", "2025-02-28", domain.TripFilters{}).
+			Return([]domain.ExpenseTotalRow{
+				{TripDate: "2025-01-15", Currency: "USD", Amount: domain.MustMoney("16.5"), ExpenseCount: 2},
+				{TripDate: "2025-01-20", Currency: "CAD", Amount: domain.MustMoney("30"), ExpenseCount: 1}, // No exchange rates
+			}, nil)
+		mockTripRepo.On("GetSummaryBuckets", mock.Anything, domain.GranularityMonth, "2025-01-01", "2025-02-28", domain.TripFilters{}, false).
+			Return([]domain.SummaryBucket{
+				{StartDate: "2025-01-01", TripCount: 2, TotalMiles: 100},
+				{StartDate: "2025-02-01", TripCount: 1, TotalMiles: 20},
+			}, nil)
+
+		result, err := tripService.GetSummary(context.Background(), domain.SummaryQuery{From: "2025-01-01", To: "2025-02-28"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.MustMoney("50"), result.Buckets[0].Amount)
+		assert.Equal(t, domain.MustMoney("16.5"), result.Buckets[0].ExpenseAmount)
+		assert.Equal(t, domain.MustMoney("66.5"), result.Buckets[0].TotalAmount)
+		assert.Equal(t, domain.MustMoney("10"), result.Buckets[1].TotalAmount)
+		assert.Equal(t, domain.SummaryTotals{
+			TripCount: 3, TotalMiles: 120, Amount: domain.MustMoney("60"), TotalDistance: 120, ExpenseAmount: domain.MustMoney("16.5"), UnconvertedExpenses: 1, TotalAmount: domain.MustMoney("76.5"),
+		}, result.Totals)
+
+		// Months run newest first
+		assert.Equal(t, domain.MustMoney("16.5"), result.Months[1].ExpenseAmount)
+		assert.Equal(t, domain.MustMoney("66.5"), result.Months[1].TotalAmount)
+	})
+}
+
+func TestTripService_SummaryCurrency(t *testing.T) {
+	newService := func(tripRepo *MockTripRepository, rates []domain.ExchangeRate) TripService {
+		settingsRepo := newMockTripSettingsRepository()
+		stubSetting(settingsRepo, "mileage_rate").Return(&domain.Settings{Key: "mileage_rate", Value: "0.5"}, nil)
+		settingsRepo.On("GetByKey", mock.Anything, "mileage_rate_currency").Return(&domain.Settings{Key: "mileage_rate_currency", Value: "USD"}, nil)
+		exchangeRates := new(MockExchangeRateService)
+		exchangeRates.On("Converter", mock.Anything, mock.Anything, mock.Anything).Return(NewCurrencyConverter(rates), nil)
+
+		svc := NewTripService(tripRepo, new(MockTripClientService), settingsRepo, nil, nil, nil, exchangeRates, time.UTC)
+		svc.(*tripService).now = func() time.Time { return summaryTestNow }
+		return svc
+	}
+	rates := []domain.ExchangeRate{
+		{Date: "2025-01-01", Base: "USD", Quote: "CAD", Rate: decimal.RequireFromString("1.4")},
+		{Date: "2025-02-01", Base: "USD", Quote: "CAD", Rate: decimal.RequireFromString("1.5")},
+	}
+
+	t.Run("should convert amounts as of each trip date", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		mockTripRepo.On("GetExpenseTotals", mock.Anything, domain.SummaryDimension(""), "2025-01-01", "2025-02-28", domain.TripFilters{}).
+			Return([]domain.ExpenseTotalRow{
+				{TripDate: "2025-01-15", Currency: "USD", Amount: domain.MustMoney("10"), ExpenseCount: 1},
+				{TripDate: "2025-01-20", Currency: "CAD", Amount: domain.MustMoney("30"), ExpenseCount: 1},
+			}, nil)
+		// The mileage rate has to be converted, so trips are totalled per day
+		mockTripRepo.On("GetSummaryBuckets", mock.Anything, domain.GranularityDay, "2025-01-01", "2025-02-28", domain.TripFilters{}, false).
+			Return([]domain.SummaryBucket{
+				{StartDate: "2025-01-15", TripCount: 1, TotalMiles: 60},
+				{StartDate: "2025-01-31", TripCount: 1, TotalMiles: 40},
+				{StartDate: "2025-02-03", TripCount: 1, TotalMiles: 20},
+			}, nil)
+
+		result, err := newService(mockTripRepo, rates).GetSummary(context.Background(), domain.SummaryQuery{
+			From: "2025-01-01", To: "2025-02-28", Currency: "cad",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "CAD", result.Currency)
+		assert.Len(t, result.Buckets, 2)
+		assert.Equal(t, int64(2), result.Buckets[0].TripCount)
+		assert.Equal(t, domain.MustMoney("70"), result.Buckets[0].Amount)        // 100 * 0.5 * 1.4
+		assert.Equal(t, domain.MustMoney("44"), result.Buckets[0].ExpenseAmount) // 10 * 1.4 + 30
+		assert.Equal(t, domain.MustMoney("15"), result.Buckets[1].Amount)        // 20 * 0.5 * 1.5
+		assert.Equal(t, domain.MustMoney("129"), result.Totals.TotalAmount)
+	})
+
+	t.Run("should reject a currency the mileage rate cannot be converted to", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		mockTripRepo.On("GetExpenseTotals", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.ExpenseTotalRow{}, nil)
+		mockTripRepo.On("GetSummaryBuckets", mock.Anything, domain.GranularityDay, "2025-01-01", "2025-02-28", domain.TripFilters{}, false).
+			Return([]domain.SummaryBucket{{StartDate: "2025-01-15", TripCount: 1, TotalMiles: 60}}, nil)
+
+		_, err := newService(mockTripRepo, rates).GetSummary(context.Background(), domain.SummaryQuery{
+			From: "2025-01-01", To: "2025-02-28", Currency: "EUR",
+		})
+
+		assert.ErrorIs(t, err, ErrValidation)
+		assert.Contains(t, err.Error(), "no exchange rate from USD to EUR")
+	})
+
+	t.Run("should reject an invalid currency", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		mockTripRepo.On("GetExpenseTotals", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.ExpenseTotalRow{}, nil)
+
+		_, err := newService(mockTripRepo, rates).GetSummary(context.Background(), domain.SummaryQuery{Currency: "dollars"})
+
+		assert.ErrorIs(t, err, ErrValidation)
+		mockTripRepo.AssertNotCalled(t, "GetSummaryBuckets", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTripService_DistanceUnits(t *testing.T) {
+	kmSettingsRepo := func() *MockTripSettingsRepository {
+		settingsRepo := new(MockTripSettingsRepository)
+		settingsRepo.On("GetByKey", mock.Anything, "distance_unit").Return(&domain.Settings{Key: "distance_unit", Value: "km"}, nil)
+		settingsRepo.On("GetByKey", mock.Anything, "mileage_rate").Return(&domain.Settings{Key: "mileage_rate", Value: "0.67"}, nil).Maybe()
+		return settingsRepo
+	}
+
+	t.Run("should store a distance in kilometers as miles", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		mockClientService := new(MockTripClientService)
+		tripService := NewTripService(mockTripRepo, mockClientService, kmSettingsRepo(), nil, nil, nil, nil, time.UTC)
+
+		mockClientService.On("GetOrCreateClient", mock.Anything, "Test Client").Return(&domain.Client{ID: 1, Name: "Test Client"}, nil)
+		mockTripRepo.On("Create", mock.Anything, mock.MatchedBy(func(trip *domain.Trip) bool {
+			return trip.Miles == 62.1371
+		})).Return(nil)
+
+		result, err := tripService.CreateTrip(context.Background(), domain.CreateTripRequest{
+			ClientName: "Test Client",
+			TripDate:   "2025-01-15",
+			Distance:   100,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 100.0, result.Distance)
+		assert.Equal(t, domain.DistanceKilometers, result.Unit)
+		mockTripRepo.AssertExpectations(t)
+	})
+
+	t.Run("should accept an explicit unit", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		mockClientService := new(MockTripClientService)
+		tripService := NewTripService(mockTripRepo, mockClientService, kmSettingsRepo(), nil, nil, nil, nil, time.UTC)
+
+		mockClientService.On("GetOrCreateClient", mock.Anything, "Test Client").Return(&domain.Client{ID: 1, Name: "Test Client"}, nil)
+		mockTripRepo.On("Create", mock.Anything, mock.MatchedBy(func(trip *domain.Trip) bool {
+			return trip.Miles == 12.5
+		})).Return(nil)
+
+		result, err := tripService.CreateTrip(context.Background(), domain.CreateTripRequest{
+			ClientName: "Test Client",
+			TripDate:   "2025-01-15",
+			Distance:   12.5,
+			Unit:       "miles",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 12.5, result.Distance)
+		assert.Equal(t, domain.DistanceMiles, result.Unit)
+	})
+
+	tests := []struct {
+		name     string
+		miles    float64
+		distance float64
+		unit     string
+	}{
+		{"miles and distance", 10, 16, "km"},
+		{"a unit without distance", 10, 0, "km"},
+		{"an unknown unit", 0, 16, "leagues"},
+	}
+	for _, tt := range tests {
+		t.Run("should reject "+tt.name, func(t *testing.T) {
+			mockTripRepo := new(MockTripRepository)
+			tripService := NewTripService(mockTripRepo, new(MockTripClientService), newMockTripSettingsRepository(), nil, nil, nil, nil, time.UTC)
+
+			_, err := tripService.CreateTrip(context.Background(), domain.CreateTripRequest{
+				ClientName: "Test Client",
+				TripDate:   "2025-01-15",
+				Miles:      tt.miles,
+				Distance:   tt.distance,
+				Unit:       tt.unit,
+			})
+
+			assert.ErrorIs(t, err, ErrValidation)
+			mockTripRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("should list trips in the requested unit", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		tripService := NewTripService(mockTripRepo, new(MockTripClientService), newMockTripSettingsRepository(), nil, nil, nil, nil, time.UTC)
+
+		filters := domain.TripFilters{Unit: domain.DistanceKilometers}
+		mockTripRepo.On("GetPaginated", mock.Anything, 1, 10, filters).
+			Return([]domain.Trip{{ID: 1, Miles: 62.1371}, {ID: 2, Miles: 10}}, int64(2), nil)
+
+		trips, _, err := tripService.GetTrips(context.Background(), 1, 10, filters)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 100.0, trips[0].Distance)
+		assert.Equal(t, 16.09, trips[1].Distance)
+		assert.Equal(t, domain.DistanceKilometers, trips[1].Unit)
+	})
+
+	t.Run("should default to the distance unit setting", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		tripService := NewTripService(mockTripRepo, new(MockTripClientService), kmSettingsRepo(), nil, nil, nil, nil, time.UTC)
+
+		mockTripRepo.On("FindByID", mock.Anything, uint(1)).Return(&domain.Trip{ID: 1, Miles: 10}, nil)
+
+		trip, err := tripService.GetTripByID(context.Background(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 16.09, trip.Distance)
+		assert.Equal(t, domain.DistanceKilometers, trip.Unit)
+	})
+
+	t.Run("should total summary distances in the requested unit", func(t *testing.T) {
+		mockTripRepo := new(MockTripRepository)
+		settingsRepo := newMockTripSettingsRepository()
+		stubSetting(settingsRepo, "mileage_rate").Return(&domain.Settings{Key: "mileage_rate", Value: "0.5"}, nil)
+		settingsRepo.On("GetByKey", mock.Anything, "mileage_rate_currency").Return(&domain.Settings{Key: "mileage_rate_currency", Value: "USD"}, nil)
+		tripService := newSummaryTestService(mockTripRepo, new(MockTripClientService), settingsRepo)
+
+		mockTripRepo.On("GetExpenseTotals", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.ExpenseTotalRow{}, nil)
+		mockTripRepo.On("GetSummaryBuckets", mock.Anything, domain.GranularityMonth, "2025-09-01", "2025-09-30", mock.Anything, mock.Anything).
+			Return([]domain.SummaryBucket{{StartDate: "2025-09-01", TripCount: 2, TotalMiles: 100}}, nil)
+
+		result, err := tripService.GetSummary(context.Background(), domain.SummaryQuery{
+			From: "2025-09-01", To: "2025-09-30", Unit: domain.DistanceKilometers,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.DistanceKilometers, result.Unit)
+		assert.InDelta(t, 160.9344, result.Buckets[0].TotalDistance, 0.0001)
+		assert.InDelta(t, 160.9344, result.Months[0].TotalDistance, 0.0001)
+		assert.Equal(t, 100.0, result.Totals.TotalMiles)
+		assert.Equal(t, domain.MustMoney("50"), result.Totals.Amount)
+	})
+}
+
+func TestTripService_AmountRounding(t *testing.T) {
+	summary := func(rounding string) *domain.SummaryResponse {
+		mockTripRepo := new(MockTripRepository)
+		settingsRepo := newMockTripSettingsRepository()
+		stubSetting(settingsRepo, "amount_rounding").Return(&domain.Settings{Key: "amount_rounding", Value: rounding}, nil)
+		settingsRepo.On("GetByKey", mock.Anything, "mileage_rate_currency").Return(&domain.Settings{Key: "mileage_rate_currency", Value: "USD"}, nil)
+		tripService := newSummaryTestService(mockTripRepo, new(MockTripClientService), settingsRepo)
+
+		mockTripRepo.On("GetExpenseTotals", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.ExpenseTotalRow{}, nil)
+		// Trips are only listed when they are rounded one by one
+		mockTripRepo.On("GetSummaryBuckets", mock.Anything, domain.GranularityMonth, "2025-09-01", "2025-09-30", mock.Anything, rounding == "per_trip").
+			Return([]domain.SummaryBucket{{StartDate: "2025-09-01", TripCount: 3, TotalMiles: 4.5, TripMiles: []float64{1.5, 1.5, 1.5}}}, nil)
+
+		result, err := tripService.GetSummary(context.Background(), domain.SummaryQuery{From: "2025-09-01", To: "2025-09-30"})
+		assert.NoError(t, err)
+		return result
+	}
+
+	t.Run("should round the total of a period once", func(t *testing.T) {
+		result := summary("per_period")
+
+		assert.Equal(t, domain.MustMoney("3.02"), result.Buckets[0].Amount) // 4.5 * 0.67 = 3.015
+		assert.Equal(t, domain.MustMoney("3.02"), result.Totals.TotalAmount)
+	})
+
+	t.Run("should round every trip before adding them up", func(t *testing.T) {
+		result := summary("per_trip")
+
+		assert.Equal(t, domain.MustMoney("3.03"), result.Buckets[0].Amount) // 3 * round(1.5 * 0.67 = 1.005)
+		assert.Equal(t, domain.MustMoney("3.03"), result.Months[0].Amount)
+		assert.Equal(t, domain.MustMoney("3.03"), result.Totals.TotalAmount)
+		assert.Nil(t, result.Buckets[0].TripMiles)
+	})
+}
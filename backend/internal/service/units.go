@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/repository"
+)
+
+const distanceUnitKey = "distance_unit"
+
+// parseDistanceUnit parses a requested unit. A blank unit is returned empty
+// so callers can apply the distance_unit setting.
+func parseDistanceUnit(value string) (domain.DistanceUnit, error) {
+	unit, ok := domain.ParseDistanceUnit(value)
+	if !ok {
+		return "", fmt.Errorf("%w: unit must be mi or km", ErrValidation)
+	}
+	return unit, nil
+}
+
+// distanceUnit returns the default unit of distances and rates
+func distanceUnit(ctx context.Context, settingsRepo repository.SettingsRepository) (domain.DistanceUnit, error) {
+	value, err := settingValue(ctx, settingsRepo, distanceUnitSetting)
+	return domain.DistanceUnit(value), err
+}
+
+// resolveDistanceUnit parses a requested unit, defaulting to the
+// distance_unit setting
+func resolveDistanceUnit(ctx context.Context, settingsRepo repository.SettingsRepository, requested string) (domain.DistanceUnit, error) {
+	unit, err := parseDistanceUnit(requested)
+	if err != nil || unit != "" {
+		return unit, err
+	}
+	return distanceUnit(ctx, settingsRepo)
+}
+
+// requestMiles returns the miles of a trip request given either in miles or
+// as a distance in a unit. Miles are kept to 4 decimals so a distance in
+// kilometers reads back unchanged at 2.
+func requestMiles(ctx context.Context, settingsRepo repository.SettingsRepository, miles, distance float64, unitName string) (float64, error) {
+	if distance == 0 {
+		if unitName != "" {
+			return 0, fmt.Errorf("%w: unit requires distance", ErrValidation)
+		}
+		return miles, nil
+	}
+	if miles != 0 {
+		return 0, fmt.Errorf("%w: give either miles or distance, not both", ErrValidation)
+	}
+	if distance < 0 {
+		return 0, fmt.Errorf("%w: distance must not be negative", ErrValidation)
+	}
+
+	unit, err := resolveDistanceUnit(ctx, settingsRepo, unitName)
+	if err != nil {
+		return 0, err
+	}
+	return math.Round(unit.ToMiles(distance)*10000) / 10000, nil
+}
+
+// setTripDistance fills in the trip's distance in unit
+func setTripDistance(trip *domain.Trip, unit domain.DistanceUnit) {
+	trip.Distance = roundDistance(unit.FromMiles(trip.Miles))
+	trip.Unit = unit
+}
+
+// setTripDistances fills in the distance of every trip in unit
+func setTripDistances(trips []domain.Trip, unit domain.DistanceUnit) {
+	for i := range trips {
+		setTripDistance(&trips[i], unit)
+	}
+}
+
+func roundDistance(distance float64) float64 {
+	return math.Round(distance*100) / 100
+}
+
+// ratePerUnit converts a rate per mile to a rate per unit. Converted rates
+// are rounded to a hundredth of a cent.
+func ratePerUnit(ratePerMile domain.Money, unit domain.DistanceUnit) domain.Money {
+	if unit == domain.DistanceMiles {
+		return ratePerMile
+	}
+	return unit.RateFromPerMile(ratePerMile).Round(4)
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/repository"
+	"gorm.io/gorm"
+)
+
+type ViewService interface {
+	GetViews(ctx context.Context) ([]domain.SavedView, error)
+	GetView(ctx context.Context, id uint) (*domain.SavedView, error)
+	CreateView(ctx context.Context, req domain.SaveViewRequest) (*domain.SavedView, error)
+	UpdateView(ctx context.Context, id uint, req domain.SaveViewRequest) (*domain.SavedView, error)
+	DeleteView(ctx context.Context, id uint) error
+	ResolveFilters(ctx context.Context, id uint) (domain.TripFilters, error)
+}
+
+type viewService struct {
+	viewRepo repository.SavedViewRepository
+
+	// location is the business timezone relative date ranges are resolved in
+	location *time.Location
+	now      func() time.Time
+}
+
+func NewViewService(viewRepo repository.SavedViewRepository, location *time.Location) ViewService {
+	return &viewService{
+		viewRepo: viewRepo,
+		location: location,
+		now:      time.Now,
+	}
+}
+
+func (s *viewService) GetViews(ctx context.Context) ([]domain.SavedView, error) {
+	return s.viewRepo.GetAll(ctx)
+}
+
+func (s *viewService) GetView(ctx context.Context, id uint) (*domain.SavedView, error) {
+	view, err := s.viewRepo.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("saved view %d %w", id, ErrNotFound)
+	}
+	return view, err
+}
+
+func (s *viewService) CreateView(ctx context.Context, req domain.SaveViewRequest) (*domain.SavedView, error) {
+	name, err := s.validateView(ctx, 0, req)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &domain.SavedView{
+		Name:      name,
+		Filters:   req.Filters,
+		DateRange: req.DateRange,
+	}
+
+	if err := s.viewRepo.Create(ctx, view); err != nil {
+		return nil, viewNameTaken(name, err)
+	}
+
+	return view, nil
+}
+
+func (s *viewService) UpdateView(ctx context.Context, id uint, req domain.SaveViewRequest) (*domain.SavedView, error) {
+	view, err := s.GetView(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := s.validateView(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+
+	view.Name = name
+	view.Filters = req.Filters
+	view.DateRange = req.DateRange
+
+	if err := s.viewRepo.Update(ctx, view); err != nil {
+		return nil, viewNameTaken(name, err)
+	}
+
+	return view, nil
+}
+
+func (s *viewService) DeleteView(ctx context.Context, id uint) error {
+	if _, err := s.GetView(ctx, id); err != nil {
+		return err
+	}
+	return s.viewRepo.Delete(ctx, id)
+}
+
+// ResolveFilters returns the view's filters with its relative date range,
+// if any, turned into dates as of today
+func (s *viewService) ResolveFilters(ctx context.Context, id uint) (domain.TripFilters, error) {
+	view, err := s.GetView(ctx, id)
+	if err != nil {
+		return domain.TripFilters{}, err
+	}
+
+	filters := view.Filters
+	if view.DateRange != "" {
+		from, to := resolveDateRange(view.DateRange, s.now().In(s.location))
+		filters.DateFrom = from.Format("2006-01-02")
+		filters.DateTo = to.Format("2006-01-02")
+	}
+
+	return filters, nil
+}
+
+// validateView checks a view request and returns its trimmed name. id is
+// the view being updated, or 0 for a new view.
+func (s *viewService) validateView(ctx context.Context, id uint, req domain.SaveViewRequest) (string, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return "", fmt.Errorf("%w: name must not be blank", ErrValidation)
+	}
+
+	if req.DateRange != "" {
+		if !req.DateRange.Valid() {
+			return "", fmt.Errorf("%w: unknown date_range %q", ErrValidation, req.DateRange)
+		}
+		if req.Filters.DateFrom != "" || req.Filters.DateTo != "" {
+			return "", fmt.Errorf("%w: date_range cannot be combined with date_from or date_to", ErrValidation)
+		}
+	}
+
+	if err := validateTripFilters(req.Filters); err != nil {
+		return "", err
+	}
+
+	existing, err := s.viewRepo.FindByName(ctx, name)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+	if err == nil && existing.ID != id {
+		return "", fmt.Errorf("%w: a view named %q already exists", ErrValidation, name)
+	}
+
+	return name, nil
+}
+
+// viewNameTaken reports a view name taken since validateView checked it as
+// a validation error
+func viewNameTaken(name string, err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return fmt.Errorf("%w: a view named %q already exists", ErrValidation, name)
+	}
+	return err
+}
+
+// validateTripFilters applies the checks the trips list makes on its query
+// parameters to filters received as JSON
+func validateTripFilters(filters domain.TripFilters) error {
+	var dateFrom, dateTo time.Time
+	var err error
+	if filters.DateFrom != "" {
+		if dateFrom, err = time.Parse("2006-01-02", filters.DateFrom); err != nil {
+			return fmt.Errorf("%w: date_from must be in YYYY-MM-DD format", ErrValidation)
+		}
+	}
+	if filters.DateTo != "" {
+		if dateTo, err = time.Parse("2006-01-02", filters.DateTo); err != nil {
+			return fmt.Errorf("%w: date_to must be in YYYY-MM-DD format", ErrValidation)
+		}
+	}
+	if filters.DateFrom != "" && filters.DateTo != "" && dateFrom.After(dateTo) {
+		return fmt.Errorf("%w: date_from cannot be after date_to", ErrValidation)
+	}
+
+	if (filters.MinMiles != nil && *filters.MinMiles < 0) || (filters.MaxMiles != nil && *filters.MaxMiles < 0) {
+		return fmt.Errorf("%w: min_miles and max_miles must be non-negative", ErrValidation)
+	}
+	if filters.MinMiles != nil && filters.MaxMiles != nil && *filters.MinMiles > *filters.MaxMiles {
+		return fmt.Errorf("%w: min_miles cannot be greater than max_miles", ErrValidation)
+	}
+
+	for name, value := range map[string]string{
+		"created_after":  filters.CreatedAfter,
+		"created_before": filters.CreatedBefore,
+		"updated_after":  filters.UpdatedAfter,
+		"updated_before": filters.UpdatedBefore,
+	} {
+		if value == "" {
+			continue
+		}
+		if _, err := domain.ParseTimeBound(value, time.UTC); err != nil {
+			return fmt.Errorf("%w: %s must be a YYYY-MM-DD date or an RFC 3339 timestamp", ErrValidation, name)
+		}
+	}
+
+	return nil
+}
+
+// resolveDateRange returns the first and last day of the range as of today
+func resolveDateRange(dateRange domain.DateRange, today time.Time) (time.Time, time.Time) {
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+
+	period := func(granularity domain.Granularity, offset int) (time.Time, time.Time) {
+		start := stepBucket(bucketStart(today, granularity), granularity, offset)
+		return start, nextBucket(start, granularity).AddDate(0, 0, -1)
+	}
+
+	switch dateRange {
+	case domain.DateRangeYesterday:
+		yesterday := today.AddDate(0, 0, -1)
+		return yesterday, yesterday
+	case domain.DateRangeThisWeek:
+		return period(domain.GranularityWeek, 0)
+	case domain.DateRangeLastWeek:
+		return period(domain.GranularityWeek, -1)
+	case domain.DateRangeThisMonth:
+		return period(domain.GranularityMonth, 0)
+	case domain.DateRangeLastMonth:
+		return period(domain.GranularityMonth, -1)
+	case domain.DateRangeThisQuarter:
+		return period(domain.GranularityQuarter, 0)
+	case domain.DateRangeLastQuarter:
+		return period(domain.GranularityQuarter, -1)
+	case domain.DateRangeThisYear:
+		return period(domain.GranularityYear, 0)
+	case domain.DateRangeLastYear:
+		return period(domain.GranularityYear, -1)
+	case domain.DateRangeLast7Days:
+		return today.AddDate(0, 0, -6), today
+	case domain.DateRangeLast30Days:
+		return today.AddDate(0, 0, -29), today
+	case domain.DateRangeLast90Days:
+		return today.AddDate(0, 0, -89), today
+	default:
+		return today, today
+	}
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockSavedViewRepository implements the SavedViewRepository interface for testing
+type MockSavedViewRepository struct {
+	mock.Mock
+}
+
+func (m *MockSavedViewRepository) Create(ctx context.Context, view *domain.SavedView) error {
+	args := m.Called(ctx, view)
+	return args.Error(0)
+}
+
+func (m *MockSavedViewRepository) Update(ctx context.Context, view *domain.SavedView) error {
+	args := m.Called(ctx, view)
+	return args.Error(0)
+}
+
+func (m *MockSavedViewRepository) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockSavedViewRepository) FindByID(ctx context.Context, id uint) (*domain.SavedView, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SavedView), args.Error(1)
+}
+
+func (m *MockSavedViewRepository) FindByName(ctx context.Context, name string) (*domain.SavedView, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SavedView), args.Error(1)
+}
+
+func (m *MockSavedViewRepository) GetAll(ctx context.Context) ([]domain.SavedView, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.SavedView), args.Error(1)
+}
+
+func newTestViewService(repo *MockSavedViewRepository, now time.Time) *viewService {
+	s := NewViewService(repo, time.UTC).(*viewService)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestViewService_CreateView(t *testing.T) {
+	t.Run("should create view with trimmed name", func(t *testing.T) {
+		mockRepo := new(MockSavedViewRepository)
+		viewService := newTestViewService(mockRepo, time.Now())
+
+		mockRepo.On("FindByName", mock.Anything, "Acme").Return(nil, gorm.ErrRecordNotFound)
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.SavedView")).Return(nil)
+
+		result, err := viewService.CreateView(context.Background(), domain.SaveViewRequest{
+			Name:      "  Acme ",
+			Filters:   domain.TripFilters{Client: "Acme Corp"},
+			DateRange: domain.DateRangeLastMonth,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Acme", result.Name)
+		assert.Equal(t, "Acme Corp", result.Filters.Client)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject duplicate name", func(t *testing.T) {
+		mockRepo := new(MockSavedViewRepository)
+		viewService := newTestViewService(mockRepo, time.Now())
+
+		mockRepo.On("FindByName", mock.Anything, "Acme").Return(&domain.SavedView{ID: 3, Name: "Acme"}, nil)
+
+		result, err := viewService.CreateView(context.Background(), domain.SaveViewRequest{Name: "Acme"})
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrValidation)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject a name taken by a concurrent request", func(t *testing.T) {
+		mockRepo := new(MockSavedViewRepository)
+		viewService := newTestViewService(mockRepo, time.Now())
+
+		mockRepo.On("FindByName", mock.Anything, "Acme").Return(nil, gorm.ErrRecordNotFound)
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.SavedView")).Return(gorm.ErrDuplicatedKey)
+
+		result, err := viewService.CreateView(context.Background(), domain.SaveViewRequest{Name: "Acme"})
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrValidation)
+		assert.Contains(t, err.Error(), `a view named "Acme" already exists`)
+	})
+
+	t.Run("should reject invalid filters", func(t *testing.T) {
+		minMiles, maxMiles := 5.0, 10.0
+		tests := []struct {
+			name string
+			req  domain.SaveViewRequest
+		}{
+			{"blank name", domain.SaveViewRequest{Name: "   "}},
+			{"unknown date range", domain.SaveViewRequest{Name: "A", DateRange: "next_week"}},
+			{"date range with explicit dates", domain.SaveViewRequest{
+				Name: "A", DateRange: domain.DateRangeThisYear, Filters: domain.TripFilters{DateFrom: "2024-01-01"},
+			}},
+			{"malformed date", domain.SaveViewRequest{Name: "A", Filters: domain.TripFilters{DateTo: "01/31/2024"}}},
+			{"inverted miles", domain.SaveViewRequest{Name: "A", Filters: domain.TripFilters{
+				MinMiles: &maxMiles, MaxMiles: &minMiles,
+			}}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mockRepo := new(MockSavedViewRepository)
+				viewService := newTestViewService(mockRepo, time.Now())
+
+				_, err := viewService.CreateView(context.Background(), tt.req)
+
+				assert.ErrorIs(t, err, ErrValidation)
+				mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			})
+		}
+	})
+}
+
+func TestViewService_UpdateView(t *testing.T) {
+	t.Run("should allow keeping the same name", func(t *testing.T) {
+		mockRepo := new(MockSavedViewRepository)
+		viewService := newTestViewService(mockRepo, time.Now())
+
+		existing := &domain.SavedView{ID: 3, Name: "Acme"}
+		mockRepo.On("FindByID", mock.Anything, uint(3)).Return(existing, nil)
+		mockRepo.On("FindByName", mock.Anything, "Acme").Return(existing, nil)
+		mockRepo.On("Update", mock.Anything, existing).Return(nil)
+
+		result, err := viewService.UpdateView(context.Background(), 3, domain.SaveViewRequest{
+			Name:    "Acme",
+			Filters: domain.TripFilters{Search: "site visit"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "site visit", result.Filters.Search)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return not found for missing view", func(t *testing.T) {
+		mockRepo := new(MockSavedViewRepository)
+		viewService := newTestViewService(mockRepo, time.Now())
+
+		mockRepo.On("FindByID", mock.Anything, uint(9)).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := viewService.UpdateView(context.Background(), 9, domain.SaveViewRequest{Name: "Acme"})
+
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestViewService_ResolveFilters(t *testing.T) {
+	mockRepo := new(MockSavedViewRepository)
+	viewService := newTestViewService(mockRepo, time.Date(2024, time.May, 15, 23, 30, 0, 0, time.UTC))
+
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&domain.SavedView{
+		ID:        1,
+		Filters:   domain.TripFilters{Client: "Acme Corp"},
+		DateRange: domain.DateRangeLastMonth,
+	}, nil)
+	mockRepo.On("FindByID", mock.Anything, uint(2)).Return(&domain.SavedView{
+		ID:      2,
+		Filters: domain.TripFilters{DateFrom: "2024-01-01", DateTo: "2024-01-31"},
+	}, nil)
+
+	filters, err := viewService.ResolveFilters(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.TripFilters{Client: "Acme Corp", DateFrom: "2024-04-01", DateTo: "2024-04-30"}, filters)
+
+	filters, err = viewService.ResolveFilters(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-01-01", filters.DateFrom)
+	assert.Equal(t, "2024-01-31", filters.DateTo)
+}
+
+func TestResolveDateRange(t *testing.T) {
+	// A Wednesday
+	today := time.Date(2024, time.May, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		dateRange domain.DateRange
+		from, to  string
+	}{
+		{domain.DateRangeToday, "2024-05-15", "2024-05-15"},
+		{domain.DateRangeYesterday, "2024-05-14", "2024-05-14"},
+		{domain.DateRangeThisWeek, "2024-05-13", "2024-05-19"},
+		{domain.DateRangeLastWeek, "2024-05-06", "2024-05-12"},
+		{domain.DateRangeThisMonth, "2024-05-01", "2024-05-31"},
+		{domain.DateRangeLastMonth, "2024-04-01", "2024-04-30"},
+		{domain.DateRangeThisQuarter, "2024-04-01", "2024-06-30"},
+		{domain.DateRangeLastQuarter, "2024-01-01", "2024-03-31"},
+		{domain.DateRangeThisYear, "2024-01-01", "2024-12-31"},
+		{domain.DateRangeLastYear, "2023-01-01", "2023-12-31"},
+		{domain.DateRangeLast7Days, "2024-05-09", "2024-05-15"},
+		{domain.DateRangeLast30Days, "2024-04-16", "2024-05-15"},
+		{domain.DateRangeLast90Days, "2024-02-16", "2024-05-15"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.dateRange), func(t *testing.T) {
+			from, to := resolveDateRange(tt.dateRange, today)
+			assert.Equal(t, tt.from, from.Format("2006-01-02"))
+			assert.Equal(t, tt.to, to.Format("2006-01-02"))
+		})
+	}
+}
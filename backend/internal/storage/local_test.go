@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := NewLocalStore(dir)
+	require.NoError(t, err)
+
+	t.Run("should store, read and delete a blob", func(t *testing.T) {
+		err := store.Put(ctx, "trips/1/abc", strings.NewReader("receipt"), 7, "image/png")
+		require.NoError(t, err)
+		assert.FileExists(t, filepath.Join(dir, "trips", "1", "abc"))
+
+		blob, err := store.Get(ctx, "trips/1/abc")
+		require.NoError(t, err)
+		content, err := io.ReadAll(blob)
+		blob.Close()
+		require.NoError(t, err)
+		assert.Equal(t, "receipt", string(content))
+
+		require.NoError(t, store.Delete(ctx, "trips/1/abc"))
+		_, err = store.Get(ctx, "trips/1/abc")
+		assert.True(t, errors.Is(err, fs.ErrNotExist))
+	})
+
+	t.Run("should treat deleting a missing blob as success", func(t *testing.T) {
+		assert.NoError(t, store.Delete(ctx, "trips/1/missing"))
+	})
+
+	t.Run("should reject a short write and leave nothing behind", func(t *testing.T) {
+		err := store.Put(ctx, "trips/2/short", strings.NewReader("abc"), 10, "image/png")
+		assert.Error(t, err)
+
+		entries, readErr := os.ReadDir(filepath.Join(dir, "trips", "2"))
+		require.NoError(t, readErr)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("should reject keys outside the storage directory", func(t *testing.T) {
+		for _, key := range []string{"", "../escape", "trips/../../escape"} {
+			err := store.Put(ctx, key, strings.NewReader("x"), 1, "image/png")
+			assert.Error(t, err, key)
+		}
+		assert.NoFileExists(t, filepath.Join(filepath.Dir(dir), "escape"))
+	})
+}
@@ -0,0 +1,245 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Config configures an S3Store
+type S3Config struct {
+	Endpoint        string // e.g. "https://s3.eu-west-1.amazonaws.com" or "http://minio:9000"
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+
+	// PathStyle addresses objects as endpoint/bucket/key rather than
+	// bucket.endpoint/key, as most S3-compatible servers expect
+	PathStyle bool
+}
+
+// S3Store keeps blobs as objects in an S3 or S3-compatible bucket. Requests
+// are signed with AWS Signature Version 4.
+type S3Store struct {
+	config S3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewS3Store creates a store for the configured bucket. timeout bounds the
+// wait for the server to start responding; the transfer of a body is bound
+// only by the request context, so large files can stream at any speed.
+func NewS3Store(config S3Config, timeout time.Duration) *S3Store {
+	config.Endpoint = strings.TrimRight(config.Endpoint, "/")
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = timeout
+	return &S3Store{
+		config: config,
+		client: &http.Client{Transport: transport},
+		now:    time.Now,
+	}
+}
+
+// objectURL returns the URL of the object stored under key
+func (s *S3Store) objectURL(key string) (string, error) {
+	endpoint, err := url.Parse(s.config.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return "", fmt.Errorf("invalid S3 endpoint %q", s.config.Endpoint)
+	}
+
+	path := "/" + uriEncode(key, false)
+	if s.config.PathStyle {
+		path = "/" + uriEncode(s.config.Bucket, true) + path
+	} else {
+		endpoint.Host = s.config.Bucket + "." + endpoint.Host
+	}
+	return endpoint.Scheme + "://" + endpoint.Host + path, nil
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error {
+	resp, err := s.do(ctx, http.MethodPut, key, content, size, func(req *http.Request) {
+		req.Header.Set("Content-Type", contentType)
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return s.responseError("store", key, resp)
+	}
+	return nil
+}
+
+// Get opens an object; the error wraps fs.ErrNotExist when there is none
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, fmt.Errorf("S3 object %q: %w", key, fs.ErrNotExist)
+	default:
+		defer resp.Body.Close()
+		return nil, s.responseError("read", key, resp)
+	}
+}
+
+// Delete removes an object. Deleting a missing object succeeds.
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, 0, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return s.responseError("delete", key, resp)
+	}
+}
+
+// do sends a signed request for the object stored under key
+func (s *S3Store) do(ctx context.Context, method, key string, body io.Reader, size int64, prepare func(*http.Request)) (*http.Response, error) {
+	endpoint, err := s.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if body == nil {
+		body = http.NoBody
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build S3 request: %w", err)
+	}
+	if method == http.MethodPut {
+		req.ContentLength = size
+	}
+	if prepare != nil {
+		prepare(req)
+	}
+
+	// The body is streamed, so its hash is not part of the signature
+	req.Header.Set("X-Amz-Content-Sha256", "UNSIGNED-PAYLOAD")
+	signV4(req, s.config.Region, s.config.AccessKeyID, s.config.SecretAccessKey, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("S3 request failed: %w", err)
+	}
+	return resp, nil
+}
+
+func (s *S3Store) responseError(action, key string, resp *http.Response) error {
+	// S3 explains failures in a short XML document
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("failed to %s S3 object %q (status %d): %s", action, key, resp.StatusCode, strings.TrimSpace(string(detail)))
+}
+
+// signV4 adds the X-Amz-Date and Authorization headers of an AWS Signature
+// Version 4 for the S3 service. The host and every header already set on
+// the request are signed; X-Amz-Content-Sha256 must be among them.
+func signV4(req *http.Request, region, accessKeyID, secretAccessKey string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		if strings.EqualFold(name, "Authorization") {
+			continue
+		}
+		headers[strings.ToLower(name)] = strings.Join(values, ",")
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		req.Header.Get("X-Amz-Content-Sha256"),
+	}, "\n")
+
+	scope := date + "/" + region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hashHex(canonicalRequest)
+
+	key := hmacSHA256([]byte("AWS4"+secretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKeyID, scope, signedHeaders, signature))
+}
+
+func canonicalQuery(values url.Values) string {
+	pairs := make([]string, 0, len(values))
+	for name, list := range values {
+		for _, value := range list {
+			pairs = append(pairs, uriEncode(name, true)+"="+uriEncode(value, true))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent-encodes everything but unreserved characters, and
+// slashes too when encodeSlash is set, as Signature Version 4 requires
+func uriEncode(value string, encodeSlash bool) string {
+	var encoded strings.Builder
+	for _, b := range []byte(value) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9',
+			b == '-', b == '_', b == '.', b == '~':
+			encoded.WriteByte(b)
+		case b == '/' && !encodeSlash:
+			encoded.WriteByte(b)
+		default:
+			fmt.Fprintf(&encoded, "%%%02X", b)
+		}
+	}
+	return encoded.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func hashHex(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
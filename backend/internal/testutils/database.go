@@ -3,12 +3,17 @@ package testutils
 import (
 	"context"
 	"fmt"
+	"os"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
-	"github.com/oscar/mileagetracker/internal/domain"
+	"github.com/oscar/mileagetracker/internal/database"
+	"github.com/oscar/mileagetracker/migrations"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -22,35 +27,33 @@ type TestDB struct {
 	migrated bool
 }
 
-// SetupTestDB creates and returns a new in-memory SQLite database for testing.
-// Each call creates a fresh database to ensure test isolation.
-// The database is automatically migrated with all domain entities.
+// SetupTestDB creates and returns a new database for testing, with the
+// schema built by the embedded migrations. Each call creates a fresh
+// database to ensure test isolation.
+//
+// By default the database is an in-memory SQLite one. When
+// TEST_POSTGRES_DSN is set, it is instead a fresh schema on that PostgreSQL
+// server, dropped again when the test ends, so the same tests verify the
+// queries and migrations of both dialects.
 func SetupTestDB(t *testing.T) *gorm.DB {
 	t.Helper()
 
+	if dsn := os.Getenv(PostgresDSNEnv); dsn != "" {
+		return setupPostgresTestDB(t, dsn)
+	}
+
 	// Create unique database name for this test using temp directory
 	// This prevents :memory: files from cluttering the project directory
 	tempDir := t.TempDir()
-	dbName := "file:" + tempDir + "/test.db?mode=memory&cache=shared"
+	dbName := "file:" + tempDir + "/test.db?mode=memory&cache=shared&_foreign_keys=on"
 
 	db, err := gorm.Open(sqlite.Open(dbName), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent),
-		// Ensure foreign keys are enabled for referential integrity
-		DisableForeignKeyConstraintWhenMigrating: false,
+		Logger:  logger.Default.LogMode(logger.Silent),
+		NowFunc: database.NowUTC,
 	})
 	assert.NoError(t, err, "failed to create test database")
 
-	// Enable foreign key constraints for SQLite
-	err = db.Exec("PRAGMA foreign_keys = ON").Error
-	assert.NoError(t, err, "failed to enable foreign key constraints")
-
-	// Auto-migrate all domain entities
-	err = db.AutoMigrate(
-		&domain.Trip{},
-		&domain.Client{},
-		&domain.Settings{},
-	)
-	assert.NoError(t, err, "failed to migrate test database schema")
+	migrateTestDB(t, db)
 
 	// Register cleanup function
 	t.Cleanup(func() {
@@ -60,6 +63,56 @@ func SetupTestDB(t *testing.T) *gorm.DB {
 	return db
 }
 
+// PostgresDSNEnv names the environment variable that points SetupTestDB at
+// a PostgreSQL server
+const PostgresDSNEnv = "TEST_POSTGRES_DSN"
+
+var testSchemaCounter atomic.Int64
+
+// setupPostgresTestDB creates a uniquely named schema, connects with it as
+// the search path and migrates it
+func setupPostgresTestDB(t *testing.T, dsn string) *gorm.DB {
+	t.Helper()
+
+	admin, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err, "failed to connect to test database")
+
+	schema := fmt.Sprintf("test_%d_%d", os.Getpid(), testSchemaCounter.Add(1))
+	require.NoError(t, admin.Exec("CREATE SCHEMA "+schema).Error, "failed to create test schema")
+
+	db, err := gorm.Open(postgres.Open(dsn+" search_path="+schema), &gorm.Config{
+		Logger:  logger.Default.LogMode(logger.Silent),
+		NowFunc: database.NowUTC,
+	})
+	require.NoError(t, err, "failed to connect to test schema")
+
+	t.Cleanup(func() {
+		CleanupTestDB(t, db)
+		if err := admin.Exec("DROP SCHEMA " + schema + " CASCADE").Error; err != nil {
+			t.Logf("failed to drop test schema %s: %v", schema, err)
+		}
+		CleanupTestDB(t, admin)
+	})
+
+	migrateTestDB(t, db)
+
+	return db
+}
+
+// migrateTestDB applies the migrations for the database's dialect
+func migrateTestDB(t *testing.T, db *gorm.DB) {
+	t.Helper()
+
+	files, err := migrations.For(db.Dialector.Name())
+	require.NoError(t, err)
+	migrator, err := database.NewMigrator(db, files)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err, "failed to migrate test database schema")
+}
+
 // SetupTestDBWithSchema creates a test database and applies custom schema migrations.
 // This is useful for tests that need specific database schema configurations.
 func SetupTestDBWithSchema(t *testing.T, migrations ...func(*gorm.DB) error) *gorm.DB {
@@ -118,7 +171,7 @@ func TruncateTables(t *testing.T, db *gorm.DB, tables ...string) {
 
 	// If no tables specified, truncate all known tables
 	if len(tables) == 0 {
-		tables = []string{"trips", "clients", "settings"}
+		tables = []string{"exchange_rates", "expenses", "attachments", "trip_tags", "tags", "trips", "clients", "settings", "location_distances", "locations", "route_distance_cache", "saved_views"}
 	}
 
 	// Disable foreign key checks during truncation
@@ -208,7 +261,7 @@ type TestDBConfig struct {
 	EnableForeignKeys bool
 	// LogLevel controls the GORM log level (default: Silent)
 	LogLevel logger.LogLevel
-	// CustomMigrations are additional migrations to run after auto-migrate
+	// CustomMigrations are additional migrations to run after the schema migrations
 	CustomMigrations []func(*gorm.DB) error
 	// InitialData is a function to populate the database with test data
 	InitialData func(*gorm.DB) error
@@ -225,6 +278,7 @@ func SetupTestDBWithConfig(t *testing.T, config TestDBConfig) *gorm.DB {
 	db, err := gorm.Open(sqlite.Open(dbName), &gorm.Config{
 		Logger:                                   logger.Default.LogMode(config.LogLevel),
 		DisableForeignKeyConstraintWhenMigrating: !config.EnableForeignKeys,
+		NowFunc:                                  database.NowUTC,
 	})
 	assert.NoError(t, err, "failed to create test database")
 
@@ -234,13 +288,7 @@ func SetupTestDBWithConfig(t *testing.T, config TestDBConfig) *gorm.DB {
 		assert.NoError(t, err, "failed to enable foreign key constraints")
 	}
 
-	// Auto-migrate domain entities
-	err = db.AutoMigrate(
-		&domain.Trip{},
-		&domain.Client{},
-		&domain.Settings{},
-	)
-	assert.NoError(t, err, "failed to migrate test database schema")
+	migrateTestDB(t, db)
 
 	// Apply custom migrations
 	for i, migration := range config.CustomMigrations {
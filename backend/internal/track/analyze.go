@@ -0,0 +1,122 @@
+package track
+
+import (
+	"time"
+
+	"github.com/oscar/mileagetracker/internal/geo"
+)
+
+// Options controls how a recorded track is cleaned up and split into trips
+type Options struct {
+	// MinMovementMeters drops points closer than this to the last kept
+	// point, which removes GPS jitter while standing still
+	MinMovementMeters float64
+	// MaxSpeedMPH drops points that would require moving faster than this
+	// from the last kept point. Only applies to timestamped points; zero
+	// disables the check.
+	MaxSpeedMPH float64
+	// StopDuration splits the track wherever the time between two kept
+	// points is at least this long. Zero disables splitting.
+	StopDuration time.Duration
+	// MinSegmentMiles discards segments shorter than this, such as moving
+	// the car across a parking lot
+	MinSegmentMiles float64
+}
+
+// DefaultOptions are sensible settings for phone-recorded driving tracks
+var DefaultOptions = Options{
+	MinMovementMeters: 10,
+	MaxSpeedMPH:       120,
+	StopDuration:      5 * time.Minute,
+	MinSegmentMiles:   0.1,
+}
+
+// Segment is one continuous drive found in a track
+type Segment struct {
+	Start  time.Time // zero when the track has no timestamps
+	End    time.Time
+	Miles  float64
+	Points int
+}
+
+// Smooth removes jitter and impossible jumps from a track
+func Smooth(points []Point, opts Options) []Point {
+	if len(points) == 0 {
+		return nil
+	}
+
+	kept := []Point{points[0]}
+	for _, p := range points[1:] {
+		last := kept[len(kept)-1]
+		meters := geo.HaversineMiles(last.Point, p.Point) * geo.MetersPerMile
+
+		if meters < opts.MinMovementMeters {
+			continue
+		}
+
+		if opts.MaxSpeedMPH > 0 && !last.Time.IsZero() && !p.Time.IsZero() {
+			elapsed := p.Time.Sub(last.Time)
+			if elapsed > 0 {
+				mph := (meters / geo.MetersPerMile) / elapsed.Hours()
+				if mph > opts.MaxSpeedMPH {
+					continue
+				}
+			}
+		}
+
+		kept = append(kept, p)
+	}
+
+	return kept
+}
+
+// Split breaks a track wherever the recorder sat still for at least the
+// stop duration. Each new part starts from the place the previous one
+// stopped, since jitter filtering usually drops the first points of a drive.
+func Split(points []Point, stop time.Duration) [][]Point {
+	if len(points) == 0 {
+		return nil
+	}
+	if stop <= 0 {
+		return [][]Point{points}
+	}
+
+	var parts [][]Point
+	current := []Point{points[0]}
+	for i := 1; i < len(points); i++ {
+		prev, cur := points[i-1], points[i]
+		if !prev.Time.IsZero() && !cur.Time.IsZero() && cur.Time.Sub(prev.Time) >= stop {
+			parts = append(parts, current)
+			current = []Point{{Point: prev.Point, Time: cur.Time}}
+		}
+		current = append(current, cur)
+	}
+	return append(parts, current)
+}
+
+// DistanceMiles returns the length of the path through the points in miles
+func DistanceMiles(points []Point) float64 {
+	total := 0.0
+	for i := 1; i < len(points); i++ {
+		total += geo.HaversineMiles(points[i-1].Point, points[i].Point)
+	}
+	return total
+}
+
+// Analyze smooths a track and splits it into drives
+func Analyze(points []Point, opts Options) []Segment {
+	var segments []Segment
+	for _, part := range Split(Smooth(points, opts), opts.StopDuration) {
+		miles := geo.RoundMiles(DistanceMiles(part))
+		if len(part) < 2 || miles < opts.MinSegmentMiles {
+			continue
+		}
+		segments = append(segments, Segment{
+			Start:  part[0].Time,
+			End:    part[len(part)-1].Time,
+			Miles:  miles,
+			Points: len(part),
+		})
+	}
+	return segments
+}
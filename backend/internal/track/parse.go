@@ -0,0 +1,187 @@
+package track
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/oscar/mileagetracker/internal/geo"
+)
+
+// Point is a single recorded position. Time is zero when the source file
+// does not carry timestamps.
+type Point struct {
+	geo.Point
+	Time time.Time
+}
+
+// ErrUnsupportedFormat is returned when a file is neither GPX nor GeoJSON
+var ErrUnsupportedFormat = errors.New("unsupported track format, expected GPX or GeoJSON")
+
+// Parse detects whether data is GPX or GeoJSON and returns its track points
+func Parse(data []byte) ([]Point, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, fmt.Errorf("track file is empty")
+	}
+
+	switch trimmed[0] {
+	case '<':
+		return ParseGPX(trimmed)
+	case '{':
+		return ParseGeoJSON(trimmed)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+type gpxFile struct {
+	Tracks []struct {
+		Segments []struct {
+			Points []struct {
+				Lat  float64 `xml:"lat,attr"`
+				Lon  float64 `xml:"lon,attr"`
+				Time string  `xml:"time"`
+			} `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+}
+
+// ParseGPX returns the points of every track segment in a GPX document, in
+// file order
+func ParseGPX(data []byte) ([]Point, error) {
+	var doc gpxFile
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid GPX: %w", err)
+	}
+
+	var points []Point
+	for _, trk := range doc.Tracks {
+		for _, seg := range trk.Segments {
+			for _, pt := range seg.Points {
+				p := Point{Point: geo.Point{Lat: pt.Lat, Lon: pt.Lon}}
+				if pt.Time != "" {
+					t, err := time.Parse(time.RFC3339, pt.Time)
+					if err != nil {
+						return nil, fmt.Errorf("invalid GPX point time %q: %w", pt.Time, err)
+					}
+					p.Time = t
+				}
+				points = append(points, p)
+			}
+		}
+	}
+
+	if len(points) == 0 {
+		return nil, fmt.Errorf("GPX file contains no track points")
+	}
+	return points, nil
+}
+
+type geoJSONObject struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+	Geometry    *geoJSONObject  `json:"geometry"`
+	Features    []geoJSONObject `json:"features"`
+	Properties  struct {
+		// coordTimes is written by common GPX-to-GeoJSON converters
+		CoordTimes json.RawMessage `json:"coordTimes"`
+	} `json:"properties"`
+}
+
+// ParseGeoJSON returns the points of every LineString or MultiLineString in
+// a GeoJSON geometry, Feature or FeatureCollection. Timestamps are read from
+// a "coordTimes" feature property when present.
+func ParseGeoJSON(data []byte) ([]Point, error) {
+	var obj geoJSONObject
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("invalid GeoJSON: %w", err)
+	}
+
+	points, err := geoJSONPoints(&obj, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(points) == 0 {
+		return nil, fmt.Errorf("GeoJSON contains no LineString coordinates")
+	}
+	return points, nil
+}
+
+func geoJSONPoints(obj *geoJSONObject, coordTimes json.RawMessage) ([]Point, error) {
+	switch obj.Type {
+	case "FeatureCollection":
+		var points []Point
+		for i := range obj.Features {
+			featurePoints, err := geoJSONPoints(&obj.Features[i], nil)
+			if err != nil {
+				return nil, err
+			}
+			points = append(points, featurePoints...)
+		}
+		return points, nil
+	case "Feature":
+		if obj.Geometry == nil {
+			return nil, nil
+		}
+		return geoJSONPoints(obj.Geometry, obj.Properties.CoordTimes)
+	case "LineString":
+		var coords [][]float64
+		if err := json.Unmarshal(obj.Coordinates, &coords); err != nil {
+			return nil, fmt.Errorf("invalid LineString coordinates: %w", err)
+		}
+		var times []string
+		if len(coordTimes) > 0 {
+			_ = json.Unmarshal(coordTimes, &times)
+		}
+		return linePoints(coords, times)
+	case "MultiLineString":
+		var lines [][][]float64
+		if err := json.Unmarshal(obj.Coordinates, &lines); err != nil {
+			return nil, fmt.Errorf("invalid MultiLineString coordinates: %w", err)
+		}
+		var times [][]string
+		if len(coordTimes) > 0 {
+			_ = json.Unmarshal(coordTimes, &times)
+		}
+		var points []Point
+		for i, line := range lines {
+			var lineTimes []string
+			if i < len(times) {
+				lineTimes = times[i]
+			}
+			linePts, err := linePoints(line, lineTimes)
+			if err != nil {
+				return nil, err
+			}
+			points = append(points, linePts...)
+		}
+		return points, nil
+	default:
+		// Other geometries (points, polygons) carry no track to follow
+		return nil, nil
+	}
+}
+
+func linePoints(coords [][]float64, times []string) ([]Point, error) {
+	points := make([]Point, 0, len(coords))
+	for i, c := range coords {
+		if len(c) < 2 {
+			return nil, fmt.Errorf("invalid GeoJSON position at index %d", i)
+		}
+		// GeoJSON positions are [longitude, latitude, (elevation)]
+		p := Point{Point: geo.Point{Lat: c[1], Lon: c[0]}}
+		if len(times) == len(coords) {
+			t, err := time.Parse(time.RFC3339, times[i])
+			if err != nil {
+				return nil, fmt.Errorf("invalid coordTimes entry %q: %w", times[i], err)
+			}
+			p.Time = t
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
@@ -0,0 +1,158 @@
+package track
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oscar/mileagetracker/internal/geo"
+	"github.com/stretchr/testify/assert"
+)
+
+// Moving 0.0145 degrees of latitude is almost exactly one mile
+const mileOfLatitude = 0.0145
+
+func gpxDocument(points []Point) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0"?><gpx version="1.1" creator="test"><trk><name>Drive</name><trkseg>`)
+	for _, p := range points {
+		fmt.Fprintf(&b, `<trkpt lat="%f" lon="%f"><ele>10</ele><time>%s</time></trkpt>`,
+			p.Lat, p.Lon, p.Time.Format(time.RFC3339))
+	}
+	b.WriteString(`</trkseg></trk></gpx>`)
+	return b.String()
+}
+
+// drive returns points heading north one mile per minute starting at start
+func drive(start time.Time, lat float64, minutes int) []Point {
+	points := make([]Point, 0, minutes+1)
+	for i := 0; i <= minutes; i++ {
+		points = append(points, Point{
+			Point: geo.Point{Lat: lat + float64(i)*mileOfLatitude, Lon: -74.0},
+			Time:  start.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	return points
+}
+
+func TestParse(t *testing.T) {
+	start := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+
+	t.Run("should parse GPX track points", func(t *testing.T) {
+		points, err := Parse([]byte(gpxDocument(drive(start, 40.0, 3))))
+
+		assert.NoError(t, err)
+		assert.Len(t, points, 4)
+		assert.InDelta(t, 40.0, points[0].Lat, 1e-6)
+		assert.Equal(t, start, points[0].Time)
+	})
+
+	t.Run("should parse GeoJSON feature with coordTimes", func(t *testing.T) {
+		doc := `{"type":"FeatureCollection","features":[{"type":"Feature",
+			"properties":{"coordTimes":["2025-03-10T08:00:00Z","2025-03-10T08:01:00Z"]},
+			"geometry":{"type":"LineString","coordinates":[[-74.0,40.0,5],[-74.0,40.0145,6]]}}]}`
+
+		points, err := Parse([]byte(doc))
+
+		assert.NoError(t, err)
+		assert.Len(t, points, 2)
+		assert.InDelta(t, -74.0, points[1].Lon, 1e-9)
+		assert.InDelta(t, 40.0145, points[1].Lat, 1e-9)
+		assert.Equal(t, start.Add(time.Minute), points[1].Time)
+	})
+
+	t.Run("should parse bare GeoJSON LineString without times", func(t *testing.T) {
+		points, err := Parse([]byte(`{"type":"LineString","coordinates":[[-74.0,40.0],[-74.0,40.01]]}`))
+
+		assert.NoError(t, err)
+		assert.Len(t, points, 2)
+		assert.True(t, points[0].Time.IsZero())
+	})
+
+	t.Run("should reject unknown formats", func(t *testing.T) {
+		_, err := Parse([]byte("lat,lon\n40,-74"))
+		assert.ErrorIs(t, err, ErrUnsupportedFormat)
+	})
+
+	t.Run("should reject GPX without points", func(t *testing.T) {
+		_, err := Parse([]byte(`<gpx><trk><trkseg></trkseg></trk></gpx>`))
+		assert.Error(t, err)
+	})
+
+	t.Run("should reject GeoJSON without lines", func(t *testing.T) {
+		_, err := Parse([]byte(`{"type":"Point","coordinates":[-74.0,40.0]}`))
+		assert.Error(t, err)
+	})
+}
+
+func TestSmooth(t *testing.T) {
+	start := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+
+	t.Run("should drop jitter below minimum movement", func(t *testing.T) {
+		points := []Point{
+			{Point: geo.Point{Lat: 40.0, Lon: -74.0}, Time: start},
+			{Point: geo.Point{Lat: 40.00001, Lon: -74.0}, Time: start.Add(time.Second)},
+			{Point: geo.Point{Lat: 40.00002, Lon: -74.00001}, Time: start.Add(2 * time.Second)},
+			{Point: geo.Point{Lat: 40.001, Lon: -74.0}, Time: start.Add(10 * time.Second)},
+		}
+
+		smoothed := Smooth(points, Options{MinMovementMeters: 10})
+
+		assert.Len(t, smoothed, 2)
+	})
+
+	t.Run("should drop impossible jumps", func(t *testing.T) {
+		points := []Point{
+			{Point: geo.Point{Lat: 40.0, Lon: -74.0}, Time: start},
+			{Point: geo.Point{Lat: 41.0, Lon: -74.0}, Time: start.Add(time.Minute)},
+			{Point: geo.Point{Lat: 40.0145, Lon: -74.0}, Time: start.Add(time.Minute)},
+		}
+
+		smoothed := Smooth(points, Options{MaxSpeedMPH: 120})
+
+		assert.Len(t, smoothed, 2)
+		assert.InDelta(t, 40.0145, smoothed[1].Lat, 1e-9)
+	})
+}
+
+func TestAnalyze(t *testing.T) {
+	start := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+
+	t.Run("should split drives at long stops", func(t *testing.T) {
+		morning := drive(start, 40.0, 10)
+		// Parked for an hour, then drove back
+		afternoon := drive(start.Add(70*time.Minute), 40.0+10*mileOfLatitude, 5)
+		points := append(morning, afternoon...)
+
+		segments := Analyze(points, DefaultOptions)
+
+		assert.Len(t, segments, 2)
+		assert.InDelta(t, 10.0, segments[0].Miles, 0.05)
+		assert.Equal(t, start, segments[0].Start)
+		assert.Equal(t, start.Add(10*time.Minute), segments[0].End)
+		assert.InDelta(t, 5.0, segments[1].Miles, 0.05)
+	})
+
+	t.Run("should keep a single segment without timestamps", func(t *testing.T) {
+		points := drive(start, 40.0, 3)
+		for i := range points {
+			points[i].Time = time.Time{}
+		}
+
+		segments := Analyze(points, DefaultOptions)
+
+		assert.Len(t, segments, 1)
+		assert.True(t, segments[0].Start.IsZero())
+		assert.InDelta(t, 3.0, segments[0].Miles, 0.05)
+	})
+
+	t.Run("should discard segments shorter than minimum", func(t *testing.T) {
+		points := []Point{
+			{Point: geo.Point{Lat: 40.0, Lon: -74.0}, Time: start},
+			{Point: geo.Point{Lat: 40.0005, Lon: -74.0}, Time: start.Add(time.Minute)},
+		}
+
+		assert.Empty(t, Analyze(points, DefaultOptions))
+	})
+}
@@ -0,0 +1,29 @@
+// Package migrations holds the versioned SQL schema migrations, one
+// directory per database dialect. Each version is a NNN_name.up.sql script
+// and the NNN_name.down.sql script that reverts it, and both dialects share
+// the same versions. They are embedded so the server binary can apply them
+// itself.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+)
+
+//go:embed postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+// Dialects lists the database dialects there are migrations for
+var Dialects = []string{"postgres", "sqlite"}
+
+// For returns the migrations for a dialect as named by its GORM dialector,
+// "postgres" or "sqlite"
+func For(dialect string) (fs.FS, error) {
+	for _, d := range Dialects {
+		if d == dialect {
+			return fs.Sub(files, dialect)
+		}
+	}
+	return nil, fmt.Errorf("no migrations for database dialect %q", dialect)
+}
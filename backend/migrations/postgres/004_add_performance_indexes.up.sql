@@ -21,10 +21,9 @@ CREATE INDEX IF NOT EXISTS idx_trips_client_id_date ON trips(client_id, trip_dat
 CREATE INDEX IF NOT EXISTS idx_trips_pagination_covering ON trips(trip_date DESC, created_at DESC) 
   INCLUDE (id, client_name, miles, notes);
 
--- Partial index for recent trips (last 2 years)
--- Optimizes queries for recent data which are most common
-CREATE INDEX IF NOT EXISTS idx_trips_recent ON trips(trip_date DESC, created_at DESC) 
-  WHERE trip_date >= (CURRENT_DATE - INTERVAL '2 years');
+-- No partial index for recent trips: PostgreSQL only accepts immutable
+-- functions in an index predicate, so one relative to CURRENT_DATE cannot be
+-- created. idx_trips_date_created_desc serves those queries.
 
 -- Statistics update for better query planning
 -- This ensures PostgreSQL has up-to-date statistics for optimization
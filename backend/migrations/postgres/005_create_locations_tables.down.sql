@@ -0,0 +1,8 @@
+-- Detach trips from saved locations and drop the locations tables
+DROP INDEX IF EXISTS idx_trips_to_location_id;
+DROP INDEX IF EXISTS idx_trips_from_location_id;
+ALTER TABLE trips DROP COLUMN IF EXISTS to_location_id;
+ALTER TABLE trips DROP COLUMN IF EXISTS from_location_id;
+
+DROP TABLE IF EXISTS location_distances;
+DROP TABLE IF EXISTS locations;
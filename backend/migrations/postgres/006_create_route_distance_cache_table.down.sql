@@ -0,0 +1,2 @@
+-- Drop route distance cache table
+DROP TABLE IF EXISTS route_distance_cache;
@@ -0,0 +1,4 @@
+-- Drop optional start and end times from trips
+DROP INDEX IF EXISTS idx_trips_date_start_created;
+ALTER TABLE trips DROP COLUMN IF EXISTS end_time;
+ALTER TABLE trips DROP COLUMN IF EXISTS start_time;
@@ -0,0 +1,2 @@
+-- Remove fiscal year start month setting
+DELETE FROM settings WHERE key = 'fiscal_year_start_month';
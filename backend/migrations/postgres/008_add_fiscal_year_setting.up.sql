@@ -0,0 +1,4 @@
+-- Month fiscal years start in (1 = January, i.e. calendar years)
+INSERT INTO settings (key, value)
+VALUES ('fiscal_year_start_month', '1')
+ON CONFLICT (key) DO NOTHING;
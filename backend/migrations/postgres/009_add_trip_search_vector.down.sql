@@ -0,0 +1,3 @@
+-- Drop full-text search vector from trips
+DROP INDEX IF EXISTS idx_trips_search_vector;
+ALTER TABLE trips DROP COLUMN IF EXISTS search_vector;
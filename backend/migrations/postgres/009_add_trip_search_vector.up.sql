@@ -0,0 +1,11 @@
+-- Full-text search over client names and notes
+-- Client names weigh more than notes when ranking matches; the column is
+-- generated so it never drifts from the text it indexes
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', COALESCE(client_name, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE(notes, '')), 'B')
+    ) STORED;
+
+-- Optimizes: WHERE search_vector @@ <tsquery>
+CREATE INDEX IF NOT EXISTS idx_trips_search_vector ON trips USING GIN (search_vector);
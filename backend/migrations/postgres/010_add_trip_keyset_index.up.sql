@@ -0,0 +1,3 @@
+-- Index for cursor pagination of the trips list
+-- Optimizes: WHERE (trip_date, created_at, id) < (...) ORDER BY trip_date DESC, created_at DESC, id DESC
+CREATE INDEX IF NOT EXISTS idx_trips_keyset ON trips(trip_date DESC, created_at DESC, id DESC);
@@ -0,0 +1,8 @@
+-- Indexes for the sortable trip list columns
+-- Each pairs the column with id, the tie-breaker, so single-key sorts and
+-- their cursor pages in either direction can be read in index order
+CREATE INDEX IF NOT EXISTS idx_trips_sort_trip_date ON trips(trip_date, id);
+CREATE INDEX IF NOT EXISTS idx_trips_sort_miles ON trips(miles, id);
+CREATE INDEX IF NOT EXISTS idx_trips_sort_client_name ON trips(LOWER(client_name), id);
+CREATE INDEX IF NOT EXISTS idx_trips_sort_created_at ON trips(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_trips_sort_updated_at ON trips(updated_at, id);
@@ -0,0 +1,2 @@
+-- Drop saved views table
+DROP TABLE IF EXISTS saved_views;
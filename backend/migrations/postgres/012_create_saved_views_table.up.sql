@@ -0,0 +1,11 @@
+-- Create saved_views table (named sets of trip list filters)
+-- filters holds the JSON-encoded filter set; date_range, when set, is a
+-- relative range such as last_month resolved each time the view is used
+CREATE TABLE IF NOT EXISTS saved_views (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    filters TEXT NOT NULL,
+    date_range VARCHAR(20),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
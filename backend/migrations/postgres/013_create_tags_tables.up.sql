@@ -0,0 +1,17 @@
+-- Create tags table (labels such as "billable" or "site-visit")
+CREATE TABLE IF NOT EXISTS tags (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(30) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Link trips to their tags; links go away with either side
+CREATE TABLE IF NOT EXISTS trip_tags (
+    trip_id INTEGER NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (trip_id, tag_id)
+);
+
+-- Optimizes: tag filters and summaries looking up trips by tag
+CREATE INDEX IF NOT EXISTS idx_trip_tags_tag_id ON trip_tags(tag_id, trip_id);
@@ -0,0 +1,14 @@
+-- Create attachments table (receipts and other files kept with a trip);
+-- the content itself lives in the configured blob store under storage_key
+CREATE TABLE IF NOT EXISTS attachments (
+    id SERIAL PRIMARY KEY,
+    trip_id INTEGER NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Optimizes: listing a trip's attachments
+CREATE INDEX IF NOT EXISTS idx_attachments_trip_id ON attachments(trip_id, created_at);
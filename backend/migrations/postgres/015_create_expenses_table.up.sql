@@ -0,0 +1,15 @@
+-- Create expenses table (tolls, parking and fuel paid on a trip and
+-- reimbursed on top of the mileage amount)
+CREATE TABLE IF NOT EXISTS expenses (
+    id SERIAL PRIMARY KEY,
+    trip_id INTEGER NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('toll', 'parking', 'fuel', 'other')),
+    amount DECIMAL(10,2) NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL DEFAULT 'USD',
+    note TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Optimizes: loading a trip's expenses and totalling them in summaries
+CREATE INDEX IF NOT EXISTS idx_expenses_trip_id ON expenses(trip_id);
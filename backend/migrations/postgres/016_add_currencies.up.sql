@@ -0,0 +1,24 @@
+-- Exchange rates used to convert amounts between currencies as of each trip
+-- date: 1 base = rate quote
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    id SERIAL PRIMARY KEY,
+    date DATE NOT NULL,
+    base VARCHAR(3) NOT NULL,
+    quote VARCHAR(3) NOT NULL,
+    rate DECIMAL(18,8) NOT NULL CHECK (rate > 0),
+    source VARCHAR(20) NOT NULL DEFAULT 'manual',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Optimizes: replacing a pair's rate for a date and finding the latest rate
+-- on or before a date
+CREATE UNIQUE INDEX IF NOT EXISTS idx_exchange_rates_pair_date ON exchange_rates(base, quote, date);
+
+-- Currency a client reimburses in; NULL for the mileage rate currency
+ALTER TABLE clients ADD COLUMN IF NOT EXISTS currency VARCHAR(3);
+
+-- Currency the mileage rate is set in
+INSERT INTO settings (key, value)
+VALUES ('mileage_rate_currency', 'USD')
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1,3 @@
+-- Remove distance unit setting and restore two-decimal miles
+DELETE FROM settings WHERE key = 'distance_unit';
+ALTER TABLE trips ALTER COLUMN miles TYPE DECIMAL(8,2);
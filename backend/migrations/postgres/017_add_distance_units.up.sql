@@ -0,0 +1,8 @@
+-- Trips are stored in miles whatever unit they are entered in; 4 decimals
+-- keep a distance in kilometers exact to 2 decimals
+ALTER TABLE trips ALTER COLUMN miles TYPE DECIMAL(10,4);
+
+-- Unit distances and the mileage rate are shown in: mi or km
+INSERT INTO settings (key, value)
+VALUES ('distance_unit', 'mi')
+ON CONFLICT (key) DO NOTHING;
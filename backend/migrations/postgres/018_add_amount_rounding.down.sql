@@ -0,0 +1,2 @@
+-- Remove amount rounding setting
+DELETE FROM settings WHERE key = 'amount_rounding';
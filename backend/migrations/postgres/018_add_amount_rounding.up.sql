@@ -0,0 +1,5 @@
+-- Where mileage amounts are rounded to the cent: per_trip rounds every
+-- trip's amount, per_period rounds each period's total once
+INSERT INTO settings (key, value)
+VALUES ('amount_rounding', 'per_period')
+ON CONFLICT (key) DO NOTHING;
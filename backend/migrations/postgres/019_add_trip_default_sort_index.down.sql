@@ -0,0 +1,4 @@
+-- Restore the separate page and cursor pagination indexes
+DROP INDEX IF EXISTS idx_trips_default_sort;
+CREATE INDEX IF NOT EXISTS idx_trips_keyset ON trips(trip_date DESC, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_trips_date_start_created ON trips(trip_date DESC, (start_time IS NULL), start_time DESC, created_at DESC);
@@ -0,0 +1,2 @@
+-- Nothing to revert
+SELECT 1;
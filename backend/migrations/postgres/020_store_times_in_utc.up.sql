@@ -0,0 +1,3 @@
+-- TIMESTAMPTZ columns already compare as instants; only SQLite needs its
+-- stored times rewritten in UTC
+SELECT 1;
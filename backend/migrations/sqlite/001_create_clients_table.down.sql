@@ -0,0 +1,2 @@
+-- Drop clients table
+DROP TABLE IF EXISTS clients;
@@ -0,0 +1,9 @@
+-- Create clients table
+CREATE TABLE IF NOT EXISTS clients (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(30) NOT NULL UNIQUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index on name for faster lookups
+CREATE INDEX IF NOT EXISTS idx_clients_name ON clients(name);
@@ -0,0 +1,2 @@
+-- Drop trips table
+DROP TABLE IF EXISTS trips;
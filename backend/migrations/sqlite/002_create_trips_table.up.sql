@@ -0,0 +1,16 @@
+-- Create trips table
+CREATE TABLE IF NOT EXISTS trips (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    client_id INTEGER REFERENCES clients(id) ON DELETE SET NULL,
+    client_name VARCHAR(30) NOT NULL,
+    trip_date TEXT NOT NULL, -- YYYY-MM-DD; DATE columns would be read back as timestamps
+    miles DECIMAL(8,2) NOT NULL CHECK (miles >= 0),
+    notes TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS idx_trips_trip_date ON trips(trip_date);
+CREATE INDEX IF NOT EXISTS idx_trips_client_name ON trips(client_name);
+CREATE INDEX IF NOT EXISTS idx_trips_created_at ON trips(created_at);
@@ -0,0 +1,15 @@
+-- Create settings table
+CREATE TABLE IF NOT EXISTS settings (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    key VARCHAR(50) NOT NULL UNIQUE,
+    value VARCHAR(100) NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Insert default mileage rate
+INSERT INTO settings (key, value)
+VALUES ('mileage_rate', '0.67')
+ON CONFLICT (key) DO NOTHING;
+
+-- Create index on key for faster lookups
+CREATE INDEX IF NOT EXISTS idx_settings_key ON settings(key);
@@ -0,0 +1,5 @@
+-- Drop performance optimization indexes
+DROP INDEX IF EXISTS idx_trips_client_id_date;
+DROP INDEX IF EXISTS idx_clients_name_lower_partial;
+DROP INDEX IF EXISTS idx_trips_date_month_aggregation;
+DROP INDEX IF EXISTS idx_trips_date_created_desc;
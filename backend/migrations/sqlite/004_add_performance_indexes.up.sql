@@ -0,0 +1,22 @@
+-- Add performance optimization indexes
+-- These indexes are designed to optimize the most common query patterns
+
+-- Composite index for trips pagination with ordering
+CREATE INDEX IF NOT EXISTS idx_trips_date_created_desc ON trips(trip_date DESC, created_at DESC);
+
+-- Composite index for monthly summary aggregations
+CREATE INDEX IF NOT EXISTS idx_trips_date_month_aggregation ON trips(trip_date, miles) WHERE trip_date IS NOT NULL;
+
+-- Partial index for client suggestions (non-empty names only)
+CREATE INDEX IF NOT EXISTS idx_clients_name_lower_partial ON clients(LOWER(name)) WHERE LENGTH(name) > 0;
+
+-- Index for foreign key relationship optimization
+CREATE INDEX IF NOT EXISTS idx_trips_client_id_date ON trips(client_id, trip_date) WHERE client_id IS NOT NULL;
+
+-- SQLite indexes cannot INCLUDE extra columns, so there is no covering
+-- pagination index; idx_trips_date_created_desc serves those queries.
+
+-- Statistics update for better query planning
+ANALYZE trips;
+ANALYZE clients;
+ANALYZE settings;
@@ -0,0 +1,29 @@
+-- Detach trips from saved locations and drop the locations tables.
+-- SQLite cannot drop a column that has a foreign key, so trips is rebuilt
+-- without them.
+CREATE TABLE trips_without_locations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    client_id INTEGER REFERENCES clients(id) ON DELETE SET NULL,
+    client_name VARCHAR(30) NOT NULL,
+    trip_date TEXT NOT NULL,
+    miles DECIMAL(8,2) NOT NULL CHECK (miles >= 0),
+    notes TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO trips_without_locations (id, client_id, client_name, trip_date, miles, notes, created_at, updated_at)
+SELECT id, client_id, client_name, trip_date, miles, notes, created_at, updated_at FROM trips;
+
+DROP TABLE trips;
+ALTER TABLE trips_without_locations RENAME TO trips;
+
+CREATE INDEX IF NOT EXISTS idx_trips_trip_date ON trips(trip_date);
+CREATE INDEX IF NOT EXISTS idx_trips_client_name ON trips(client_name);
+CREATE INDEX IF NOT EXISTS idx_trips_created_at ON trips(created_at);
+CREATE INDEX IF NOT EXISTS idx_trips_date_created_desc ON trips(trip_date DESC, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_trips_date_month_aggregation ON trips(trip_date, miles) WHERE trip_date IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_trips_client_id_date ON trips(client_id, trip_date) WHERE client_id IS NOT NULL;
+
+DROP TABLE IF EXISTS location_distances;
+DROP TABLE IF EXISTS locations;
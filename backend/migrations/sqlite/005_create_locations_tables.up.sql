@@ -0,0 +1,27 @@
+-- Saved locations and the known driving distances between them
+CREATE TABLE IF NOT EXISTS locations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    label VARCHAR(50) NOT NULL UNIQUE,
+    address TEXT,
+    latitude DECIMAL(9,6) CHECK (latitude BETWEEN -90 AND 90),
+    longitude DECIMAL(9,6) CHECK (longitude BETWEEN -180 AND 180),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS location_distances (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    from_location_id INTEGER NOT NULL REFERENCES locations(id) ON DELETE CASCADE,
+    to_location_id INTEGER NOT NULL REFERENCES locations(id) ON DELETE CASCADE,
+    miles DECIMAL(8,2) NOT NULL CHECK (miles > 0),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT idx_location_distances_pair UNIQUE (from_location_id, to_location_id)
+);
+
+-- Trips may reference the saved locations they started from and ended at
+ALTER TABLE trips ADD COLUMN from_location_id INTEGER REFERENCES locations(id) ON DELETE SET NULL;
+ALTER TABLE trips ADD COLUMN to_location_id INTEGER REFERENCES locations(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_trips_from_location_id ON trips(from_location_id) WHERE from_location_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_trips_to_location_id ON trips(to_location_id) WHERE to_location_id IS NOT NULL;
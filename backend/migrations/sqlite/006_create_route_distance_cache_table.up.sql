@@ -0,0 +1,13 @@
+-- Cache of driving distances returned by the routing provider, keyed by
+-- provider and rounded coordinates
+CREATE TABLE IF NOT EXISTS route_distance_cache (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    provider VARCHAR(30) NOT NULL,
+    from_lat DECIMAL(9,6) NOT NULL,
+    from_lon DECIMAL(9,6) NOT NULL,
+    to_lat DECIMAL(9,6) NOT NULL,
+    to_lon DECIMAL(9,6) NOT NULL,
+    miles DECIMAL(8,2) NOT NULL CHECK (miles >= 0),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT idx_route_distance_cache_key UNIQUE (provider, from_lat, from_lon, to_lat, to_lon)
+);
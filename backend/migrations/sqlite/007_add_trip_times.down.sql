@@ -0,0 +1,4 @@
+-- Drop optional start and end times from trips
+DROP INDEX IF EXISTS idx_trips_date_start_created;
+ALTER TABLE trips DROP COLUMN end_time;
+ALTER TABLE trips DROP COLUMN start_time;
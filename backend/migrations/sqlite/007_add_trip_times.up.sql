@@ -0,0 +1,7 @@
+-- Add optional departure and arrival times to trips
+ALTER TABLE trips ADD COLUMN start_time DATETIME;
+ALTER TABLE trips ADD COLUMN end_time DATETIME;
+
+-- Index for the trips list order at the time; replaced by idx_trips_default_sort
+-- in migration 019
+CREATE INDEX IF NOT EXISTS idx_trips_date_start_created ON trips(trip_date DESC, (start_time IS NULL), start_time DESC, created_at DESC);
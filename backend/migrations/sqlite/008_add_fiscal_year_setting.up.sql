@@ -0,0 +1,4 @@
+-- Month (1-12) the fiscal year starts in
+INSERT INTO settings (key, value)
+VALUES ('fiscal_year_start_month', '1')
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1 @@
+-- Nothing to revert; see 009_add_trip_search_vector.up.sql
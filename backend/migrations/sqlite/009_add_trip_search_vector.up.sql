@@ -0,0 +1,3 @@
+-- SQLite has no full-text search vector; trip search falls back to
+-- case-insensitive substring matching on client names and notes, so this
+-- version changes nothing.
@@ -0,0 +1,6 @@
+-- Drop trip sort indexes
+DROP INDEX IF EXISTS idx_trips_sort_updated_at;
+DROP INDEX IF EXISTS idx_trips_sort_created_at;
+DROP INDEX IF EXISTS idx_trips_sort_client_name;
+DROP INDEX IF EXISTS idx_trips_sort_miles;
+DROP INDEX IF EXISTS idx_trips_sort_trip_date;
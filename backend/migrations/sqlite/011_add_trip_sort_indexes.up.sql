@@ -0,0 +1,7 @@
+-- One index per sortable column, with the id tie-breaker, so a single-key
+-- sort can be served from an index in either direction
+CREATE INDEX IF NOT EXISTS idx_trips_sort_trip_date ON trips(trip_date, id);
+CREATE INDEX IF NOT EXISTS idx_trips_sort_miles ON trips(miles, id);
+CREATE INDEX IF NOT EXISTS idx_trips_sort_client_name ON trips(LOWER(client_name), id);
+CREATE INDEX IF NOT EXISTS idx_trips_sort_created_at ON trips(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_trips_sort_updated_at ON trips(updated_at, id);
@@ -0,0 +1,3 @@
+-- Drop tags and the trip to tag join table
+DROP TABLE IF EXISTS trip_tags;
+DROP TABLE IF EXISTS tags;
@@ -0,0 +1,16 @@
+-- Tags and the trips they are attached to
+CREATE TABLE IF NOT EXISTS tags (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(30) NOT NULL UNIQUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS trip_tags (
+    trip_id INTEGER NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (trip_id, tag_id)
+);
+
+-- Optimizes: trips with a given tag
+CREATE INDEX IF NOT EXISTS idx_trip_tags_tag_id ON trip_tags(tag_id, trip_id);
@@ -0,0 +1,4 @@
+-- Remove currency support
+DELETE FROM settings WHERE key = 'mileage_rate_currency';
+ALTER TABLE clients DROP COLUMN currency;
+DROP TABLE IF EXISTS exchange_rates;
@@ -0,0 +1,20 @@
+-- Exchange rates: 1 base = rate quote on date
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    date TEXT NOT NULL, -- YYYY-MM-DD, as trips.trip_date
+    base VARCHAR(3) NOT NULL,
+    quote VARCHAR(3) NOT NULL,
+    rate DECIMAL(18,8) NOT NULL CHECK (rate > 0),
+    source VARCHAR(20) NOT NULL DEFAULT 'manual',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_exchange_rates_pair_date ON exchange_rates(base, quote, date);
+
+-- Currency a client reimburses in; NULL means the mileage rate currency
+ALTER TABLE clients ADD COLUMN currency VARCHAR(3);
+
+INSERT INTO settings (key, value)
+VALUES ('mileage_rate_currency', 'USD')
+ON CONFLICT (key) DO NOTHING;
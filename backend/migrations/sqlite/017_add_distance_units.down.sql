@@ -0,0 +1,2 @@
+-- Remove distance unit setting
+DELETE FROM settings WHERE key = 'distance_unit';
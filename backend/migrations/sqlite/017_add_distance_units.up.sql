@@ -0,0 +1,6 @@
+-- Distances may be entered in kilometers and are stored converted to miles.
+-- SQLite does not enforce DECIMAL precision, so unlike PostgreSQL the miles
+-- column needs no change to hold the extra decimals.
+INSERT INTO settings (key, value)
+VALUES ('distance_unit', 'mi')
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1,4 @@
+-- Whether amounts are rounded per trip or per period
+INSERT INTO settings (key, value)
+VALUES ('amount_rounding', 'per_period')
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1,6 @@
+-- Index for the default trips list order, shared by page and cursor pagination
+-- Optimizes: WHERE (trip_date, COALESCE(start_time, '-infinity'), created_at, id) < (...)
+-- ORDER BY trip_date DESC, COALESCE(start_time, '-infinity') DESC, created_at DESC, id DESC
+DROP INDEX IF EXISTS idx_trips_date_start_created;
+DROP INDEX IF EXISTS idx_trips_keyset;
+CREATE INDEX IF NOT EXISTS idx_trips_default_sort ON trips(trip_date DESC, COALESCE(start_time, '-infinity') DESC, created_at DESC, id DESC);
@@ -0,0 +1,2 @@
+-- Times stay in UTC; the offsets they were written with are not kept
+SELECT 1;
@@ -0,0 +1,23 @@
+-- Rewrite stored times in UTC. Times are kept as text carrying the offset
+-- they were written with and compared as strings, so only times sharing one
+-- offset compare as instants. strftime converts any offset to UTC.
+UPDATE clients SET created_at = strftime('%Y-%m-%d %H:%M:%f', created_at) || '+00:00' WHERE created_at IS NOT NULL AND created_at NOT LIKE '%+00:00';
+UPDATE trips SET created_at = strftime('%Y-%m-%d %H:%M:%f', created_at) || '+00:00' WHERE created_at IS NOT NULL AND created_at NOT LIKE '%+00:00';
+UPDATE trips SET updated_at = strftime('%Y-%m-%d %H:%M:%f', updated_at) || '+00:00' WHERE updated_at IS NOT NULL AND updated_at NOT LIKE '%+00:00';
+UPDATE trips SET start_time = strftime('%Y-%m-%d %H:%M:%f', start_time) || '+00:00' WHERE start_time IS NOT NULL AND start_time NOT LIKE '%+00:00';
+UPDATE trips SET end_time = strftime('%Y-%m-%d %H:%M:%f', end_time) || '+00:00' WHERE end_time IS NOT NULL AND end_time NOT LIKE '%+00:00';
+UPDATE settings SET updated_at = strftime('%Y-%m-%d %H:%M:%f', updated_at) || '+00:00' WHERE updated_at IS NOT NULL AND updated_at NOT LIKE '%+00:00';
+UPDATE locations SET created_at = strftime('%Y-%m-%d %H:%M:%f', created_at) || '+00:00' WHERE created_at IS NOT NULL AND created_at NOT LIKE '%+00:00';
+UPDATE locations SET updated_at = strftime('%Y-%m-%d %H:%M:%f', updated_at) || '+00:00' WHERE updated_at IS NOT NULL AND updated_at NOT LIKE '%+00:00';
+UPDATE location_distances SET created_at = strftime('%Y-%m-%d %H:%M:%f', created_at) || '+00:00' WHERE created_at IS NOT NULL AND created_at NOT LIKE '%+00:00';
+UPDATE location_distances SET updated_at = strftime('%Y-%m-%d %H:%M:%f', updated_at) || '+00:00' WHERE updated_at IS NOT NULL AND updated_at NOT LIKE '%+00:00';
+UPDATE route_distance_cache SET created_at = strftime('%Y-%m-%d %H:%M:%f', created_at) || '+00:00' WHERE created_at IS NOT NULL AND created_at NOT LIKE '%+00:00';
+UPDATE saved_views SET created_at = strftime('%Y-%m-%d %H:%M:%f', created_at) || '+00:00' WHERE created_at IS NOT NULL AND created_at NOT LIKE '%+00:00';
+UPDATE saved_views SET updated_at = strftime('%Y-%m-%d %H:%M:%f', updated_at) || '+00:00' WHERE updated_at IS NOT NULL AND updated_at NOT LIKE '%+00:00';
+UPDATE tags SET created_at = strftime('%Y-%m-%d %H:%M:%f', created_at) || '+00:00' WHERE created_at IS NOT NULL AND created_at NOT LIKE '%+00:00';
+UPDATE tags SET updated_at = strftime('%Y-%m-%d %H:%M:%f', updated_at) || '+00:00' WHERE updated_at IS NOT NULL AND updated_at NOT LIKE '%+00:00';
+UPDATE attachments SET created_at = strftime('%Y-%m-%d %H:%M:%f', created_at) || '+00:00' WHERE created_at IS NOT NULL AND created_at NOT LIKE '%+00:00';
+UPDATE expenses SET created_at = strftime('%Y-%m-%d %H:%M:%f', created_at) || '+00:00' WHERE created_at IS NOT NULL AND created_at NOT LIKE '%+00:00';
+UPDATE expenses SET updated_at = strftime('%Y-%m-%d %H:%M:%f', updated_at) || '+00:00' WHERE updated_at IS NOT NULL AND updated_at NOT LIKE '%+00:00';
+UPDATE exchange_rates SET created_at = strftime('%Y-%m-%d %H:%M:%f', created_at) || '+00:00' WHERE created_at IS NOT NULL AND created_at NOT LIKE '%+00:00';
+UPDATE exchange_rates SET updated_at = strftime('%Y-%m-%d %H:%M:%f', updated_at) || '+00:00' WHERE updated_at IS NOT NULL AND updated_at NOT LIKE '%+00:00';
@@ -55,7 +55,7 @@ const mockSummaryData = {
       year: 2025,
       month_num: 1,
       total_miles: 100,
-      amount: 67,
+      amount: "67",
     },
   ],
 };
@@ -362,7 +362,7 @@ describe("TripsPage", () => {
           year: 2025,
           month_num: 1,
           total_miles: 100,
-          amount: 67,
+          amount: "67",
         },
       ],
       // Note: no total_trips property - this is correct per the API spec
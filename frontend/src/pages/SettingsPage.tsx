@@ -44,7 +44,7 @@ export default function SettingsPage() {
   // Set initial rate when settings load (proper useEffect to avoid render-time side effect)
   useEffect(() => {
     if (settings && !mileageRate) {
-      setMileageRate(settings.mileage_rate.toString());
+      setMileageRate(settings.mileage_rate);
     }
   }, [settings, mileageRate]);
 
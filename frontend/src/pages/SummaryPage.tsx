@@ -22,7 +22,7 @@ function SummaryStats({ summaryData }: SummaryStatsProps) {
     (sum, month) => sum + month.total_miles,
     0,
   );
-  const totalAmount = summaryData.reduce((sum, month) => sum + month.amount, 0);
+  const totalAmount = summaryData.reduce((sum, month) => sum + Number(month.amount), 0);
   const totalMonths = summaryData.length;
   const averagePerMonth = totalMonths > 0 ? totalMiles / totalMonths : 0;
 
@@ -158,7 +158,7 @@ function MonthlyBreakdown({ summaryData }: MonthlyBreakdownProps) {
                     </div>
                     <div className="flex items-center gap-1 text-ctp-green font-medium">
                       <CurrencyDollarIcon className="h-4 w-4" />
-                      {month.amount.toFixed(2)}
+                      {Number(month.amount).toFixed(2)}
                     </div>
                   </div>
                 </div>
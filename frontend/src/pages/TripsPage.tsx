@@ -50,7 +50,7 @@ export default function TripsPage() {
     summaryData?.months?.reduce((sum, month) => sum + month.total_miles, 0) ||
     0;
   const estimatedDeduction =
-    summaryData?.months?.reduce((sum, month) => sum + month.amount, 0) || 0;
+    summaryData?.months?.reduce((sum, month) => sum + Number(month.amount), 0) || 0;
 
   return (
     <div className="min-h-screen pb-20 px-4 pt-4 space-y-6 max-w-7xl mx-auto">
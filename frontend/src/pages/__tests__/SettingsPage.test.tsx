@@ -35,7 +35,7 @@ vi.mock("../../utils/errorUtils", () => ({
 
 // Mock data
 const mockSettingsData = {
-  mileage_rate: 0.67,
+  mileage_rate: "0.67",
 };
 
 describe("SettingsPage", () => {
@@ -136,7 +136,7 @@ describe("SettingsPage", () => {
   it("submits form with valid data", async () => {
     const mockPut = vi.mocked(apiClient.apiClient.put);
     mockPut.mockClear();
-    mockPut.mockResolvedValue({ data: { mileage_rate: 0.75 } });
+    mockPut.mockResolvedValue({ data: { mileage_rate: "0.75" } });
 
     const user = userEvent.setup();
     renderWithProviders(<SettingsPage />);
@@ -206,7 +206,7 @@ describe("SettingsPage", () => {
     expect(saveButton).toBeDisabled();
 
     // Resolve the promise to clean up
-    resolvePromise!({ data: { mileage_rate: 0.75 } });
+    resolvePromise!({ data: { mileage_rate: "0.75" } });
 
     // Wait for loading to complete
     await waitFor(() => {
@@ -196,14 +196,14 @@ export const FIXTURE_MONTHLY_SUMMARY = {
       year: 2025,
       month_num: 9,
       total_miles: 206.95,
-      amount: 138.66,
+      amount: "138.66",
     },
     {
       month: "August 2025",
       year: 2025,
       month_num: 8,
       total_miles: 1146.35,
-      amount: 768.06,
+      amount: "768.06",
     },
   ],
 };
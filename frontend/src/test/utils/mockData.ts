@@ -36,7 +36,7 @@ export const mockSummaryResponse = {
       year: 2025,
       month_num: 1,
       total_miles: 200.5,
-      amount: 134.53,
+      amount: "134.53",
     },
   ],
 };
@@ -37,7 +37,7 @@ export interface MonthlySummary {
   year: number;
   month_num: number; // 1-12
   total_miles: number;
-  amount: number;
+  amount: string; // decimal, e.g. "97.49"
 }
 
 export interface SummaryResponse {
@@ -55,7 +55,7 @@ export interface ClientSuggestionsResponse {
 }
 
 export interface SettingsResponse {
-  mileage_rate: number;
+  mileage_rate: string; // decimal, e.g. "0.67"
 }
 
 export interface UpdateSettingsRequest {